@@ -2,17 +2,44 @@ package main
 
 import (
 	"amartha-backend-test/internal/config"
+	"amartha-backend-test/internal/domain/blob"
+	domainNotification "amartha-backend-test/internal/domain/notification"
+	domainSignature "amartha-backend-test/internal/domain/signature"
 	"amartha-backend-test/internal/infrastructure/cache"
+	"context"
 	"log"
 	"os"
 	"time"
 
 	httpadp "amartha-backend-test/internal/adapter/http"
+	"amartha-backend-test/internal/adapter/job"
 	idmp "amartha-backend-test/internal/adapter/middleware"
 	repomysql "amartha-backend-test/internal/adapter/repository/mysql"
 	dbinfra "amartha-backend-test/internal/infrastructure/db"
+	"amartha-backend-test/internal/infrastructure/esign"
+	"amartha-backend-test/internal/infrastructure/eventbus"
+	"amartha-backend-test/internal/infrastructure/mail"
+	"amartha-backend-test/internal/infrastructure/storage"
+	"amartha-backend-test/internal/infrastructure/webhookhttp"
+	usecaseAgreement "amartha-backend-test/internal/usecase/agreement"
 	usecaseApproval "amartha-backend-test/internal/usecase/approval"
+	usecaseCancellation "amartha-backend-test/internal/usecase/cancellation"
+	usecaseDelinquency "amartha-backend-test/internal/usecase/delinquency"
+	usecaseDisbursement "amartha-backend-test/internal/usecase/disbursement"
+	usecaseInvestment "amartha-backend-test/internal/usecase/investment"
+	usecaseLedger "amartha-backend-test/internal/usecase/ledger"
 	usecaseLoan "amartha-backend-test/internal/usecase/loan"
+	usecaseLoanHistory "amartha-backend-test/internal/usecase/loanhistory"
+	usecaseNotification "amartha-backend-test/internal/usecase/notification"
+	usecaseOutbox "amartha-backend-test/internal/usecase/outbox"
+	usecasePhoto "amartha-backend-test/internal/usecase/photo"
+	usecasePortfolio "amartha-backend-test/internal/usecase/portfolio"
+	usecaseRejection "amartha-backend-test/internal/usecase/rejection"
+	usecaseRepayment "amartha-backend-test/internal/usecase/repayment"
+	usecaseSchedule "amartha-backend-test/internal/usecase/schedule"
+	usecaseSignature "amartha-backend-test/internal/usecase/signature"
+	usecaseWallet "amartha-backend-test/internal/usecase/wallet"
+	usecaseWebhook "amartha-backend-test/internal/usecase/webhook"
 
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
@@ -36,14 +63,139 @@ func main() {
 	}
 	defer rdb.Close()
 
+	// blob storage for uploads; the local driver's links are served by this API
+	var blobStore blob.Store
+	var localBlobs *storage.Local
+	switch cfg.BlobDriver {
+	case "s3":
+		blobStore, err = storage.NewS3(storage.S3Config{
+			Endpoint: cfg.S3Endpoint, Region: cfg.S3Region, Bucket: cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey, SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		localBlobs, err = storage.NewLocal(cfg.BlobLocalDir, cfg.BlobPublicURL, []byte(cfg.BlobSigningKey))
+		blobStore = localBlobs
+	}
+	if err != nil {
+		log.Fatalf("blob store: %v", err)
+	}
+
 	loanRepo := repomysql.NewLoanRepository(gormDB)
-	ucLoan := usecaseLoan.NewUsecase(loanRepo)
 	approvalRepo := repomysql.NewApprovalRepository(gormDB)
+	investmentRepo := repomysql.NewInvestmentRepository(gormDB)
+	disbursementRepo := repomysql.NewDisbursementRepository(gormDB)
 	// UoW (one generic Unit-of-Work for all flows)
 	uow := repomysql.NewGormUoW(gormDB)
+	ucLoan := usecaseLoan.NewUsecase(loanRepo).
+		WithRejectionCooldown(time.Duration(cfg.RejectionCooldownDays)*24*time.Hour).
+		WithDetails(approvalRepo, investmentRepo, disbursementRepo).
+		WithOutbox(uow)
 
 	// Usecase (inject repos + UoW)
 	ucApproval := usecaseApproval.NewUsecase(loanRepo, approvalRepo, uow)
+	ucInvestment := usecaseInvestment.NewUsecase(investmentRepo, uow).WithAgreements(blobStore)
+	ucDisbursement := usecaseDisbursement.NewUsecase(disbursementRepo, uow).WithUntrackedSignatures(cfg.DisburseAllowUntracked)
+	rejectionRepo := repomysql.NewRejectionRepository(gormDB)
+	ucRejection := usecaseRejection.NewUsecase(rejectionRepo, uow)
+	ucCancellation := usecaseCancellation.NewUsecase(uow)
+	loanHistoryRepo := repomysql.NewLoanHistoryRepository(gormDB)
+	ucLoanHistory := usecaseLoanHistory.NewUsecase(loanRepo, loanHistoryRepo)
+	scheduleRepo := repomysql.NewRepaymentScheduleRepository(gormDB)
+	ucSchedule := usecaseSchedule.NewUsecase(loanRepo, scheduleRepo)
+	repaymentRepo := repomysql.NewRepaymentRepository(gormDB)
+	ucRepayment := usecaseRepayment.NewUsecase(repaymentRepo, uow)
+	ucDelinquency := usecaseDelinquency.NewUsecase(loanRepo, scheduleRepo)
+	ledgerRepo := repomysql.NewLedgerRepository(gormDB)
+	ucLedger := usecaseLedger.NewUsecase(ledgerRepo)
+	ucWallet := usecaseWallet.NewUsecase(ledgerRepo, uow).WithGatewaySecret(cfg.PaymentGatewaySecret)
+	payoutRepo := repomysql.NewPayoutRepository(gormDB)
+	ucPortfolio := usecasePortfolio.NewUsecase(investmentRepo, loanRepo, payoutRepo)
+	photoRepo := repomysql.NewPhotoRepository(gormDB)
+	ucPhoto := usecasePhoto.NewUsecase(photoRepo, blobStore, time.Duration(cfg.PhotoURLTTLMinutes)*time.Minute)
+	// e-signature providers, addressed by name in /webhooks/signature/:provider
+	var signers []domainSignature.Provider
+	if cfg.ESignFakeSecret != "" {
+		fake, err := esign.NewFake("fake", []byte(cfg.ESignFakeSecret), cfg.ESignFakeSigningURL)
+		if err != nil {
+			log.Fatalf("esign: %v", err)
+		}
+		signers = append(signers, fake)
+	}
+	ucSignature := usecaseSignature.NewUsecase(uow, blobStore, signers...)
+	ucAgreement := usecaseAgreement.NewUsecase(loanRepo, blobStore, time.Duration(cfg.AgreementURLTTLMinutes)*time.Minute)
+
+	// daily DPD recompute (WIB calendar day)
+	dpdHour, dpdMinute := cfg.DPDJobClock()
+	go (&job.Daily{
+		Name: "dpd-recompute", Hour: dpdHour, Minute: dpdMinute, Loc: job.WIB,
+		Run: func(ctx context.Context, now time.Time) error {
+			res, err := ucDelinquency.Recompute(ctx, now)
+			if res != nil {
+				log.Printf("dpd-recompute %s: loans=%d changed=%d failed=%d buckets=%v",
+					res.AsOf, res.Loans, res.Changed, res.Failed, res.ByBucket)
+			}
+			return err
+		},
+	}).Start(context.Background())
+
+	// partner webhooks: the relay fans each loan event out into deliveries,
+	// which the dispatcher below signs and posts
+	webhookRepo := repomysql.NewWebhookRepository(gormDB)
+	ucWebhook := usecaseWebhook.NewUsecase(webhookRepo, uow, webhookhttp.New(time.Duration(cfg.WebhookTimeoutSecs)*time.Second, cfg.WebhookAllowInsecure)).
+		WithMaxAttempts(cfg.WebhookMaxAttempts).
+		WithInsecureTargets(cfg.WebhookAllowInsecure)
+
+	// investor emails: queued per investor when a loan is invested, sent by
+	// the dispatcher below
+	var mailer domainNotification.Sender
+	switch cfg.MailDriver {
+	case "smtp":
+		mailer, err = mail.NewSMTP(mail.SMTPConfig{
+			Addr: cfg.SMTPAddr, Username: cfg.SMTPUsername, Password: cfg.SMTPPassword, From: cfg.MailFrom,
+		})
+	default:
+		mailer, err = mail.NewFile(cfg.MailFileDir, cfg.MailFrom)
+	}
+	if err != nil {
+		log.Fatalf("mail: %v", err)
+	}
+	notificationRepo := repomysql.NewNotificationRepository(gormDB)
+	ucNotification := usecaseNotification.NewUsecase(notificationRepo, uow, mailer, blobStore, time.Duration(cfg.NotifyLinkTTLHours)*time.Hour).
+		WithMaxAttempts(cfg.NotifyMaxAttempts)
+
+	// loan events appended to the outbox by the flows above, published to the
+	// log, partner webhooks and investor notifications
+	ucOutbox := usecaseOutbox.NewUsecase(uow, eventbus.Fanout{eventbus.Log{}, ucWebhook, ucNotification}).WithMaxAttempts(cfg.OutboxMaxAttempts)
+	go (&job.Interval{
+		Name: "outbox-relay", Every: time.Duration(cfg.OutboxRelaySecs) * time.Second,
+		Run: func(ctx context.Context, now time.Time) error {
+			res, err := ucOutbox.Relay(ctx, now)
+			if res != nil && *res != (usecaseOutbox.RelayResult{}) {
+				log.Printf("outbox-relay: sent=%d retried=%d failed=%d", res.Sent, res.Retried, res.Failed)
+			}
+			return err
+		},
+	}).Start(context.Background())
+	go (&job.Interval{
+		Name: "webhook-dispatch", Every: time.Duration(cfg.WebhookDispatchSecs) * time.Second,
+		Run: func(ctx context.Context, now time.Time) error {
+			res, err := ucWebhook.Dispatch(ctx, now)
+			if res != nil && *res != (usecaseWebhook.DispatchResult{}) {
+				log.Printf("webhook-dispatch: delivered=%d retried=%d dead=%d", res.Delivered, res.Retried, res.Dead)
+			}
+			return err
+		},
+	}).Start(context.Background())
+	go (&job.Interval{
+		Name: "notification-dispatch", Every: time.Duration(cfg.NotifyDispatchSecs) * time.Second,
+		Run: func(ctx context.Context, now time.Time) error {
+			res, err := ucNotification.Dispatch(ctx, now)
+			if res != nil && *res != (usecaseNotification.DispatchResult{}) {
+				log.Printf("notification-dispatch: sent=%d retried=%d failed=%d", res.Sent, res.Retried, res.Failed)
+			}
+			return err
+		},
+	}).Start(context.Background())
 
 	e := echo.New()
 	e.HideBanner = true
@@ -51,18 +203,65 @@ func main() {
 	e.Validator = httpadp.NewValidator()
 	e.Logger.SetOutput(os.Stdout)
 	log.SetOutput(os.Stdout)
-	// global idempotency for mutating methods, TTL in seconds
-	e.Use(idmp.IdempotencyMiddleware(rdb, time.Duration(cfg.IdempTTLSecs)*time.Second))
+	// expose Ax-Request-Id to usecases (audit trail)
+	e.Use(idmp.RequestContextMiddleware())
+	// global idempotency for mutating methods, TTL in seconds; inbound
+	// webhooks and the top-up callback are deduplicated by their own ids instead
+	e.Use(idmp.IdempotencyMiddleware(rdb, time.Duration(cfg.IdempTTLSecs)*time.Second, idmp.PathPrefixSkipper("/webhooks/", "/payments/topups/callback")))
 	h := httpadp.NewHandler()
 	hLoan := httpadp.NewLoanHandler(ucLoan)
 	hApproval := httpadp.NewApprovalHandler(ucApproval)
+	hInvestment := httpadp.NewInvestmentHandler(ucInvestment)
+	hDisbursement := httpadp.NewDisbursementHandler(ucDisbursement)
+	hRejection := httpadp.NewRejectionHandler(ucRejection)
+	hCancellation := httpadp.NewCancellationHandler(ucCancellation)
+	hHistory := httpadp.NewHistoryHandler(ucLoanHistory)
+	hSchedule := httpadp.NewScheduleHandler(ucSchedule)
+	hRepayment := httpadp.NewRepaymentHandler(ucRepayment)
+	hDelinquency := httpadp.NewDelinquencyHandler(ucDelinquency)
+	hLedger := httpadp.NewLedgerHandler(ucLedger)
+	hWallet := httpadp.NewWalletHandler(ucWallet)
+	hPortfolio := httpadp.NewPortfolioHandler(ucPortfolio)
+	hPhoto := httpadp.NewPhotoHandler(ucPhoto)
+	hSignature := httpadp.NewSignatureHandler(ucSignature)
+	hAgreement := httpadp.NewAgreementHandler(ucAgreement)
+	hWebhook := httpadp.NewWebhookHandler(ucWebhook)
+	hNotification := httpadp.NewNotificationHandler(ucNotification)
 
 	// routes
 	e.GET("/health", h.Health)
 
 	e.POST("/loans", hLoan.CreateLoan)
+	e.GET("/loans", hLoan.ListLoans)
+	e.POST("/photos", hPhoto.UploadPhoto)
+	e.GET("/photos/:photo_id", hPhoto.GetPhoto)
+	if localBlobs != nil {
+		e.GET("/blobs/*", httpadp.NewBlobHandler(localBlobs).ServeBlob)
+	}
 	e.POST("/loans/:loan_id/approve", hApproval.ApproveLoan)
+	e.DELETE("/loans/:loan_id/approval", hApproval.RevokeApproval)
+	e.POST("/loans/:loan_id/reject", hRejection.RejectLoan)
+	e.POST("/loans/:loan_id/cancel", hCancellation.CancelLoan)
+	e.POST("/loans/:loan_id/investments", hInvestment.InvestLoan)
+	e.GET("/loans/:loan_id/agreement", hAgreement.GetAgreement)
+	e.GET("/loans/:loan_id/notifications", hNotification.GetLoanNotifications)
+	e.POST("/loans/:loan_id/signature", hSignature.RequestSignature)
+	e.GET("/loans/:loan_id/signature", hSignature.GetSignature)
+	e.POST("/loans/:loan_id/disburse", hDisbursement.DisburseLoan)
+	e.POST("/loans/:loan_id/repayments", hRepayment.RecordRepayment)
 	e.GET("/loans/:loan_id", hLoan.GetLoan)
+	e.GET("/loans/:loan_id/history", hHistory.GetLoanHistory)
+	e.GET("/loans/:loan_id/schedule", hSchedule.GetLoanSchedule)
+	e.GET("/reports/tkb90", hDelinquency.GetTKB90)
+	e.GET("/ledger/balance", hLedger.GetBalance)
+	e.GET("/borrowers/:borrower_id/loans", hLoan.ListBorrowerLoans, idmp.BorrowerScopeMiddleware("borrower_id"))
+	e.GET("/investors/:investor_id/wallet", hWallet.GetWallet)
+	e.GET("/investors/:investor_id/portfolio", hPortfolio.GetPortfolio)
+	e.POST("/payments/topups/callback", hWallet.TopUpCallback)
+	e.POST("/partners/:partner_id/webhooks", hWebhook.Subscribe)
+	e.GET("/partners/:partner_id/webhooks", hWebhook.ListSubscriptions)
+	e.POST("/webhooks/signature/:provider", hSignature.SignatureWebhook)
+	e.GET("/webhooks/deliveries", hWebhook.ListDeliveries)
 
 	for _, r := range e.Routes() {
 		log.Printf("route: %-6s %s", r.Method, r.Path)
@@ -0,0 +1,26 @@
+// Command lifecycle prints the loan state machine as Mermaid or Graphviz DOT,
+// so the lifecycle can be reviewed without reading the usecases.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+)
+
+func main() {
+	format := flag.String("format", "mermaid", "output format: mermaid | dot")
+	flag.Parse()
+
+	switch *format {
+	case "mermaid":
+		fmt.Fprint(os.Stdout, domainLoan.Lifecycle.Mermaid())
+	case "dot":
+		fmt.Fprint(os.Stdout, domainLoan.Lifecycle.DOT())
+	default:
+		log.Fatalf("unknown format %q (want mermaid or dot)", *format)
+	}
+}
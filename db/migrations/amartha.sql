@@ -17,6 +17,19 @@
 SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
+-- ----------------------------
+-- Table structure for approval_photo_bands
+-- ----------------------------
+DROP TABLE IF EXISTS `approval_photo_bands`;
+CREATE TABLE `approval_photo_bands` (
+  `approval_id` bigint unsigned NOT NULL,
+  `band` tinyint unsigned NOT NULL,
+  `value` bigint unsigned NOT NULL,
+  PRIMARY KEY (`approval_id`,`band`),
+  KEY `idx_approval_photo_bands_lookup` (`band`,`value`),
+  CONSTRAINT `fk_approval_photo_bands_approval` FOREIGN KEY (`approval_id`) REFERENCES `approvals` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
 -- ----------------------------
 -- Table structure for approvals
 -- ----------------------------
@@ -25,6 +38,8 @@ CREATE TABLE `approvals` (
   `id` bigint unsigned NOT NULL AUTO_INCREMENT,
   `approval_id` char(32) NOT NULL,
   `loan_id` bigint unsigned NOT NULL,
+  `photo_id` char(32) DEFAULT NULL,
+  `photo_phash` bigint DEFAULT NULL,
   `photo_url` text NOT NULL,
   `validator_employee_id` char(32) NOT NULL,
   `approval_date` date NOT NULL,
@@ -32,10 +47,12 @@ CREATE TABLE `approvals` (
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `deleted_at` timestamp NULL DEFAULT NULL,
   `deleted_by` char(32) DEFAULT NULL,
-  `deleted_flag` tinyint(1) GENERATED ALWAYS AS (if((`deleted_at` is null),0,1)) STORED,
+  -- NULL once deleted: unique keys ignore NULLs, so a loan can be approved and revoked more than once
+  `deleted_flag` tinyint(1) GENERATED ALWAYS AS (if((`deleted_at` is null),0,NULL)) STORED,
   PRIMARY KEY (`id`),
   UNIQUE KEY `ux_approvals_approval_id_active` (`approval_id`,`deleted_flag`),
   UNIQUE KEY `ux_approvals_loan_active` (`loan_id`,`deleted_flag`),
+  KEY `idx_approvals_photo_id` (`photo_id`),
   CONSTRAINT `fk_approvals_loan` FOREIGN KEY (`loan_id`) REFERENCES `loans` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 
@@ -76,7 +93,9 @@ CREATE TABLE `investments` (
   `investment_id` char(32) NOT NULL,
   `loan_id` bigint unsigned NOT NULL,
   `investor_id` char(32) NOT NULL,
+  `investor_email` varchar(254) DEFAULT NULL,
   `amount` decimal(18,2) NOT NULL,
+  `status` enum('held','released','converted') NOT NULL DEFAULT 'held',
   `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   `deleted_at` timestamp NULL DEFAULT NULL,
@@ -90,6 +109,100 @@ CREATE TABLE `investments` (
   CONSTRAINT `investments_chk_1` CHECK ((`amount` > 0))
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 
+-- ----------------------------
+-- Table structure for investor_payouts
+-- ----------------------------
+DROP TABLE IF EXISTS `investor_payouts`;
+CREATE TABLE `investor_payouts` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `payout_id` char(32) NOT NULL,
+  `repayment_id` bigint unsigned NOT NULL,
+  `loan_id` bigint unsigned NOT NULL,
+  `investment_id` bigint unsigned NOT NULL,
+  `investor_id` char(32) NOT NULL,
+  `principal` decimal(18,2) NOT NULL,
+  `interest` decimal(18,2) NOT NULL,
+  `total` decimal(18,2) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_payouts_payout_id` (`payout_id`),
+  UNIQUE KEY `ux_payouts_repayment_investment` (`repayment_id`,`investment_id`),
+  KEY `idx_payouts_loan` (`loan_id`),
+  KEY `idx_payouts_investor` (`investor_id`),
+  CONSTRAINT `fk_payouts_repayment` FOREIGN KEY (`repayment_id`) REFERENCES `repayments` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `fk_payouts_loan` FOREIGN KEY (`loan_id`) REFERENCES `loans` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `fk_payouts_investment` FOREIGN KEY (`investment_id`) REFERENCES `investments` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `investor_payouts_chk_1` CHECK ((`total` = (`principal` + `interest`)))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for journal_entries
+-- ----------------------------
+DROP TABLE IF EXISTS `journal_entries`;
+CREATE TABLE `journal_entries` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `entry_id` char(32) NOT NULL,
+  `kind` varchar(32) NOT NULL,
+  `ref_id` char(32) NOT NULL,
+  `loan_id` bigint unsigned DEFAULT NULL,
+  `posted_at` datetime NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_je_entry_id` (`entry_id`),
+  UNIQUE KEY `ux_je_ref` (`kind`,`ref_id`),
+  KEY `idx_je_loan` (`loan_id`),
+  CONSTRAINT `fk_je_loan` FOREIGN KEY (`loan_id`) REFERENCES `loans` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for journal_lines
+-- ----------------------------
+DROP TABLE IF EXISTS `journal_lines`;
+CREATE TABLE `journal_lines` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `entry_id` bigint unsigned NOT NULL,
+  `account` varchar(32) NOT NULL,
+  `owner_id` varchar(32) NOT NULL DEFAULT '',
+  `direction` enum('debit','credit') NOT NULL,
+  `amount` decimal(18,2) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_jl_entry` (`entry_id`),
+  KEY `idx_jl_account` (`account`,`owner_id`),
+  CONSTRAINT `fk_jl_entry` FOREIGN KEY (`entry_id`) REFERENCES `journal_entries` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `journal_lines_chk_1` CHECK ((`amount` > 0)),
+  CONSTRAINT `journal_lines_chk_2` CHECK ((`account` in ('investor_wallet','escrow','borrower_receivable','cash','platform_fee')))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for ledger_accounts
+-- ----------------------------
+DROP TABLE IF EXISTS `ledger_accounts`;
+CREATE TABLE `ledger_accounts` (
+  `account` varchar(32) NOT NULL,
+  `owner_id` varchar(32) NOT NULL DEFAULT '',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`account`,`owner_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for loan_state_transitions
+-- ----------------------------
+DROP TABLE IF EXISTS `loan_state_transitions`;
+CREATE TABLE `loan_state_transitions` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `loan_id` bigint unsigned NOT NULL,
+  `from_state` varchar(16) NOT NULL,
+  `to_state` varchar(16) NOT NULL,
+  `actor` varchar(64) NOT NULL,
+  `reason` text,
+  `request_id` varchar(64) DEFAULT NULL,
+  `transitioned_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_lst_loan` (`loan_id`,`transitioned_at`),
+  CONSTRAINT `fk_lst_loan` FOREIGN KEY (`loan_id`) REFERENCES `loans` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
 -- ----------------------------
 -- Table structure for loans
 -- ----------------------------
@@ -101,8 +214,17 @@ CREATE TABLE `loans` (
   `principal` decimal(18,2) NOT NULL,
   `rate` decimal(6,4) NOT NULL,
   `roi` decimal(6,4) NOT NULL,
+  `tenor` smallint unsigned NOT NULL,
+  `tenor_unit` enum('week','month') NOT NULL,
+  `interest_method` enum('flat','effective') NOT NULL DEFAULT 'flat',
+  `outstanding_balance` decimal(18,2) NOT NULL DEFAULT '0.00',
+  `dpd` smallint unsigned NOT NULL DEFAULT '0',
+  `dpd_bucket` enum('current','1-30','31-60','61-90','90+') NOT NULL DEFAULT 'current',
+  `dpd_as_of` date DEFAULT NULL,
   `agreement_link` text,
-  `state` enum('proposed','approved','invested','disbursed') NOT NULL DEFAULT 'proposed',
+  `agreement_version` varchar(16) DEFAULT NULL,
+  `agreement_sha256` char(64) DEFAULT NULL,
+  `state` enum('proposed','rejected','approved','invested','disbursed','repaid','cancelled') NOT NULL DEFAULT 'proposed',
   `state_updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -112,7 +234,251 @@ CREATE TABLE `loans` (
   PRIMARY KEY (`id`),
   UNIQUE KEY `ux_loans_loan_id_active` (`loan_id`,`deleted_flag`),
   KEY `idx_loans_borrower_active` (`borrower_id`,`deleted_flag`),
-  CONSTRAINT `loans_chk_1` CHECK ((`principal` > 0))
+  KEY `idx_loans_state_dpd` (`state`,`dpd_bucket`,`id`),
+  CONSTRAINT `loans_chk_1` CHECK ((`principal` > 0)),
+  CONSTRAINT `loans_chk_2` CHECK ((`tenor` > 0))
 ) ENGINE=InnoDB AUTO_INCREMENT=16 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 
+-- ----------------------------
+-- Table structure for notifications
+-- ----------------------------
+DROP TABLE IF EXISTS `notifications`;
+CREATE TABLE `notifications` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `notification_id` char(32) NOT NULL,
+  `event_id` char(32) NOT NULL,
+  `template` varchar(64) NOT NULL,
+  `loan_id` char(32) NOT NULL,
+  `recipient_id` char(32) NOT NULL,
+  `email` varchar(254) DEFAULT NULL,
+  `data` json NOT NULL,
+  `status` enum('PENDING','SENT','FAILED','SKIPPED') NOT NULL DEFAULT 'PENDING',
+  `attempts` smallint unsigned NOT NULL DEFAULT '0',
+  `next_attempt_at` datetime NOT NULL,
+  `last_error` text,
+  `sent_at` datetime DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_notifications_notification_id` (`notification_id`),
+  UNIQUE KEY `ux_notifications_event_recipient` (`event_id`,`recipient_id`),
+  KEY `idx_notifications_loan` (`loan_id`),
+  KEY `idx_notifications_due` (`status`,`next_attempt_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for outbox
+-- ----------------------------
+DROP TABLE IF EXISTS `outbox`;
+CREATE TABLE `outbox` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `event_id` char(32) NOT NULL,
+  `event_type` varchar(32) NOT NULL,
+  `aggregate_id` char(32) NOT NULL,
+  `payload` json NOT NULL,
+  `status` enum('PENDING','SENT','FAILED') NOT NULL DEFAULT 'PENDING',
+  `attempts` smallint unsigned NOT NULL DEFAULT '0',
+  `next_attempt_at` datetime NOT NULL,
+  `last_error` text,
+  `sent_at` datetime DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_outbox_event_id` (`event_id`),
+  KEY `idx_outbox_due` (`status`,`next_attempt_at`),
+  KEY `idx_outbox_aggregate` (`aggregate_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for photos
+-- ----------------------------
+DROP TABLE IF EXISTS `photos`;
+CREATE TABLE `photos` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `photo_id` char(32) NOT NULL,
+  `storage_key` varchar(255) NOT NULL,
+  `sha256` char(64) NOT NULL,
+  `phash` bigint DEFAULT NULL,
+  `content_type` varchar(64) NOT NULL,
+  `size_bytes` bigint NOT NULL,
+  `uploaded_by` char(32) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+  `deleted_by` char(32) DEFAULT NULL,
+  `deleted_flag` tinyint(1) GENERATED ALWAYS AS (if((`deleted_at` is null),0,1)) STORED,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_photos_photo_id_active` (`photo_id`,`deleted_flag`),
+  KEY `idx_photos_sha256` (`sha256`),
+  CONSTRAINT `photos_chk_1` CHECK ((`size_bytes` > 0))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for rejections
+-- ----------------------------
+DROP TABLE IF EXISTS `rejections`;
+CREATE TABLE `rejections` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `rejection_id` char(32) NOT NULL,
+  `loan_id` bigint unsigned NOT NULL,
+  `reason_code` varchar(64) NOT NULL,
+  `reason_text` text,
+  `validator_employee_id` char(32) NOT NULL,
+  `rejected_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+  `deleted_by` char(32) DEFAULT NULL,
+  `deleted_flag` tinyint(1) GENERATED ALWAYS AS (if((`deleted_at` is null),0,1)) STORED,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_rejections_rejection_id_active` (`rejection_id`,`deleted_flag`),
+  UNIQUE KEY `ux_rejections_loan_active` (`loan_id`,`deleted_flag`),
+  CONSTRAINT `fk_rejections_loan` FOREIGN KEY (`loan_id`) REFERENCES `loans` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for repayment_schedules
+-- ----------------------------
+DROP TABLE IF EXISTS `repayment_schedules`;
+CREATE TABLE `repayment_schedules` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `loan_id` bigint unsigned NOT NULL,
+  `installment_no` smallint unsigned NOT NULL,
+  `due_date` date NOT NULL,
+  `principal_due` decimal(18,2) NOT NULL,
+  `interest_due` decimal(18,2) NOT NULL,
+  `fee_due` decimal(18,2) NOT NULL DEFAULT '0.00',
+  `total_due` decimal(18,2) NOT NULL,
+  `principal_paid` decimal(18,2) NOT NULL DEFAULT '0.00',
+  `interest_paid` decimal(18,2) NOT NULL DEFAULT '0.00',
+  `fee_paid` decimal(18,2) NOT NULL DEFAULT '0.00',
+  `paid_at` datetime DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_rs_loan_installment` (`loan_id`,`installment_no`),
+  KEY `idx_rs_due_date` (`due_date`),
+  CONSTRAINT `fk_rs_loan` FOREIGN KEY (`loan_id`) REFERENCES `loans` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for repayments
+-- ----------------------------
+DROP TABLE IF EXISTS `repayments`;
+CREATE TABLE `repayments` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `repayment_id` char(32) NOT NULL,
+  `loan_id` bigint unsigned NOT NULL,
+  `amount` decimal(18,2) NOT NULL,
+  `fee_paid` decimal(18,2) NOT NULL,
+  `interest_paid` decimal(18,2) NOT NULL,
+  `principal_paid` decimal(18,2) NOT NULL,
+  `outstanding_after` decimal(18,2) NOT NULL,
+  `collector_employee_id` char(32) NOT NULL,
+  `paid_at` datetime NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_repayments_repayment_id` (`repayment_id`),
+  KEY `idx_repayments_loan` (`loan_id`,`paid_at`),
+  CONSTRAINT `fk_repayments_loan` FOREIGN KEY (`loan_id`) REFERENCES `loans` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT,
+  CONSTRAINT `repayments_chk_1` CHECK ((`amount` > 0))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for signature_envelopes
+-- ----------------------------
+DROP TABLE IF EXISTS `signature_envelopes`;
+CREATE TABLE `signature_envelopes` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `envelope_id` char(32) NOT NULL,
+  `loan_id` bigint unsigned NOT NULL,
+  `signature_provider` varchar(64) NOT NULL,
+  `signature_tx_id` varchar(128) NOT NULL,
+  `signature_status` enum('PENDING','SIGNED','CANCELLED') NOT NULL DEFAULT 'PENDING',
+  `document_url` text NOT NULL,
+  `signing_url` text NOT NULL,
+  `signed_at` datetime DEFAULT NULL,
+  `document_sha256` char(64) DEFAULT NULL,
+  `signed_document_key` varchar(255) DEFAULT NULL,
+  `requested_by` char(32) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+  `deleted_by` char(32) DEFAULT NULL,
+  `deleted_flag` tinyint(1) GENERATED ALWAYS AS (if((`deleted_at` is null),0,1)) STORED,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_sigenv_envelope_id_active` (`envelope_id`,`deleted_flag`),
+  UNIQUE KEY `ux_sigenv_tx_active` (`signature_provider`,`signature_tx_id`,`deleted_flag`),
+  KEY `idx_sigenv_loan_id` (`loan_id`),
+  CONSTRAINT `fk_sigenv_loan` FOREIGN KEY (`loan_id`) REFERENCES `loans` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for signature_events
+-- ----------------------------
+DROP TABLE IF EXISTS `signature_events`;
+CREATE TABLE `signature_events` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `signature_provider` varchar(64) NOT NULL,
+  `event_id` varchar(128) NOT NULL,
+  `envelope_id` bigint unsigned NOT NULL,
+  `signature_status` enum('PENDING','SIGNED','CANCELLED') NOT NULL,
+  `applied` tinyint(1) NOT NULL,
+  `occurred_at` datetime NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_sigevt_provider_event` (`signature_provider`,`event_id`),
+  KEY `idx_sigevt_envelope` (`envelope_id`),
+  CONSTRAINT `fk_sigevt_envelope` FOREIGN KEY (`envelope_id`) REFERENCES `signature_envelopes` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for webhook_deliveries
+-- ----------------------------
+DROP TABLE IF EXISTS `webhook_deliveries`;
+CREATE TABLE `webhook_deliveries` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `delivery_id` char(32) NOT NULL,
+  `subscription_id` bigint unsigned NOT NULL,
+  `partner_id` char(32) NOT NULL,
+  `event_id` char(32) NOT NULL,
+  `event_type` varchar(32) NOT NULL,
+  `payload` json NOT NULL,
+  `status` enum('PENDING','DELIVERED','DEAD') NOT NULL DEFAULT 'PENDING',
+  `attempts` smallint unsigned NOT NULL DEFAULT '0',
+  `next_attempt_at` datetime NOT NULL,
+  `last_status_code` int DEFAULT NULL,
+  `last_error` text,
+  `delivered_at` datetime DEFAULT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_whdel_delivery_id` (`delivery_id`),
+  UNIQUE KEY `ux_whdel_subscription_event` (`subscription_id`,`event_id`),
+  KEY `idx_whdel_partner` (`partner_id`),
+  KEY `idx_whdel_due` (`status`,`next_attempt_at`),
+  CONSTRAINT `fk_whdel_subscription` FOREIGN KEY (`subscription_id`) REFERENCES `webhook_subscriptions` (`id`) ON DELETE RESTRICT ON UPDATE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- ----------------------------
+-- Table structure for webhook_subscriptions
+-- ----------------------------
+DROP TABLE IF EXISTS `webhook_subscriptions`;
+CREATE TABLE `webhook_subscriptions` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `subscription_id` char(32) NOT NULL,
+  `partner_id` char(32) NOT NULL,
+  `url` varchar(2048) NOT NULL,
+  `secret` char(64) NOT NULL,
+  `event_types` varchar(255) NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `deleted_at` timestamp NULL DEFAULT NULL,
+  `deleted_by` char(32) DEFAULT NULL,
+  `deleted_flag` tinyint(1) GENERATED ALWAYS AS (if((`deleted_at` is null),0,1)) STORED,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `ux_whsub_subscription_id_active` (`subscription_id`,`deleted_flag`),
+  KEY `idx_whsub_partner` (`partner_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
 SET FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	domainAgreement "amartha-backend-test/internal/domain/agreement"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	ucAgreement "amartha-backend-test/internal/usecase/agreement"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AgreementHandler struct{ uc *ucAgreement.Usecase }
+
+func NewAgreementHandler(uc *ucAgreement.Usecase) *AgreementHandler {
+	return &AgreementHandler{uc: uc}
+}
+
+// GetAgreement returns a signed link to the loan agreement, with the
+// template version and hash it was rendered with.
+func (h *AgreementHandler) GetAgreement(c echo.Context) error {
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+	dto, err := h.uc.Get(c.Request().Context(), loanID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(err, domainAgreement.ErrNotGenerated):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, dto)
+}
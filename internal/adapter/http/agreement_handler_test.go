@@ -0,0 +1,60 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/testutil/blobmock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	ucAgreement "amartha-backend-test/internal/usecase/agreement"
+
+	"gorm.io/gorm"
+)
+
+func TestGetAgreement(t *testing.T) {
+	loans := &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			switch loanID {
+			case "LN-1":
+				return &domainLoan.Loan{LoanID: "LN-1", AgreementLink: "agreements/LN-1/agreement-v1.txt", AgreementVersion: "v1"}, nil
+			case "LN-2":
+				return &domainLoan.Loan{LoanID: "LN-2", State: domainLoan.StateApproved}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	store := &blobmock.Store{
+		SignedURLFn: func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+			return "http://blobs/" + key, nil
+		},
+	}
+	h := NewAgreementHandler(ucAgreement.NewUsecase(loans, store, time.Hour))
+
+	for id, want := range map[string]int{"LN-1": stdhttp.StatusOK, "LN-2": stdhttp.StatusNotFound, "LN-3": stdhttp.StatusNotFound} {
+		e := newEchoWithValidator()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(stdhttp.MethodGet, "/loans/"+id+"/agreement", nil), rec)
+		c.SetParamNames("loan_id")
+		c.SetParamValues(id)
+		if err := h.GetAgreement(c); err != nil {
+			t.Fatalf("GetAgreement error: %v", err)
+		}
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d (body=%s)", id, rec.Code, want, rec.Body.String())
+		}
+		if want == stdhttp.StatusOK {
+			var dto ucAgreement.AgreementDTO
+			if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+				t.Fatalf("bad json: %v", err)
+			}
+			if dto.URL != "http://blobs/agreements/LN-1/agreement-v1.txt" || dto.TemplateVersion != "v1" {
+				t.Fatalf("unexpected dto: %+v", dto)
+			}
+		}
+	}
+}
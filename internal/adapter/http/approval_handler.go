@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"time"
 
+	domainApproval "amartha-backend-test/internal/domain/approval"
 	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainPhoto "amartha-backend-test/internal/domain/photo"
 	ucApproval "amartha-backend-test/internal/usecase/approval"
 
 	"github.com/labstack/echo/v4"
@@ -16,7 +18,7 @@ type ApprovalHandler struct{ uc *ucApproval.Usecase }
 func NewApprovalHandler(uc *ucApproval.Usecase) *ApprovalHandler { return &ApprovalHandler{uc: uc} }
 
 type approveLoanReq struct {
-	PhotoURL            string `json:"photo_url"             validate:"required,url"`
+	PhotoID             string `json:"photo_id"              validate:"required,hex32"`
 	ValidatorEmployeeID string `json:"validator_employee_id" validate:"required,hex32"`
 	// canonical date `YYYY-MM-DD` (matches MySQL DATE)
 	ApprovalDate string `json:"approval_date"               validate:"required,datetime=2006-01-02"`
@@ -56,7 +58,7 @@ func (h *ApprovalHandler) ApproveLoan(c echo.Context) error {
 		c.Request().Context(),
 		ucApproval.ApproveInput{
 			LoanID:              loanID,
-			PhotoURL:            req.PhotoURL,
+			PhotoID:             req.PhotoID,
 			ValidatorEmployeeID: req.ValidatorEmployeeID,
 			ApprovalDate:        ad,
 		},
@@ -65,8 +67,15 @@ func (h *ApprovalHandler) ApproveLoan(c echo.Context) error {
 		switch {
 		case errors.Is(uerr, domainLoan.ErrNotFound):
 			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(uerr, domainPhoto.ErrNotFound):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "PhotoID", Message: "must be a photo_id returned by POST /photos"}},
+			})
 		case errors.Is(uerr, domainLoan.ErrAlreadyApproved):
 			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan already approved"})
+		case errors.Is(uerr, domainApproval.ErrDuplicatePhoto):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: uerr.Error()})
 		case errors.Is(uerr, domainLoan.ErrInvalidTransition):
 			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan not in a state that can be approved"})
 		default:
@@ -77,3 +86,46 @@ func (h *ApprovalHandler) ApproveLoan(c echo.Context) error {
 	// success
 	return c.JSON(http.StatusOK, dto)
 }
+
+type revokeApprovalReq struct {
+	LoanID              string `param:"loan_id"               validate:"required,max=64"`
+	Reason              string `json:"reason"                 validate:"required,max=1000"`
+	ValidatorEmployeeID string `json:"validator_employee_id"  validate:"required,hex32"`
+}
+
+// RevokeApproval moves an approved loan with no investments back to proposed
+// and soft-deletes its approval.
+func (h *ApprovalHandler) RevokeApproval(c echo.Context) error {
+	var req revokeApprovalReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.Revoke(c.Request().Context(), ucApproval.RevokeInput(req))
+	if err != nil {
+		switch {
+		case errors.Is(err, ucApproval.ErrReasonRequired):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "Reason", Message: "is required"}},
+			})
+		case errors.Is(err, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(err, domainApproval.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "approval not found"})
+		case errors.Is(err, domainApproval.ErrLoanHasFunding):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: domainApproval.ErrLoanHasFunding.Error()})
+		case errors.Is(err, domainLoan.ErrInvalidTransition):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan is not approved"})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, dto)
+}
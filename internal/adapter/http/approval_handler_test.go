@@ -10,12 +10,18 @@ import (
 	"testing"
 
 	domainApproval "amartha-backend-test/internal/domain/approval"
+	domainInv "amartha-backend-test/internal/domain/investment"
 	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainPhoto "amartha-backend-test/internal/domain/photo"
 	"amartha-backend-test/internal/domain/uow"
 	"amartha-backend-test/internal/testutil/approvalmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
 	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/photomock"
 	"amartha-backend-test/internal/testutil/uowmock"
 	ucApproval "amartha-backend-test/internal/usecase/approval"
+	"amartha-backend-test/pkg/phash"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -31,6 +37,18 @@ func hasFieldDetail(details []FieldError, field, contains string) bool {
 	return false
 }
 
+var photoID = strings.Repeat("b", 32)
+
+// issuedPhotos knows only photoID, as if it had been uploaded through POST /photos.
+var issuedPhotos = &photomock.Repo{
+	GetByPhotoIDForUpdateFn: func(ctx context.Context, id string) (*domainPhoto.Photo, error) {
+		if id != photoID {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return &domainPhoto.Photo{PhotoID: id, StorageKey: "photos/" + id + ".jpg"}, nil
+	},
+}
+
 func TestApproveLoan_Success(t *testing.T) {
 	e := newEchoWithValidator()
 
@@ -49,14 +67,14 @@ func TestApproveLoan_Success(t *testing.T) {
 	}
 	tx := &uowmock.UoW{
 		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-			return fn(uow.Repos{Loans: loans, Approvals: apprs})
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Photos: issuedPhotos, Outbox: &outboxmock.Repo{}})
 		},
 	}
 	uc := ucApproval.NewUsecase(loans, apprs, tx)
 	h := NewApprovalHandler(uc)
 
 	body := map[string]any{
-		"photo_url":             "https://cdn.example.com/img.jpg",
+		"photo_id":              photoID,
 		"validator_employee_id": strings.Repeat("a", 32), // hex32
 		"approval_date":         "2025-09-06",
 	}
@@ -81,8 +99,106 @@ func TestApproveLoan_Success(t *testing.T) {
 	if dto.LoanID != "llllllllllllllllllllllllllllllll" {
 		t.Fatalf("dto.LoanID = %s, want path loan_id", dto.LoanID)
 	}
-	if dto.PhotoURL != "https://cdn.example.com/img.jpg" {
-		t.Fatalf("dto.PhotoURL mismatch: %s", dto.PhotoURL)
+	if dto.PhotoID != photoID || dto.PhotoURL != "photos/"+photoID+".jpg" {
+		t.Fatalf("dto photo mismatch: %s %s", dto.PhotoID, dto.PhotoURL)
+	}
+}
+
+func TestApproveLoan_UnknownPhoto(t *testing.T) {
+	e := newEchoWithValidator()
+	loans := &loanmock.Repo{
+		GetByLoanIDForUpdateFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			return &domainLoan.Loan{ID: 777, LoanID: loanID, State: domainLoan.StateProposed}, nil
+		},
+	}
+	apprs := &approvalmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, numeric uint64) (*domainApproval.Approval, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	tx := &uowmock.UoW{
+		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Photos: issuedPhotos, Outbox: &outboxmock.Repo{}})
+		},
+	}
+	h := NewApprovalHandler(ucApproval.NewUsecase(loans, apprs, tx))
+
+	// well-formed, but never uploaded
+	body := map[string]any{
+		"photo_id":              strings.Repeat("c", 32),
+		"validator_employee_id": strings.Repeat("a", 32),
+		"approval_date":         "2025-09-06",
+	}
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/LN-1/approve", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("loan_id")
+	c.SetParamValues("LN-1")
+
+	if err := h.ApproveLoan(c); err != nil {
+		t.Fatalf("ApproveLoan error: %v", err)
+	}
+	if rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var er ErrorResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &er)
+	if !hasFieldDetail(er.Details, "PhotoID", "POST /photos") {
+		t.Fatalf("missing PhotoID detail: %+v", er.Details)
+	}
+}
+
+func TestApproveLoan_DuplicatePhoto(t *testing.T) {
+	e := newEchoWithValidator()
+	h := phash.Hash(7)
+	loans := &loanmock.Repo{
+		GetByLoanIDForUpdateFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			return &domainLoan.Loan{ID: 777, LoanID: loanID, State: domainLoan.StateProposed}, nil
+		},
+	}
+	apprs := &approvalmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, numeric uint64) (*domainApproval.Approval, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		FindNearPhotoFn: func(context.Context, phash.Hash, int, uint64) (*domainApproval.Approval, error) {
+			return &domainApproval.Approval{ApprovalID: "APR-OTHER"}, nil
+		},
+	}
+	photos := &photomock.Repo{
+		GetByPhotoIDForUpdateFn: func(ctx context.Context, id string) (*domainPhoto.Photo, error) {
+			return &domainPhoto.Photo{PhotoID: id, StorageKey: "photos/" + id + ".jpg", PHash: &h}, nil
+		},
+	}
+	tx := &uowmock.UoW{
+		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Photos: photos, Outbox: &outboxmock.Repo{}})
+		},
+	}
+	hd := NewApprovalHandler(ucApproval.NewUsecase(loans, apprs, tx))
+
+	body := map[string]any{
+		"photo_id":              photoID,
+		"validator_employee_id": strings.Repeat("a", 32),
+		"approval_date":         "2025-09-06",
+	}
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/LN-1/approve", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("loan_id")
+	c.SetParamValues("LN-1")
+
+	if err := hd.ApproveLoan(c); err != nil {
+		t.Fatalf("ApproveLoan error: %v", err)
+	}
+	if rec.Code != stdhttp.StatusConflict {
+		t.Fatalf("status = %d, want 409 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var er ErrorResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &er)
+	if !strings.Contains(er.Error, "APR-OTHER") {
+		t.Fatalf("error should name the matching approval: %q", er.Error)
 	}
 }
 
@@ -117,7 +233,7 @@ func TestApproveLoan_BindError(t *testing.T) {
 	uc := ucApproval.NewUsecase(nil, nil, nil)
 	h := NewApprovalHandler(uc)
 
-	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/abcd/approve", strings.NewReader(`{"photo_url":`)) // broken JSON
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/abcd/approve", strings.NewReader(`{"photo_id":`)) // broken JSON
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -142,9 +258,9 @@ func TestApproveLoan_ValidationError(t *testing.T) {
 	uc := ucApproval.NewUsecase(nil, nil, nil) // won’t be called
 	h := NewApprovalHandler(uc)
 
-	// invalid: bad URL, not hex32, wrong date format
+	// invalid: photo_id and validator not hex32, wrong date format
 	body := map[string]any{
-		"photo_url":             "not-a-url",
+		"photo_id":              "not-a-url",
 		"validator_employee_id": "NOTHEX",
 		"approval_date":         "2025/09/06",
 	}
@@ -169,7 +285,7 @@ func TestApproveLoan_ValidationError(t *testing.T) {
 		t.Fatalf("error = %q, want %q", er.Error, "validation failed")
 	}
 	// ensure at least some field details are present
-	if !(hasFieldDetail(er.Details, "PhotoURL", "url") || hasFieldDetail(er.Details, "ValidatorEmployeeID", "32") || hasFieldDetail(er.Details, "ApprovalDate", "datetime")) {
+	if !(hasFieldDetail(er.Details, "PhotoID", "32") || hasFieldDetail(er.Details, "ValidatorEmployeeID", "32") || hasFieldDetail(er.Details, "ApprovalDate", "datetime")) {
 		t.Fatalf("missing expected field errors: %+v", er.Details)
 	}
 }
@@ -185,14 +301,14 @@ func TestApproveLoan_NotFound(t *testing.T) {
 	apprs := &approvalmock.Repo{}
 	tx := &uowmock.UoW{
 		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-			return fn(uow.Repos{Loans: loans, Approvals: apprs})
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Outbox: &outboxmock.Repo{}})
 		},
 	}
 	uc := ucApproval.NewUsecase(loans, apprs, tx)
 	h := NewApprovalHandler(uc)
 
 	body := map[string]any{
-		"photo_url":             "https://cdn/img.jpg",
+		"photo_id":              photoID,
 		"validator_employee_id": strings.Repeat("a", 32),
 		"approval_date":         "2025-09-06",
 	}
@@ -227,14 +343,14 @@ func TestApproveLoan_AlreadyApproved(t *testing.T) {
 	apprs := &approvalmock.Repo{}
 	tx := &uowmock.UoW{
 		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-			return fn(uow.Repos{Loans: loans, Approvals: apprs})
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Outbox: &outboxmock.Repo{}})
 		},
 	}
 	uc := ucApproval.NewUsecase(loans, apprs, tx)
 	h := NewApprovalHandler(uc)
 
 	body := map[string]any{
-		"photo_url":             "https://cdn/img.jpg",
+		"photo_id":              photoID,
 		"validator_employee_id": strings.Repeat("a", 32),
 		"approval_date":         "2025-09-06",
 	}
@@ -269,14 +385,14 @@ func TestApproveLoan_InvalidTransition(t *testing.T) {
 	apprs := &approvalmock.Repo{}
 	tx := &uowmock.UoW{
 		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-			return fn(uow.Repos{Loans: loans, Approvals: apprs})
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Outbox: &outboxmock.Repo{}})
 		},
 	}
 	uc := ucApproval.NewUsecase(loans, apprs, tx)
 	h := NewApprovalHandler(uc)
 
 	body := map[string]any{
-		"photo_url":             "https://cdn/img.jpg",
+		"photo_id":              photoID,
 		"validator_employee_id": strings.Repeat("a", 32),
 		"approval_date":         "2025-09-06",
 	}
@@ -319,14 +435,14 @@ func TestApproveLoan_GenericError(t *testing.T) {
 	}
 	tx := &uowmock.UoW{
 		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-			return fn(uow.Repos{Loans: loans, Approvals: apprs})
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Photos: issuedPhotos, Outbox: &outboxmock.Repo{}})
 		},
 	}
 	uc := ucApproval.NewUsecase(loans, apprs, tx)
 	h := NewApprovalHandler(uc)
 
 	body := map[string]any{
-		"photo_url":             "https://cdn/img.jpg",
+		"photo_id":              photoID,
 		"validator_employee_id": strings.Repeat("a", 32),
 		"approval_date":         "2025-09-06",
 	}
@@ -349,3 +465,63 @@ func TestApproveLoan_GenericError(t *testing.T) {
 		t.Fatalf("error = %q, want %q", er.Error, "insert failed")
 	}
 }
+
+// newRevokeHandler wires the revoke flow over a loan in state s with n investments.
+func newRevokeHandler(s domainLoan.State, n int) *ApprovalHandler {
+	repos := uow.Repos{
+		Loans:       &loanmock.Repo{},
+		LoanHistory: &loanmock.HistoryRepo{},
+		Approvals: &approvalmock.Repo{
+			GetByLoanIDFn: func(context.Context, uint64) (*domainApproval.Approval, error) {
+				return &domainApproval.Approval{ApprovalID: "AP-1"}, nil
+			},
+		},
+		Investments: &investmentmock.Repo{
+			ListByLoanIDFn: func(context.Context, uint64) ([]domainInv.Investment, error) {
+				return make([]domainInv.Investment, n), nil
+			},
+		},
+		Outbox: &outboxmock.Repo{},
+	}
+	tx := uowmock.New().WithWithinLoanTx(func(ctx context.Context, loanID string, fn func(uow.Repos, *domainLoan.Loan) error) error {
+		return fn(repos, &domainLoan.Loan{ID: 1, LoanID: loanID, State: s})
+	})
+	return NewApprovalHandler(ucApproval.NewUsecase(nil, nil, tx))
+}
+
+func TestRevokeApproval(t *testing.T) {
+	body := map[string]any{"reason": "approved the wrong loan", "validator_employee_id": strings.Repeat("a", 32)}
+	cases := []struct {
+		name string
+		h    *ApprovalHandler
+		body any
+		want int
+	}{
+		{"approved, unfunded", newRevokeHandler(domainLoan.StateApproved, 0), body, stdhttp.StatusOK},
+		{"has investments", newRevokeHandler(domainLoan.StateApproved, 1), body, stdhttp.StatusConflict},
+		{"not approved", newRevokeHandler(domainLoan.StateInvested, 0), body, stdhttp.StatusConflict},
+		{"no reason", newRevokeHandler(domainLoan.StateApproved, 0), map[string]any{"validator_employee_id": strings.Repeat("a", 32)}, stdhttp.StatusUnprocessableEntity},
+		{"blank reason", newRevokeHandler(domainLoan.StateApproved, 0), map[string]any{"reason": " ", "validator_employee_id": strings.Repeat("a", 32)}, stdhttp.StatusUnprocessableEntity},
+	}
+	for _, c := range cases {
+		e := newEchoWithValidator()
+		req := httptest.NewRequest(stdhttp.MethodDelete, "/loans/LN-1/approval", mustJSON(c.body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetParamNames("loan_id")
+		ctx.SetParamValues("LN-1")
+		if err := c.h.RevokeApproval(ctx); err != nil {
+			t.Fatalf("%s: RevokeApproval error: %v", c.name, err)
+		}
+		if rec.Code != c.want {
+			t.Fatalf("%s: status = %d, want %d (body=%s)", c.name, rec.Code, c.want, rec.Body.String())
+		}
+		if c.want == stdhttp.StatusOK {
+			var dto ucApproval.RevocationDTO
+			if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil || dto.ApprovalID != "AP-1" || dto.LoanState != "proposed" {
+				t.Fatalf("%s: dto %+v, %v", c.name, dto, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	ucCancellation "amartha-backend-test/internal/usecase/cancellation"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CancellationHandler struct{ uc *ucCancellation.Usecase }
+
+func NewCancellationHandler(uc *ucCancellation.Usecase) *CancellationHandler {
+	return &CancellationHandler{uc: uc}
+}
+
+type cancelLoanReq struct {
+	LoanID      string `param:"loan_id"      validate:"required,max=64"`
+	CancelledBy string `json:"cancelled_by" validate:"required,hex32"`
+}
+
+// CancelLoan soft-deletes a loan that has not been disbursed and refunds its
+// held investments to the investors' wallets.
+func (h *CancellationHandler) CancelLoan(c echo.Context) error {
+	var req cancelLoanReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.Cancel(c.Request().Context(), ucCancellation.CancelInput(req))
+	if err != nil {
+		switch {
+		case errors.Is(err, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(err, domainLoan.ErrNotCancellable):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, dto)
+}
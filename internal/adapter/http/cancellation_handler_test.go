@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainInv "amartha-backend-test/internal/domain/investment"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/approvalmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	ucCancellation "amartha-backend-test/internal/usecase/cancellation"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// newCancelHandler wires the cancel flow over l; a nil l is a missing loan.
+func newCancelHandler(l *domainLoan.Loan) *CancellationHandler {
+	repos := uow.Repos{
+		Loans:     &loanmock.Repo{},
+		Approvals: &approvalmock.Repo{},
+		Investments: &investmentmock.Repo{
+			ListByLoanIDFn: func(context.Context, uint64) ([]domainInv.Investment, error) {
+				return []domainInv.Investment{{
+					InvestmentID: "IV-1", InvestorID: strings.Repeat("a", 32),
+					Amount: money.NewFromInt(250_000), Status: domainInv.StatusHeld,
+				}}, nil
+			},
+		},
+		Ledger:      &ledgermock.Repo{},
+		LoanHistory: &loanmock.HistoryRepo{},
+		Outbox:      &outboxmock.Repo{},
+	}
+	tx := uowmock.New().WithWithinLoanTx(func(ctx context.Context, loanID string, fn func(uow.Repos, *domainLoan.Loan) error) error {
+		if l == nil {
+			return gorm.ErrRecordNotFound
+		}
+		return fn(repos, l)
+	})
+	return NewCancellationHandler(ucCancellation.NewUsecase(tx))
+}
+
+func doCancel(t *testing.T, h *CancellationHandler, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/LN-1/cancel", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("loan_id")
+	c.SetParamValues("LN-1")
+	if err := h.CancelLoan(c); err != nil {
+		t.Fatalf("CancelLoan error: %v", err)
+	}
+	return rec
+}
+
+func TestCancelLoan(t *testing.T) {
+	body := map[string]any{"cancelled_by": strings.Repeat("e", 32)}
+
+	rec := doCancel(t, newCancelHandler(&domainLoan.Loan{ID: 1, LoanID: "LN-1", State: domainLoan.StateApproved}), body)
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucCancellation.CancellationDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.LoanID != "LN-1" || len(dto.Refunds) != 1 || dto.RefundedTotal.String() != "250000" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+
+	for name, c := range map[string]struct {
+		l    *domainLoan.Loan
+		body any
+		want int
+	}{
+		"disbursed": {&domainLoan.Loan{ID: 1, State: domainLoan.StateDisbursed}, body, stdhttp.StatusConflict},
+		"missing":   {nil, body, stdhttp.StatusNotFound},
+		"bad actor": {&domainLoan.Loan{ID: 1, State: domainLoan.StateProposed}, map[string]any{"cancelled_by": "me"}, stdhttp.StatusUnprocessableEntity},
+		"no actor":  {&domainLoan.Loan{ID: 1, State: domainLoan.StateProposed}, map[string]any{}, stdhttp.StatusUnprocessableEntity},
+	} {
+		if rec := doCancel(t, newCancelHandler(c.l), c.body); rec.Code != c.want {
+			t.Fatalf("%s: status = %d, want %d (body=%s)", name, rec.Code, c.want, rec.Body.String())
+		}
+	}
+}
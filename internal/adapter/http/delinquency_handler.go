@@ -0,0 +1,24 @@
+package http
+
+import (
+	"net/http"
+
+	ucDelinquency "amartha-backend-test/internal/usecase/delinquency"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DelinquencyHandler struct{ uc *ucDelinquency.Usecase }
+
+func NewDelinquencyHandler(uc *ucDelinquency.Usecase) *DelinquencyHandler {
+	return &DelinquencyHandler{uc: uc}
+}
+
+// GetTKB90 reports the portfolio TKB90 from the buckets of the last DPD run.
+func (h *DelinquencyHandler) GetTKB90(c echo.Context) error {
+	dto, err := h.uc.TKB90(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/repaymentmock"
+	ucDelinquency "amartha-backend-test/internal/usecase/delinquency"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+)
+
+func doGetTKB90(t *testing.T, schedules *repaymentmock.Repo) *httptest.ResponseRecorder {
+	t.Helper()
+	h := NewDelinquencyHandler(ucDelinquency.NewUsecase(&loanmock.Repo{}, schedules))
+	e := echo.New()
+	req := httptest.NewRequest(stdhttp.MethodGet, "/reports/tkb90", nil)
+	rec := httptest.NewRecorder()
+	if err := h.GetTKB90(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("GetTKB90 error: %v", err)
+	}
+	return rec
+}
+
+func TestGetTKB90_Success(t *testing.T) {
+	rec := doGetTKB90(t, &repaymentmock.Repo{
+		ExposureByBucketFn: func(ctx context.Context) ([]domainRepayment.BucketExposure, error) {
+			return []domainRepayment.BucketExposure{
+				{Bucket: domainLoan.DPDCurrent, Loans: 3, OutstandingPrincipal: money.NewFromInt(900)},
+				{Bucket: domainLoan.DPD90Plus, Loans: 1, OutstandingPrincipal: money.NewFromInt(100)},
+			}, nil
+		},
+	})
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucDelinquency.TKB90DTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.TKB90.String() != "90" || dto.TWP90.String() != "10" || len(dto.Buckets) != 5 {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+}
+
+func TestGetTKB90_RepoError(t *testing.T) {
+	rec := doGetTKB90(t, &repaymentmock.Repo{}) // default → context.Canceled
+	if rec.Code != stdhttp.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+}
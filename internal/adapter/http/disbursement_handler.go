@@ -0,0 +1,108 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	domainDisbursement "amartha-backend-test/internal/domain/disbursement"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainSignature "amartha-backend-test/internal/domain/signature"
+	ucDisbursement "amartha-backend-test/internal/usecase/disbursement"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DisbursementHandler struct{ uc *ucDisbursement.Usecase }
+
+func NewDisbursementHandler(uc *ucDisbursement.Usecase) *DisbursementHandler {
+	return &DisbursementHandler{uc: uc}
+}
+
+type disburseLoanReq struct {
+	SignedAgreementURL string `json:"signed_agreement_url" validate:"required,url"`
+	SignatureProvider  string `json:"signature_provider"   validate:"required,max=64"`
+	SignatureTxID      string `json:"signature_tx_id"      validate:"required,max=128"`
+	SignatureStatus    string `json:"signature_status"     validate:"required,oneof=PENDING SIGNED CANCELLED"`
+	// RFC3339 with timezone; defaults to now when omitted
+	SignedAt          string `json:"signed_at"            validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	DocumentSHA256    string `json:"document_sha256"      validate:"omitempty,len=64,hexadecimal"`
+	OfficerEmployeeID string `json:"officer_employee_id"  validate:"required,hex32"`
+	// canonical date `YYYY-MM-DD` (matches MySQL DATE)
+	DisbursementDate string `json:"disbursement_date"    validate:"required,datetime=2006-01-02"`
+}
+
+func (h *DisbursementHandler) DisburseLoan(c echo.Context) error {
+	// path param
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+
+	// bind + validate
+	var req disburseLoanReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	// parse dates
+	dd, err := time.Parse("2006-01-02", req.DisbursementDate)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: []FieldError{{Field: "DisbursementDate", Message: "must be YYYY-MM-DD"}},
+		})
+	}
+	var signedAt *time.Time
+	if req.SignedAt != "" {
+		sa, err := time.Parse(time.RFC3339, req.SignedAt)
+		if err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "SignedAt", Message: "must be RFC3339 with timezone"}},
+			})
+		}
+		signedAt = &sa
+	}
+
+	// call usecase
+	dto, uerr := h.uc.Disburse(
+		c.Request().Context(),
+		ucDisbursement.DisburseInput{
+			LoanID:             loanID,
+			SignedAgreementURL: req.SignedAgreementURL,
+			SignatureProvider:  req.SignatureProvider,
+			SignatureTxID:      req.SignatureTxID,
+			SignatureStatus:    req.SignatureStatus,
+			SignedAt:           signedAt,
+			DocumentSHA256:     req.DocumentSHA256,
+			OfficerEmployeeID:  req.OfficerEmployeeID,
+			DisbursementDate:   dd,
+		},
+	)
+	if uerr != nil {
+		switch {
+		case errors.Is(uerr, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(uerr, domainDisbursement.ErrAgreementNotSigned),
+			errors.Is(uerr, domainSignature.ErrEnvelopeMismatch),
+			errors.Is(uerr, domainLoan.ErrInvalidTenor):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: uerr.Error()})
+		case errors.Is(uerr, domainLoan.ErrAlreadyDisbursed):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan already disbursed"})
+		case errors.Is(uerr, domainLoan.ErrInvalidTransition):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan not in a state that can be disbursed"})
+		default:
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Error: uerr.Error()})
+		}
+	}
+
+	// success
+	return c.JSON(http.StatusOK, dto)
+}
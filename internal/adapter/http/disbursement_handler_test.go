@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domainDisbursement "amartha-backend-test/internal/domain/disbursement"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainSignature "amartha-backend-test/internal/domain/signature"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/disbursementmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/repaymentmock"
+	"amartha-backend-test/internal/testutil/signaturemock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	ucDisbursement "amartha-backend-test/internal/usecase/disbursement"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// newDisburseHandler wires a handler whose locked loan is l (nil → not found)
+// and whose only tracked signature envelope is env (default: l's, SIGNED).
+func newDisburseHandler(l *domainLoan.Loan, env ...*domainSignature.Envelope) *DisbursementHandler {
+	loans := &loanmock.Repo{SaveFn: func(ctx context.Context, l *domainLoan.Loan) error { return nil }}
+	disbs := &disbursementmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, id uint64) (*domainDisbursement.Disbursement, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		CreateFn: func(ctx context.Context, d *domainDisbursement.Disbursement) error { return nil },
+	}
+	if len(env) == 0 && l != nil {
+		signedAt := time.Date(2025, 9, 9, 3, 0, 0, 0, time.UTC)
+		env = append(env, &domainSignature.Envelope{LoanID: l.ID, SignatureProvider: "fake", SignatureTxID: "TX-1",
+			SignatureStatus: domainSignature.StatusSigned, SignedAt: &signedAt})
+	}
+	sigs := &signaturemock.Repo{
+		GetLatestByLoanIDFn: func(ctx context.Context, loanID uint64) (*domainSignature.Envelope, error) {
+			if len(env) == 0 || env[0].LoanID != loanID {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return env[0], nil
+		},
+	}
+	tx := &uowmock.UoW{
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *domainLoan.Loan) error) error {
+			if l == nil {
+				return gorm.ErrRecordNotFound
+			}
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Disbursements: disbs, Schedules: &repaymentmock.Repo{}, Ledger: &ledgermock.Repo{}, Investments: &investmentmock.Repo{}, Signatures: sigs, Outbox: &outboxmock.Repo{}}, l)
+		},
+	}
+	return NewDisbursementHandler(ucDisbursement.NewUsecase(disbs, tx))
+}
+
+// investedLoan is a fully funded 4-week loan ready for disbursement.
+func investedLoan(loanID string) *domainLoan.Loan {
+	return &domainLoan.Loan{
+		ID: 7, LoanID: loanID, State: domainLoan.StateInvested,
+		Principal: money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"),
+		Tenor: 4, TenorUnit: domainLoan.TenorWeek,
+	}
+}
+
+func validDisburseBody() map[string]any {
+	return map[string]any{
+		"signed_agreement_url": "https://docs.example.com/signed.pdf",
+		"signature_provider":   "fake",
+		"signature_tx_id":      "TX-1",
+		"signature_status":     "SIGNED",
+		"signed_at":            "2025-09-09T10:00:00+07:00",
+		"document_sha256":      strings.Repeat("f", 64),
+		"officer_employee_id":  strings.Repeat("a", 32),
+		"disbursement_date":    "2025-09-10",
+	}
+}
+
+func doDisburse(t *testing.T, h *DisbursementHandler, loanID string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/"+loanID+"/disburse", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if loanID != "" {
+		c.SetParamNames("loan_id")
+		c.SetParamValues(loanID)
+	}
+	if err := h.DisburseLoan(c); err != nil {
+		t.Fatalf("DisburseLoan error: %v", err)
+	}
+	return rec
+}
+
+func TestDisburseLoan_Success(t *testing.T) {
+	l := investedLoan(strings.Repeat("l", 32))
+	rec := doDisburse(t, newDisburseHandler(l), l.LoanID, validDisburseBody())
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucDisbursement.DisbursementDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.LoanID != l.LoanID || dto.SignatureStatus != "SIGNED" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+	if dto.SignedAt.Hour() != 3 { // the envelope's signed_at
+		t.Fatalf("signed_at not taken from the envelope: %v", dto.SignedAt)
+	}
+}
+
+func TestDisburseLoan_MissingPathParam(t *testing.T) {
+	rec := doDisburse(t, newDisburseHandler(nil), "", validDisburseBody())
+	if rec.Code != stdhttp.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestDisburseLoan_ValidationError(t *testing.T) {
+	body := validDisburseBody()
+	body["signed_agreement_url"] = "not-a-url"
+	body["signature_status"] = "DONE"
+	body["document_sha256"] = "xyz"
+	rec := doDisburse(t, newDisburseHandler(nil), "abcd", body)
+	if rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", rec.Code)
+	}
+	var er ErrorResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &er)
+	if len(er.Details) < 3 {
+		t.Fatalf("expected field errors, got %+v", er.Details)
+	}
+}
+
+func TestDisburseLoan_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		loan     *domainLoan.Loan
+		envelope *domainSignature.Envelope
+		status   string
+		wantCode int
+	}{
+		{name: "not found", loan: nil, status: "SIGNED", wantCode: stdhttp.StatusNotFound},
+		{name: "not signed", loan: &domainLoan.Loan{ID: 1, State: domainLoan.StateInvested}, status: "PENDING", wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "already disbursed", loan: &domainLoan.Loan{ID: 1, State: domainLoan.StateDisbursed}, status: "SIGNED", wantCode: stdhttp.StatusConflict},
+		{name: "wrong state", loan: &domainLoan.Loan{ID: 1, State: domainLoan.StateApproved}, status: "SIGNED", wantCode: stdhttp.StatusConflict},
+		{name: "envelope pending", loan: investedLoan("LN-1"), envelope: &domainSignature.Envelope{LoanID: 7, SignatureProvider: "fake", SignatureTxID: "TX-1", SignatureStatus: domainSignature.StatusPending}, status: "SIGNED", wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "envelope for another transaction", loan: investedLoan("LN-1"), envelope: &domainSignature.Envelope{LoanID: 7, SignatureProvider: "fake", SignatureTxID: "TX-9", SignatureStatus: domainSignature.StatusSigned}, status: "SIGNED", wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "no envelope", loan: investedLoan("LN-1"), envelope: &domainSignature.Envelope{LoanID: 8, SignatureStatus: domainSignature.StatusSigned}, status: "SIGNED", wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "no tenor", loan: &domainLoan.Loan{ID: 1, State: domainLoan.StateInvested}, status: "SIGNED", wantCode: stdhttp.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := validDisburseBody()
+			body["signature_status"] = tt.status
+			h := newDisburseHandler(tt.loan)
+			if tt.envelope != nil {
+				h = newDisburseHandler(tt.loan, tt.envelope)
+			}
+			rec := doDisburse(t, h, "LN-1", body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body=%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	ucHistory "amartha-backend-test/internal/usecase/loanhistory"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HistoryHandler struct{ uc *ucHistory.Usecase }
+
+func NewHistoryHandler(uc *ucHistory.Usecase) *HistoryHandler { return &HistoryHandler{uc: uc} }
+
+func (h *HistoryHandler) GetLoanHistory(c echo.Context) error {
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+	dto, err := h.uc.Get(c.Request().Context(), loanID)
+	if err != nil {
+		if errors.Is(err, domainLoan.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
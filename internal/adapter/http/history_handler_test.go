@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/testutil/loanmock"
+	ucHistory "amartha-backend-test/internal/usecase/loanhistory"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func doGetHistory(t *testing.T, h *HistoryHandler, loanID string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(stdhttp.MethodGet, "/loans/"+loanID+"/history", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if loanID != "" {
+		c.SetParamNames("loan_id")
+		c.SetParamValues(loanID)
+	}
+	if err := h.GetLoanHistory(c); err != nil {
+		t.Fatalf("GetLoanHistory error: %v", err)
+	}
+	return rec
+}
+
+func TestGetLoanHistory_Success(t *testing.T) {
+	loans := &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			return &domainLoan.Loan{ID: 1, LoanID: loanID, State: domainLoan.StateInvested}, nil
+		},
+	}
+	history := &loanmock.HistoryRepo{
+		ListByLoanIDFn: func(ctx context.Context, id uint64) ([]domainLoan.StateTransition, error) {
+			now := time.Now().UTC()
+			return []domainLoan.StateTransition{
+				{FromState: domainLoan.StateProposed, ToState: domainLoan.StateApproved, Actor: "EMP-1", TransitionedAt: now.Add(-time.Hour)},
+				{FromState: domainLoan.StateApproved, ToState: domainLoan.StateInvested, Actor: "INV-1", TransitionedAt: now},
+			}, nil
+		},
+	}
+	rec := doGetHistory(t, NewHistoryHandler(ucHistory.NewUsecase(loans, history)), "LN-1")
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var dto ucHistory.HistoryDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.CurrentState != "invested" || len(dto.Transitions) != 2 || dto.Transitions[1].Actor != "INV-1" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+}
+
+func TestGetLoanHistory_NotFound(t *testing.T) {
+	loans := &loanmock.Repo{
+		GetByLoanIDFn: func(context.Context, string) (*domainLoan.Loan, error) { return nil, gorm.ErrRecordNotFound },
+	}
+	rec := doGetHistory(t, NewHistoryHandler(ucHistory.NewUsecase(loans, &loanmock.HistoryRepo{})), "LN-404")
+	if rec.Code != stdhttp.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestGetLoanHistory_MissingPathParam(t *testing.T) {
+	rec := doGetHistory(t, NewHistoryHandler(ucHistory.NewUsecase(nil, nil)), "")
+	if rec.Code != stdhttp.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestGetLoanHistory_RepoError(t *testing.T) {
+	loans := &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			return &domainLoan.Loan{ID: 1, LoanID: loanID}, nil
+		},
+	}
+	rec := doGetHistory(t, NewHistoryHandler(ucHistory.NewUsecase(loans, &loanmock.HistoryRepo{})), "LN-1")
+	if rec.Code != stdhttp.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainWallet "amartha-backend-test/internal/domain/wallet"
+	ucInvestment "amartha-backend-test/internal/usecase/investment"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InvestmentHandler struct{ uc *ucInvestment.Usecase }
+
+func NewInvestmentHandler(uc *ucInvestment.Usecase) *InvestmentHandler {
+	return &InvestmentHandler{uc: uc}
+}
+
+type investLoanReq struct {
+	InvestorID string `json:"investor_id" validate:"required,hex32"`
+	// where the agreement link is sent once the loan is fully invested
+	InvestorEmail string `json:"investor_email" validate:"omitempty,email,max=254"`
+	// amount: positive with max 2 decimals (matches decimal(18,2))
+	Amount money.Decimal `json:"amount"      validate:"required,dec2,gt=0"`
+}
+
+func (h *InvestmentHandler) InvestLoan(c echo.Context) error {
+	// path param
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+
+	// bind + validate
+	var req investLoanReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	// call usecase
+	dto, uerr := h.uc.Invest(
+		c.Request().Context(),
+		ucInvestment.InvestInput{
+			LoanID:        loanID,
+			InvestorID:    req.InvestorID,
+			InvestorEmail: req.InvestorEmail,
+			Amount:        req.Amount,
+		},
+	)
+	if uerr != nil {
+		switch {
+		case errors.Is(uerr, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(uerr, domainLoan.ErrInvalidTransition):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan not in a state that can be invested"})
+		case errors.Is(uerr, domainInvestment.ErrExceedsPrincipal):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: uerr.Error()})
+		case errors.Is(uerr, domainWallet.ErrInsufficientBalance):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: uerr.Error()})
+		case errors.Is(uerr, domainInvestment.ErrInvalidAmount):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: uerr.Error()})
+		default:
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Error: uerr.Error()})
+		}
+	}
+
+	// success
+	return c.JSON(http.StatusCreated, dto)
+}
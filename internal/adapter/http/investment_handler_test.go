@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	ucInvestment "amartha-backend-test/internal/usecase/investment"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// newInvestHandler wires a handler whose locked loan is l, whose escrow (invested total) is sum
+// and whose investor has wallet available.
+func newInvestHandler(l *domainLoan.Loan, sum, wallet money.Decimal) *InvestmentHandler {
+	loans := &loanmock.Repo{SaveFn: func(ctx context.Context, l *domainLoan.Loan) error { return nil }}
+	invs := &investmentmock.Repo{
+		CreateFn: func(ctx context.Context, i *domainInvestment.Investment) error { return nil },
+	}
+	books := &ledgermock.Repo{
+		BalanceFn: func(ctx context.Context, a domainLedger.Account) (money.Decimal, error) {
+			if a.Type == domainLedger.AccountInvestorWallet {
+				return wallet, nil
+			}
+			return sum, nil
+		},
+	}
+	tx := &uowmock.UoW{
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *domainLoan.Loan) error) error {
+			if l == nil {
+				return gorm.ErrRecordNotFound
+			}
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Investments: invs, Ledger: books, Outbox: &outboxmock.Repo{}}, l)
+		},
+	}
+	return NewInvestmentHandler(ucInvestment.NewUsecase(invs, tx))
+}
+
+func doInvest(t *testing.T, h *InvestmentHandler, loanID string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/"+loanID+"/investments", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if loanID != "" {
+		c.SetParamNames("loan_id")
+		c.SetParamValues(loanID)
+	}
+	if err := h.InvestLoan(c); err != nil {
+		t.Fatalf("InvestLoan error: %v", err)
+	}
+	return rec
+}
+
+func TestInvestLoan_Success_FullyFunded(t *testing.T) {
+	l := &domainLoan.Loan{ID: 7, LoanID: strings.Repeat("l", 32), Principal: money.NewFromInt(5_000_000), State: domainLoan.StateApproved}
+	h := newInvestHandler(l, money.NewFromInt(4_000_000), money.NewFromInt(1_000_000))
+
+	rec := doInvest(t, h, l.LoanID, map[string]any{
+		"investor_id": strings.Repeat("a", 32),
+		"amount":      1_000_000,
+	})
+	if rec.Code != stdhttp.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucInvestment.InvestmentDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.LoanState != string(domainLoan.StateInvested) || !dto.TotalInvested.Equal(money.NewFromInt(5_000_000)) {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+	// amounts go over the wire as exact decimal strings
+	if !strings.Contains(rec.Body.String(), `"total_invested":"5000000"`) {
+		t.Fatalf("total_invested not a decimal string: %s", rec.Body.String())
+	}
+}
+
+func TestInvestLoan_MissingPathParam(t *testing.T) {
+	h := NewInvestmentHandler(ucInvestment.NewUsecase(nil, nil))
+	rec := doInvest(t, h, "", map[string]any{})
+	if rec.Code != stdhttp.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestInvestLoan_BindError(t *testing.T) {
+	e := newEchoWithValidator()
+	h := NewInvestmentHandler(ucInvestment.NewUsecase(nil, nil))
+
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/abcd/investments", strings.NewReader(`{"amount":`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("loan_id")
+	c.SetParamValues("abcd")
+
+	if err := h.InvestLoan(c); err != nil {
+		t.Fatalf("InvestLoan error: %v", err)
+	}
+	if rec.Code != stdhttp.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestInvestLoan_ValidationError(t *testing.T) {
+	h := NewInvestmentHandler(ucInvestment.NewUsecase(nil, nil))
+	rec := doInvest(t, h, "abcd", map[string]any{
+		"investor_id":    "NOTHEX",
+		"investor_email": "investor-at-example",
+		"amount":         10.555,
+	})
+	if rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", rec.Code)
+	}
+	var er ErrorResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &er)
+	if !hasFieldDetail(er.Details, "InvestorID", "32") || !hasFieldDetail(er.Details, "Amount", "2 decimal") ||
+		!hasFieldDetail(er.Details, "InvestorEmail", "email") {
+		t.Fatalf("missing expected field errors: %+v", er.Details)
+	}
+}
+
+func TestInvestLoan_ErrorMapping(t *testing.T) {
+	approved := func() *domainLoan.Loan {
+		return &domainLoan.Loan{ID: 1, LoanID: "LN-1", Principal: money.NewFromInt(5_000_000), State: domainLoan.StateApproved}
+	}
+	tests := []struct {
+		name     string
+		loan     *domainLoan.Loan
+		sum      money.Decimal
+		wallet   money.Decimal
+		wantCode int
+	}{
+		{name: "not found", loan: nil, wantCode: stdhttp.StatusNotFound},
+		{name: "wrong state", loan: &domainLoan.Loan{ID: 1, State: domainLoan.StateProposed}, wantCode: stdhttp.StatusConflict},
+		{name: "exceeds principal", loan: approved(), sum: money.NewFromInt(4_500_000), wantCode: stdhttp.StatusConflict},
+		{name: "insufficient wallet", loan: approved(), wallet: money.MustParse("999999.99"), wantCode: stdhttp.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := tt.wallet
+			if wallet.IsZero() {
+				wallet = money.NewFromInt(10_000_000)
+			}
+			h := newInvestHandler(tt.loan, tt.sum, wallet)
+			rec := doInvest(t, h, "LN-1", map[string]any{
+				"investor_id": strings.Repeat("a", 32),
+				"amount":      1_000_000,
+			})
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body=%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	ucLedger "amartha-backend-test/internal/usecase/ledger"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LedgerHandler struct{ uc *ucLedger.Usecase }
+
+func NewLedgerHandler(uc *ucLedger.Usecase) *LedgerHandler { return &LedgerHandler{uc: uc} }
+
+type balanceReq struct {
+	Account string `query:"account"  validate:"required,oneof=investor_wallet escrow borrower_receivable cash platform_fee"`
+	OwnerID string `query:"owner_id" validate:"omitempty,hex32"`
+}
+
+// GetBalance reports one ledger account's balance, e.g.
+// ?account=escrow&owner_id=<loan_id> or ?account=platform_fee.
+func (h *LedgerHandler) GetBalance(c echo.Context) error {
+	var req balanceReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid query"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.Balance(c.Request().Context(), ucLedger.BalanceInput(req))
+	if err != nil {
+		if errors.Is(err, domainLedger.ErrInvalidAccount) {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: "owner_id is required for investor_wallet, escrow and borrower_receivable, and not allowed otherwise"})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
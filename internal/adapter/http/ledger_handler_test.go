@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	ucLedger "amartha-backend-test/internal/usecase/ledger"
+	"amartha-backend-test/pkg/money"
+)
+
+func doGetBalance(t *testing.T, books *ledgermock.Repo, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	h := NewLedgerHandler(ucLedger.NewUsecase(books))
+	req := httptest.NewRequest(stdhttp.MethodGet, "/ledger/balance"+query, nil)
+	rec := httptest.NewRecorder()
+	if err := h.GetBalance(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("GetBalance error: %v", err)
+	}
+	return rec
+}
+
+func TestGetBalance_Success(t *testing.T) {
+	loanID := strings.Repeat("a", 32)
+	books := &ledgermock.Repo{
+		BalanceFn: func(ctx context.Context, a domainLedger.Account) (money.Decimal, error) {
+			if a != domainLedger.Escrow(loanID) {
+				t.Fatalf("account mismatch: %+v", a)
+			}
+			return money.MustParse("2500000.50"), nil
+		},
+	}
+	rec := doGetBalance(t, books, "?account=escrow&owner_id="+loanID)
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucLedger.BalanceDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.Account != "escrow" || dto.OwnerID != loanID || dto.Balance.String() != "2500000.5" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+}
+
+func TestGetBalance_Validation(t *testing.T) {
+	books := &ledgermock.Repo{
+		BalanceFn: func(context.Context, domainLedger.Account) (money.Decimal, error) { return money.Zero, nil },
+	}
+	owner := strings.Repeat("b", 32)
+	for query, want := range map[string]int{
+		"":                                stdhttp.StatusUnprocessableEntity, // account required
+		"?account=bank":                   stdhttp.StatusUnprocessableEntity,
+		"?account=escrow&owner_id=NOPE":   stdhttp.StatusUnprocessableEntity,
+		"?account=escrow":                 stdhttp.StatusUnprocessableEntity, // owner missing
+		"?account=cash&owner_id=" + owner: stdhttp.StatusUnprocessableEntity, // platform account with owner
+		"?account=platform_fee":           stdhttp.StatusOK,
+	} {
+		if rec := doGetBalance(t, books, query); rec.Code != want {
+			t.Fatalf("%q: status = %d, want %d (body=%s)", query, rec.Code, want, rec.Body.String())
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package http
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
+	domainLoan "amartha-backend-test/internal/domain/loan"
 	"amartha-backend-test/internal/usecase/loan"
+	"amartha-backend-test/pkg/money"
 
 	"github.com/labstack/echo/v4"
 )
@@ -15,11 +19,16 @@ func NewLoanHandler(uc *loan.Usecase) *LoanHandler { return &LoanHandler{uc: uc}
 type createLoanReq struct {
 	BorrowerID string `json:"borrower_id" validate:"required,hex32"`
 	// principal: integer in [5000000 .. 100000000]
-	Principal float64 `json:"principal"  validate:"required,intlike,gte=5000000,lte=100000000"`
+	Principal money.Decimal `json:"principal"  validate:"required,intlike,gte=5000000,lte=100000000"`
 	// rate: [1.29 .. 2.99] with max 2 decimals
-	Rate float64 `json:"rate"       validate:"required,dec2,gte=1.29,lte=2.99"`
+	Rate money.Decimal `json:"rate"       validate:"required,dec2,gte=1.29,lte=2.99"`
 	// roi: [0.90 .. 1.29] with max 2 decimals
-	ROI float64 `json:"roi"        validate:"required,dec2,gte=0.90,lte=1.29"`
+	ROI money.Decimal `json:"roi"        validate:"required,dec2,gte=0.90,lte=1.29"`
+	// tenor: number of installments, weekly or monthly
+	Tenor     uint16 `json:"tenor"           validate:"required,gte=1,lte=260"`
+	TenorUnit string `json:"tenor_unit"      validate:"required,oneof=week month"`
+	// interest_method: flat (default) or effective (declining balance)
+	InterestMethod string `json:"interest_method" validate:"omitempty,oneof=flat effective"`
 }
 
 func (h *LoanHandler) CreateLoan(c echo.Context) error {
@@ -37,16 +46,103 @@ func (h *LoanHandler) CreateLoan(c echo.Context) error {
 
 	dto, err := h.uc.Create(c.Request().Context(), loan.CreateLoanInput(req))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		switch {
+		case errors.Is(err, domainLoan.ErrPendingLoan),
+			errors.Is(err, domainLoan.ErrReapplyCooldown):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, domainLoan.ErrInvalidLoan),
+			errors.Is(err, domainLoan.ErrInvalidTenor):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 	}
 	return c.JSON(http.StatusCreated, dto)
 }
 
+// GetLoan returns the loan; ?expand=approval,investments,disbursement adds
+// those sub-objects, read with the loan in one query.
 func (h *LoanHandler) GetLoan(c echo.Context) error {
 	loanID := c.Param("loan_id")
-	dto, err := h.uc.Get(c.Request().Context(), loanID)
+	x, err := loan.ParseExpand(c.QueryParam("expand"))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+	}
+	dto, err := h.uc.GetDetail(c.Request().Context(), loanID, x)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
 	}
 	return c.JSON(http.StatusOK, dto)
 }
+
+type listLoansReq struct {
+	State      string `query:"state"       validate:"omitempty,oneof=proposed rejected approved invested disbursed repaid cancelled"`
+	BorrowerID string `query:"borrower_id" validate:"omitempty,hex32"`
+	DPDBucket  string `query:"dpd_bucket"  validate:"omitempty,oneof=current 1-30 31-60 61-90 90+"`
+	// principal bounds, inclusive
+	PrincipalMin money.Decimal `query:"principal_min" validate:"omitempty,dec2,gt=0"`
+	PrincipalMax money.Decimal `query:"principal_max" validate:"omitempty,dec2,gt=0"`
+	// RFC3339 timestamps; from is inclusive, to is exclusive
+	CreatedFrom      time.Time `query:"created_from"`
+	CreatedTo        time.Time `query:"created_to"`
+	StateUpdatedFrom time.Time `query:"state_updated_from"`
+	StateUpdatedTo   time.Time `query:"state_updated_to"`
+	Sort             string    `query:"sort"   validate:"omitempty,oneof=created_at -created_at state_updated_at -state_updated_at principal -principal"`
+	Cursor           string    `query:"cursor" validate:"omitempty,max=512"`
+	Limit            int       `query:"limit"  validate:"omitempty,gte=1,lte=100"`
+}
+
+func (h *LoanHandler) ListLoans(c echo.Context) error {
+	var req listLoansReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid query"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.List(c.Request().Context(), loan.ListInput(req))
+	if err != nil {
+		switch {
+		case errors.Is(err, domainLoan.ErrInvalidDPDBucket),
+			errors.Is(err, domainLoan.ErrInvalidRange),
+			errors.Is(err, domainLoan.ErrInvalidCursor):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
+
+type borrowerLoansReq struct {
+	BorrowerID string `param:"borrower_id" validate:"required,hex32"`
+	Cursor     string `query:"cursor"      validate:"omitempty,max=512"`
+	Limit      int    `query:"limit"       validate:"omitempty,gte=1,lte=100"`
+}
+
+// ListBorrowerLoans is the borrower's own loan history, newest first, with
+// approval, funding and disbursement. The route is guarded by
+// BorrowerScopeMiddleware, so :borrower_id is always the caller.
+func (h *LoanHandler) ListBorrowerLoans(c echo.Context) error {
+	var req borrowerLoansReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid query"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.ListByBorrower(c.Request().Context(), loan.BorrowerLoansInput(req))
+	if err != nil {
+		if errors.Is(err, domainLoan.ErrInvalidCursor) {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
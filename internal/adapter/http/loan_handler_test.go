@@ -11,9 +11,16 @@ import (
 	"testing"
 	"time"
 
+	domainApproval "amartha-backend-test/internal/domain/approval"
+	domainDisb "amartha-backend-test/internal/domain/disbursement"
+	domainInv "amartha-backend-test/internal/domain/investment"
 	domain "amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/testutil/approvalmock"
+	"amartha-backend-test/internal/testutil/disbursementmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
 	loanmock "amartha-backend-test/internal/testutil/loanmock"
 	uc "amartha-backend-test/internal/usecase/loan"
+	"amartha-backend-test/pkg/money"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
@@ -57,6 +64,8 @@ func TestCreateLoan_Success(t *testing.T) {
 		"principal":   5000000,
 		"rate":        1.29,
 		"roi":         0.90,
+		"tenor":       25,
+		"tenor_unit":  "week",
 	}
 	req := httptest.NewRequest(stdhttp.MethodPost, "/loans", mustJSON(reqBody))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -73,7 +82,7 @@ func TestCreateLoan_Success(t *testing.T) {
 	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
 		t.Fatalf("bad json: %v", err)
 	}
-	if got.BorrowerID != strings.Repeat("b", 32) || got.Principal != 5000000 {
+	if got.BorrowerID != strings.Repeat("b", 32) || !got.Principal.Equal(money.NewFromInt(5000000)) {
 		t.Fatalf("unexpected dto: %+v", got)
 	}
 	if got.State != string(domain.StateProposed) {
@@ -115,6 +124,8 @@ func TestCreateLoan_ValidationError(t *testing.T) {
 		"principal":   5000000.01,
 		"rate":        1.234,
 		"roi":         0.89,
+		"tenor":       0,
+		"tenor_unit":  "day",
 	}
 	req := httptest.NewRequest(stdhttp.MethodPost, "/loans", mustJSON(reqBody))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -143,6 +154,12 @@ func TestCreateLoan_ValidationError(t *testing.T) {
 	if !containsFieldMsg(er.Details, "Rate", "at most 2 decimal places") {
 		t.Fatalf("missing dec2 detail for rate: %+v", er.Details)
 	}
+	if !containsFieldMsg(er.Details, "Tenor", "is required") {
+		t.Fatalf("missing required detail for tenor: %+v", er.Details)
+	}
+	if !containsFieldMsg(er.Details, "TenorUnit", "one of: week month") {
+		t.Fatalf("missing oneof detail for tenor_unit: %+v", er.Details)
+	}
 }
 
 func TestCreateLoan_PendingLoanConflict(t *testing.T) {
@@ -168,6 +185,8 @@ func TestCreateLoan_PendingLoanConflict(t *testing.T) {
 		"principal":   5000000,
 		"rate":        1.29,
 		"roi":         0.90,
+		"tenor":       25,
+		"tenor_unit":  "week",
 	}
 	req := httptest.NewRequest(stdhttp.MethodPost, "/loans", mustJSON(reqBody))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -177,8 +196,62 @@ func TestCreateLoan_PendingLoanConflict(t *testing.T) {
 	if err := h.CreateLoan(c); err != nil {
 		t.Fatalf("CreateLoan error: %v", err)
 	}
-	if rec.Code != stdhttp.StatusBadRequest { // handler maps usecase err to 400
-		t.Fatalf("status = %d, want 400", rec.Code)
+	if rec.Code != stdhttp.StatusConflict {
+		t.Fatalf("status = %d, want 409", rec.Code)
+	}
+}
+
+func TestCreateLoan_UsecaseErrors(t *testing.T) {
+	cases := map[string]struct {
+		repo *loanmock.Repo
+		want int
+	}{
+		"reapply cooldown": {
+			repo: &loanmock.Repo{
+				GetPendingLoanByBorrowerIDFn: func(ctx context.Context, borrowerID string) (*domain.Loan, error) {
+					return nil, gorm.ErrRecordNotFound
+				},
+				GetLatestRejectedLoanByBorrowerIDFn: func(ctx context.Context, borrowerID string) (*domain.Loan, error) {
+					return &domain.Loan{State: domain.StateRejected, StateUpdatedAt: time.Now().UTC()}, nil
+				},
+			},
+			want: stdhttp.StatusConflict,
+		},
+		"database failure": {
+			repo: &loanmock.Repo{
+				GetPendingLoanByBorrowerIDFn: func(ctx context.Context, borrowerID string) (*domain.Loan, error) {
+					return nil, errors.New("connection reset")
+				},
+			},
+			want: stdhttp.StatusInternalServerError,
+		},
+	}
+	for name, tc := range cases {
+		e := newEchoWithValidator()
+		h := NewLoanHandler(uc.NewUsecase(tc.repo).WithRejectionCooldown(24 * time.Hour))
+
+		reqBody := map[string]any{
+			"borrower_id": strings.Repeat("b", 32),
+			"principal":   5000000,
+			"rate":        1.29,
+			"roi":         0.90,
+			"tenor":       25,
+			"tenor_unit":  "week",
+		}
+		req := httptest.NewRequest(stdhttp.MethodPost, "/loans", mustJSON(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		if err := h.CreateLoan(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("%s: CreateLoan error: %v", name, err)
+		}
+		if rec.Code != tc.want {
+			t.Fatalf("%s: status = %d, want %d", name, rec.Code, tc.want)
+		}
+		var er ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &er); err != nil || er.Error == "" {
+			t.Fatalf("%s: body %s", name, rec.Body.String())
+		}
 	}
 }
 
@@ -193,9 +266,9 @@ func TestGetLoan_Success(t *testing.T) {
 			return &domain.Loan{
 				LoanID:     loanID,
 				BorrowerID: strings.Repeat("b", 32),
-				Principal:  7000000,
-				Rate:       1.5,
-				ROI:        1.0,
+				Principal:  money.NewFromInt(7000000),
+				Rate:       money.MustParse("1.5"),
+				ROI:        money.MustParse("1.0"),
 				State:      domain.StateProposed,
 				CreatedAt:  time.Now().UTC(),
 			}, nil
@@ -253,3 +326,230 @@ func TestGetLoan_NotFound(t *testing.T) {
 		t.Fatalf("error = %q, want %q", m["error"], "not found")
 	}
 }
+
+func TestGetLoan_Expand(t *testing.T) {
+	approvalID := strings.Repeat("a", 32)
+	repo := &loanmock.Repo{
+		GetDetailByLoanIDFn: func(ctx context.Context, loanID string) (*domain.Detail, error) {
+			return &domain.Detail{
+				Loan:        domain.Loan{LoanID: loanID, Principal: money.NewFromInt(4_000_000), State: domain.StateApproved},
+				Invested:    money.NewFromInt(1_000_000),
+				Investments: 2,
+				Investors:   2,
+				ApprovalID:  &approvalID,
+			}, nil
+		},
+	}
+	h := NewLoanHandler(uc.NewUsecase(repo))
+	do := func(query string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(stdhttp.MethodGet, "/loans/LN-1"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("loan_id")
+		c.SetParamValues("LN-1")
+		if err := h.GetLoan(c); err != nil {
+			t.Fatalf("GetLoan error: %v", err)
+		}
+		return rec
+	}
+
+	rec := do("?expand=approval,investments,disbursement")
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto uc.LoanDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.Approval == nil || dto.Approval.ApprovalID != approvalID || dto.Disbursement != nil {
+		t.Fatalf("sub-objects: %+v", dto)
+	}
+	if f := dto.Funding; f == nil || f.RemainingAmount.String() != "3000000" || f.FundingPercentage.String() != "25" || f.InvestorCount != 2 {
+		t.Fatalf("funding: %+v", dto.Funding)
+	}
+
+	rec = do("?expand=investments")
+	dto = uc.LoanDTO{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &dto)
+	if dto.Funding == nil || dto.Approval != nil {
+		t.Fatalf("only investments expanded: %+v", dto)
+	}
+
+	if rec := do("?expand=investors"); rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("unknown expand: status = %d, want 422", rec.Code)
+	}
+}
+
+func doListLoans(t *testing.T, repo *loanmock.Repo, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	h := NewLoanHandler(uc.NewUsecase(repo))
+	req := httptest.NewRequest(stdhttp.MethodGet, "/loans"+query, nil)
+	rec := httptest.NewRecorder()
+	if err := h.ListLoans(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("ListLoans error: %v", err)
+	}
+	return rec
+}
+
+func TestListLoans_ByDPDBucket(t *testing.T) {
+	var got domain.SearchFilter
+	repo := &loanmock.Repo{
+		SearchFn: func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			got = f
+			return []domain.Loan{{LoanID: "LN-1", State: domain.StateDisbursed, DPD: 12, DPDBucket: domain.DPD1To30}}, nil
+		},
+	}
+	rec := doListLoans(t, repo, "?dpd_bucket=1-30&limit=20")
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	if got.DPDBucket != domain.DPD1To30 || got.State != domain.StateDisbursed || got.Limit != 21 {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	var out uc.LoanListDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if len(out.Items) != 1 || out.Items[0].DPDBucket != "1-30" || *out.Items[0].DPD != 12 {
+		t.Fatalf("unexpected items: %+v", out.Items)
+	}
+}
+
+func TestListLoans_FiltersAndCursor(t *testing.T) {
+	borrower := strings.Repeat("b", 32)
+	var got domain.SearchFilter
+	repo := &loanmock.Repo{
+		SearchFn: func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			got = f
+			// one more row than asked for: there is a next page
+			return []domain.Loan{
+				{ID: 3, LoanID: "LN-3", BorrowerID: borrower, Principal: money.NewFromInt(9_000_000)},
+				{ID: 2, LoanID: "LN-2", BorrowerID: borrower, Principal: money.NewFromInt(8_000_000)},
+			}, nil
+		},
+	}
+	rec := doListLoans(t, repo, "?state=approved&borrower_id="+borrower+
+		"&principal_min=5000000&principal_max=10000000"+
+		"&created_from=2025-10-01T00:00:00Z&state_updated_to=2025-11-01T00:00:00%2B07:00"+
+		"&sort=-principal&limit=1")
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	wantTo := time.Date(2025, 10, 31, 17, 0, 0, 0, time.UTC)
+	if got.State != domain.StateApproved || got.BorrowerID != borrower || got.Sort != domain.SortPrincipal || !got.Desc ||
+		got.PrincipalMin.String() != "5000000" || got.PrincipalMax.String() != "10000000" ||
+		!got.CreatedFrom.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) || !got.StateUpdatedTo.Equal(wantTo) || got.Limit != 2 {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	var out uc.LoanListDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if len(out.Items) != 1 || out.Items[0].LoanID != "LN-3" || out.NextCursor == "" {
+		t.Fatalf("unexpected page: %+v", out)
+	}
+
+	rec = doListLoans(t, repo, "?sort=-principal&limit=1&cursor="+out.NextCursor)
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("next page: status = %d (body=%s)", rec.Code, rec.Body.String())
+	}
+	if got.After == nil || got.After.ID != 3 || got.After.Principal.String() != "9000000" {
+		t.Fatalf("cursor not passed through: %+v", got.After)
+	}
+	// the same cursor under another sort is refused
+	if rec := doListLoans(t, repo, "?sort=principal&cursor="+out.NextCursor); rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("foreign cursor: status = %d, want 422", rec.Code)
+	}
+
+	// cancelled is a state like any other
+	if rec := doListLoans(t, repo, "?state=cancelled"); rec.Code != stdhttp.StatusOK || got.State != domain.StateCancelled {
+		t.Fatalf("cancelled: status = %d, filter %+v", rec.Code, got)
+	}
+}
+
+func TestListLoans_Errors(t *testing.T) {
+	// "90+" must be URL-encoded; a bare "+" decodes to a space and is rejected
+	rec := doListLoans(t, &loanmock.Repo{}, "?dpd_bucket=90+")
+	if rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("bad bucket: status = %d, want 422", rec.Code)
+	}
+	rec = doListLoans(t, &loanmock.Repo{}, "?limit=500")
+	if rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("bad limit: status = %d, want 422", rec.Code)
+	}
+	for _, q := range []string{
+		"?state=defaulted",
+		"?borrower_id=NOPE",
+		"?principal_min=abc",
+		"?sort=id",
+		"?principal_min=2000000&principal_max=1000000",
+		"?created_from=2025-10-02T00:00:00Z&created_to=2025-10-01T00:00:00Z",
+		"?cursor=bogus",
+	} {
+		rec = doListLoans(t, &loanmock.Repo{}, q)
+		if rec.Code != stdhttp.StatusUnprocessableEntity && rec.Code != stdhttp.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400/422 (body=%s)", q, rec.Code, rec.Body.String())
+		}
+	}
+	rec = doListLoans(t, &loanmock.Repo{}, "?dpd_bucket=90%2B") // default SearchFn → context.Canceled
+	if rec.Code != stdhttp.StatusInternalServerError {
+		t.Fatalf("repo error: status = %d, want 500", rec.Code)
+	}
+}
+
+func TestListBorrowerLoans(t *testing.T) {
+	borrower := strings.Repeat("b", 32)
+	loans := &loanmock.Repo{
+		SearchFn: func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			if f.BorrowerID != borrower {
+				t.Fatalf("searched borrower %q", f.BorrowerID)
+			}
+			return []domain.Loan{{ID: 1, LoanID: "LN-1", BorrowerID: borrower, Principal: money.NewFromInt(1_000_000)}}, nil
+		},
+	}
+	h := NewLoanHandler(uc.NewUsecase(loans).WithDetails(&approvalmock.Repo{
+		ListByLoanIDsFn: func(context.Context, []uint64) ([]domainApproval.Approval, error) { return nil, nil },
+	}, &investmentmock.Repo{
+		FundingByLoanIDsFn: func(context.Context, []uint64) ([]domainInv.Funding, error) {
+			return []domainInv.Funding{{LoanID: 1, Invested: money.NewFromInt(250_000), Investments: 1, Investors: 1}}, nil
+		},
+	}, &disbursementmock.Repo{
+		ListByLoanIDsFn: func(context.Context, []uint64) ([]domainDisb.Disbursement, error) { return nil, nil },
+	}))
+
+	do := func(id, query string) *httptest.ResponseRecorder {
+		e := newEchoWithValidator()
+		req := httptest.NewRequest(stdhttp.MethodGet, "/borrowers/"+id+"/loans"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("borrower_id")
+		c.SetParamValues(id)
+		if err := h.ListBorrowerLoans(c); err != nil {
+			t.Fatalf("ListBorrowerLoans error: %v", err)
+		}
+		return rec
+	}
+
+	rec := do(borrower, "?limit=10")
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var out uc.LoanListDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if len(out.Items) != 1 || out.Items[0].Funding == nil || out.Items[0].Funding.FundingPercentage.String() != "25" {
+		t.Fatalf("unexpected items: %+v", out.Items)
+	}
+
+	for _, q := range []string{"?limit=500", "?cursor=not-a-cursor"} {
+		if rec := do(borrower, q); rec.Code != stdhttp.StatusUnprocessableEntity {
+			t.Fatalf("%s: status = %d, want 422 (body=%s)", q, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := do("NOPE", ""); rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("bad id: status = %d, want 422", rec.Code)
+	}
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"net/http"
+
+	ucNotification "amartha-backend-test/internal/usecase/notification"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct{ uc *ucNotification.Usecase }
+
+func NewNotificationHandler(uc *ucNotification.Usecase) *NotificationHandler {
+	return &NotificationHandler{uc: uc}
+}
+
+// GetLoanNotifications lists the loan's investor notifications with each
+// recipient's delivery status; empty until the loan is invested.
+func (h *NotificationHandler) GetLoanNotifications(c echo.Context) error {
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+	dto, err := h.uc.ListByLoan(c.Request().Context(), loanID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domainNotification "amartha-backend-test/internal/domain/notification"
+	"amartha-backend-test/internal/testutil/notificationmock"
+	ucNotification "amartha-backend-test/internal/usecase/notification"
+
+	"github.com/labstack/echo/v4"
+)
+
+func doGetNotifications(t *testing.T, h *NotificationHandler, loanID string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(stdhttp.MethodGet, "/loans/"+loanID+"/notifications", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	if loanID != "" {
+		c.SetParamNames("loan_id")
+		c.SetParamValues(loanID)
+	}
+	if err := h.GetLoanNotifications(c); err != nil {
+		t.Fatalf("GetLoanNotifications error: %v", err)
+	}
+	return rec
+}
+
+func TestGetLoanNotifications(t *testing.T) {
+	loanID := strings.Repeat("1", 32)
+	addr, sentAt := "investor@example.com", time.Now().UTC()
+	repo := &notificationmock.Repo{
+		ListByLoanIDFn: func(ctx context.Context, id string) ([]domainNotification.Notification, error) {
+			if id != loanID {
+				t.Fatalf("loan id mismatch: %s", id)
+			}
+			return []domainNotification.Notification{
+				{NotificationID: strings.Repeat("n", 32), Template: domainNotification.TemplateLoanInvested, LoanID: id,
+					RecipientID: strings.Repeat("a", 32), Email: &addr, Status: domainNotification.StatusSent, Attempts: 1, SentAt: &sentAt},
+				{NotificationID: strings.Repeat("m", 32), Template: domainNotification.TemplateLoanInvested, LoanID: id,
+					RecipientID: strings.Repeat("b", 32), Status: domainNotification.StatusSkipped},
+			}, nil
+		},
+	}
+	h := NewNotificationHandler(ucNotification.NewUsecase(repo, nil, nil, nil, time.Hour))
+
+	rec := doGetNotifications(t, h, loanID)
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucNotification.NotificationListDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.LoanID != loanID || len(dto.Items) != 2 || dto.Items[0].Status != "SENT" || dto.Items[0].Email != addr ||
+		dto.Items[1].Status != "SKIPPED" || dto.Items[1].Email != "" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+
+	if rec := doGetNotifications(t, h, ""); rec.Code != stdhttp.StatusBadRequest {
+		t.Fatalf("missing loan_id: status = %d, want 400", rec.Code)
+	}
+	// default ListByLoanID → context.Canceled
+	failing := NewNotificationHandler(ucNotification.NewUsecase(&notificationmock.Repo{}, nil, nil, nil, time.Hour))
+	if rec := doGetNotifications(t, failing, loanID); rec.Code != stdhttp.StatusInternalServerError {
+		t.Fatalf("repo error: status = %d, want 500", rec.Code)
+	}
+}
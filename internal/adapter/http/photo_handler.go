@@ -0,0 +1,133 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+
+	"amartha-backend-test/internal/domain/blob"
+	domainPhoto "amartha-backend-test/internal/domain/photo"
+	ucPhoto "amartha-backend-test/internal/usecase/photo"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PhotoHandler struct{ uc *ucPhoto.Usecase }
+
+func NewPhotoHandler(uc *ucPhoto.Usecase) *PhotoHandler { return &PhotoHandler{uc: uc} }
+
+type uploadPhotoReq struct {
+	UploadedBy string `form:"uploaded_by" validate:"required,hex32"`
+}
+
+// UploadPhoto takes a multipart form with the image in the "photo" file
+// field. The photo_id it returns is what ApproveLoan expects.
+func (h *PhotoHandler) UploadPhoto(c echo.Context) error {
+	var req uploadPhotoReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid multipart form"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+	fh, err := c.FormFile("photo")
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: []FieldError{{Field: "Photo", Message: "file field \"photo\" is required"}},
+		})
+	}
+	if fh.Size > domainPhoto.MaxBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: domainPhoto.ErrTooLarge.Error()})
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "unreadable photo"})
+	}
+	defer f.Close()
+
+	dto, err := h.uc.Upload(c.Request().Context(), ucPhoto.UploadInput{UploadedBy: req.UploadedBy, Body: f})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainPhoto.ErrTooLarge):
+			return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, domainPhoto.ErrEmpty), errors.Is(err, domainPhoto.ErrUnsupportedType):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusCreated, dto)
+}
+
+type photoReq struct {
+	PhotoID string `param:"photo_id" validate:"required,hex32"`
+}
+
+// GetPhoto returns the photo's metadata with a freshly signed URL.
+func (h *PhotoHandler) GetPhoto(c echo.Context) error {
+	var req photoReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid path"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.Get(c.Request().Context(), req.PhotoID)
+	if err != nil {
+		if errors.Is(err, domainPhoto.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
+
+// SignedBlobOpener is the part of the local blob store that checks its own
+// signed URLs.
+type SignedBlobOpener interface {
+	OpenSigned(ctx context.Context, key, expires, signature string) (io.ReadCloser, error)
+}
+
+type BlobHandler struct{ store SignedBlobOpener }
+
+func NewBlobHandler(store SignedBlobOpener) *BlobHandler { return &BlobHandler{store: store} }
+
+// ServeBlob streams a blob from the local store to the holder of a URL
+// signed by it. Only mounted when BLOB_DRIVER=local; S3 serves its own.
+func (h *BlobHandler) ServeBlob(c echo.Context) error {
+	key, err := url.PathUnescape(c.Param("*"))
+	if err != nil || !blob.ValidKey(key) {
+		return c.JSON(http.StatusNotFound, ErrorResponse{Error: blob.ErrNotFound.Error()})
+	}
+	rc, err := h.store.OpenSigned(c.Request().Context(), key, c.QueryParam("expires"), c.QueryParam("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, blob.ErrBadSignature):
+			return c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, blob.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	defer rc.Close()
+
+	ct := mime.TypeByExtension(path.Ext(key))
+	if ct == "" {
+		ct = echo.MIMEOctetStream
+	}
+	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+	return c.Stream(http.StatusOK, ct, rc)
+}
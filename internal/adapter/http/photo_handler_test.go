@@ -0,0 +1,178 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/blob"
+	domainPhoto "amartha-backend-test/internal/domain/photo"
+	"amartha-backend-test/internal/testutil/blobmock"
+	"amartha-backend-test/internal/testutil/photomock"
+	ucPhoto "amartha-backend-test/internal/usecase/photo"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func newPhotoHandler() *PhotoHandler {
+	rows := map[string]*domainPhoto.Photo{}
+	repo := &photomock.Repo{
+		CreateFn: func(ctx context.Context, p *domainPhoto.Photo) error { rows[p.PhotoID] = p; return nil },
+		GetByPhotoIDFn: func(ctx context.Context, id string) (*domainPhoto.Photo, error) {
+			if p, ok := rows[id]; ok {
+				return p, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	store := &blobmock.Store{
+		SignedURLFn: func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+			return "http://blobs.test/" + key + "?signature=x", nil
+		},
+	}
+	return NewPhotoHandler(ucPhoto.NewUsecase(repo, store, time.Minute))
+}
+
+// multipartBody builds a form with uploaded_by and, unless data is nil, a "photo" file.
+func multipartBody(uploadedBy string, data []byte) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	_ = w.WriteField("uploaded_by", uploadedBy)
+	if data != nil {
+		fw, _ := w.CreateFormFile("photo", "visit.jpg")
+		_, _ = fw.Write(data)
+	}
+	_ = w.Close()
+	return &buf, w.FormDataContentType()
+}
+
+func doUpload(t *testing.T, h *PhotoHandler, uploadedBy string, data []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	body, ct := multipartBody(uploadedBy, data)
+	req := httptest.NewRequest(stdhttp.MethodPost, "/photos", body)
+	req.Header.Set(echo.HeaderContentType, ct)
+	rec := httptest.NewRecorder()
+	if err := h.UploadPhoto(newEchoWithValidator().NewContext(req, rec)); err != nil {
+		t.Fatalf("UploadPhoto error: %v", err)
+	}
+	return rec
+}
+
+func TestUploadPhoto(t *testing.T) {
+	h := newPhotoHandler()
+	employee := strings.Repeat("e", 32)
+
+	var visit bytes.Buffer
+	_ = jpeg.Encode(&visit, image.NewGray(image.Rect(0, 0, 32, 24)), nil)
+
+	rec := doUpload(t, h, employee, visit.Bytes())
+	if rec.Code != stdhttp.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucPhoto.PhotoDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if len(dto.PhotoID) != 32 || dto.ContentType != "image/jpeg" || len(dto.SHA256) != 64 ||
+		dto.URL != "http://blobs.test/photos/"+dto.PhotoID+".jpg?signature=x" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+
+	// the issued id resolves to a fresh link
+	req := httptest.NewRequest(stdhttp.MethodGet, "/photos/"+dto.PhotoID, nil)
+	rec = httptest.NewRecorder()
+	c := newEchoWithValidator().NewContext(req, rec)
+	c.SetParamNames("photo_id")
+	c.SetParamValues(dto.PhotoID)
+	if err := h.GetPhoto(c); err != nil {
+		t.Fatalf("GetPhoto error: %v", err)
+	}
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("GetPhoto status = %d, want 200", rec.Code)
+	}
+
+	for name, c := range map[string]struct {
+		by   string
+		data []byte
+		want int
+	}{
+		"bad uploader": {"EMP-1", []byte("\xff\xd8\xff"), stdhttp.StatusUnprocessableEntity},
+		"no file":      {employee, nil, stdhttp.StatusUnprocessableEntity},
+		"not an image": {employee, []byte("https://example.com/a.jpg"), stdhttp.StatusUnprocessableEntity},
+		"too large":    {employee, make([]byte, domainPhoto.MaxBytes+1), stdhttp.StatusRequestEntityTooLarge},
+	} {
+		if rec := doUpload(t, h, c.by, c.data); rec.Code != c.want {
+			t.Fatalf("%s: status = %d, want %d (body=%s)", name, rec.Code, c.want, rec.Body.String())
+		}
+	}
+}
+
+func TestGetPhoto_NotFound(t *testing.T) {
+	h := newPhotoHandler()
+	for id, want := range map[string]int{strings.Repeat("0", 32): stdhttp.StatusNotFound, "NOPE": stdhttp.StatusUnprocessableEntity} {
+		req := httptest.NewRequest(stdhttp.MethodGet, "/photos/"+id, nil)
+		rec := httptest.NewRecorder()
+		c := newEchoWithValidator().NewContext(req, rec)
+		c.SetParamNames("photo_id")
+		c.SetParamValues(id)
+		if err := h.GetPhoto(c); err != nil {
+			t.Fatalf("GetPhoto error: %v", err)
+		}
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", id, rec.Code, want)
+		}
+	}
+}
+
+type openerFunc func(ctx context.Context, key, expires, signature string) (io.ReadCloser, error)
+
+func (f openerFunc) OpenSigned(ctx context.Context, key, expires, signature string) (io.ReadCloser, error) {
+	return f(ctx, key, expires, signature)
+}
+
+func TestServeBlob(t *testing.T) {
+	h := NewBlobHandler(openerFunc(func(ctx context.Context, key, expires, signature string) (io.ReadCloser, error) {
+		switch {
+		case signature != "good":
+			return nil, blob.ErrBadSignature
+		case key != "photos/p.jpg":
+			return nil, blob.ErrNotFound
+		}
+		return io.NopCloser(strings.NewReader("jpeg")), nil
+	}))
+
+	for _, c := range []struct {
+		key, sig string
+		want     int
+	}{
+		{"photos/p.jpg", "good", stdhttp.StatusOK},
+		{"photos/p.jpg", "forged", stdhttp.StatusForbidden},
+		{"photos/q.jpg", "good", stdhttp.StatusNotFound},
+		{"../etc/passwd", "good", stdhttp.StatusNotFound},
+	} {
+		req := httptest.NewRequest(stdhttp.MethodGet, "/blobs/"+c.key+"?expires=1&signature="+c.sig, nil)
+		rec := httptest.NewRecorder()
+		ctx := newEchoWithValidator().NewContext(req, rec)
+		ctx.SetParamNames("*")
+		ctx.SetParamValues(c.key)
+		if err := h.ServeBlob(ctx); err != nil {
+			t.Fatalf("ServeBlob error: %v", err)
+		}
+		if rec.Code != c.want {
+			t.Fatalf("%s/%s: status = %d, want %d", c.key, c.sig, rec.Code, c.want)
+		}
+		if c.want == stdhttp.StatusOK && (rec.Body.String() != "jpeg" || rec.Header().Get(echo.HeaderContentType) != "image/jpeg") {
+			t.Fatalf("unexpected blob response: %q %s", rec.Body.String(), rec.Header().Get(echo.HeaderContentType))
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+
+	ucPortfolio "amartha-backend-test/internal/usecase/portfolio"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PortfolioHandler struct{ uc *ucPortfolio.Usecase }
+
+func NewPortfolioHandler(uc *ucPortfolio.Usecase) *PortfolioHandler {
+	return &PortfolioHandler{uc: uc}
+}
+
+// GetPortfolio lists the investor's investments with expected and realised
+// returns, plus totals per loan state.
+func (h *PortfolioHandler) GetPortfolio(c echo.Context) error {
+	var req walletReq // same path shape: /investors/:investor_id/...
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid path"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.Get(c.Request().Context(), req.InvestorID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainPayout "amartha-backend-test/internal/domain/payout"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/payoutmock"
+	ucPortfolio "amartha-backend-test/internal/usecase/portfolio"
+	"amartha-backend-test/pkg/money"
+)
+
+func doGetPortfolio(t *testing.T, h *PortfolioHandler, investorID string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodGet, "/investors/"+investorID+"/portfolio", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("investor_id")
+	c.SetParamValues(investorID)
+	if err := h.GetPortfolio(c); err != nil {
+		t.Fatalf("GetPortfolio error: %v", err)
+	}
+	return rec
+}
+
+func TestGetPortfolio(t *testing.T) {
+	investor := strings.Repeat("a", 32)
+	h := NewPortfolioHandler(ucPortfolio.NewUsecase(
+		&investmentmock.Repo{ListByInvestorIDFn: func(context.Context, string) ([]domainInvestment.Investment, error) {
+			return []domainInvestment.Investment{{ID: 1, InvestmentID: "INV-1", LoanID: 7, Amount: money.NewFromInt(2_500_000), Status: domainInvestment.StatusHeld}}, nil
+		}},
+		&loanmock.Repo{ListByIDsFn: func(context.Context, []uint64) ([]domainLoan.Loan, error) {
+			return []domainLoan.Loan{{ID: 7, LoanID: "LN-7", State: domainLoan.StateInvested, Principal: money.NewFromInt(5_000_000),
+				Rate: money.MustParse("2"), ROI: money.MustParse("1"), Tenor: 10, TenorUnit: domainLoan.TenorMonth}}, nil
+		}},
+		&payoutmock.Repo{SumByInvestorIDFn: func(context.Context, string) ([]domainPayout.Sum, error) { return nil, nil }},
+	))
+
+	rec := doGetPortfolio(t, h, investor)
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucPortfolio.PortfolioDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.InvestorID != investor || len(dto.Items) != 1 || dto.Items[0].LoanID != "LN-7" || dto.Items[0].Share.String() != "50" ||
+		dto.Items[0].ExpectedInterest.String() != "250000" || len(dto.ByState) != 1 || dto.ByState[0].LoanState != "invested" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+
+	if rec := doGetPortfolio(t, h, "NOPE"); rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("bad id: status = %d, want 422", rec.Code)
+	}
+	failing := NewPortfolioHandler(ucPortfolio.NewUsecase(&investmentmock.Repo{}, &loanmock.Repo{}, &payoutmock.Repo{}))
+	if rec := doGetPortfolio(t, failing, investor); rec.Code != stdhttp.StatusInternalServerError {
+		t.Fatalf("repo error: status = %d, want 500", rec.Code)
+	}
+}
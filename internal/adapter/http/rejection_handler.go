@@ -0,0 +1,81 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainRejection "amartha-backend-test/internal/domain/rejection"
+	ucRejection "amartha-backend-test/internal/usecase/rejection"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RejectionHandler struct{ uc *ucRejection.Usecase }
+
+func NewRejectionHandler(uc *ucRejection.Usecase) *RejectionHandler {
+	return &RejectionHandler{uc: uc}
+}
+
+type rejectLoanReq struct {
+	// reason_code: one of the rejection catalog codes
+	ReasonCode          string `json:"reason_code"           validate:"required,max=64"`
+	ReasonText          string `json:"reason_text"           validate:"max=1000"`
+	ValidatorEmployeeID string `json:"validator_employee_id" validate:"required,hex32"`
+}
+
+func (h *RejectionHandler) RejectLoan(c echo.Context) error {
+	// path param
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+
+	// bind + validate
+	var req rejectLoanReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	// call usecase
+	dto, uerr := h.uc.Reject(
+		c.Request().Context(),
+		ucRejection.RejectInput{
+			LoanID:              loanID,
+			ReasonCode:          req.ReasonCode,
+			ReasonText:          req.ReasonText,
+			ValidatorEmployeeID: req.ValidatorEmployeeID,
+		},
+	)
+	if uerr != nil {
+		switch {
+		case errors.Is(uerr, domainRejection.ErrUnknownReason):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "ReasonCode", Message: "must be a known rejection reason code"}},
+			})
+		case errors.Is(uerr, domainRejection.ErrReasonTextRequired):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "ReasonText", Message: "is required for reason OTHER"}},
+			})
+		case errors.Is(uerr, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(uerr, domainLoan.ErrAlreadyRejected):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan already rejected"})
+		case errors.Is(uerr, domainLoan.ErrInvalidTransition):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan not in a state that can be rejected"})
+		default:
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Error: uerr.Error()})
+		}
+	}
+
+	// success
+	return c.JSON(http.StatusOK, dto)
+}
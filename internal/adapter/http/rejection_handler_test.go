@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainRejection "amartha-backend-test/internal/domain/rejection"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/rejectionmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	ucRejection "amartha-backend-test/internal/usecase/rejection"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// newRejectHandler wires a handler whose locked loan is l (nil → not found).
+func newRejectHandler(l *domainLoan.Loan) *RejectionHandler {
+	loans := &loanmock.Repo{SaveFn: func(ctx context.Context, l *domainLoan.Loan) error { return nil }}
+	rjs := &rejectionmock.Repo{}
+	tx := &uowmock.UoW{
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *domainLoan.Loan) error) error {
+			if l == nil {
+				return gorm.ErrRecordNotFound
+			}
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Rejections: rjs, Outbox: &outboxmock.Repo{}}, l)
+		},
+	}
+	return NewRejectionHandler(ucRejection.NewUsecase(rjs, tx))
+}
+
+func doReject(t *testing.T, h *RejectionHandler, loanID string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/"+loanID+"/reject", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if loanID != "" {
+		c.SetParamNames("loan_id")
+		c.SetParamValues(loanID)
+	}
+	if err := h.RejectLoan(c); err != nil {
+		t.Fatalf("RejectLoan error: %v", err)
+	}
+	return rec
+}
+
+func TestRejectLoan_Success(t *testing.T) {
+	l := &domainLoan.Loan{ID: 7, LoanID: strings.Repeat("l", 32), State: domainLoan.StateProposed}
+	rec := doReject(t, newRejectHandler(l), l.LoanID, map[string]any{
+		"reason_code":           string(domainRejection.ReasonIncompleteDocuments),
+		"reason_text":           "missing KTP scan",
+		"validator_employee_id": strings.Repeat("a", 32),
+	})
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucRejection.RejectionDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.LoanID != l.LoanID || dto.ReasonCode != string(domainRejection.ReasonIncompleteDocuments) {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+}
+
+func TestRejectLoan_MissingPathParam(t *testing.T) {
+	rec := doReject(t, newRejectHandler(nil), "", map[string]any{})
+	if rec.Code != stdhttp.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestRejectLoan_ValidationError(t *testing.T) {
+	rec := doReject(t, newRejectHandler(nil), "abcd", map[string]any{
+		"validator_employee_id": "NOTHEX",
+	})
+	if rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", rec.Code)
+	}
+	var er ErrorResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &er)
+	if !hasFieldDetail(er.Details, "ReasonCode", "required") || !hasFieldDetail(er.Details, "ValidatorEmployeeID", "32") {
+		t.Fatalf("missing expected field errors: %+v", er.Details)
+	}
+}
+
+func TestRejectLoan_ErrorMapping(t *testing.T) {
+	proposed := &domainLoan.Loan{ID: 1, State: domainLoan.StateProposed}
+	tests := []struct {
+		name     string
+		loan     *domainLoan.Loan
+		code     string
+		text     string
+		wantCode int
+	}{
+		{name: "unknown reason", loan: proposed, code: "NOPE", wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "OTHER without text", loan: proposed, code: "OTHER", wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "not found", loan: nil, code: "OTHER", text: "x", wantCode: stdhttp.StatusNotFound},
+		{name: "already rejected", loan: &domainLoan.Loan{ID: 1, State: domainLoan.StateRejected}, code: "OTHER", text: "x", wantCode: stdhttp.StatusConflict},
+		{name: "wrong state", loan: &domainLoan.Loan{ID: 1, State: domainLoan.StateInvested}, code: "OTHER", text: "x", wantCode: stdhttp.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doReject(t, newRejectHandler(tt.loan), "LN-1", map[string]any{
+				"reason_code":           tt.code,
+				"reason_text":           tt.text,
+				"validator_employee_id": strings.Repeat("a", 32),
+			})
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body=%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainPayout "amartha-backend-test/internal/domain/payout"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	ucRepayment "amartha-backend-test/internal/usecase/repayment"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RepaymentHandler struct{ uc *ucRepayment.Usecase }
+
+func NewRepaymentHandler(uc *ucRepayment.Usecase) *RepaymentHandler {
+	return &RepaymentHandler{uc: uc}
+}
+
+type recordRepaymentReq struct {
+	// amount: positive with max 2 decimals (matches decimal(18,2))
+	Amount              money.Decimal `json:"amount"                validate:"required,dec2,gt=0"`
+	CollectorEmployeeID string        `json:"collector_employee_id" validate:"required,hex32"`
+	// RFC3339 with timezone; defaults to now when omitted
+	PaidAt string `json:"paid_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// RecordRepayment is retried safely by IdempotencyMiddleware: a resubmitted
+// Ax-Request-Id replays the stored response instead of collecting twice.
+func (h *RepaymentHandler) RecordRepayment(c echo.Context) error {
+	// path param
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+
+	// bind + validate
+	var req recordRepaymentReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+	var paidAt *time.Time
+	if req.PaidAt != "" {
+		pa, err := time.Parse(time.RFC3339, req.PaidAt)
+		if err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "PaidAt", Message: "must be RFC3339 with timezone"}},
+			})
+		}
+		paidAt = &pa
+	}
+
+	// call usecase
+	dto, uerr := h.uc.Record(
+		c.Request().Context(),
+		ucRepayment.RecordInput{
+			LoanID:              loanID,
+			Amount:              req.Amount,
+			CollectorEmployeeID: req.CollectorEmployeeID,
+			PaidAt:              paidAt,
+		},
+	)
+	if uerr != nil {
+		switch {
+		case errors.Is(uerr, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(uerr, domainRepayment.ErrOverpayment),
+			errors.Is(uerr, domainRepayment.ErrInvalidAmount):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: uerr.Error()})
+		case errors.Is(uerr, domainLoan.ErrAlreadyRepaid):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan already repaid"})
+		case errors.Is(uerr, domainLoan.ErrInvalidTransition),
+			errors.Is(uerr, domainRepayment.ErrScheduleNotFound):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: "loan not in a state that can be repaid"})
+		case errors.Is(uerr, domainPayout.ErrNoInvestments):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: uerr.Error()})
+		default:
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Error: uerr.Error()})
+		}
+	}
+
+	// success
+	return c.JSON(http.StatusCreated, dto)
+}
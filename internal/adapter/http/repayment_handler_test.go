@@ -0,0 +1,207 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	idmp "amartha-backend-test/internal/adapter/middleware"
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/payoutmock"
+	"amartha-backend-test/internal/testutil/repaymentmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	ucRepayment "amartha-backend-test/internal/usecase/repayment"
+	"amartha-backend-test/pkg/money"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// newRepaymentHandler wires a handler whose locked loan is l (nil → not found)
+// with a single 1010 installment; *collected counts stored repayments.
+func newRepaymentHandler(l *domainLoan.Loan, collected *int) *RepaymentHandler {
+	items := []domainRepayment.Installment{{
+		ID: 1, LoanID: 7, InstallmentNo: 1,
+		PrincipalDue: money.NewFromInt(1000), InterestDue: money.NewFromInt(10), TotalDue: money.NewFromInt(1010),
+	}}
+	repos := uow.Repos{
+		Loans:       &loanmock.Repo{SaveFn: func(ctx context.Context, l *domainLoan.Loan) error { return nil }},
+		LoanHistory: &loanmock.HistoryRepo{},
+		Outbox:      &outboxmock.Repo{},
+		Schedules: &repaymentmock.Repo{
+			ListByLoanIDFn: func(ctx context.Context, id uint64) ([]domainRepayment.Installment, error) {
+				return append([]domainRepayment.Installment(nil), items...), nil
+			},
+			UpdatePaidFn: func(ctx context.Context, got []domainRepayment.Installment) error {
+				items[0] = got[0]
+				return nil
+			},
+		},
+		Repayments: &repaymentmock.PaymentRepo{CreateFn: func(ctx context.Context, p *domainRepayment.Repayment) error {
+			if collected != nil {
+				*collected++
+			}
+			return nil
+		}},
+		Investments: &investmentmock.Repo{ListByLoanIDFn: func(ctx context.Context, id uint64) ([]domainInvestment.Investment, error) {
+			return []domainInvestment.Investment{{ID: 1, InvestorID: strings.Repeat("e", 32), Amount: money.NewFromInt(1000)}}, nil
+		}},
+		Payouts: &payoutmock.Repo{},
+		Ledger:  &ledgermock.Repo{},
+	}
+	tx := &uowmock.UoW{
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *domainLoan.Loan) error) error {
+			if l == nil {
+				return gorm.ErrRecordNotFound
+			}
+			return fn(repos, l)
+		},
+	}
+	return NewRepaymentHandler(ucRepayment.NewUsecase(&repaymentmock.PaymentRepo{}, tx))
+}
+
+func disbursedLoan(loanID string) *domainLoan.Loan {
+	return &domainLoan.Loan{ID: 7, LoanID: loanID, State: domainLoan.StateDisbursed, OutstandingBalance: money.NewFromInt(1010)}
+}
+
+func validRepaymentBody(amount string) map[string]any {
+	return map[string]any{
+		"amount":                amount,
+		"collector_employee_id": strings.Repeat("c", 32),
+		"paid_at":               "2025-10-10T09:00:00+07:00",
+	}
+}
+
+func doRepay(t *testing.T, h *RepaymentHandler, loanID string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/"+loanID+"/repayments", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if loanID != "" {
+		c.SetParamNames("loan_id")
+		c.SetParamValues(loanID)
+	}
+	if err := h.RecordRepayment(c); err != nil {
+		t.Fatalf("RecordRepayment error: %v", err)
+	}
+	return rec
+}
+
+func TestRecordRepayment_Success(t *testing.T) {
+	l := disbursedLoan(strings.Repeat("l", 32))
+	rec := doRepay(t, newRepaymentHandler(l, nil), l.LoanID, validRepaymentBody("300.50"))
+	if rec.Code != stdhttp.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucRepayment.RepaymentDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.InterestPaid.String() != "10" || dto.PrincipalPaid.String() != "290.5" || dto.OutstandingBalance.String() != "709.5" {
+		t.Fatalf("unexpected split: %+v", dto)
+	}
+	if dto.LoanState != "disbursed" || dto.PaidAt.Hour() != 2 { // 09:00+07:00 → 02:00Z
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+}
+
+func TestRecordRepayment_FullPayoff(t *testing.T) {
+	l := disbursedLoan("LN-1")
+	rec := doRepay(t, newRepaymentHandler(l, nil), l.LoanID, validRepaymentBody("1010"))
+	if rec.Code != stdhttp.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body=%s)", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"loan_state":"repaid"`) || l.State != domainLoan.StateRepaid {
+		t.Fatalf("loan should be repaid: %s", rec.Body.String())
+	}
+}
+
+func TestRecordRepayment_ValidationError(t *testing.T) {
+	body := validRepaymentBody("10.001")
+	body["collector_employee_id"] = "EMP-1"
+	body["paid_at"] = "2025-10-10 09:00"
+	rec := doRepay(t, newRepaymentHandler(nil, nil), "LN-1", body)
+	if rec.Code != stdhttp.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", rec.Code)
+	}
+	var er ErrorResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &er)
+	if !containsFieldMsg(er.Details, "Amount", "at most 2 decimal places") || len(er.Details) < 3 {
+		t.Fatalf("expected field errors, got %+v", er.Details)
+	}
+}
+
+func TestRecordRepayment_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		loan     *domainLoan.Loan
+		amount   string
+		wantCode int
+	}{
+		{name: "not found", loan: nil, amount: "10", wantCode: stdhttp.StatusNotFound},
+		{name: "overpayment", loan: disbursedLoan("LN-1"), amount: "1010.01", wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "already repaid", loan: &domainLoan.Loan{ID: 7, State: domainLoan.StateRepaid}, amount: "10", wantCode: stdhttp.StatusConflict},
+		{name: "not disbursed", loan: &domainLoan.Loan{ID: 7, State: domainLoan.StateInvested}, amount: "10", wantCode: stdhttp.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRepay(t, newRepaymentHandler(tt.loan, nil), "LN-1", validRepaymentBody(tt.amount))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body=%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+// A field officer double-submitting the same collection must not double-count.
+func TestRecordRepayment_IdempotentReplay(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis: %v", err)
+	}
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	collected := 0
+	h := newRepaymentHandler(disbursedLoan("LN-1"), &collected)
+	e := newEchoWithValidator()
+	e.Use(idmp.IdempotencyMiddleware(rdb, time.Minute))
+	e.POST("/loans/:loan_id/repayments", h.RecordRepayment)
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(stdhttp.MethodPost, "/loans/LN-1/repayments", mustJSON(validRepaymentBody("100")))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Ax-Request-Id", strings.Repeat("a", 32))
+		req.Header.Set("Ax-Request-At", time.Now().UTC().Format(time.RFC3339))
+		req.Header.Set("Ax-Borrower-Id", strings.Repeat("b", 32))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first, second := send(), send()
+	if first.Code != stdhttp.StatusCreated || second.Code != stdhttp.StatusCreated {
+		t.Fatalf("codes = %d/%d, want 201/201", first.Code, second.Code)
+	}
+	if collected != 1 {
+		t.Fatalf("repayment stored %d times, want 1", collected)
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Fatalf("replay body differs:\n%s\n%s", first.Body.String(), second.Body.String())
+	}
+}
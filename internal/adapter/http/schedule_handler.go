@@ -0,0 +1,35 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	ucSchedule "amartha-backend-test/internal/usecase/schedule"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ScheduleHandler struct{ uc *ucSchedule.Usecase }
+
+func NewScheduleHandler(uc *ucSchedule.Usecase) *ScheduleHandler { return &ScheduleHandler{uc: uc} }
+
+func (h *ScheduleHandler) GetLoanSchedule(c echo.Context) error {
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+	dto, err := h.uc.Get(c.Request().Context(), loanID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(err, domainRepayment.ErrScheduleNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "schedule is generated at disbursement"})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, dto)
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/repaymentmock"
+	ucSchedule "amartha-backend-test/internal/usecase/schedule"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func doGetSchedule(t *testing.T, h *ScheduleHandler, loanID string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(stdhttp.MethodGet, "/loans/"+loanID+"/schedule", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if loanID != "" {
+		c.SetParamNames("loan_id")
+		c.SetParamValues(loanID)
+	}
+	if err := h.GetLoanSchedule(c); err != nil {
+		t.Fatalf("GetLoanSchedule error: %v", err)
+	}
+	return rec
+}
+
+func scheduleLoans() *loanmock.Repo {
+	return &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			if loanID == "LN-404" {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return &domainLoan.Loan{ID: 1, LoanID: loanID, Principal: money.NewFromInt(1000), Tenor: 1, TenorUnit: domainLoan.TenorWeek}, nil
+		},
+	}
+}
+
+func TestGetLoanSchedule_Success(t *testing.T) {
+	schedules := &repaymentmock.Repo{
+		ListByLoanIDFn: func(ctx context.Context, id uint64) ([]domainRepayment.Installment, error) {
+			return []domainRepayment.Installment{{
+				InstallmentNo: 1, DueDate: time.Date(2025, 9, 17, 0, 0, 0, 0, time.UTC),
+				PrincipalDue: money.NewFromInt(1000), InterestDue: money.MustParse("15.5"), TotalDue: money.MustParse("1015.5"),
+			}}, nil
+		},
+	}
+	rec := doGetSchedule(t, NewScheduleHandler(ucSchedule.NewUsecase(scheduleLoans(), schedules)), "LN-1")
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var dto ucSchedule.ScheduleDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if len(dto.Installments) != 1 || dto.Installments[0].DueDate != "2025-09-17" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+	if !strings.Contains(rec.Body.String(), `"total":"1015.5"`) {
+		t.Fatalf("amounts must be decimal strings: %s", rec.Body.String())
+	}
+}
+
+func TestGetLoanSchedule_ErrorMapping(t *testing.T) {
+	empty := &repaymentmock.Repo{
+		ListByLoanIDFn: func(context.Context, uint64) ([]domainRepayment.Installment, error) { return nil, nil },
+	}
+	tests := []struct {
+		name     string
+		loanID   string
+		repo     *repaymentmock.Repo
+		wantCode int
+	}{
+		{name: "missing path param", loanID: "", repo: empty, wantCode: stdhttp.StatusBadRequest},
+		{name: "loan not found", loanID: "LN-404", repo: empty, wantCode: stdhttp.StatusNotFound},
+		{name: "not disbursed", loanID: "LN-1", repo: empty, wantCode: stdhttp.StatusNotFound},
+		{name: "repo error", loanID: "LN-1", repo: &repaymentmock.Repo{}, wantCode: stdhttp.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doGetSchedule(t, NewScheduleHandler(ucSchedule.NewUsecase(scheduleLoans(), tt.repo)), tt.loanID)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body=%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
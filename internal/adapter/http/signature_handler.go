@@ -0,0 +1,126 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	domainAgreement "amartha-backend-test/internal/domain/agreement"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainSignature "amartha-backend-test/internal/domain/signature"
+	ucSignature "amartha-backend-test/internal/usecase/signature"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxWebhookBytes caps an inbound provider callback.
+const maxWebhookBytes = 1 << 20
+
+type SignatureHandler struct{ uc *ucSignature.Usecase }
+
+func NewSignatureHandler(uc *ucSignature.Usecase) *SignatureHandler {
+	return &SignatureHandler{uc: uc}
+}
+
+type requestSignatureReq struct {
+	LoanID      string `param:"loan_id"     validate:"required"`
+	Provider    string `json:"provider"     validate:"required,max=64"`
+	DocumentURL string `json:"document_url" validate:"omitempty,url"`
+	RequestedBy string `json:"requested_by" validate:"required,hex32"`
+}
+
+// RequestSignature sends an invested loan's agreement to the borrower
+// through an e-signature provider.
+func (h *SignatureHandler) RequestSignature(c echo.Context) error {
+	var req requestSignatureReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.Request(c.Request().Context(), ucSignature.RequestInput{
+		LoanID:      req.LoanID,
+		Provider:    req.Provider,
+		DocumentURL: req.DocumentURL,
+		RequestedBy: req.RequestedBy,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(err, domainSignature.ErrUnknownProvider):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "Provider", Message: err.Error()}},
+			})
+		case errors.Is(err, domainAgreement.ErrNotGenerated):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "DocumentURL", Message: "required while the loan has no generated agreement"}},
+			})
+		case errors.Is(err, domainSignature.ErrLoanNotInvested),
+			errors.Is(err, domainSignature.ErrEnvelopeExists):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusCreated, dto)
+}
+
+// GetSignature reports the loan's latest envelope.
+func (h *SignatureHandler) GetSignature(c echo.Context) error {
+	loanID := c.Param("loan_id")
+	if loanID == "" {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing loan_id path param"})
+	}
+	dto, err := h.uc.Get(c.Request().Context(), loanID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainLoan.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: "loan not found"})
+		case errors.Is(err, domainSignature.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, dto)
+}
+
+// SignatureWebhook receives provider callbacks. The raw body is passed
+// through untouched because the Ax-Signature HMAC covers its exact bytes.
+func (h *SignatureHandler) SignatureWebhook(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBytes+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "unreadable body"})
+	}
+	if len(body) > maxWebhookBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "webhook body too large"})
+	}
+
+	dto, err := h.uc.HandleWebhook(c.Request().Context(), ucSignature.WebhookInput{
+		Provider:  c.Param("provider"),
+		Body:      body,
+		Signature: c.Request().Header.Get(domainSignature.SignatureHeader),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainSignature.ErrUnknownProvider),
+			errors.Is(err, domainSignature.ErrNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, domainSignature.ErrBadSignature):
+			return c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, domainSignature.ErrInvalidEvent):
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, dto)
+}
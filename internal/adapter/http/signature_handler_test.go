@@ -0,0 +1,231 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainSignature "amartha-backend-test/internal/domain/signature"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/infrastructure/esign"
+	"amartha-backend-test/internal/testutil/blobmock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/signaturemock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	ucSignature "amartha-backend-test/internal/usecase/signature"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// newSignatureHandler wires a handler over loan l with in-memory envelopes
+// and events, and the fake provider it signs webhooks with.
+func newSignatureHandler(t *testing.T, l *domainLoan.Loan) (*SignatureHandler, *esign.Fake) {
+	t.Helper()
+	fake, err := esign.NewFake("fake", []byte("0123456789abcdef"), "http://esign.local/sign")
+	if err != nil {
+		t.Fatalf("NewFake: %v", err)
+	}
+	var envelopes []*domainSignature.Envelope
+	var events []*domainSignature.Event
+	byTx := func(ctx context.Context, provider, txID string) (*domainSignature.Envelope, error) {
+		for _, e := range envelopes {
+			if e.SignatureTxID == txID {
+				return e, nil
+			}
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+	sigs := &signaturemock.Repo{
+		CreateEnvelopeFn: func(ctx context.Context, e *domainSignature.Envelope) error {
+			envelopes = append(envelopes, e)
+			return nil
+		},
+		GetLatestByLoanIDFn: func(context.Context, uint64) (*domainSignature.Envelope, error) {
+			if len(envelopes) == 0 {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return envelopes[len(envelopes)-1], nil
+		},
+		GetByProviderTxFn:          byTx,
+		GetByProviderTxForUpdateFn: byTx,
+		GetEventFn: func(ctx context.Context, provider, eventID string) (*domainSignature.Event, error) {
+			for _, ev := range events {
+				if ev.EventID == eventID {
+					return ev, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		CreateEventFn: func(ctx context.Context, ev *domainSignature.Event) error {
+			events = append(events, ev)
+			return nil
+		},
+	}
+	loans := &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			if l == nil {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return l, nil
+		},
+	}
+	tx := &uowmock.UoW{
+		WithinTxFn: func(ctx context.Context, fn func(uow.Repos) error) error {
+			return fn(uow.Repos{Loans: loans, Signatures: sigs})
+		},
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(uow.Repos, *domainLoan.Loan) error) error {
+			if l == nil {
+				return gorm.ErrRecordNotFound
+			}
+			return fn(uow.Repos{Signatures: sigs}, l)
+		},
+	}
+	return NewSignatureHandler(ucSignature.NewUsecase(tx, &blobmock.Store{}, fake)), fake
+}
+
+func doRequestSignature(t *testing.T, h *SignatureHandler, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/loans/LN-1/signature", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("loan_id")
+	c.SetParamValues("LN-1")
+	if err := h.RequestSignature(c); err != nil {
+		t.Fatalf("RequestSignature error: %v", err)
+	}
+	return rec
+}
+
+func doSignatureWebhook(t *testing.T, h *SignatureHandler, provider string, body []byte, sig string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/webhooks/signature/"+provider, bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(domainSignature.SignatureHeader, sig)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("provider")
+	c.SetParamValues(provider)
+	if err := h.SignatureWebhook(c); err != nil {
+		t.Fatalf("SignatureWebhook error: %v", err)
+	}
+	return rec
+}
+
+func validSignatureBody() map[string]any {
+	return map[string]any{"provider": "fake", "document_url": "https://docs.example.com/agreement.pdf", "requested_by": strings.Repeat("e", 32)}
+}
+
+func TestRequestSignature(t *testing.T) {
+	h, _ := newSignatureHandler(t, &domainLoan.Loan{ID: 1, LoanID: "LN-1", State: domainLoan.StateInvested})
+	rec := doRequestSignature(t, h, validSignatureBody())
+	if rec.Code != stdhttp.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucSignature.EnvelopeDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.SignatureStatus != "PENDING" || dto.SignatureTxID == "" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+	if rec := doRequestSignature(t, h, validSignatureBody()); rec.Code != stdhttp.StatusConflict {
+		t.Fatalf("second envelope: status = %d, want 409", rec.Code)
+	}
+}
+
+func TestRequestSignature_ErrorMapping(t *testing.T) {
+	invested := &domainLoan.Loan{ID: 1, LoanID: "LN-1", State: domainLoan.StateInvested}
+	unknown := validSignatureBody()
+	unknown["provider"] = "docusign"
+	noURL := validSignatureBody()
+	delete(noURL, "document_url")
+	badURL := validSignatureBody()
+	badURL["document_url"] = "not a url"
+
+	tests := []struct {
+		name     string
+		loan     *domainLoan.Loan
+		body     map[string]any
+		wantCode int
+	}{
+		{name: "loan not found", loan: nil, body: validSignatureBody(), wantCode: stdhttp.StatusNotFound},
+		{name: "loan not invested", loan: &domainLoan.Loan{ID: 1, State: domainLoan.StateApproved}, body: validSignatureBody(), wantCode: stdhttp.StatusConflict},
+		{name: "unknown provider", loan: invested, body: unknown, wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "no document and no generated agreement", loan: invested, body: noURL, wantCode: stdhttp.StatusUnprocessableEntity},
+		{name: "bad document url", loan: invested, body: badURL, wantCode: stdhttp.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newSignatureHandler(t, tt.loan)
+			if rec := doRequestSignature(t, h, tt.body); rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body=%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetSignature(t *testing.T) {
+	h, _ := newSignatureHandler(t, &domainLoan.Loan{ID: 1, LoanID: "LN-1", State: domainLoan.StateInvested})
+	get := func() *httptest.ResponseRecorder {
+		e := newEchoWithValidator()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(stdhttp.MethodGet, "/loans/LN-1/signature", nil), rec)
+		c.SetParamNames("loan_id")
+		c.SetParamValues("LN-1")
+		if err := h.GetSignature(c); err != nil {
+			t.Fatalf("GetSignature error: %v", err)
+		}
+		return rec
+	}
+	if rec := get(); rec.Code != stdhttp.StatusNotFound {
+		t.Fatalf("no envelope: status = %d, want 404", rec.Code)
+	}
+	doRequestSignature(t, h, validSignatureBody())
+	if rec := get(); rec.Code != stdhttp.StatusOK || !strings.Contains(rec.Body.String(), `"signature_status":"PENDING"`) {
+		t.Fatalf("status = %d (body=%s)", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSignatureWebhook(t *testing.T) {
+	h, fake := newSignatureHandler(t, &domainLoan.Loan{ID: 1, LoanID: "LN-1", State: domainLoan.StateInvested})
+	var env ucSignature.EnvelopeDTO
+	_ = json.Unmarshal(doRequestSignature(t, h, validSignatureBody()).Body.Bytes(), &env)
+	body, sig, err := fake.Complete(env.SignatureTxID, domainSignature.StatusCancelled)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if rec := doSignatureWebhook(t, h, "fake", body, "deadbeef"); rec.Code != stdhttp.StatusUnauthorized {
+		t.Fatalf("bad signature: status = %d, want 401", rec.Code)
+	}
+	if rec := doSignatureWebhook(t, h, "other", body, sig); rec.Code != stdhttp.StatusNotFound {
+		t.Fatalf("unknown provider: status = %d, want 404", rec.Code)
+	}
+	junk := []byte(`{"event_id":"e"}`)
+	if rec := doSignatureWebhook(t, h, "fake", junk, esign.Sign([]byte("0123456789abcdef"), junk)); rec.Code != stdhttp.StatusBadRequest {
+		t.Fatalf("malformed event: status = %d, want 400", rec.Code)
+	}
+
+	for i, wantDup := range []bool{false, true} {
+		rec := doSignatureWebhook(t, h, "fake", body, sig)
+		if rec.Code != stdhttp.StatusOK {
+			t.Fatalf("delivery %d: status = %d (body=%s)", i, rec.Code, rec.Body.String())
+		}
+		var dto ucSignature.WebhookDTO
+		if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+			t.Fatalf("bad json: %v", err)
+		}
+		if dto.SignatureStatus != "CANCELLED" || !dto.Applied || dto.Duplicate != wantDup {
+			t.Fatalf("delivery %d: unexpected dto %+v", i, dto)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
-	"math"
+	"reflect"
 	"regexp"
 
+	"amartha-backend-test/pkg/money"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -24,24 +26,41 @@ type CustomValidator struct{ v *validator.Validate }
 func NewValidator() *CustomValidator {
 	v := validator.New()
 
+	// money.Decimal is validated as float64 so the stock required/gte/lte/gt tags
+	// work; intlike/dec2 below re-read the exact decimal via decimalField.
+	v.RegisterCustomTypeFunc(func(f reflect.Value) any {
+		return f.Interface().(money.Decimal).Float64()
+	}, money.Decimal{})
+
 	// borrower id = 32-char lowercase hex
 	_ = v.RegisterValidation("hex32", func(fl validator.FieldLevel) bool {
 		return reHex32.MatchString(fl.Field().String())
 	})
-	// principal must be "integer-like" even if float64
+	// principal must be an integer amount
 	_ = v.RegisterValidation("intlike", func(fl validator.FieldLevel) bool {
-		f := fl.Field().Float()
-		return math.Abs(f-math.Round(f)) < 1e-9
+		return decimalField(fl).IsInteger()
 	})
 	// max 2 decimal places
 	_ = v.RegisterValidation("dec2", func(fl validator.FieldLevel) bool {
-		f := fl.Field().Float()
-		return math.Abs(f-(math.Round(f*100)/100)) < 1e-9
+		return decimalField(fl).Places() <= 2
 	})
 
 	return &CustomValidator{v: v}
 }
 
+// decimalField returns the exact value behind fl: the original money.Decimal
+// when the struct field has that type, otherwise the float's shortest decimal form.
+func decimalField(fl validator.FieldLevel) money.Decimal {
+	if p := reflect.Indirect(fl.Parent()); p.Kind() == reflect.Struct {
+		if f := p.FieldByName(fl.StructFieldName()); f.IsValid() {
+			if d, ok := f.Interface().(money.Decimal); ok {
+				return d
+			}
+		}
+	}
+	return money.NewFromFloat(fl.Field().Float())
+}
+
 func (cv *CustomValidator) Validate(i any) error { return cv.v.Struct(i) }
 
 // Map validator.ValidationErrors → []FieldError with readable messages.
@@ -66,6 +85,12 @@ func ToFieldErrors(err error) []FieldError {
 			out = append(out, FieldError{Field: field, Message: "must be greater than or equal to " + e.Param()})
 		case "lte":
 			out = append(out, FieldError{Field: field, Message: "must be less than or equal to " + e.Param()})
+		case "gt":
+			out = append(out, FieldError{Field: field, Message: "must be greater than " + e.Param()})
+		case "oneof":
+			out = append(out, FieldError{Field: field, Message: "must be one of: " + e.Param()})
+		case "email":
+			out = append(out, FieldError{Field: field, Message: "must be an email address"})
 		default:
 			out = append(out, FieldError{Field: field, Message: e.Tag() + " validation failed"})
 		}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"strings"
 	"testing"
+
+	"amartha-backend-test/pkg/money"
 )
 
 func TestHex32Validation(t *testing.T) {
@@ -48,17 +50,17 @@ func TestHex32Validation(t *testing.T) {
 
 func TestIntLikeValidation(t *testing.T) {
 	type P struct {
-		Amount float64 `validate:"intlike"`
+		Amount money.Decimal `validate:"intlike"`
 	}
 	cv := NewValidator()
 
-	for _, v := range []float64{0, 5_000_000, 100_000_000, 123.0} {
-		if err := cv.Validate(P{Amount: v}); err != nil {
+	for _, v := range []string{"0", "5000000", "100000000", "123.0", "5000000.00"} {
+		if err := cv.Validate(P{Amount: money.MustParse(v)}); err != nil {
 			t.Fatalf("expected intlike OK for %v, got %v", v, err)
 		}
 	}
-	for _, v := range []float64{1.1, 5_000_000.01, -3.14} {
-		err := cv.Validate(P{Amount: v})
+	for _, v := range []string{"1.1", "5000000.01", "-3.14", "100000000.0000001"} {
+		err := cv.Validate(P{Amount: money.MustParse(v)})
 		if err == nil {
 			t.Fatalf("expected intlike error for %v", v)
 		}
@@ -71,17 +73,17 @@ func TestIntLikeValidation(t *testing.T) {
 
 func TestDec2Validation(t *testing.T) {
 	type P struct {
-		Rate float64 `validate:"dec2"`
+		Rate money.Decimal `validate:"dec2"`
 	}
 	cv := NewValidator()
 
-	for _, v := range []float64{1.29, 2.00, 0.9, 1.2} {
-		if err := cv.Validate(P{Rate: v}); err != nil {
+	for _, v := range []string{"1.29", "2.00", "0.9", "1.2", "1.2900"} {
+		if err := cv.Validate(P{Rate: money.MustParse(v)}); err != nil {
 			t.Fatalf("expected dec2 OK for %v, got %v", v, err)
 		}
 	}
-	for _, v := range []float64{1.234, 2.9999} {
-		err := cv.Validate(P{Rate: v})
+	for _, v := range []string{"1.234", "2.9999", "0.290000001"} {
+		err := cv.Validate(P{Rate: money.MustParse(v)})
 		if err == nil {
 			t.Fatalf("expected dec2 error for %v", v)
 		}
@@ -98,6 +100,7 @@ func TestRequiredAndBoundsMapping(t *testing.T) {
 		Min  int     `validate:"gte=10"`
 		Max  int     `validate:"lte=5"`
 		ROI  float64 `validate:"dec2,gte=0.90,lte=1.29"`
+		Amt  float64 `validate:"gt=0"`
 	}
 	cv := NewValidator()
 
@@ -107,6 +110,7 @@ func TestRequiredAndBoundsMapping(t *testing.T) {
 		Min:  9,     // gte=10
 		Max:  6,     // lte=5
 		ROI:  1.333, // dec2 + lte fail, but dec2 will trigger first
+		Amt:  -1,    // gt=0
 	})
 	if err == nil {
 		t.Fatalf("expected validation errors")
@@ -125,12 +129,41 @@ func TestRequiredAndBoundsMapping(t *testing.T) {
 	if !containsFieldMsg(fe, "Max", "less than or equal to 5") {
 		t.Fatalf("missing lte message for Max: %+v", fe)
 	}
+	// gt
+	if !containsFieldMsg(fe, "Amt", "greater than 0") {
+		t.Fatalf("missing gt message for Amt: %+v", fe)
+	}
 	// dec2 mapping should show for ROI
 	if !containsFieldMsg(fe, "ROI", "at most 2 decimal places") {
 		t.Fatalf("missing dec2 message for ROI: %+v", fe)
 	}
 }
 
+func TestDecimalBoundsMapping(t *testing.T) {
+	type P struct {
+		Principal money.Decimal `validate:"required,intlike,gte=5000000,lte=100000000"`
+		Amount    money.Decimal `validate:"required,dec2,gt=0"`
+	}
+	cv := NewValidator()
+
+	if err := cv.Validate(P{Principal: money.NewFromInt(5_000_000), Amount: money.MustParse("0.01")}); err != nil {
+		t.Fatalf("expected valid, got %v", err)
+	}
+
+	fe := ToFieldErrors(cv.Validate(P{Principal: money.MustParse("4999999"), Amount: money.MustParse("-1")}))
+	if !containsFieldMsg(fe, "Principal", "greater than or equal to 5000000") {
+		t.Fatalf("missing gte message for Principal: %+v", fe)
+	}
+	if !containsFieldMsg(fe, "Amount", "greater than 0") {
+		t.Fatalf("missing gt message for Amount: %+v", fe)
+	}
+
+	fe = ToFieldErrors(cv.Validate(P{}))
+	if !containsFieldMsg(fe, "Principal", "is required") || !containsFieldMsg(fe, "Amount", "is required") {
+		t.Fatalf("zero decimals should fail required: %+v", fe)
+	}
+}
+
 func TestToFieldErrors_NonValidation(t *testing.T) {
 	err := errors.New("boom")
 	fe := ToFieldErrors(err)
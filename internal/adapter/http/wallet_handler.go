@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	domainWallet "amartha-backend-test/internal/domain/wallet"
+	ucWallet "amartha-backend-test/internal/usecase/wallet"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WalletHandler struct{ uc *ucWallet.Usecase }
+
+func NewWalletHandler(uc *ucWallet.Usecase) *WalletHandler { return &WalletHandler{uc: uc} }
+
+type walletReq struct {
+	InvestorID string `param:"investor_id" validate:"required,hex32"`
+}
+
+// GetWallet reports the investor's available balance.
+func (h *WalletHandler) GetWallet(c echo.Context) error {
+	var req walletReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid path"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.Get(c.Request().Context(), req.InvestorID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
+
+type topUpCallbackReq struct {
+	GatewayRef string        `json:"gateway_ref" validate:"required,hex32"`
+	InvestorID string        `json:"investor_id" validate:"required,hex32"`
+	Amount     money.Decimal `json:"amount"      validate:"required,dec2,gt=0"`
+	Status     string        `json:"status"      validate:"required,oneof=PAID FAILED"`
+}
+
+// TopUpCallback is the payment gateway's settlement callback. The
+// Ax-Signature HMAC over the raw body is checked before anything is read
+// from it. Replays of the same gateway_ref are acknowledged without
+// crediting twice.
+func (h *WalletHandler) TopUpCallback(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBytes+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "unreadable body"})
+	}
+	if len(body) > maxWebhookBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "callback body too large"})
+	}
+	if err := h.uc.VerifyCallback(body, c.Request().Header.Get(domainWallet.SignatureHeader)); err != nil {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+	}
+
+	var req topUpCallbackReq
+	if err := json.Unmarshal(body, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.TopUp(c.Request().Context(), ucWallet.TopUpInput(req))
+	if err != nil {
+		switch {
+		case errors.Is(err, domainWallet.ErrTopUpMismatch):
+			return c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, domainWallet.ErrInvalidAmount):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+	}
+	return c.JSON(http.StatusOK, dto)
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/internal/domain/uow"
+	domainWallet "amartha-backend-test/internal/domain/wallet"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	ucWallet "amartha-backend-test/internal/usecase/wallet"
+	"amartha-backend-test/pkg/money"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// gatewaySecret signs the top-up callbacks in these tests.
+const gatewaySecret = "gateway-test-secret"
+
+// newWalletHandler wires a wallet over books, with prev as the already-settled top-up (if any).
+func newWalletHandler(balance money.Decimal, prev *domainLedger.Entry) *WalletHandler {
+	return newWalletHandlerOver(&ledgermock.Repo{
+		BalanceFn: func(context.Context, domainLedger.Account) (money.Decimal, error) { return balance, nil },
+		GetByRefFn: func(context.Context, domainLedger.EntryKind, string) (*domainLedger.Entry, error) {
+			if prev == nil {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return prev, nil
+		},
+	})
+}
+
+func newWalletHandlerOver(books *ledgermock.Repo) *WalletHandler {
+	tx := uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+		return fn(uow.Repos{Ledger: books})
+	})
+	return NewWalletHandler(ucWallet.NewUsecase(books, tx).WithGatewaySecret(gatewaySecret))
+}
+
+func signGateway(body []byte) string {
+	m := hmac.New(sha256.New, []byte(gatewaySecret))
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// doTopUp posts body signed with the gateway secret.
+func doTopUp(t *testing.T, h *WalletHandler, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	raw, _ := json.Marshal(body)
+	return doTopUpSigned(t, h, raw, signGateway(raw))
+}
+
+func doTopUpSigned(t *testing.T, h *WalletHandler, raw []byte, sig string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := newEchoWithValidator()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/payments/topups/callback", bytes.NewReader(raw))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if sig != "" {
+		req.Header.Set(domainWallet.SignatureHeader, sig)
+	}
+	rec := httptest.NewRecorder()
+	if err := h.TopUpCallback(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("TopUpCallback error: %v", err)
+	}
+	return rec
+}
+
+func TestGetWallet(t *testing.T) {
+	investor := strings.Repeat("a", 32)
+	h := newWalletHandler(money.MustParse("750000"), nil)
+	for id, want := range map[string]int{investor: stdhttp.StatusOK, "NOPE": stdhttp.StatusUnprocessableEntity} {
+		e := newEchoWithValidator()
+		req := httptest.NewRequest(stdhttp.MethodGet, "/investors/"+id+"/wallet", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("investor_id")
+		c.SetParamValues(id)
+		if err := h.GetWallet(c); err != nil {
+			t.Fatalf("GetWallet error: %v", err)
+		}
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d (body=%s)", id, rec.Code, want, rec.Body.String())
+		}
+		if want == stdhttp.StatusOK {
+			var dto ucWallet.WalletDTO
+			if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+				t.Fatalf("bad json: %v", err)
+			}
+			if dto.InvestorID != investor || dto.Balance.String() != "750000" {
+				t.Fatalf("unexpected dto: %+v", dto)
+			}
+		}
+	}
+}
+
+func TestTopUpCallback(t *testing.T) {
+	investor, ref := strings.Repeat("a", 32), strings.Repeat("f", 32)
+	body := map[string]any{"gateway_ref": ref, "investor_id": investor, "amount": "500000", "status": "PAID"}
+
+	rec := doTopUp(t, newWalletHandler(money.MustParse("500000"), nil), body)
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucWallet.TopUpDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if !dto.Credited || dto.Balance.String() != "500000" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+
+	// the ref was settled earlier for a different amount
+	prev := domainLedger.ForTopUp(investor, ref, money.NewFromInt(1), time.Now())
+	if rec := doTopUp(t, newWalletHandler(money.NewFromInt(1), prev), body); rec.Code != stdhttp.StatusConflict {
+		t.Fatalf("mismatch: status = %d, want 409 (body=%s)", rec.Code, rec.Body.String())
+	}
+
+	for _, bad := range []map[string]any{
+		{"gateway_ref": "x", "investor_id": investor, "amount": "1", "status": "PAID"},
+		{"gateway_ref": ref, "investor_id": investor, "amount": "0.001", "status": "PAID"},
+		{"gateway_ref": ref, "investor_id": investor, "amount": "1", "status": "PENDING"},
+	} {
+		if rec := doTopUp(t, newWalletHandler(money.Zero, nil), bad); rec.Code != stdhttp.StatusUnprocessableEntity {
+			t.Fatalf("%v: status = %d, want 422 (body=%s)", bad, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestTopUpCallback_RejectsUnsignedCallbacks(t *testing.T) {
+	investor, ref := strings.Repeat("a", 32), strings.Repeat("f", 32)
+	raw, _ := json.Marshal(map[string]any{"gateway_ref": ref, "investor_id": investor, "amount": "500000", "status": "PAID"})
+	books := &ledgermock.Repo{
+		PostFn: func(context.Context, *domainLedger.Entry) error {
+			t.Fatalf("an unsigned callback reached the ledger")
+			return nil
+		},
+	}
+	h := newWalletHandlerOver(books)
+
+	forged := hmac.New(sha256.New, []byte("not-the-gateway"))
+	forged.Write(raw)
+	for name, sig := range map[string]string{
+		"missing":  "",
+		"not hex":  "zz",
+		"forged":   hex.EncodeToString(forged.Sum(nil)),
+		"tampered": signGateway(append([]byte(" "), raw...)),
+	} {
+		if rec := doTopUpSigned(t, h, raw, sig); rec.Code != stdhttp.StatusUnauthorized {
+			t.Fatalf("%s: status = %d, want 401 (body=%s)", name, rec.Code, rec.Body.String())
+		}
+	}
+
+	// without a configured secret nothing verifies
+	tx := uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+		return fn(uow.Repos{Ledger: books})
+	})
+	if rec := doTopUpSigned(t, NewWalletHandler(ucWallet.NewUsecase(books, tx)), raw, signGateway(raw)); rec.Code != stdhttp.StatusUnauthorized {
+		t.Fatalf("no secret: status = %d, want 401", rec.Code)
+	}
+}
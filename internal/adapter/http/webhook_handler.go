@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	domainWebhook "amartha-backend-test/internal/domain/webhook"
+	ucWebhook "amartha-backend-test/internal/usecase/webhook"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct{ uc *ucWebhook.Usecase }
+
+func NewWebhookHandler(uc *ucWebhook.Usecase) *WebhookHandler { return &WebhookHandler{uc: uc} }
+
+type subscribeReq struct {
+	PartnerID string   `param:"partner_id" validate:"required,hex32"`
+	URL       string   `json:"url"         validate:"required,url,max=2048"`
+	Events    []string `json:"events"      validate:"required,min=1,dive,required"`
+}
+
+// Subscribe registers a partner endpoint for loan events. The response
+// carries the signing secret; it is not shown again.
+func (h *WebhookHandler) Subscribe(c echo.Context) error {
+	var req subscribeReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.Subscribe(c.Request().Context(), ucWebhook.SubscribeInput(req))
+	if err != nil {
+		switch {
+		case errors.Is(err, domainWebhook.ErrInvalidURL),
+			errors.Is(err, domainWebhook.ErrInsecureURL),
+			errors.Is(err, domainWebhook.ErrPrivateHost):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "URL", Message: err.Error()}},
+			})
+		case errors.Is(err, domainWebhook.ErrInvalidEvents):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "validation failed",
+				Details: []FieldError{{Field: "Events", Message: err.Error()}},
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusCreated, dto)
+}
+
+type partnerReq struct {
+	PartnerID string `param:"partner_id" validate:"required,hex32"`
+}
+
+// ListSubscriptions lists the partner's subscriptions, without secrets.
+func (h *WebhookHandler) ListSubscriptions(c echo.Context) error {
+	var req partnerReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid path"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.ListSubscriptions(c.Request().Context(), req.PartnerID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
+
+type listDeliveriesReq struct {
+	PartnerID string `query:"partner_id" validate:"omitempty,hex32"`
+	Status    string `query:"status"     validate:"omitempty,oneof=PENDING DELIVERED DEAD"`
+	EventID   string `query:"event_id"   validate:"omitempty,hex32"`
+	Cursor    string `query:"cursor"     validate:"omitempty,max=512"`
+	Limit     int    `query:"limit"      validate:"omitempty,gte=1,lte=100"`
+}
+
+// ListDeliveries pages through webhook deliveries, newest first, e.g.
+// ?partner_id=<id>&status=DEAD to find what needs a look.
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	var req listDeliveriesReq
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid query"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "validation failed",
+			Details: ToFieldErrors(err),
+		})
+	}
+
+	dto, err := h.uc.ListDeliveries(c.Request().Context(), ucWebhook.ListDeliveriesInput(req))
+	if err != nil {
+		if errors.Is(err, domainWebhook.ErrInvalidCursor) {
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{Error: err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, dto)
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	domainWebhook "amartha-backend-test/internal/domain/webhook"
+	"amartha-backend-test/internal/testutil/webhookmock"
+	ucWebhook "amartha-backend-test/internal/usecase/webhook"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newWebhookHandler(subs *[]domainWebhook.Subscription, search func(domainWebhook.DeliveryFilter) []domainWebhook.Delivery) *WebhookHandler {
+	repo := &webhookmock.Repo{
+		CreateSubscriptionFn: func(ctx context.Context, s *domainWebhook.Subscription) error {
+			s.ID = uint64(len(*subs) + 1)
+			*subs = append(*subs, *s)
+			return nil
+		},
+		ListSubscriptionsByPartnerFn: func(ctx context.Context, partnerID string) ([]domainWebhook.Subscription, error) {
+			var out []domainWebhook.Subscription
+			for _, s := range *subs {
+				if s.PartnerID == partnerID {
+					out = append(out, s)
+				}
+			}
+			return out, nil
+		},
+		GetSubscriptionByIDFn: func(ctx context.Context, id uint64) (*domainWebhook.Subscription, error) {
+			return &(*subs)[id-1], nil
+		},
+		SearchDeliveriesFn: func(ctx context.Context, f domainWebhook.DeliveryFilter) ([]domainWebhook.Delivery, error) {
+			return search(f), nil
+		},
+	}
+	return NewWebhookHandler(ucWebhook.NewUsecase(repo, nil, nil).WithResolver(coopDNS{}))
+}
+
+// coopDNS resolves coop.example to a public address and nothing else.
+type coopDNS struct{}
+
+func (coopDNS) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	if host == "coop.example" {
+		return []netip.Addr{netip.MustParseAddr("93.184.215.16")}, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func doSubscribe(t *testing.T, h *WebhookHandler, partnerID string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(stdhttp.MethodPost, "/partners/"+partnerID+"/webhooks", mustJSON(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := newEchoWithValidator().NewContext(req, rec)
+	c.SetParamNames("partner_id")
+	c.SetParamValues(partnerID)
+	if err := h.Subscribe(c); err != nil {
+		t.Fatalf("Subscribe error: %v", err)
+	}
+	return rec
+}
+
+func TestWebhookHandler_Subscribe(t *testing.T) {
+	var subs []domainWebhook.Subscription
+	h := newWebhookHandler(&subs, nil)
+	partner := strings.Repeat("a", 32)
+
+	rec := doSubscribe(t, h, partner, map[string]any{"url": "https://coop.example/hooks", "events": []string{"LoanInvested"}})
+	if rec.Code != stdhttp.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body=%s)", rec.Code, rec.Body.String())
+	}
+	var dto ucWebhook.SubscriptionDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if dto.PartnerID != partner || len(dto.Secret) != 64 || len(dto.Events) != 1 || dto.Events[0] != "LoanInvested" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+
+	// the listing hides the secret
+	req := httptest.NewRequest(stdhttp.MethodGet, "/partners/"+partner+"/webhooks", nil)
+	rec = httptest.NewRecorder()
+	c := newEchoWithValidator().NewContext(req, rec)
+	c.SetParamNames("partner_id")
+	c.SetParamValues(partner)
+	if err := h.ListSubscriptions(c); err != nil {
+		t.Fatalf("ListSubscriptions error: %v", err)
+	}
+	if rec.Code != stdhttp.StatusOK || strings.Contains(rec.Body.String(), dto.Secret) || !strings.Contains(rec.Body.String(), dto.SubscriptionID) {
+		t.Fatalf("list: status = %d, body=%s", rec.Code, rec.Body.String())
+	}
+
+	for name, c := range map[string]struct {
+		partner string
+		body    map[string]any
+		field   string
+	}{
+		"bad partner":   {"P-1", map[string]any{"url": "https://coop.example", "events": []string{"LoanInvested"}}, "PartnerID"},
+		"no url":        {partner, map[string]any{"events": []string{"LoanInvested"}}, "URL"},
+		"ftp url":       {partner, map[string]any{"url": "ftp://coop.example", "events": []string{"LoanInvested"}}, "URL"},
+		"http url":      {partner, map[string]any{"url": "http://coop.example", "events": []string{"LoanInvested"}}, "URL"},
+		"metadata url":  {partner, map[string]any{"url": "https://169.254.169.254/latest", "events": []string{"LoanInvested"}}, "URL"},
+		"no events":     {partner, map[string]any{"url": "https://coop.example", "events": []string{}}, "Events"},
+		"unknown event": {partner, map[string]any{"url": "https://coop.example", "events": []string{"LoanDefaulted"}}, "Events"},
+	} {
+		rec := doSubscribe(t, h, c.partner, c.body)
+		if rec.Code != stdhttp.StatusUnprocessableEntity {
+			t.Fatalf("%s: status = %d, want 422 (body=%s)", name, rec.Code, rec.Body.String())
+		}
+		var er ErrorResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &er)
+		if !containsFieldMsg(er.Details, c.field, "") {
+			t.Fatalf("%s: want an error on %s, got %+v", name, c.field, er)
+		}
+	}
+	if len(subs) != 1 {
+		t.Fatalf("rejected subscriptions were stored: %+v", subs)
+	}
+}
+
+func doListDeliveries(t *testing.T, h *WebhookHandler, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(stdhttp.MethodGet, "/webhooks/deliveries"+query, nil)
+	rec := httptest.NewRecorder()
+	if err := h.ListDeliveries(newEchoWithValidator().NewContext(req, rec)); err != nil {
+		t.Fatalf("ListDeliveries error: %v", err)
+	}
+	return rec
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	partner := strings.Repeat("a", 32)
+	subs := []domainWebhook.Subscription{{ID: 1, SubscriptionID: strings.Repeat("5", 32), PartnerID: partner}}
+	code, msg := 503, "partner answered 503"
+	var got domainWebhook.DeliveryFilter
+	h := newWebhookHandler(&subs, func(f domainWebhook.DeliveryFilter) []domainWebhook.Delivery {
+		got = f
+		return []domainWebhook.Delivery{{
+			ID: 7, DeliveryID: strings.Repeat("d", 32), SubscriptionID: 1, PartnerID: partner,
+			EventID: strings.Repeat("e", 32), EventType: "LoanInvested", Status: domainWebhook.StatusDead,
+			Attempts: 12, LastStatusCode: &code, LastError: &msg, NextAttemptAt: time.Now(),
+		}}
+	})
+
+	cursor := base64.RawURLEncoding.EncodeToString([]byte("42"))
+	rec := doListDeliveries(t, h, "?partner_id="+partner+"&status=DEAD&limit=10&cursor="+cursor)
+	if rec.Code != stdhttp.StatusOK {
+		t.Fatalf("status = %d, want 200 (body=%s)", rec.Code, rec.Body.String())
+	}
+	if got.PartnerID != partner || got.Status != domainWebhook.StatusDead || got.BeforeID != 42 || got.Limit != 11 {
+		t.Fatalf("filter = %+v", got)
+	}
+	var dto ucWebhook.DeliveryListDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &dto); err != nil {
+		t.Fatalf("bad json: %v", err)
+	}
+	if len(dto.Items) != 1 || dto.NextCursor != "" {
+		t.Fatalf("unexpected page: %+v", dto)
+	}
+	if d := dto.Items[0]; d.SubscriptionID != subs[0].SubscriptionID || d.Status != "DEAD" || d.LastError != msg ||
+		*d.LastStatusCode != 503 || d.NextAttemptAt != nil {
+		t.Fatalf("unexpected item: %+v", d)
+	}
+
+	for query, want := range map[string]int{
+		"?status=FAILED":     stdhttp.StatusUnprocessableEntity,
+		"?partner_id=P-1":    stdhttp.StatusUnprocessableEntity,
+		"?limit=101":         stdhttp.StatusUnprocessableEntity,
+		"?cursor=not-an-id!": stdhttp.StatusUnprocessableEntity,
+		"":                   stdhttp.StatusOK,
+	} {
+		if rec := doListDeliveries(t, h, query); rec.Code != want {
+			t.Fatalf("%q: status = %d, want %d (body=%s)", query, rec.Code, want, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// WIB is Asia/Jakarta (UTC+7, no DST); ops days and due dates are WIB calendar days.
+var WIB = time.FixedZone("WIB", 7*60*60)
+
+// Daily runs Run once a day at Hour:Minute in Loc until ctx is cancelled.
+// Runs are sequential: a slow run delays, never overlaps, the next one.
+type Daily struct {
+	Name   string
+	Hour   int
+	Minute int
+	Loc    *time.Location
+	Run    func(ctx context.Context, now time.Time) error
+
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// Start blocks; call it in its own goroutine.
+func (d *Daily) Start(ctx context.Context) {
+	now, after := d.now, d.after
+	if now == nil {
+		now = time.Now
+	}
+	if after == nil {
+		after = time.After
+	}
+	for ctx.Err() == nil {
+		at := nextRun(now().In(d.Loc), d.Hour, d.Minute)
+		log.Printf("job %s: next run at %s", d.Name, at.Format(time.RFC3339))
+		select {
+		case <-ctx.Done():
+			return
+		case <-after(time.Until(at)):
+		}
+		started := now().In(d.Loc)
+		if err := d.Run(ctx, started); err != nil {
+			log.Printf("job %s: %v", d.Name, err)
+			continue
+		}
+		log.Printf("job %s: done in %s", d.Name, time.Since(started).Round(time.Millisecond))
+	}
+}
+
+// nextRun returns the first hour:minute strictly after now, in now's location.
+func nextRun(now time.Time, hour, minute int) time.Time {
+	y, m, d := now.Date()
+	at := time.Date(y, m, d, hour, minute, 0, 0, now.Location())
+	if !at.After(now) {
+		at = time.Date(y, m, d+1, hour, minute, 0, 0, now.Location())
+	}
+	return at
+}
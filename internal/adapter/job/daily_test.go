@@ -0,0 +1,60 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextRun(t *testing.T) {
+	cases := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{time.Date(2025, 10, 1, 0, 10, 0, 0, WIB), time.Date(2025, 10, 1, 0, 30, 0, 0, WIB)},
+		{time.Date(2025, 10, 1, 0, 30, 0, 0, WIB), time.Date(2025, 10, 2, 0, 30, 0, 0, WIB)},
+		{time.Date(2025, 12, 31, 23, 0, 0, 0, WIB), time.Date(2026, 1, 1, 0, 30, 0, 0, WIB)},
+	}
+	for _, tt := range cases {
+		if got := nextRun(tt.now, 0, 30); !got.Equal(tt.want) {
+			t.Errorf("nextRun(%s) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestDaily_RunsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC) // 07:00 WIB
+	var runs []time.Time
+	d := &Daily{
+		Name: "test", Hour: 0, Minute: 30, Loc: WIB,
+		Run: func(ctx context.Context, now time.Time) error {
+			runs = append(runs, now)
+			if len(runs) == 2 {
+				cancel()
+			}
+			return errors.New("failures are logged, not fatal")
+		},
+		now: func() time.Time { return clock },
+		after: func(time.Duration) <-chan time.Time {
+			clock = clock.Add(24 * time.Hour)
+			ch := make(chan time.Time, 1)
+			ch <- clock
+			return ch
+		},
+	}
+
+	done := make(chan struct{})
+	go func() { d.Start(ctx); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after cancel")
+	}
+	if len(runs) != 2 || runs[0].Location() != WIB {
+		t.Fatalf("runs = %v", runs)
+	}
+}
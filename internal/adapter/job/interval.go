@@ -0,0 +1,40 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Interval runs Run every Every until ctx is cancelled. The wait starts when
+// a run ends, so a slow run delays, never overlaps, the next one.
+type Interval struct {
+	Name  string
+	Every time.Duration
+	Run   func(ctx context.Context, now time.Time) error
+
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// Start blocks; call it in its own goroutine.
+func (j *Interval) Start(ctx context.Context) {
+	now, after := j.now, j.after
+	if now == nil {
+		now = time.Now
+	}
+	if after == nil {
+		after = time.After
+	}
+	log.Printf("job %s: every %s", j.Name, j.Every)
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-after(j.Every):
+		}
+		if err := j.Run(ctx, now().UTC()); err != nil {
+			log.Printf("job %s: %v", j.Name, err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInterval_RunsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := time.Date(2025, 10, 1, 0, 0, 0, 0, WIB)
+	var waits []time.Duration
+	var runs []time.Time
+	j := &Interval{
+		Name: "test", Every: 5 * time.Second,
+		Run: func(ctx context.Context, now time.Time) error {
+			runs = append(runs, now)
+			if len(runs) == 3 {
+				cancel()
+			}
+			return errors.New("failures are logged, not fatal")
+		},
+		now: func() time.Time { return clock },
+		after: func(d time.Duration) <-chan time.Time {
+			waits = append(waits, d)
+			clock = clock.Add(d)
+			ch := make(chan time.Time, 1)
+			ch <- clock
+			return ch
+		},
+	}
+
+	done := make(chan struct{})
+	go func() { j.Start(ctx); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after cancel")
+	}
+	if len(runs) != 3 || runs[2].Location() != time.UTC || runs[2].Sub(runs[0]) != 10*time.Second {
+		t.Fatalf("runs = %v", runs)
+	}
+	for _, w := range waits {
+		if w != 5*time.Second {
+			t.Fatalf("waits = %v", waits)
+		}
+	}
+}
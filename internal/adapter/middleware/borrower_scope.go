@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BorrowerScopeMiddleware lets a request through only when Ax-Borrower-Id
+// names the borrower in the :param path segment, so borrowers can read their
+// own resources and nobody else's. Missing or malformed header → 401,
+// another borrower → 403.
+func BorrowerScopeMiddleware(param string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			caller := strings.TrimSpace(c.Request().Header.Get("Ax-Borrower-Id"))
+			if caller == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "missing Ax-Borrower-Id"})
+			}
+			if !reHex32.MatchString(caller) {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid Ax-Borrower-Id"})
+			}
+			if caller != c.Param(param) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "borrower may only access own resources"})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestBorrowerScopeMiddleware(t *testing.T) {
+	own, other := strings.Repeat("a", 32), strings.Repeat("b", 32)
+	e := echo.New()
+	e.GET("/borrowers/:borrower_id/loans", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, BorrowerScopeMiddleware("borrower_id"))
+
+	for name, tc := range map[string]struct {
+		header string
+		want   int
+	}{
+		"own loans":        {header: " " + own + " ", want: http.StatusNoContent},
+		"missing header":   {header: "", want: http.StatusUnauthorized},
+		"malformed":        {header: "ABC", want: http.StatusUnauthorized},
+		"another borrower": {header: other, want: http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/borrowers/"+own+"/loans", nil)
+		if tc.header != "" {
+			req.Header.Set("Ax-Borrower-Id", tc.header)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("%s: status = %d, want %d (body=%s)", name, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+}
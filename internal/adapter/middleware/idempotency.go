@@ -48,9 +48,15 @@ func (r *respRecorder) WriteHeader(statusCode int) { r.code = statusCode; r.w.Wr
 
 // IdempotencyMiddleware: key = method + route + user id + request id
 // Ax-Request-At **must** be epoch (seconds or ms) OR RFC3339/RFC3339Nano **with** timezone (Z or ±HH:MM).
-func IdempotencyMiddleware(rdb *redis.Client, ttl time.Duration) echo.MiddlewareFunc {
+// Requests any skipper accepts pass through untouched.
+func IdempotencyMiddleware(rdb *redis.Client, ttl time.Duration, skippers ...func(echo.Context) bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			for _, skip := range skippers {
+				if skip(c) {
+					return next(c)
+				}
+			}
 			req := c.Request()
 			method := req.Method
 
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/labstack/echo/v4"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -80,3 +81,17 @@ func saveFinal(ctx context.Context, rdb *redis.Client, key string, entry idempEn
 	payload, _ := json.Marshal(entry)
 	return rdb.Set(ctx, key, payload, ttl).Err()
 }
+
+// PathPrefixSkipper skips requests under any of prefixes, e.g. inbound
+// webhooks and gateway callbacks that are verified by the sender's HMAC and
+// deduplicated on the sender's reference instead of our Ax-* headers.
+func PathPrefixSkipper(prefixes ...string) func(echo.Context) bool {
+	return func(c echo.Context) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(c.Request().URL.Path, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
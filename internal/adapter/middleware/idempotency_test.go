@@ -73,6 +73,30 @@ func Test_BypassOnGET_NoHeadersRequired(t *testing.T) {
 	}
 }
 
+func Test_Skipper_BypassesWebhooks(t *testing.T) {
+	mr, rdb := newMiniredisClient(t)
+	defer mr.Close()
+	e := echo.New()
+	e.Use(IdempotencyMiddleware(rdb, 30*time.Second, PathPrefixSkipper("/webhooks/", "/payments/topups/callback")))
+	e.POST("/webhooks/signature/:provider", okCreatedHandler)
+	e.POST("/payments/topups/callback", okCreatedHandler)
+	e.POST("/loans", okCreatedHandler)
+
+	// no Ax-* headers at all
+	if rec := doReq(t, e, http.MethodPost, "/webhooks/signature/fake", mkJSONBody(t, map[string]string{}), nil); rec.Code != http.StatusCreated {
+		t.Fatalf("webhook: expected 201, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if rec := doReq(t, e, http.MethodPost, "/payments/topups/callback", mkJSONBody(t, map[string]string{"gateway_ref": "GW-1"}), nil); rec.Code != http.StatusCreated {
+		t.Fatalf("top-up callback: expected 201, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if rec := doReq(t, e, http.MethodPost, "/loans", mkJSONBody(t, map[string]string{}), nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("other routes still need headers: got %d", rec.Code)
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Fatalf("skipped request left idempotency keys: %v", keys)
+	}
+}
+
 func Test_ValidationFailures(t *testing.T) {
 	mr, rdb := newMiniredisClient(t)
 	defer mr.Close()
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"strings"
+
+	"amartha-backend-test/pkg/requestctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestContextMiddleware copies Ax-Request-Id into the request context so
+// usecases can stamp it on audit rows without depending on Echo.
+func RequestContextMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if reqID := strings.TrimSpace(req.Header.Get("Ax-Request-Id")); reqID != "" {
+				c.SetRequest(req.WithContext(requestctx.WithRequestID(req.Context(), reqID)))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"amartha-backend-test/pkg/requestctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequestContextMiddleware(t *testing.T) {
+	var seen string
+	e := echo.New()
+	e.Use(RequestContextMiddleware())
+	e.GET("/probe", func(c echo.Context) error {
+		seen = requestctx.RequestID(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	// Header present → copied into ctx
+	req := httptest.NewRequest(http.MethodGet, "/probe", nil)
+	req.Header.Set("Ax-Request-Id", "  0123456789abcdef0123456789abcdef ")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	if seen != "0123456789abcdef0123456789abcdef" {
+		t.Fatalf("request id in ctx = %q", seen)
+	}
+
+	// Header absent → empty
+	seen = "x"
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/probe", nil))
+	if seen != "" {
+		t.Fatalf("expected empty request id, got %q", seen)
+	}
+}
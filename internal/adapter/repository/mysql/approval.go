@@ -2,8 +2,11 @@ package mysql
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	approvalDomain "amartha-backend-test/internal/domain/approval"
+	"amartha-backend-test/pkg/phash"
 
 	"gorm.io/gorm"
 )
@@ -20,7 +23,15 @@ func (r *ApprovalRepository) Tx(ctx context.Context, fn func(repo *ApprovalRepos
 }
 
 func (r *ApprovalRepository) Create(ctx context.Context, a *approvalDomain.Approval) error {
-	return r.db.WithContext(ctx).Create(a).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(a).Error; err != nil {
+			return err
+		}
+		if bands := approvalDomain.PhotoBandsOf(a); len(bands) > 0 {
+			return tx.Create(&bands).Error
+		}
+		return nil
+	})
 }
 
 func (r *ApprovalRepository) GetByLoanID(ctx context.Context, loanNumericID uint64) (*approvalDomain.Approval, error) {
@@ -31,6 +42,25 @@ func (r *ApprovalRepository) GetByLoanID(ctx context.Context, loanNumericID uint
 	return &out, res.Error
 }
 
+func (r *ApprovalRepository) ListByLoanIDs(ctx context.Context, loanNumericIDs []uint64) ([]approvalDomain.Approval, error) {
+	if len(loanNumericIDs) == 0 {
+		return nil, nil
+	}
+	var out []approvalDomain.Approval
+	res := r.db.WithContext(ctx).
+		Where("loan_id IN ?", loanNumericIDs).
+		Order("loan_id ASC").
+		Find(&out)
+	return out, res.Error
+}
+
+func (r *ApprovalRepository) SoftDeleteByLoanID(ctx context.Context, loanNumericID uint64, by string) error {
+	return r.db.WithContext(ctx).
+		Model(&approvalDomain.Approval{}).
+		Where("loan_id = ?", loanNumericID).
+		Updates(map[string]any{"deleted_at": time.Now().UTC(), "deleted_by": by}).Error
+}
+
 func (r *ApprovalRepository) GetByApprovalID(ctx context.Context, approvalID string) (*approvalDomain.Approval, error) {
 	var out approvalDomain.Approval
 	res := r.db.WithContext(ctx).
@@ -38,3 +68,30 @@ func (r *ApprovalRepository) GetByApprovalID(ctx context.Context, approvalID str
 		First(&out)
 	return &out, res.Error
 }
+
+// FindNearPhoto compares h with the photo hashes of other loans' approvals,
+// soft-deleted ones too: revoking or cancelling must not free a photo for
+// reuse. Candidates come from the band index, so only approvals sharing a
+// band with h are read. The XOR is spelled (a | b) & ~(a & b) because SQLite,
+// used in tests, has no ^ operator.
+func (r *ApprovalRepository) FindNearPhoto(ctx context.Context, h phash.Hash, maxDistance int, exceptLoanID uint64) (*approvalDomain.Approval, error) {
+	if maxDistance >= approvalDomain.PhotoBands {
+		return nil, fmt.Errorf("photo distance %d exceeds the %d-band index", maxDistance, approvalDomain.PhotoBands)
+	}
+	keys := make([][]any, 0, approvalDomain.PhotoBands)
+	for i, v := range phash.Bands(h, approvalDomain.PhotoBands) {
+		keys = append(keys, []any{i, v})
+	}
+	candidates := r.db.WithContext(ctx).
+		Model(&approvalDomain.PhotoBand{}).
+		Select("approval_id").
+		Where("(band, value) IN ?", keys)
+
+	var out approvalDomain.Approval
+	res := r.db.WithContext(ctx).Unscoped().
+		Where("id IN (?)", candidates).
+		Where("loan_id <> ?", exceptLoanID).
+		Where("photo_phash IS NOT NULL AND BIT_COUNT((photo_phash | ?) & ~(photo_phash & ?)) <= ?", h, h, maxDistance).
+		First(&out)
+	return &out, res.Error
+}
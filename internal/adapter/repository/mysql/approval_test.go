@@ -2,12 +2,16 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"math/bits"
 	"testing"
 	"time"
 
 	approvalDomain "amartha-backend-test/internal/domain/approval"
+	"amartha-backend-test/pkg/phash"
 
+	"github.com/mattn/go-sqlite3"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -17,6 +21,8 @@ type approvalSQLite struct {
 	ID                  uint64         `gorm:"primaryKey;column:id;autoIncrement"`
 	ApprovalID          string         `gorm:"size:64;uniqueIndex;column:approval_id"`
 	LoanID              uint64         `gorm:"column:loan_id"`
+	PhotoID             *string        `gorm:"column:photo_id"`
+	PhotoPHash          *int64         `gorm:"column:photo_phash"`
 	PhotoURL            string         `gorm:"column:photo_url"`
 	ValidatorEmployeeID string         `gorm:"column:validator_employee_id"`
 	ApprovalDate        time.Time      `gorm:"column:approval_date"`
@@ -28,15 +34,33 @@ type approvalSQLite struct {
 
 func (approvalSQLite) TableName() string { return "approvals" }
 
+type photoBandSQLite struct {
+	ApprovalID uint64 `gorm:"primaryKey;column:approval_id"`
+	Band       uint8  `gorm:"primaryKey;column:band;index:idx_band_value,priority:1"`
+	Value      uint64 `gorm:"column:value;index:idx_band_value,priority:2"`
+}
+
+func (photoBandSQLite) TableName() string { return "approval_photo_bands" }
+
+// sqlite3_bitcount is the sqlite3 driver plus MySQL's BIT_COUNT, which
+// FindNearPhoto relies on.
+func init() {
+	sql.Register("sqlite3_bitcount", &sqlite3.SQLiteDriver{
+		ConnectHook: func(c *sqlite3.SQLiteConn) error {
+			return c.RegisterFunc("bit_count", func(v int64) int64 { return int64(bits.OnesCount64(uint64(v))) }, true)
+		},
+	})
+}
+
 // openApprovalTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
 func openApprovalTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.New(sqlite.Config{DriverName: "sqlite3_bitcount", DSN: ":memory:"}), &gorm.Config{})
 	if err != nil {
 		t.Fatalf("open sqlite: %v", err)
 	}
 	// IMPORTANT: migrate the sqlite-safe model, NOT the domain model.
-	if err := db.AutoMigrate(&approvalSQLite{}); err != nil {
+	if err := db.AutoMigrate(&approvalSQLite{}, &photoBandSQLite{}); err != nil {
 		t.Fatalf("auto-migrate: %v", err)
 	}
 	return db
@@ -86,6 +110,168 @@ func TestApproval_CreateAndGet(t *testing.T) {
 	}
 }
 
+func TestApproval_ListByLoanIDs(t *testing.T) {
+	db := openApprovalTestDB(t)
+	repo := NewApprovalRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for _, a := range []*approvalDomain.Approval{
+		makeApproval("APR-003", 3, now),
+		makeApproval("APR-001", 1, now),
+		makeApproval("APR-002", 2, now),
+	} {
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := repo.ListByLoanIDs(ctx, []uint64{3, 1, 9})
+	if err != nil {
+		t.Fatalf("ListByLoanIDs: %v", err)
+	}
+	if len(got) != 2 || got[0].ApprovalID != "APR-001" || got[1].ApprovalID != "APR-003" {
+		t.Fatalf("unexpected approvals: %+v", got)
+	}
+	if none, err := repo.ListByLoanIDs(ctx, nil); err != nil || len(none) != 0 {
+		t.Fatalf("empty ids: %+v, %v", none, err)
+	}
+}
+
+func TestApproval_SoftDeleteByLoanID(t *testing.T) {
+	db := openApprovalTestDB(t)
+	repo := NewApprovalRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for _, a := range []*approvalDomain.Approval{makeApproval("APR-001", 1, now), makeApproval("APR-002", 2, now)} {
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if err := repo.SoftDeleteByLoanID(ctx, 1, "EMP-1"); err != nil {
+		t.Fatalf("SoftDeleteByLoanID: %v", err)
+	}
+	if _, err := repo.GetByLoanID(ctx, 1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("deleted approval still visible: %v", err)
+	}
+	if _, err := repo.GetByLoanID(ctx, 2); err != nil {
+		t.Fatalf("other loan's approval: %v", err)
+	}
+
+	var row approvalSQLite
+	if err := db.Unscoped().Where("approval_id = ?", "APR-001").First(&row).Error; err != nil {
+		t.Fatalf("raw row: %v", err)
+	}
+	if !row.DeletedAt.Valid || row.DeletedBy != "EMP-1" {
+		t.Fatalf("deleted_at/by not stamped: %+v", row)
+	}
+}
+
+func TestApproval_FindNearPhoto(t *testing.T) {
+	db := openApprovalTestDB(t)
+	repo := NewApprovalRepository(db)
+	ctx := context.Background()
+
+	hashed := func(id string, loanID uint64, h phash.Hash) *approvalDomain.Approval {
+		a := makeApproval(id, loanID, time.Now())
+		a.PhotoPHash = &h
+		return a
+	}
+	// -1 has every bit set, so it exercises the sign bit too
+	for _, a := range []*approvalDomain.Approval{
+		makeApproval("APR-LEGACY", 1, time.Now()),
+		hashed("APR-NEG", 2, -1),
+		hashed("APR-POS", 3, 0x0f0f),
+	} {
+		if err := repo.Create(ctx, a); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	cases := []struct {
+		h      phash.Hash
+		max    int
+		except uint64
+		want   string
+	}{
+		{0x0f0f, 0, 9, "APR-POS"},
+		{0x0f0e, 1, 9, "APR-POS"},
+		{0x0f00, 3, 9, ""},         // 4 bits away
+		{-1 << 3, 3, 9, "APR-NEG"}, // low 3 bits cleared
+		{0x0f0f, 0, 3, ""},         // the loan's own approval does not count
+	}
+	for _, c := range cases {
+		got, err := repo.FindNearPhoto(ctx, c.h, c.max, c.except)
+		if c.want == "" {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Fatalf("%x/%d: want ErrRecordNotFound, got %+v, %v", c.h, c.max, got, err)
+			}
+			continue
+		}
+		if err != nil || got.ApprovalID != c.want {
+			t.Fatalf("%x/%d: got %+v, %v; want %s", c.h, c.max, got, err, c.want)
+		}
+	}
+}
+
+func TestApproval_FindNearPhoto_RevokedStillCounts(t *testing.T) {
+	db := openApprovalTestDB(t)
+	repo := NewApprovalRepository(db)
+	ctx := context.Background()
+
+	h := phash.Hash(0x7000)
+	a := makeApproval("APR-GONE", 4, time.Now())
+	a.PhotoPHash = &h
+	if err := repo.Create(ctx, a); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// revoke (or cancel) loan 4, then reuse its photo on loan 5
+	if err := repo.SoftDeleteByLoanID(ctx, 4, "EMP-1"); err != nil {
+		t.Fatalf("SoftDeleteByLoanID: %v", err)
+	}
+	got, err := repo.FindNearPhoto(ctx, h^1, 2, 5)
+	if err != nil || got.ApprovalID != "APR-GONE" {
+		t.Fatalf("revoked approval must still block reuse: got %+v, %v", got, err)
+	}
+	// loan 4 itself may be re-approved with its own photo
+	if got, err := repo.FindNearPhoto(ctx, h, 0, 4); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("re-approving loan 4: want ErrRecordNotFound, got %+v, %v", got, err)
+	}
+}
+
+func TestApproval_FindNearPhoto_UsesBandIndex(t *testing.T) {
+	db := openApprovalTestDB(t)
+	repo := NewApprovalRepository(db)
+	ctx := context.Background()
+
+	h := phash.Hash(0x5555_5555_5555_5555)
+	a := makeApproval("APR-BANDED", 6, time.Now())
+	a.PhotoPHash = &h
+	if err := repo.Create(ctx, a); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	var n int64
+	db.Model(&photoBandSQLite{}).Where("approval_id = ?", a.ID).Count(&n)
+	if n != approvalDomain.PhotoBands {
+		t.Fatalf("want %d bands, got %d", approvalDomain.PhotoBands, n)
+	}
+
+	// six scattered bit flips still share a band
+	near := h ^ (1 | 1<<11 | 1<<21 | 1<<31 | 1<<41 | 1<<51)
+	if got, err := repo.FindNearPhoto(ctx, near, 6, 7); err != nil || got.ApprovalID != "APR-BANDED" {
+		t.Fatalf("near hash: got %+v, %v", got, err)
+	}
+	// a hash with no bands is never read
+	db.Where("approval_id = ?", a.ID).Delete(&photoBandSQLite{})
+	if _, err := repo.FindNearPhoto(ctx, h, 0, 7); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("unindexed approval matched: %v", err)
+	}
+	if _, err := repo.FindNearPhoto(ctx, h, approvalDomain.PhotoBands, 7); err == nil {
+		t.Fatalf("distance beyond the index must be refused")
+	}
+}
+
 func TestApproval_NotFound(t *testing.T) {
 	db := openApprovalTestDB(t)
 	repo := NewApprovalRepository(db)
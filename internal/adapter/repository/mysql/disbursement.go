@@ -0,0 +1,47 @@
+package mysql
+
+import (
+	"context"
+
+	disbursementDomain "amartha-backend-test/internal/domain/disbursement"
+
+	"gorm.io/gorm"
+)
+
+type DisbursementRepository struct{ db *gorm.DB }
+
+func NewDisbursementRepository(db *gorm.DB) *DisbursementRepository {
+	return &DisbursementRepository{db: db}
+}
+
+func (r *DisbursementRepository) Create(ctx context.Context, d *disbursementDomain.Disbursement) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+func (r *DisbursementRepository) GetByLoanID(ctx context.Context, loanNumericID uint64) (*disbursementDomain.Disbursement, error) {
+	var out disbursementDomain.Disbursement
+	res := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanNumericID).
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *DisbursementRepository) ListByLoanIDs(ctx context.Context, loanNumericIDs []uint64) ([]disbursementDomain.Disbursement, error) {
+	if len(loanNumericIDs) == 0 {
+		return nil, nil
+	}
+	var out []disbursementDomain.Disbursement
+	res := r.db.WithContext(ctx).
+		Where("loan_id IN ?", loanNumericIDs).
+		Order("loan_id ASC").
+		Find(&out)
+	return out, res.Error
+}
+
+func (r *DisbursementRepository) GetByDisbursementID(ctx context.Context, disbursementID string) (*disbursementDomain.Disbursement, error) {
+	var out disbursementDomain.Disbursement
+	res := r.db.WithContext(ctx).
+		Where("disbursement_id = ?", disbursementID).
+		First(&out)
+	return &out, res.Error
+}
@@ -0,0 +1,128 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	disbursementDomain "amartha-backend-test/internal/domain/disbursement"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests (no enums/engine specifics) ---
+type disbursementSQLite struct {
+	ID                 uint64         `gorm:"primaryKey;column:id;autoIncrement"`
+	DisbursementID     string         `gorm:"size:64;uniqueIndex;column:disbursement_id"`
+	LoanID             uint64         `gorm:"column:loan_id"`
+	SignedAgreementURL string         `gorm:"column:signed_agreement_url"`
+	SignatureProvider  string         `gorm:"column:signature_provider"`
+	SignatureTxID      string         `gorm:"column:signature_tx_id"`
+	SignatureStatus    string         `gorm:"type:text;column:signature_status"`
+	SignedAt           *time.Time     `gorm:"column:signed_at"`
+	DocumentSHA256     *string        `gorm:"column:document_sha256"`
+	OfficerEmployeeID  string         `gorm:"column:officer_employee_id"`
+	DisbursementDate   time.Time      `gorm:"column:disbursement_date"`
+	CreatedAt          time.Time      `gorm:"column:created_at"`
+	UpdatedAt          time.Time      `gorm:"column:updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"column:deleted_at"`
+	DeletedBy          string         `gorm:"column:deleted_by"`
+}
+
+func (disbursementSQLite) TableName() string { return "disbursements" }
+
+// openDisbursementTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openDisbursementTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&disbursementSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func makeDisbursement(disbursementID string, loanNumericID uint64, when time.Time) *disbursementDomain.Disbursement {
+	signedAt := when.UTC()
+	return &disbursementDomain.Disbursement{
+		DisbursementID:     disbursementID,
+		LoanID:             loanNumericID,
+		SignedAgreementURL: "https://example.com/signed.pdf",
+		SignatureProvider:  "fake",
+		SignatureTxID:      "TX-" + disbursementID,
+		SignatureStatus:    disbursementDomain.SignatureSigned,
+		SignedAt:           &signedAt,
+		OfficerEmployeeID:  "EMP-1",
+		DisbursementDate:   when.UTC(),
+	}
+}
+
+func TestDisbursement_CreateAndGet(t *testing.T) {
+	db := openDisbursementTestDB(t)
+	repo := NewDisbursementRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	if err := repo.Create(ctx, makeDisbursement("DSB-001", 777, now)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// By LoanID
+	got, err := repo.GetByLoanID(ctx, 777)
+	if err != nil {
+		t.Fatalf("GetByLoanID: %v", err)
+	}
+	if got.DisbursementID != "DSB-001" || got.SignatureStatus != disbursementDomain.SignatureSigned {
+		t.Errorf("unexpected row by loan: %+v", got)
+	}
+	if got.SignedAt == nil || !got.SignedAt.Equal(now) {
+		t.Errorf("SignedAt not preserved: %v", got.SignedAt)
+	}
+
+	// By DisbursementID
+	gotByID, err := repo.GetByDisbursementID(ctx, "DSB-001")
+	if err != nil || gotByID.LoanID != 777 {
+		t.Fatalf("GetByDisbursementID: got %+v err=%v", gotByID, err)
+	}
+}
+
+func TestDisbursement_ListByLoanIDs(t *testing.T) {
+	db := openDisbursementTestDB(t)
+	repo := NewDisbursementRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for _, d := range []*disbursementDomain.Disbursement{
+		makeDisbursement("DSB-002", 2, now),
+		makeDisbursement("DSB-001", 1, now),
+	} {
+		if err := repo.Create(ctx, d); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := repo.ListByLoanIDs(ctx, []uint64{2, 1, 7})
+	if err != nil {
+		t.Fatalf("ListByLoanIDs: %v", err)
+	}
+	if len(got) != 2 || got[0].DisbursementID != "DSB-001" || got[1].DisbursementID != "DSB-002" {
+		t.Fatalf("unexpected disbursements: %+v", got)
+	}
+}
+
+func TestDisbursement_NotFound(t *testing.T) {
+	db := openDisbursementTestDB(t)
+	repo := NewDisbursementRepository(db)
+	ctx := context.Background()
+
+	if _, err := repo.GetByLoanID(ctx, 999); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound for GetByLoanID, got %v", err)
+	}
+	if _, err := repo.GetByDisbursementID(ctx, "NOPE"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound for GetByDisbursementID, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"context"
+
+	investmentDomain "amartha-backend-test/internal/domain/investment"
+
+	"gorm.io/gorm"
+)
+
+type InvestmentRepository struct{ db *gorm.DB }
+
+func NewInvestmentRepository(db *gorm.DB) *InvestmentRepository {
+	return &InvestmentRepository{db: db}
+}
+
+func (r *InvestmentRepository) Create(ctx context.Context, i *investmentDomain.Investment) error {
+	return r.db.WithContext(ctx).Create(i).Error
+}
+
+func (r *InvestmentRepository) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]investmentDomain.Investment, error) {
+	var out []investmentDomain.Investment
+	res := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanNumericID).
+		Order("id ASC").
+		Find(&out)
+	return out, res.Error
+}
+
+func (r *InvestmentRepository) ListByInvestorID(ctx context.Context, investorID string) ([]investmentDomain.Investment, error) {
+	var out []investmentDomain.Investment
+	res := r.db.WithContext(ctx).
+		Where("investor_id = ?", investorID).
+		Order("id ASC").
+		Find(&out)
+	return out, res.Error
+}
+
+func (r *InvestmentRepository) FundingByLoanIDs(ctx context.Context, loanNumericIDs []uint64) ([]investmentDomain.Funding, error) {
+	if len(loanNumericIDs) == 0 {
+		return nil, nil
+	}
+	var out []investmentDomain.Funding
+	res := r.db.WithContext(ctx).
+		Model(&investmentDomain.Investment{}).
+		Select("loan_id, SUM(amount) AS invested, COUNT(*) AS investments, COUNT(DISTINCT investor_id) AS investors").
+		Where("loan_id IN ? AND status <> ?", loanNumericIDs, investmentDomain.StatusReleased).
+		Group("loan_id").
+		Order("loan_id ASC").
+		Scan(&out)
+	return out, res.Error
+}
+
+func (r *InvestmentRepository) UpdateStatusByLoanID(ctx context.Context, loanNumericID uint64, from, to investmentDomain.Status) error {
+	return r.db.WithContext(ctx).
+		Model(&investmentDomain.Investment{}).
+		Where("loan_id = ? AND status = ?", loanNumericID, from).
+		Update("status", to).Error
+}
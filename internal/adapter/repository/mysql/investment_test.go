@@ -0,0 +1,199 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	investmentDomain "amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests (no CHECK/engine specifics) ---
+type investmentSQLite struct {
+	ID            uint64         `gorm:"primaryKey;column:id;autoIncrement"`
+	InvestmentID  string         `gorm:"size:64;uniqueIndex;column:investment_id"`
+	LoanID        uint64         `gorm:"column:loan_id"`
+	InvestorID    string         `gorm:"column:investor_id"`
+	InvestorEmail *string        `gorm:"column:investor_email"`
+	Amount        float64        `gorm:"column:amount"`
+	Status        string         `gorm:"column:status;default:held"`
+	CreatedAt     time.Time      `gorm:"column:created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at"`
+	DeletedBy     string         `gorm:"column:deleted_by"`
+}
+
+func (investmentSQLite) TableName() string { return "investments" }
+
+// openInvestmentTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openInvestmentTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&investmentSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func makeInvestment(investmentID string, loanNumericID uint64, amount string) *investmentDomain.Investment {
+	return &investmentDomain.Investment{
+		InvestmentID: investmentID,
+		LoanID:       loanNumericID,
+		InvestorID:   "iiiiiiiiiiiiiiiiiiiiiiiiiiiiiiii",
+		Amount:       money.MustParse(amount),
+	}
+}
+
+func TestInvestment_CreateAndList(t *testing.T) {
+	db := openInvestmentTestDB(t)
+	repo := NewInvestmentRepository(db)
+	ctx := context.Background()
+
+	for _, in := range []*investmentDomain.Investment{
+		makeInvestment("INV-001", 777, "1000000"),
+		makeInvestment("INV-002", 777, "2500000.50"),
+		makeInvestment("INV-003", 888, "10000"),
+	} {
+		if err := repo.Create(ctx, in); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := repo.ListByLoanID(ctx, 777)
+	if err != nil {
+		t.Fatalf("ListByLoanID: %v", err)
+	}
+	if len(got) != 2 || got[0].InvestmentID != "INV-001" || got[1].InvestmentID != "INV-002" {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+
+	none, err := repo.ListByLoanID(ctx, 999)
+	if err != nil || len(none) != 0 {
+		t.Fatalf("expected empty list, got %+v err=%v", none, err)
+	}
+}
+
+func TestInvestment_ListByInvestorID(t *testing.T) {
+	db := openInvestmentTestDB(t)
+	repo := NewInvestmentRepository(db)
+	ctx := context.Background()
+
+	other := makeInvestment("INV-002", 777, "5")
+	other.InvestorID = "jjjjjjjjjjjjjjjjjjjjjjjjjjjjjjjj"
+	for _, in := range []*investmentDomain.Investment{
+		makeInvestment("INV-001", 777, "1000000"),
+		other,
+		makeInvestment("INV-003", 888, "10000"),
+	} {
+		if err := repo.Create(ctx, in); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := repo.ListByInvestorID(ctx, "iiiiiiiiiiiiiiiiiiiiiiiiiiiiiiii")
+	if err != nil {
+		t.Fatalf("ListByInvestorID: %v", err)
+	}
+	if len(got) != 2 || got[0].InvestmentID != "INV-001" || got[1].InvestmentID != "INV-003" || got[1].LoanID != 888 {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+}
+
+func TestInvestment_FundingByLoanIDs(t *testing.T) {
+	db := openInvestmentTestDB(t)
+	repo := NewInvestmentRepository(db)
+	ctx := context.Background()
+
+	other := makeInvestment("INV-003", 777, "500000")
+	other.InvestorID = "jjjjjjjjjjjjjjjjjjjjjjjjjjjjjjjj"
+	released := makeInvestment("INV-005", 888, "99")
+	released.Status = investmentDomain.StatusReleased
+	for _, in := range []*investmentDomain.Investment{
+		makeInvestment("INV-001", 777, "1000000"),
+		makeInvestment("INV-002", 777, "2500000.50"),
+		other,
+		makeInvestment("INV-004", 888, "10000"),
+		released,
+	} {
+		if err := repo.Create(ctx, in); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := repo.FundingByLoanIDs(ctx, []uint64{888, 777, 999})
+	if err != nil {
+		t.Fatalf("FundingByLoanIDs: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 loans, got %+v", got)
+	}
+	if f := got[0]; f.LoanID != 777 || f.Invested.String() != "4000000.5" || f.Investments != 3 || f.Investors != 2 {
+		t.Fatalf("loan 777: %+v", f)
+	}
+	if f := got[1]; f.LoanID != 888 || f.Invested.String() != "10000" || f.Investments != 1 || f.Investors != 1 {
+		t.Fatalf("loan 888 (released excluded): %+v", f)
+	}
+}
+
+func TestInvestment_ListByLoanID_SkipsSoftDeleted(t *testing.T) {
+	db := openInvestmentTestDB(t)
+	repo := NewInvestmentRepository(db)
+	ctx := context.Background()
+
+	_ = repo.Create(ctx, makeInvestment("INV-A", 777, "1000000"))
+	deleted := makeInvestment("INV-D", 777, "9999")
+	_ = repo.Create(ctx, deleted)
+	if err := db.Delete(&investmentDomain.Investment{}, deleted.ID).Error; err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+
+	got, err := repo.ListByLoanID(ctx, 777)
+	if err != nil {
+		t.Fatalf("ListByLoanID: %v", err)
+	}
+	if len(got) != 1 || got[0].InvestmentID != "INV-A" || !got[0].Amount.Equal(money.NewFromInt(1_000_000)) {
+		t.Fatalf("expected only the active investment, got %+v", got)
+	}
+}
+
+func TestInvestment_UpdateStatusByLoanID(t *testing.T) {
+	db := openInvestmentTestDB(t)
+	repo := NewInvestmentRepository(db)
+	ctx := context.Background()
+
+	a, b, other := makeInvestment("INV-A", 777, "100"), makeInvestment("INV-B", 777, "200"), makeInvestment("INV-C", 888, "300")
+	for _, in := range []*investmentDomain.Investment{a, b, other} {
+		if err := repo.Create(ctx, in); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	// b was already given back; only held rows convert
+	if err := db.Model(&investmentDomain.Investment{}).Where("id = ?", b.ID).Update("status", investmentDomain.StatusReleased).Error; err != nil {
+		t.Fatalf("seed status: %v", err)
+	}
+
+	if err := repo.UpdateStatusByLoanID(ctx, 777, investmentDomain.StatusHeld, investmentDomain.StatusConverted); err != nil {
+		t.Fatalf("UpdateStatusByLoanID: %v", err)
+	}
+	want := map[string]investmentDomain.Status{
+		"INV-A": investmentDomain.StatusConverted,
+		"INV-B": investmentDomain.StatusReleased,
+	}
+	got, _ := repo.ListByLoanID(ctx, 777)
+	for _, in := range got {
+		if in.Status != want[in.InvestmentID] {
+			t.Fatalf("%s: status %q, want %q", in.InvestmentID, in.Status, want[in.InvestmentID])
+		}
+	}
+	untouched, _ := repo.ListByLoanID(ctx, 888)
+	if len(untouched) != 1 || untouched[0].Status != investmentDomain.StatusHeld {
+		t.Fatalf("other loan must stay held: %+v", untouched)
+	}
+}
@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	"context"
+
+	ledgerDomain "amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LedgerRepository struct{ db *gorm.DB }
+
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// Post inserts the entry and its lines together; callers pass a tx-bound
+// repository (uow.Repos.Ledger) so postings commit with the business change.
+func (r *LedgerRepository) Post(ctx context.Context, e *ledgerDomain.Entry) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if e.EntryID == "" {
+		e.EntryID = id.NewID32()
+	}
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+func (r *LedgerRepository) GetByRef(ctx context.Context, kind ledgerDomain.EntryKind, refID string) (*ledgerDomain.Entry, error) {
+	var out ledgerDomain.Entry
+	res := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("kind = ? AND ref_id = ?", kind, refID).
+		First(&out)
+	return &out, res.Error
+}
+
+// Lock creates the account's ledger_accounts row on first use, then takes
+// SELECT ... FOR UPDATE on it.
+func (r *LedgerRepository) Lock(ctx context.Context, a ledgerDomain.Account) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	db := r.db.WithContext(ctx)
+	rec := ledgerDomain.AccountRecord{Account: a.Type, OwnerID: a.OwnerID}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rec).Error; err != nil {
+		return err
+	}
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account = ? AND owner_id = ?", a.Type, a.OwnerID).
+		First(&rec).Error
+}
+
+func (r *LedgerRepository) Balance(ctx context.Context, a ledgerDomain.Account) (money.Decimal, error) {
+	if err := a.Validate(); err != nil {
+		return money.Zero, err
+	}
+	var net money.Decimal // debits minus credits
+	res := r.db.WithContext(ctx).
+		Model(&ledgerDomain.Line{}).
+		Where("account = ? AND owner_id = ?", a.Type, a.OwnerID).
+		Select("COALESCE(SUM(CASE WHEN direction = ? THEN amount ELSE -amount END), 0)", ledgerDomain.Debit).
+		Scan(&net)
+	if res.Error != nil {
+		return money.Zero, res.Error
+	}
+	return ledgerDomain.Signed(a.Type, ledgerDomain.Debit, net), nil
+}
@@ -0,0 +1,178 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ledgerDomain "amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests ---
+type journalEntrySQLite struct {
+	ID        uint64    `gorm:"primaryKey;column:id;autoIncrement"`
+	EntryID   string    `gorm:"column:entry_id;uniqueIndex"`
+	Kind      string    `gorm:"column:kind;uniqueIndex:ux_je_ref"`
+	RefID     string    `gorm:"column:ref_id;uniqueIndex:ux_je_ref"`
+	LoanID    *uint64   `gorm:"column:loan_id"`
+	PostedAt  time.Time `gorm:"column:posted_at"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (journalEntrySQLite) TableName() string { return "journal_entries" }
+
+type journalLineSQLite struct {
+	ID        uint64  `gorm:"primaryKey;column:id;autoIncrement"`
+	EntryID   uint64  `gorm:"column:entry_id"`
+	Account   string  `gorm:"column:account"`
+	OwnerID   string  `gorm:"column:owner_id"`
+	Direction string  `gorm:"column:direction"`
+	Amount    float64 `gorm:"column:amount"`
+}
+
+func (journalLineSQLite) TableName() string { return "journal_lines" }
+
+type ledgerAccountSQLite struct {
+	Account   string    `gorm:"column:account;primaryKey"`
+	OwnerID   string    `gorm:"column:owner_id;primaryKey"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (ledgerAccountSQLite) TableName() string { return "ledger_accounts" }
+
+// openLedgerTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openLedgerTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&journalEntrySQLite{}, &journalLineSQLite{}, &ledgerAccountSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func TestLedger_PostAndBalance(t *testing.T) {
+	db := openLedgerTestDB(t)
+	repo := NewLedgerRepository(db)
+	ctx := context.Background()
+	at := time.Date(2025, 10, 10, 9, 0, 0, 0, time.UTC)
+	loanID := uint64(7)
+
+	e := ledgerDomain.NewEntry(ledgerDomain.KindInvestment, "inv-1", &loanID, at).
+		Debit(ledgerDomain.Cash, money.MustParse("600.50")).
+		Credit(ledgerDomain.Escrow("ln"), money.MustParse("600.50"))
+	if err := repo.Post(ctx, e); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if e.ID == 0 || len(e.EntryID) != 32 || e.Lines[0].EntryID != e.ID {
+		t.Fatalf("expected ids to be assigned: %+v", e)
+	}
+	if err := repo.Post(ctx, ledgerDomain.NewEntry(ledgerDomain.KindDisbursement, "d-1", &loanID, at).
+		Debit(ledgerDomain.BorrowerReceivable("ln"), money.NewFromInt(400)).
+		Credit(ledgerDomain.Cash, money.NewFromInt(400))); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+
+	for a, want := range map[ledgerDomain.Account]string{
+		ledgerDomain.Cash:                     "200.5",
+		ledgerDomain.Escrow("ln"):             "600.5",
+		ledgerDomain.BorrowerReceivable("ln"): "400",
+		ledgerDomain.Escrow("other"):          "0",
+		ledgerDomain.PlatformFee:              "0",
+	} {
+		got, err := repo.Balance(ctx, a)
+		if err != nil {
+			t.Fatalf("Balance(%+v): %v", a, err)
+		}
+		if got.String() != want {
+			t.Fatalf("Balance(%+v) = %s, want %s", a, got, want)
+		}
+	}
+	if _, err := repo.Balance(ctx, ledgerDomain.Escrow("")); !errors.Is(err, ledgerDomain.ErrInvalidAccount) {
+		t.Fatalf("want ErrInvalidAccount, got %v", err)
+	}
+}
+
+func TestLedger_RejectsUnbalancedAndImmutable(t *testing.T) {
+	db := openLedgerTestDB(t)
+	repo := NewLedgerRepository(db)
+	ctx := context.Background()
+	at := time.Date(2025, 10, 10, 9, 0, 0, 0, time.UTC)
+
+	bad := ledgerDomain.NewEntry(ledgerDomain.KindInvestment, "inv-1", nil, at).
+		Debit(ledgerDomain.Cash, money.NewFromInt(10)).
+		Credit(ledgerDomain.Escrow("ln"), money.NewFromInt(9))
+	if err := repo.Post(ctx, bad); !errors.Is(err, ledgerDomain.ErrUnbalanced) {
+		t.Fatalf("want ErrUnbalanced, got %v", err)
+	}
+	var n int64
+	db.Table("journal_lines").Count(&n)
+	if n != 0 {
+		t.Fatalf("unbalanced entry must not be written, found %d lines", n)
+	}
+
+	e := ledgerDomain.NewEntry(ledgerDomain.KindInvestment, "inv-1", nil, at).
+		Debit(ledgerDomain.Cash, money.NewFromInt(10)).
+		Credit(ledgerDomain.Escrow("ln"), money.NewFromInt(10))
+	if err := repo.Post(ctx, e); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	// one entry per business event
+	dup := ledgerDomain.NewEntry(ledgerDomain.KindInvestment, "inv-1", nil, at).
+		Debit(ledgerDomain.Cash, money.NewFromInt(10)).
+		Credit(ledgerDomain.Escrow("ln"), money.NewFromInt(10))
+	if err := repo.Post(ctx, dup); err == nil {
+		t.Fatalf("expected unique violation on (kind, ref_id)")
+	}
+
+	e.Lines[0].Amount = money.NewFromInt(11)
+	if err := db.Save(&e.Lines[0]).Error; !errors.Is(err, ledgerDomain.ErrImmutable) {
+		t.Fatalf("update line: want ErrImmutable, got %v", err)
+	}
+	if err := db.Delete(e).Error; !errors.Is(err, ledgerDomain.ErrImmutable) {
+		t.Fatalf("delete entry: want ErrImmutable, got %v", err)
+	}
+}
+
+func TestLedger_GetByRefAndLock(t *testing.T) {
+	db := openLedgerTestDB(t)
+	repo := NewLedgerRepository(db)
+	ctx := context.Background()
+	at := time.Date(2025, 10, 10, 9, 0, 0, 0, time.UTC)
+
+	if err := repo.Post(ctx, ledgerDomain.ForTopUp("alice", "gw-1", money.MustParse("250.75"), at)); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	got, err := repo.GetByRef(ctx, ledgerDomain.KindTopUp, "gw-1")
+	if err != nil {
+		t.Fatalf("GetByRef: %v", err)
+	}
+	if len(got.Lines) != 2 || got.Lines[1].Account != ledgerDomain.AccountInvestorWallet || got.Lines[1].Amount.String() != "250.75" {
+		t.Fatalf("lines not preloaded in order: %+v", got.Lines)
+	}
+	if _, err := repo.GetByRef(ctx, ledgerDomain.KindTopUp, "gw-2"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("want ErrRecordNotFound, got %v", err)
+	}
+
+	// first Lock creates the row, later ones reuse it
+	for i := 0; i < 2; i++ {
+		if err := repo.Lock(ctx, ledgerDomain.InvestorWallet("alice")); err != nil {
+			t.Fatalf("Lock #%d: %v", i, err)
+		}
+	}
+	var n int64
+	db.Table("ledger_accounts").Count(&n)
+	if n != 1 {
+		t.Fatalf("want 1 ledger_accounts row, got %d", n)
+	}
+	if err := repo.Lock(ctx, ledgerDomain.InvestorWallet("")); !errors.Is(err, ledgerDomain.ErrInvalidAccount) {
+		t.Fatalf("want ErrInvalidAccount, got %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package mysql
 
 import (
+	investmentDomain "amartha-backend-test/internal/domain/investment"
 	loanDomain "amartha-backend-test/internal/domain/loan"
 	"context"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -42,6 +45,15 @@ func (r *LoanRepository) GetPendingLoanByBorrowerID(ctx context.Context, borrowe
 	return &out, res.Error
 }
 
+func (r *LoanRepository) GetLatestRejectedLoanByBorrowerID(ctx context.Context, borrowerID string) (*loanDomain.Loan, error) {
+	var out loanDomain.Loan
+	res := r.db.WithContext(ctx).
+		Where("borrower_id = ? AND state = ?", borrowerID, loanDomain.StateRejected).
+		Order("state_updated_at DESC, id DESC").
+		First(&out)
+	return &out, res.Error
+}
+
 func (r *LoanRepository) GetByLoanIDForUpdate(ctx context.Context, loanID string) (*loanDomain.Loan, error) {
 	var out loanDomain.Loan
 	res := r.db.WithContext(ctx).
@@ -50,3 +62,120 @@ func (r *LoanRepository) GetByLoanIDForUpdate(ctx context.Context, loanID string
 		First(&out)
 	return &out, res.Error
 }
+
+func (r *LoanRepository) List(ctx context.Context, f loanDomain.ListFilter) ([]loanDomain.Loan, error) {
+	q := r.db.WithContext(ctx).Where("id > ?", f.AfterID)
+	if f.State != "" {
+		q = q.Where("state = ?", f.State)
+	}
+	if f.DPDBucket != "" {
+		q = q.Where("dpd_bucket = ?", f.DPDBucket)
+	}
+	var out []loanDomain.Loan
+	res := q.Order("id ASC").Limit(f.Limit).Find(&out)
+	return out, res.Error
+}
+
+func (r *LoanRepository) ListByIDs(ctx context.Context, ids []uint64) ([]loanDomain.Loan, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var out []loanDomain.Loan
+	res := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id ASC").Find(&out)
+	return out, res.Error
+}
+
+// detailQuery joins the loan's active approval and disbursement (at most one
+// of each, by their unique keys) and aggregates its unreleased investments,
+// all through the loan_id indexes.
+const detailQuery = `
+SELECT l.*,
+	COALESCE(SUM(i.amount), 0) AS invested,
+	COUNT(i.id) AS investments,
+	COUNT(DISTINCT i.investor_id) AS investors,
+	a.approval_id, a.photo_id, a.photo_url, a.approval_date AS approved_at,
+	d.disbursement_id, d.signed_agreement_url, d.signature_status, d.signed_at,
+	d.disbursement_date AS disbursed_at
+FROM loans l
+LEFT JOIN approvals a ON a.loan_id = l.id AND a.deleted_at IS NULL
+LEFT JOIN disbursements d ON d.loan_id = l.id AND d.deleted_at IS NULL
+LEFT JOIN investments i ON i.loan_id = l.id AND i.deleted_at IS NULL AND i.status <> ?
+WHERE l.loan_id = ? AND l.deleted_at IS NULL
+GROUP BY l.id, a.id, d.id`
+
+func (r *LoanRepository) GetDetailByLoanID(ctx context.Context, loanID string) (*loanDomain.Detail, error) {
+	var out []loanDomain.Detail
+	if err := r.db.WithContext(ctx).Raw(detailQuery, investmentDomain.StatusReleased, loanID).Scan(&out).Error; err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &out[0], nil
+}
+
+// Search filters, orders by (sort column, id) and seeks past f.After, so
+// every page is an index range scan rather than an OFFSET.
+func (r *LoanRepository) Search(ctx context.Context, f loanDomain.SearchFilter) ([]loanDomain.Loan, error) {
+	q := r.db.WithContext(ctx)
+	if f.BorrowerID != "" {
+		// matches idx_loans_borrower_active (borrower_id, deleted_flag) on both columns
+		q = q.Where("borrower_id = ? AND deleted_flag = 0", f.BorrowerID)
+	}
+	if f.State != "" {
+		q = q.Where("state = ?", f.State)
+	}
+	if f.DPDBucket != "" {
+		q = q.Where("dpd_bucket = ?", f.DPDBucket)
+	}
+	if !f.PrincipalMin.IsZero() {
+		q = q.Where("principal >= ?", f.PrincipalMin)
+	}
+	if !f.PrincipalMax.IsZero() {
+		q = q.Where("principal <= ?", f.PrincipalMax)
+	}
+	if !f.CreatedFrom.IsZero() {
+		q = q.Where("created_at >= ?", f.CreatedFrom)
+	}
+	if !f.CreatedTo.IsZero() {
+		q = q.Where("created_at < ?", f.CreatedTo)
+	}
+	if !f.StateUpdatedFrom.IsZero() {
+		q = q.Where("state_updated_at >= ?", f.StateUpdatedFrom)
+	}
+	if !f.StateUpdatedTo.IsZero() {
+		q = q.Where("state_updated_at < ?", f.StateUpdatedTo)
+	}
+
+	// the sort key becomes a column name, so only the known keys get through
+	if !f.Sort.Valid() {
+		return nil, fmt.Errorf("unsupported loan sort %q", f.Sort)
+	}
+	col, cmp, dir := string(f.Sort), ">", "ASC"
+	if f.Desc {
+		cmp, dir = "<", "DESC"
+	}
+	if c := f.After; c != nil {
+		var v any = c.At
+		if f.Sort == loanDomain.SortPrincipal {
+			v = c.Principal
+		}
+		q = q.Where(col+" "+cmp+" ? OR ("+col+" = ? AND id "+cmp+" ?)", v, v, c.ID)
+	}
+
+	var out []loanDomain.Loan
+	res := q.Order(col + " " + dir + ", id " + dir).Limit(f.Limit).Find(&out)
+	return out, res.Error
+}
+
+func (r *LoanRepository) UpdateDelinquency(ctx context.Context, id uint64, dpd uint16, bucket loanDomain.DPDBucket, asOf time.Time) error {
+	y, m, d := asOf.Date()
+	return r.db.WithContext(ctx).
+		Model(&loanDomain.Loan{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"dpd":        dpd,
+			"dpd_bucket": bucket,
+			"dpd_as_of":  time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+		}).Error
+}
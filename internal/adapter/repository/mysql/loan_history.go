@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"context"
+
+	loanDomain "amartha-backend-test/internal/domain/loan"
+
+	"gorm.io/gorm"
+)
+
+type LoanHistoryRepository struct{ db *gorm.DB }
+
+func NewLoanHistoryRepository(db *gorm.DB) *LoanHistoryRepository {
+	return &LoanHistoryRepository{db: db}
+}
+
+func (r *LoanHistoryRepository) Append(ctx context.Context, t *loanDomain.StateTransition) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
+
+func (r *LoanHistoryRepository) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]loanDomain.StateTransition, error) {
+	var out []loanDomain.StateTransition
+	res := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanNumericID).
+		Order("transitioned_at ASC, id ASC").
+		Find(&out)
+	return out, res.Error
+}
@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	loanDomain "amartha-backend-test/internal/domain/loan"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests ---
+type stateTransitionSQLite struct {
+	ID             uint64    `gorm:"primaryKey;column:id;autoIncrement"`
+	LoanID         uint64    `gorm:"column:loan_id"`
+	FromState      string    `gorm:"column:from_state"`
+	ToState        string    `gorm:"column:to_state"`
+	Actor          string    `gorm:"column:actor"`
+	Reason         string    `gorm:"column:reason"`
+	RequestID      string    `gorm:"column:request_id"`
+	TransitionedAt time.Time `gorm:"column:transitioned_at"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+func (stateTransitionSQLite) TableName() string { return "loan_state_transitions" }
+
+// openLoanHistoryTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openLoanHistoryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&stateTransitionSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func TestLoanHistory_AppendAndList(t *testing.T) {
+	db := openLoanHistoryTestDB(t)
+	repo := NewLoanHistoryRepository(db)
+	ctx := context.Background()
+
+	t0 := time.Now().UTC().Add(-time.Hour)
+	rows := []*loanDomain.StateTransition{
+		{LoanID: 7, FromState: loanDomain.StateApproved, ToState: loanDomain.StateInvested, Actor: "INV-1", TransitionedAt: t0.Add(30 * time.Minute)},
+		{LoanID: 7, FromState: loanDomain.StateProposed, ToState: loanDomain.StateApproved, Actor: "EMP-1", RequestID: "REQ-1", TransitionedAt: t0},
+		{LoanID: 8, FromState: loanDomain.StateProposed, ToState: loanDomain.StateRejected, Actor: "EMP-2", TransitionedAt: t0},
+	}
+	for _, r := range rows {
+		if err := repo.Append(ctx, r); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	got, err := repo.ListByLoanID(ctx, 7)
+	if err != nil {
+		t.Fatalf("ListByLoanID: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 rows, got %d", len(got))
+	}
+	// Oldest first regardless of insert order
+	if got[0].ToState != loanDomain.StateApproved || got[0].RequestID != "REQ-1" || got[1].ToState != loanDomain.StateInvested {
+		t.Fatalf("unexpected order/content: %+v", got)
+	}
+}
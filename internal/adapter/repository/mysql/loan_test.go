@@ -3,11 +3,14 @@ package mysql
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	domain "amartha-backend-test/internal/domain/loan"
 	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/money"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -22,13 +25,24 @@ type loanSQLite struct {
 	Principal      float64        `gorm:"column:principal"`
 	Rate           float64        `gorm:"column:rate"`
 	ROI            float64        `gorm:"column:roi"`
+	Tenor          uint16         `gorm:"column:tenor"`
+	TenorUnit      string         `gorm:"column:tenor_unit"`
+	InterestMethod string         `gorm:"column:interest_method"`
+	Outstanding    float64        `gorm:"column:outstanding_balance;default:0"`
+	DPD            uint16         `gorm:"column:dpd;default:0"`
+	DPDBucket      string         `gorm:"column:dpd_bucket;default:current"`
+	DPDAsOf        *time.Time     `gorm:"column:dpd_as_of"`
 	AgreementLink  string         `gorm:"column:agreement_link"`
+	AgreementVer   string         `gorm:"column:agreement_version"`
+	AgreementHash  string         `gorm:"column:agreement_sha256"`
 	State          string         `gorm:"type:text;column:state"` // ← no enum
 	StateUpdatedAt time.Time      `gorm:"column:state_updated_at"`
 	CreatedAt      time.Time      `gorm:"column:created_at"`
 	UpdatedAt      time.Time      `gorm:"column:updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"column:deleted_at"`
 	DeletedBy      string         `gorm:"column:deleted_by"`
+	// mirrors the MySQL generated column; read-only so inserts leave it alone
+	DeletedFlag int `gorm:"column:deleted_flag;->;type:integer GENERATED ALWAYS AS (deleted_at IS NOT NULL) VIRTUAL"`
 }
 
 func (loanSQLite) TableName() string { return "loans" }
@@ -51,9 +65,12 @@ func makeLoan(loanID, borrowerID string) *domain.Loan {
 	return &domain.Loan{
 		LoanID:         loanID,
 		BorrowerID:     borrowerID,
-		Principal:      1_000_000.00,
-		Rate:           0.2200,
-		ROI:            0.1800,
+		Principal:      money.MustParse("1000000.00"),
+		Rate:           money.MustParse("0.2200"),
+		ROI:            money.MustParse("0.1800"),
+		Tenor:          12,
+		TenorUnit:      domain.TenorMonth,
+		InterestMethod: domain.InterestEffective,
 		State:          domain.StateProposed,
 		StateUpdatedAt: time.Now().UTC(),
 	}
@@ -82,6 +99,13 @@ func TestCreateAndGetByLoanID(t *testing.T) {
 	if got.LoanID != loanID || got.BorrowerID != borrower {
 		t.Errorf("unexpected loan: %+v", got)
 	}
+	// decimals survive the round-trip exactly
+	if !got.Principal.Equal(l.Principal) || !got.Rate.Equal(l.Rate) || !got.ROI.Equal(l.ROI) {
+		t.Errorf("decimal mismatch: principal=%s rate=%s roi=%s", got.Principal, got.Rate, got.ROI)
+	}
+	if got.Tenor != 12 || got.TenorUnit != domain.TenorMonth || got.InterestMethod != domain.InterestEffective {
+		t.Errorf("tenor mismatch: %d %s %s", got.Tenor, got.TenorUnit, got.InterestMethod)
+	}
 }
 
 func TestSaveUpdates(t *testing.T) {
@@ -99,6 +123,7 @@ func TestSaveUpdates(t *testing.T) {
 	// Update a field and persist
 	const link = "https://example.com/agreement.pdf"
 	l.AgreementLink = link
+	l.AgreementVersion, l.AgreementSHA256 = "v1", strings.Repeat("0", 64)
 	if err := repo.Save(ctx, l); err != nil {
 		t.Fatalf("Save: %v", err)
 	}
@@ -110,6 +135,109 @@ func TestSaveUpdates(t *testing.T) {
 	if got.AgreementLink != link {
 		t.Errorf("AgreementLink not updated, got=%q want=%q", got.AgreementLink, link)
 	}
+	if got.AgreementVersion != "v1" || got.AgreementSHA256 != strings.Repeat("0", 64) {
+		t.Errorf("agreement version/hash not updated: %q %q", got.AgreementVersion, got.AgreementSHA256)
+	}
+}
+
+func TestListByIDs(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewLoanRepository(db)
+	ctx := context.Background()
+
+	var ids []uint64
+	for i := 0; i < 3; i++ {
+		l := makeLoan(id.NewID32(), id.NewID32())
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ids = append(ids, l.ID)
+	}
+
+	got, err := repo.ListByIDs(ctx, []uint64{ids[2], ids[0], 9999})
+	if err != nil {
+		t.Fatalf("ListByIDs: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != ids[0] || got[1].ID != ids[2] {
+		t.Fatalf("unexpected loans: %+v", got)
+	}
+	if none, err := repo.ListByIDs(ctx, nil); err != nil || len(none) != 0 {
+		t.Fatalf("empty ids: %+v, %v", none, err)
+	}
+}
+
+func TestGetDetailByLoanID(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.AutoMigrate(&approvalSQLite{}, &disbursementSQLite{}, &investmentSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	repo := NewLoanRepository(db)
+	ctx := context.Background()
+
+	l := makeLoan(id.NewID32(), id.NewID32())
+	other := makeLoan(id.NewID32(), id.NewID32())
+	for _, x := range []*domain.Loan{l, other} {
+		if err := repo.Create(ctx, x); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	// proposed: no approval, disbursement or investments yet
+	got, err := repo.GetDetailByLoanID(ctx, l.LoanID)
+	if err != nil {
+		t.Fatalf("GetDetailByLoanID: %v", err)
+	}
+	if got.ID != l.ID || !got.Invested.IsZero() || got.Investments != 0 || got.ApprovalID != nil || got.DisbursementID != nil {
+		t.Fatalf("bare detail: %+v", got)
+	}
+
+	when := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	if err := NewApprovalRepository(db).Create(ctx, makeApproval("AP-1", l.ID, when)); err != nil {
+		t.Fatalf("approval: %v", err)
+	}
+	if err := NewDisbursementRepository(db).Create(ctx, makeDisbursement("DB-1", l.ID, when)); err != nil {
+		t.Fatalf("disbursement: %v", err)
+	}
+	invs := NewInvestmentRepository(db)
+	for i, x := range []struct {
+		loanID   uint64
+		investor string
+		amount   string
+	}{
+		{l.ID, "INV-A", "400000"},
+		{l.ID, "INV-A", "100000.50"},
+		{l.ID, "INV-B", "250000"},
+		{l.ID, "INV-C", "999"},      // released below
+		{other.ID, "INV-A", "5000"}, // another loan
+	} {
+		inv := makeInvestment(fmt.Sprintf("IV-%d", i), x.loanID, x.amount)
+		inv.InvestorID = x.investor
+		if err := invs.Create(ctx, inv); err != nil {
+			t.Fatalf("investment: %v", err)
+		}
+	}
+	if err := db.Exec("UPDATE investments SET status = 'released' WHERE investment_id = 'IV-3'").Error; err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	got, err = repo.GetDetailByLoanID(ctx, l.LoanID)
+	if err != nil {
+		t.Fatalf("GetDetailByLoanID: %v", err)
+	}
+	if !got.Invested.Equal(money.MustParse("750000.50")) || got.Investments != 3 || got.Investors != 2 {
+		t.Fatalf("funding: invested %s, %d investments, %d investors", got.Invested, got.Investments, got.Investors)
+	}
+	if got.ApprovalID == nil || *got.ApprovalID != "AP-1" || got.ApprovedAt == nil || !got.ApprovedAt.Equal(when) {
+		t.Fatalf("approval: %+v", got)
+	}
+	if got.DisbursementID == nil || *got.DisbursementID != "DB-1" || *got.SignatureStatus != "SIGNED" ||
+		got.SignedAt == nil || got.DisbursedAt == nil {
+		t.Fatalf("disbursement: %+v", got)
+	}
+
+	if _, err := repo.GetDetailByLoanID(ctx, "does-not-exist"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("missing loan: want ErrRecordNotFound, got %v", err)
+	}
 }
 
 func TestGetByLoanID_NotFound(t *testing.T) {
@@ -174,6 +302,38 @@ func TestGetPendingLoanByBorrowerID(t *testing.T) {
 	}
 }
 
+func TestGetLatestRejectedLoanByBorrowerID(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewLoanRepository(db)
+	ctx := context.Background()
+
+	b1 := "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	now := time.Now().UTC()
+
+	seed := []loanSQLite{
+		{LoanID: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", BorrowerID: b1, Principal: 1_000_000, State: "rejected", StateUpdatedAt: now.Add(-48 * time.Hour)},
+		{LoanID: "cccccccccccccccccccccccccccccccc", BorrowerID: b1, Principal: 1_000_000, State: "rejected", StateUpdatedAt: now.Add(-1 * time.Hour)},
+		{LoanID: "dddddddddddddddddddddddddddddddd", BorrowerID: b1, Principal: 1_000_000, State: "proposed", StateUpdatedAt: now},
+	}
+	for i := range seed {
+		if err := db.Create(&seed[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := repo.GetLatestRejectedLoanByBorrowerID(ctx, b1)
+	if err != nil {
+		t.Fatalf("GetLatestRejectedLoanByBorrowerID: %v", err)
+	}
+	if got.LoanID != "cccccccccccccccccccccccccccccccc" || got.State != domain.StateRejected {
+		t.Fatalf("unexpected loan: %+v", got)
+	}
+
+	if _, err := repo.GetLatestRejectedLoanByBorrowerID(ctx, "eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
 func TestTx_Commit(t *testing.T) {
 	db := openTestDB(t)
 	repo := NewLoanRepository(db)
@@ -214,3 +374,154 @@ func TestTx_Rollback(t *testing.T) {
 		t.Fatalf("expected not found after rollback, got %v", err)
 	}
 }
+
+func TestList_FiltersAndKeyset(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewLoanRepository(db)
+	ctx := context.Background()
+
+	// 5 loans: #2 and #4 disbursed in 90+, #3 disbursed current, others proposed
+	var ids []uint64
+	for i := 0; i < 5; i++ {
+		l := makeLoan(id.NewID32(), id.NewID32())
+		if i > 0 && i < 4 {
+			l.State = domain.StateDisbursed
+		}
+		if i == 1 || i == 3 {
+			l.DPDBucket = domain.DPD90Plus
+		}
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ids = append(ids, l.ID)
+	}
+
+	got, err := repo.List(ctx, domain.ListFilter{State: domain.StateDisbursed, DPDBucket: domain.DPD90Plus, Limit: 10})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != ids[1] || got[1].ID != ids[3] {
+		t.Fatalf("unexpected 90+ loans: %+v", got)
+	}
+
+	page, err := repo.List(ctx, domain.ListFilter{State: domain.StateDisbursed, AfterID: ids[1], Limit: 1})
+	if err != nil || len(page) != 1 || page[0].ID != ids[2] {
+		t.Fatalf("keyset page: %+v err=%v", page, err)
+	}
+}
+
+func TestUpdateDelinquency_OnlyTouchesDPDColumns(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewLoanRepository(db)
+	ctx := context.Background()
+
+	l := makeLoan(id.NewID32(), id.NewID32())
+	l.State = domain.StateDisbursed
+	if err := repo.Create(ctx, l); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	asOf := time.Date(2025, 11, 15, 23, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	if err := repo.UpdateDelinquency(ctx, l.ID, 45, domain.DPD31To60, asOf); err != nil {
+		t.Fatalf("UpdateDelinquency: %v", err)
+	}
+	got, err := repo.GetByLoanID(ctx, l.LoanID)
+	if err != nil {
+		t.Fatalf("GetByLoanID: %v", err)
+	}
+	if got.DPD != 45 || got.DPDBucket != domain.DPD31To60 || got.State != domain.StateDisbursed {
+		t.Fatalf("unexpected loan after update: %+v", got)
+	}
+	if got.DPDAsOf == nil || got.DPDAsOf.Format("2006-01-02") != "2025-11-15" {
+		t.Fatalf("dpd_as_of = %v, want 2025-11-15", got.DPDAsOf)
+	}
+}
+
+func TestSearch_FiltersSortAndCursor(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewLoanRepository(db)
+	ctx := context.Background()
+
+	// 6 loans for two borrowers, created a day apart, principal 1M..6M, with
+	// #3 and #6 sharing a principal to exercise the id tie-break
+	alice, bob := id.NewID32(), id.NewID32()
+	base := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	var loans []*domain.Loan
+	for i := 0; i < 6; i++ {
+		l := makeLoan(id.NewID32(), alice)
+		if i%2 == 1 {
+			l.BorrowerID = bob
+		}
+		l.Principal = money.NewFromInt(int64(i+1) * 1_000_000)
+		if i == 5 {
+			l.Principal = money.NewFromInt(3_000_000)
+		}
+		l.CreatedAt = base.AddDate(0, 0, i)
+		l.StateUpdatedAt = l.CreatedAt
+		if err := repo.Create(ctx, l); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		loans = append(loans, l)
+	}
+	// a deleted loan never shows up
+	gone := makeLoan(id.NewID32(), alice)
+	if err := repo.Create(ctx, gone); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := db.Delete(gone).Error; err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	ids := func(ls []domain.Loan) []uint64 {
+		out := make([]uint64, len(ls))
+		for i, l := range ls {
+			out[i] = l.ID
+		}
+		return out
+	}
+	want := func(idx ...int) []uint64 {
+		out := make([]uint64, len(idx))
+		for i, n := range idx {
+			out[i] = loans[n].ID
+		}
+		return out
+	}
+	check := func(name string, f domain.SearchFilter, expect []uint64) {
+		t.Helper()
+		got, err := repo.Search(ctx, f)
+		if err != nil {
+			t.Fatalf("%s: Search: %v", name, err)
+		}
+		if fmt.Sprint(ids(got)) != fmt.Sprint(expect) {
+			t.Fatalf("%s: got ids %v, want %v", name, ids(got), expect)
+		}
+	}
+
+	check("borrower, newest first", domain.SearchFilter{BorrowerID: alice, Sort: domain.SortCreatedAt, Desc: true, Limit: 10}, want(4, 2, 0))
+	check("principal range", domain.SearchFilter{PrincipalMin: money.NewFromInt(2_000_000), PrincipalMax: money.NewFromInt(4_000_000), Sort: domain.SortPrincipal, Limit: 10}, want(1, 2, 5, 3))
+	check("created range", domain.SearchFilter{CreatedFrom: base.AddDate(0, 0, 1), CreatedTo: base.AddDate(0, 0, 3), Sort: domain.SortCreatedAt, Limit: 10}, want(1, 2))
+	check("state updated range", domain.SearchFilter{StateUpdatedFrom: base.AddDate(0, 0, 4), Sort: domain.SortStateUpdatedAt, Limit: 10}, want(4, 5))
+
+	// walk principal descending two at a time: 5M, 4M | 3M(#6), 3M(#3) | 2M, 1M
+	f := domain.SearchFilter{Sort: domain.SortPrincipal, Desc: true, Limit: 2}
+	var walked []uint64
+	for page := 0; page < 4; page++ {
+		got, err := repo.Search(ctx, f)
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		walked = append(walked, ids(got)...)
+		if len(got) < f.Limit {
+			break
+		}
+		c := domain.CursorAt(&got[len(got)-1], f.Sort)
+		f.After = &c
+	}
+	if fmt.Sprint(walked) != fmt.Sprint(want(4, 3, 5, 2, 1, 0)) {
+		t.Fatalf("cursor walk = %v, want %v", walked, want(4, 3, 5, 2, 1, 0))
+	}
+
+	if _, err := repo.Search(ctx, domain.SearchFilter{Sort: "id; DROP TABLE loans", Limit: 1}); err == nil {
+		t.Fatalf("unknown sort must be refused")
+	}
+}
@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	notificationDomain "amartha-backend-test/internal/domain/notification"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct{ db *gorm.DB }
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) Get(ctx context.Context, eventID, recipientID string) (*notificationDomain.Notification, error) {
+	var out notificationDomain.Notification
+	res := r.db.WithContext(ctx).
+		Where("event_id = ? AND recipient_id = ?", eventID, recipientID).
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, n *notificationDomain.Notification) error {
+	return r.db.WithContext(ctx).Create(n).Error
+}
+
+func (r *NotificationRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]notificationDomain.Notification, error) {
+	var out []notificationDomain.Notification
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", notificationDomain.StatusPending, now.UTC()).
+		Order("id ASC").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *NotificationRepository) Save(ctx context.Context, n *notificationDomain.Notification) error {
+	return r.db.WithContext(ctx).Save(n).Error
+}
+
+func (r *NotificationRepository) ListByLoanID(ctx context.Context, loanID string) ([]notificationDomain.Notification, error) {
+	var out []notificationDomain.Notification
+	err := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanID).
+		Order("id ASC").
+		Find(&out).Error
+	return out, err
+}
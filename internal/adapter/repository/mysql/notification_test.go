@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	notificationDomain "amartha-backend-test/internal/domain/notification"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests (no engine specifics) ---
+type notificationSQLite struct {
+	ID             uint64     `gorm:"primaryKey;column:id;autoIncrement"`
+	NotificationID string     `gorm:"size:32;uniqueIndex;column:notification_id"`
+	EventID        string     `gorm:"column:event_id;uniqueIndex:ux_notif"`
+	Template       string     `gorm:"column:template"`
+	LoanID         string     `gorm:"column:loan_id"`
+	RecipientID    string     `gorm:"column:recipient_id;uniqueIndex:ux_notif"`
+	Email          *string    `gorm:"column:email"`
+	Data           string     `gorm:"column:data"`
+	Status         string     `gorm:"column:status"`
+	Attempts       uint16     `gorm:"column:attempts"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at"`
+	LastError      *string    `gorm:"column:last_error"`
+	SentAt         *time.Time `gorm:"column:sent_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at"`
+}
+
+func (notificationSQLite) TableName() string { return "notifications" }
+
+// openNotificationTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openNotificationTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&notificationSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func TestNotification_Lifecycle(t *testing.T) {
+	repo := NewNotificationRepository(openNotificationTestDB(t))
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	loanA, loanB := strings.Repeat("a", 32), strings.Repeat("b", 32)
+	email := "investor@example.com"
+
+	mk := func(c, loanID, event, recipient string, status notificationDomain.Status, next time.Time) *notificationDomain.Notification {
+		return &notificationDomain.Notification{
+			NotificationID: strings.Repeat(c, 32), EventID: strings.Repeat(event, 32), Template: notificationDomain.TemplateLoanInvested,
+			LoanID: loanID, RecipientID: strings.Repeat(recipient, 32), Email: &email, Data: `{}`, Status: status, NextAttemptAt: next,
+		}
+	}
+	due := mk("1", loanA, "e", "i", notificationDomain.StatusPending, now.Add(-time.Minute))
+	later := mk("2", loanB, "f", "i", notificationDomain.StatusPending, now.Add(time.Minute))
+	skipped := mk("3", loanA, "e", "j", notificationDomain.StatusSkipped, now)
+	skipped.Email = nil
+	for _, n := range []*notificationDomain.Notification{due, later, skipped} {
+		if err := repo.Create(ctx, n); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	dup := *due
+	dup.ID, dup.NotificationID = 0, strings.Repeat("9", 32)
+	if err := repo.Create(ctx, &dup); err == nil {
+		t.Fatalf("same event and recipient twice should violate the unique key")
+	}
+
+	if got, err := repo.Get(ctx, strings.Repeat("e", 32), strings.Repeat("j", 32)); err != nil || got.ID != skipped.ID || got.Email != nil {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	if _, err := repo.Get(ctx, strings.Repeat("f", 32), strings.Repeat("j", 32)); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("missing: want ErrRecordNotFound, got %v", err)
+	}
+
+	got, err := repo.ListDue(ctx, now, 10)
+	if err != nil || len(got) != 1 || got[0].ID != due.ID {
+		t.Fatalf("ListDue = %+v, %v", got, err)
+	}
+	got[0].Status, got[0].Attempts, got[0].SentAt = notificationDomain.StatusSent, 1, &now
+	if err := repo.Save(ctx, &got[0]); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got, _ := repo.ListDue(ctx, now.Add(time.Hour), 10); len(got) != 1 || got[0].ID != later.ID {
+		t.Fatalf("after save = %+v", got)
+	}
+
+	list, err := repo.ListByLoanID(ctx, loanA)
+	if err != nil || len(list) != 2 || list[0].ID != due.ID || list[0].Status != notificationDomain.StatusSent || list[1].ID != skipped.ID {
+		t.Fatalf("ListByLoanID = %+v, %v", list, err)
+	}
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	outboxDomain "amartha-backend-test/internal/domain/outbox"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct{ db *gorm.DB }
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository { return &OutboxRepository{db: db} }
+
+func (r *OutboxRepository) Append(ctx context.Context, m *outboxDomain.Message) error {
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+func (r *OutboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]outboxDomain.Message, error) {
+	var out []outboxDomain.Message
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", outboxDomain.StatusPending, now.UTC()).
+		Order("id ASC").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *OutboxRepository) Save(ctx context.Context, m *outboxDomain.Message) error {
+	return r.db.WithContext(ctx).Save(m).Error
+}
@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	outboxDomain "amartha-backend-test/internal/domain/outbox"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests (no engine specifics) ---
+type outboxSQLite struct {
+	ID            uint64     `gorm:"primaryKey;column:id;autoIncrement"`
+	EventID       string     `gorm:"size:32;uniqueIndex;column:event_id"`
+	EventType     string     `gorm:"column:event_type"`
+	AggregateID   string     `gorm:"column:aggregate_id"`
+	Payload       string     `gorm:"column:payload"`
+	Status        string     `gorm:"column:status"`
+	Attempts      uint16     `gorm:"column:attempts"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at"`
+	LastError     *string    `gorm:"column:last_error"`
+	SentAt        *time.Time `gorm:"column:sent_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at"`
+}
+
+func (outboxSQLite) TableName() string { return "outbox" }
+
+// openOutboxTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openOutboxTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&outboxSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func TestOutbox_AppendListDueSave(t *testing.T) {
+	repo := NewOutboxRepository(openOutboxTestDB(t))
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	msg := func(c string, status outboxDomain.Status, next time.Time) *outboxDomain.Message {
+		return &outboxDomain.Message{
+			EventID: strings.Repeat(c, 32), EventType: outboxDomain.LoanApproved, AggregateID: strings.Repeat("1", 32),
+			Payload: `{}`, Status: status, NextAttemptAt: next,
+		}
+	}
+	due := msg("a", outboxDomain.StatusPending, now.Add(-time.Minute))
+	later := msg("b", outboxDomain.StatusPending, now.Add(time.Minute))
+	sent := msg("c", outboxDomain.StatusSent, now.Add(-time.Hour))
+	due2 := msg("d", outboxDomain.StatusPending, now)
+	for _, m := range []*outboxDomain.Message{due, later, sent, due2} {
+		if err := repo.Append(ctx, m); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	dup := *due
+	dup.ID = 0
+	if err := repo.Append(ctx, &dup); err == nil {
+		t.Fatalf("same event id twice should violate the unique key")
+	}
+
+	got, err := repo.ListDue(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("ListDue: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != due.ID || got[1].ID != due2.ID {
+		t.Fatalf("ListDue = %+v, want the two due pending rows oldest first", got)
+	}
+	if got, _ := repo.ListDue(ctx, now, 1); len(got) != 1 || got[0].ID != due.ID {
+		t.Fatalf("ListDue limit 1 = %+v", got)
+	}
+
+	m := got[0]
+	m.Status, m.Attempts, m.SentAt = outboxDomain.StatusSent, 1, &now
+	if err := repo.Save(ctx, &m); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, _ = repo.ListDue(ctx, now.Add(time.Hour), 10)
+	if len(got) != 2 || got[0].ID != later.ID || got[1].ID != due2.ID {
+		t.Fatalf("after save = %+v, want the remaining pending rows", got)
+	}
+}
@@ -0,0 +1,43 @@
+package mysql
+
+import (
+	"context"
+
+	payoutDomain "amartha-backend-test/internal/domain/payout"
+
+	"gorm.io/gorm"
+)
+
+type PayoutRepository struct{ db *gorm.DB }
+
+func NewPayoutRepository(db *gorm.DB) *PayoutRepository {
+	return &PayoutRepository{db: db}
+}
+
+func (r *PayoutRepository) CreateBatch(ctx context.Context, items []payoutDomain.Payout) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&items).Error
+}
+
+func (r *PayoutRepository) SumByInvestorID(ctx context.Context, investorID string) ([]payoutDomain.Sum, error) {
+	var out []payoutDomain.Sum
+	res := r.db.WithContext(ctx).
+		Model(&payoutDomain.Payout{}).
+		Select("investment_id, SUM(principal) AS principal, SUM(interest) AS interest, SUM(total) AS total").
+		Where("investor_id = ?", investorID).
+		Group("investment_id").
+		Order("investment_id ASC").
+		Scan(&out)
+	return out, res.Error
+}
+
+func (r *PayoutRepository) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]payoutDomain.Payout, error) {
+	var out []payoutDomain.Payout
+	res := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanNumericID).
+		Order("repayment_id ASC, investment_id ASC").
+		Find(&out)
+	return out, res.Error
+}
@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	payoutDomain "amartha-backend-test/internal/domain/payout"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests ---
+type payoutSQLite struct {
+	ID           uint64    `gorm:"primaryKey;column:id;autoIncrement"`
+	PayoutID     string    `gorm:"column:payout_id;uniqueIndex"`
+	RepaymentID  uint64    `gorm:"column:repayment_id;uniqueIndex:ux_payouts_repayment_investment"`
+	LoanID       uint64    `gorm:"column:loan_id"`
+	InvestmentID uint64    `gorm:"column:investment_id;uniqueIndex:ux_payouts_repayment_investment"`
+	InvestorID   string    `gorm:"column:investor_id"`
+	Principal    float64   `gorm:"column:principal"`
+	Interest     float64   `gorm:"column:interest"`
+	Total        float64   `gorm:"column:total"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+func (payoutSQLite) TableName() string { return "investor_payouts" }
+
+// openPayoutTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openPayoutTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&payoutSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func makePayout(loanNumericID, repaymentID, investmentID uint64, principal, interest string) payoutDomain.Payout {
+	p, i := money.MustParse(principal), money.MustParse(interest)
+	return payoutDomain.Payout{
+		PayoutID:     id.NewID32(),
+		RepaymentID:  repaymentID,
+		LoanID:       loanNumericID,
+		InvestmentID: investmentID,
+		InvestorID:   id.NewID32(),
+		Principal:    p,
+		Interest:     i,
+		Total:        p.Add(i),
+	}
+}
+
+func TestPayout_CreateBatchAndList(t *testing.T) {
+	db := openPayoutTestDB(t)
+	repo := NewPayoutRepository(db)
+	ctx := context.Background()
+
+	if err := repo.CreateBatch(ctx, nil); err != nil {
+		t.Fatalf("CreateBatch(nil): %v", err)
+	}
+	if err := repo.CreateBatch(ctx, []payoutDomain.Payout{
+		makePayout(7, 2, 11, "33.33", "3"),
+		makePayout(7, 2, 10, "33.34", "3"),
+		makePayout(7, 1, 10, "10", "0.01"),
+		makePayout(8, 3, 20, "1", "0"),
+	}); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+
+	got, err := repo.ListByLoanID(ctx, 7)
+	if err != nil {
+		t.Fatalf("ListByLoanID: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(got))
+	}
+	order := [][2]uint64{{1, 10}, {2, 10}, {2, 11}}
+	for i, o := range order {
+		if got[i].RepaymentID != o[0] || got[i].InvestmentID != o[1] {
+			t.Fatalf("row %d: want repayment/investment %v, got %d/%d", i, o, got[i].RepaymentID, got[i].InvestmentID)
+		}
+	}
+	if got[1].Principal.String() != "33.34" || got[1].Total.String() != "36.34" {
+		t.Fatalf("decimal round-trip: %+v", got[1])
+	}
+
+	alice := got[0].InvestorID
+	other := makePayout(7, 3, 10, "5", "0.5")
+	other.InvestorID = alice
+	second := makePayout(8, 4, 12, "1.5", "0.25")
+	second.InvestorID = alice
+	if err := repo.CreateBatch(ctx, []payoutDomain.Payout{other, second}); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	sums, err := repo.SumByInvestorID(ctx, alice)
+	if err != nil {
+		t.Fatalf("SumByInvestorID: %v", err)
+	}
+	if len(sums) != 2 || sums[0].InvestmentID != 10 || sums[1].InvestmentID != 12 {
+		t.Fatalf("unexpected sums: %+v", sums)
+	}
+	if sums[0].Principal.String() != "15" || sums[0].Interest.String() != "0.51" || sums[0].Total.String() != "15.51" {
+		t.Fatalf("investment 10 sum: %+v", sums[0])
+	}
+
+	// one payout per investment per repayment
+	if err := repo.CreateBatch(ctx, []payoutDomain.Payout{makePayout(7, 2, 11, "1", "0")}); err == nil {
+		t.Fatalf("expected unique violation on (repayment_id, investment_id)")
+	}
+}
@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"context"
+
+	photoDomain "amartha-backend-test/internal/domain/photo"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PhotoRepository struct{ db *gorm.DB }
+
+func NewPhotoRepository(db *gorm.DB) *PhotoRepository { return &PhotoRepository{db: db} }
+
+func (r *PhotoRepository) Create(ctx context.Context, p *photoDomain.Photo) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+func (r *PhotoRepository) GetByPhotoID(ctx context.Context, photoID string) (*photoDomain.Photo, error) {
+	var out photoDomain.Photo
+	res := r.db.WithContext(ctx).
+		Where("photo_id = ?", photoID).
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *PhotoRepository) GetByPhotoIDForUpdate(ctx context.Context, photoID string) (*photoDomain.Photo, error) {
+	var out photoDomain.Photo
+	res := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("photo_id = ?", photoID).
+		First(&out)
+	return &out, res.Error
+}
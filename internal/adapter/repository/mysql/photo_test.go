@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	photoDomain "amartha-backend-test/internal/domain/photo"
+	"amartha-backend-test/pkg/phash"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests (no engine specifics) ---
+type photoSQLite struct {
+	ID          uint64         `gorm:"primaryKey;column:id;autoIncrement"`
+	PhotoID     string         `gorm:"size:64;uniqueIndex;column:photo_id"`
+	StorageKey  string         `gorm:"column:storage_key"`
+	SHA256      string         `gorm:"column:sha256"`
+	PHash       *int64         `gorm:"column:phash"`
+	ContentType string         `gorm:"column:content_type"`
+	SizeBytes   int64          `gorm:"column:size_bytes"`
+	UploadedBy  string         `gorm:"column:uploaded_by"`
+	CreatedAt   time.Time      `gorm:"column:created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at"`
+	DeletedBy   string         `gorm:"column:deleted_by"`
+}
+
+func (photoSQLite) TableName() string { return "photos" }
+
+// openPhotoTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openPhotoTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&photoSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func TestPhoto_CreateAndGet(t *testing.T) {
+	repo := NewPhotoRepository(openPhotoTestDB(t))
+	ctx := context.Background()
+
+	h := phash.Hash(-42)
+	in := &photoDomain.Photo{
+		PhotoID:     strings.Repeat("a", 32),
+		StorageKey:  "photos/" + strings.Repeat("a", 32) + ".jpg",
+		SHA256:      strings.Repeat("0", 64),
+		PHash:       &h,
+		ContentType: "image/jpeg",
+		SizeBytes:   2048,
+		UploadedBy:  strings.Repeat("e", 32),
+	}
+	if err := repo.Create(ctx, in); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if in.ID == 0 {
+		t.Fatalf("expected auto-increment ID")
+	}
+
+	got, err := repo.GetByPhotoID(ctx, in.PhotoID)
+	if err != nil {
+		t.Fatalf("GetByPhotoID: %v", err)
+	}
+	if got.StorageKey != in.StorageKey || got.SizeBytes != 2048 || got.ContentType != "image/jpeg" || got.PHash == nil || *got.PHash != h {
+		t.Fatalf("unexpected row: %+v", got)
+	}
+
+	if _, err := repo.GetByPhotoID(ctx, strings.Repeat("b", 32)); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("missing: want ErrRecordNotFound, got %v", err)
+	}
+
+	if got, err := repo.GetByPhotoIDForUpdate(ctx, in.PhotoID); err != nil || got.ID != in.ID {
+		t.Fatalf("GetByPhotoIDForUpdate: got %+v, %v", got, err)
+	}
+	if _, err := repo.GetByPhotoIDForUpdate(ctx, strings.Repeat("b", 32)); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("missing for update: want ErrRecordNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package mysql
+
+import (
+	"context"
+
+	rejectionDomain "amartha-backend-test/internal/domain/rejection"
+
+	"gorm.io/gorm"
+)
+
+type RejectionRepository struct{ db *gorm.DB }
+
+func NewRejectionRepository(db *gorm.DB) *RejectionRepository { return &RejectionRepository{db: db} }
+
+func (r *RejectionRepository) Create(ctx context.Context, rj *rejectionDomain.Rejection) error {
+	return r.db.WithContext(ctx).Create(rj).Error
+}
+
+func (r *RejectionRepository) GetByLoanID(ctx context.Context, loanNumericID uint64) (*rejectionDomain.Rejection, error) {
+	var out rejectionDomain.Rejection
+	res := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanNumericID).
+		First(&out)
+	return &out, res.Error
+}
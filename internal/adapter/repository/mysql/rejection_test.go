@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rejectionDomain "amartha-backend-test/internal/domain/rejection"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests (no engine specifics) ---
+type rejectionSQLite struct {
+	ID                  uint64         `gorm:"primaryKey;column:id;autoIncrement"`
+	RejectionID         string         `gorm:"size:64;uniqueIndex;column:rejection_id"`
+	LoanID              uint64         `gorm:"column:loan_id"`
+	ReasonCode          string         `gorm:"column:reason_code"`
+	ReasonText          string         `gorm:"column:reason_text"`
+	ValidatorEmployeeID string         `gorm:"column:validator_employee_id"`
+	RejectedAt          time.Time      `gorm:"column:rejected_at"`
+	CreatedAt           time.Time      `gorm:"column:created_at"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"column:deleted_at"`
+	DeletedBy           string         `gorm:"column:deleted_by"`
+}
+
+func (rejectionSQLite) TableName() string { return "rejections" }
+
+// openRejectionTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openRejectionTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&rejectionSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func TestRejection_CreateAndGet(t *testing.T) {
+	db := openRejectionTestDB(t)
+	repo := NewRejectionRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	in := &rejectionDomain.Rejection{
+		RejectionID:         "RJ-001",
+		LoanID:              777,
+		ReasonCode:          rejectionDomain.ReasonIncompleteDocuments,
+		ReasonText:          "missing KTP",
+		ValidatorEmployeeID: "EMP-1",
+		RejectedAt:          now,
+	}
+	if err := repo.Create(ctx, in); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := repo.GetByLoanID(ctx, 777)
+	if err != nil {
+		t.Fatalf("GetByLoanID: %v", err)
+	}
+	if got.RejectionID != "RJ-001" || got.ReasonCode != rejectionDomain.ReasonIncompleteDocuments || !got.RejectedAt.Equal(now) {
+		t.Fatalf("unexpected row: %+v", got)
+	}
+
+	if _, err := repo.GetByLoanID(ctx, 999); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound, got %v", err)
+	}
+}
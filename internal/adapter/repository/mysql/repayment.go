@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"context"
+
+	repaymentDomain "amartha-backend-test/internal/domain/repayment"
+
+	"gorm.io/gorm"
+)
+
+type RepaymentRepository struct{ db *gorm.DB }
+
+func NewRepaymentRepository(db *gorm.DB) *RepaymentRepository {
+	return &RepaymentRepository{db: db}
+}
+
+func (r *RepaymentRepository) Create(ctx context.Context, p *repaymentDomain.Repayment) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+func (r *RepaymentRepository) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]repaymentDomain.Repayment, error) {
+	var out []repaymentDomain.Repayment
+	res := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanNumericID).
+		Order("paid_at ASC, id ASC").
+		Find(&out)
+	return out, res.Error
+}
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+
+	loanDomain "amartha-backend-test/internal/domain/loan"
+	repaymentDomain "amartha-backend-test/internal/domain/repayment"
+
+	"gorm.io/gorm"
+)
+
+type RepaymentScheduleRepository struct{ db *gorm.DB }
+
+func NewRepaymentScheduleRepository(db *gorm.DB) *RepaymentScheduleRepository {
+	return &RepaymentScheduleRepository{db: db}
+}
+
+func (r *RepaymentScheduleRepository) CreateSchedule(ctx context.Context, items []repaymentDomain.Installment) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&items).Error
+}
+
+func (r *RepaymentScheduleRepository) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]repaymentDomain.Installment, error) {
+	var out []repaymentDomain.Installment
+	res := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanNumericID).
+		Order("installment_no ASC").
+		Find(&out)
+	return out, res.Error
+}
+
+func (r *RepaymentScheduleRepository) UpdatePaid(ctx context.Context, items []repaymentDomain.Installment) error {
+	db := r.db.WithContext(ctx)
+	for i := range items {
+		res := db.Model(&repaymentDomain.Installment{}).
+			Where("id = ?", items[i].ID).
+			Updates(map[string]any{
+				"fee_paid":       items[i].FeePaid,
+				"interest_paid":  items[i].InterestPaid,
+				"principal_paid": items[i].PrincipalPaid,
+				"paid_at":        items[i].PaidAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+func (r *RepaymentScheduleRepository) ExposureByBucket(ctx context.Context) ([]repaymentDomain.BucketExposure, error) {
+	var out []repaymentDomain.BucketExposure
+	res := r.db.WithContext(ctx).
+		Table("repayment_schedules AS rs").
+		Select("l.dpd_bucket AS bucket, COUNT(DISTINCT l.id) AS loans, SUM(rs.principal_due - rs.principal_paid) AS outstanding_principal").
+		Joins("JOIN loans l ON l.id = rs.loan_id").
+		Where("l.state = ? AND l.deleted_at IS NULL", loanDomain.StateDisbursed).
+		Group("l.dpd_bucket").
+		Scan(&out)
+	return out, res.Error
+}
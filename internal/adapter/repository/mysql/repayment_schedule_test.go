@@ -0,0 +1,189 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	loanDomain "amartha-backend-test/internal/domain/loan"
+	repaymentDomain "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests ---
+type repaymentScheduleSQLite struct {
+	ID            uint64     `gorm:"primaryKey;column:id;autoIncrement"`
+	LoanID        uint64     `gorm:"column:loan_id;uniqueIndex:ux_rs_loan_installment"`
+	InstallmentNo uint16     `gorm:"column:installment_no;uniqueIndex:ux_rs_loan_installment"`
+	DueDate       time.Time  `gorm:"column:due_date"`
+	PrincipalDue  float64    `gorm:"column:principal_due"`
+	InterestDue   float64    `gorm:"column:interest_due"`
+	FeeDue        float64    `gorm:"column:fee_due;default:0"`
+	TotalDue      float64    `gorm:"column:total_due"`
+	PrincipalPaid float64    `gorm:"column:principal_paid;default:0"`
+	InterestPaid  float64    `gorm:"column:interest_paid;default:0"`
+	FeePaid       float64    `gorm:"column:fee_paid;default:0"`
+	PaidAt        *time.Time `gorm:"column:paid_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at"`
+}
+
+func (repaymentScheduleSQLite) TableName() string { return "repayment_schedules" }
+
+// openScheduleTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openScheduleTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&repaymentScheduleSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func makeInstallment(loanNumericID uint64, no uint16, principal, interest string) repaymentDomain.Installment {
+	p, i := money.MustParse(principal), money.MustParse(interest)
+	return repaymentDomain.Installment{
+		LoanID:        loanNumericID,
+		InstallmentNo: no,
+		DueDate:       time.Date(2025, 10, int(no), 0, 0, 0, 0, time.UTC),
+		PrincipalDue:  p,
+		InterestDue:   i,
+		TotalDue:      p.Add(i),
+	}
+}
+
+func TestRepaymentSchedule_CreateAndList(t *testing.T) {
+	db := openScheduleTestDB(t)
+	repo := NewRepaymentScheduleRepository(db)
+	ctx := context.Background()
+
+	if err := repo.CreateSchedule(ctx, []repaymentDomain.Installment{
+		makeInstallment(7, 2, "333.32", "6.70"),
+		makeInstallment(7, 1, "330.02", "10.00"),
+		makeInstallment(8, 1, "1000", "20"),
+	}); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	got, err := repo.ListByLoanID(ctx, 7)
+	if err != nil {
+		t.Fatalf("ListByLoanID: %v", err)
+	}
+	if len(got) != 2 || got[0].InstallmentNo != 1 || got[1].InstallmentNo != 2 {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+	if got[0].TotalDue.String() != "340.02" || got[1].InterestDue.String() != "6.7" {
+		t.Fatalf("decimal round-trip: total=%s interest=%s", got[0].TotalDue, got[1].InterestDue)
+	}
+
+	none, err := repo.ListByLoanID(ctx, 999)
+	if err != nil || len(none) != 0 {
+		t.Fatalf("expected empty list, got %+v err=%v", none, err)
+	}
+}
+
+func TestRepaymentSchedule_DuplicateInstallmentRejected(t *testing.T) {
+	db := openScheduleTestDB(t)
+	repo := NewRepaymentScheduleRepository(db)
+	ctx := context.Background()
+
+	if err := repo.CreateSchedule(ctx, nil); err != nil {
+		t.Fatalf("empty schedule must be a no-op, got %v", err)
+	}
+	if err := repo.CreateSchedule(ctx, []repaymentDomain.Installment{makeInstallment(7, 1, "100", "1")}); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	if err := repo.CreateSchedule(ctx, []repaymentDomain.Installment{makeInstallment(7, 1, "100", "1")}); err == nil {
+		t.Fatalf("expected unique violation on (loan_id, installment_no)")
+	}
+}
+
+func TestRepaymentSchedule_UpdatePaid(t *testing.T) {
+	db := openScheduleTestDB(t)
+	repo := NewRepaymentScheduleRepository(db)
+	ctx := context.Background()
+
+	if err := repo.CreateSchedule(ctx, []repaymentDomain.Installment{
+		makeInstallment(7, 1, "100", "10"),
+		makeInstallment(7, 2, "100", "10"),
+	}); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	items, _ := repo.ListByLoanID(ctx, 7)
+
+	paidAt := time.Date(2025, 10, 2, 8, 0, 0, 0, time.UTC)
+	items[0].InterestPaid, items[0].PrincipalPaid, items[0].PaidAt = money.NewFromInt(10), money.NewFromInt(100), &paidAt
+	items[1].InterestPaid = money.MustParse("2.5")
+	if err := repo.UpdatePaid(ctx, items); err != nil {
+		t.Fatalf("UpdatePaid: %v", err)
+	}
+
+	got, err := repo.ListByLoanID(ctx, 7)
+	if err != nil {
+		t.Fatalf("ListByLoanID: %v", err)
+	}
+	if !got[0].Settled() || got[0].PaidAt == nil || !got[0].PaidAt.Equal(paidAt) {
+		t.Fatalf("installment 1 not persisted as settled: %+v", got[0])
+	}
+	if got[1].InterestPaid.String() != "2.5" || got[1].PaidAt != nil || got[1].Outstanding().String() != "107.5" {
+		t.Fatalf("installment 2 partial not persisted: %+v", got[1])
+	}
+}
+
+func TestRepaymentSchedule_ExposureByBucket(t *testing.T) {
+	db := openScheduleTestDB(t)
+	if err := db.AutoMigrate(&loanSQLite{}); err != nil {
+		t.Fatalf("auto-migrate loans: %v", err)
+	}
+	loans := NewLoanRepository(db)
+	repo := NewRepaymentScheduleRepository(db)
+	ctx := context.Background()
+
+	mk := func(state loanDomain.State, bucket loanDomain.DPDBucket) uint64 {
+		l := makeLoan(id.NewID32(), id.NewID32())
+		l.State, l.DPDBucket = state, bucket
+		if err := loans.Create(ctx, l); err != nil {
+			t.Fatalf("Create loan: %v", err)
+		}
+		return l.ID
+	}
+	current := mk(loanDomain.StateDisbursed, loanDomain.DPDCurrent)
+	late := mk(loanDomain.StateDisbursed, loanDomain.DPD90Plus)
+	repaid := mk(loanDomain.StateRepaid, loanDomain.DPDCurrent)
+
+	items := []repaymentDomain.Installment{
+		makeInstallment(current, 1, "100", "5"),
+		makeInstallment(current, 2, "100", "5"),
+		makeInstallment(late, 1, "300", "5"),
+		makeInstallment(repaid, 1, "999", "5"),
+	}
+	items[0].PrincipalPaid = money.MustParse("60.50")
+	if err := repo.CreateSchedule(ctx, items); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	got, err := repo.ExposureByBucket(ctx)
+	if err != nil {
+		t.Fatalf("ExposureByBucket: %v", err)
+	}
+	byBucket := map[loanDomain.DPDBucket]repaymentDomain.BucketExposure{}
+	for _, e := range got {
+		byBucket[e.Bucket] = e
+	}
+	if len(byBucket) != 2 {
+		t.Fatalf("repaid loans must be excluded, got %+v", got)
+	}
+	if e := byBucket[loanDomain.DPDCurrent]; e.Loans != 1 || e.OutstandingPrincipal.String() != "139.5" {
+		t.Fatalf("current exposure: %+v", e)
+	}
+	if e := byBucket[loanDomain.DPD90Plus]; e.Loans != 1 || e.OutstandingPrincipal.String() != "300" {
+		t.Fatalf("90+ exposure: %+v", e)
+	}
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	repaymentDomain "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests ---
+type repaymentSQLite struct {
+	ID                  uint64    `gorm:"primaryKey;column:id;autoIncrement"`
+	RepaymentID         string    `gorm:"column:repayment_id;uniqueIndex"`
+	LoanID              uint64    `gorm:"column:loan_id"`
+	Amount              float64   `gorm:"column:amount"`
+	FeePaid             float64   `gorm:"column:fee_paid"`
+	InterestPaid        float64   `gorm:"column:interest_paid"`
+	PrincipalPaid       float64   `gorm:"column:principal_paid"`
+	OutstandingAfter    float64   `gorm:"column:outstanding_after"`
+	CollectorEmployeeID string    `gorm:"column:collector_employee_id"`
+	PaidAt              time.Time `gorm:"column:paid_at"`
+	CreatedAt           time.Time `gorm:"column:created_at"`
+}
+
+func (repaymentSQLite) TableName() string { return "repayments" }
+
+// openRepaymentTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openRepaymentTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&repaymentSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func makeRepayment(loanNumericID uint64, amount string, paidAt time.Time) *repaymentDomain.Repayment {
+	a := money.MustParse(amount)
+	return &repaymentDomain.Repayment{
+		RepaymentID:         id.NewID32(),
+		LoanID:              loanNumericID,
+		Amount:              a,
+		InterestPaid:        money.NewFromInt(10),
+		PrincipalPaid:       a.Sub(money.NewFromInt(10)),
+		OutstandingAfter:    money.MustParse("990.50"),
+		CollectorEmployeeID: id.NewID32(),
+		PaidAt:              paidAt,
+	}
+}
+
+func TestRepayment_CreateAndList(t *testing.T) {
+	db := openRepaymentTestDB(t)
+	repo := NewRepaymentRepository(db)
+	ctx := context.Background()
+
+	later := time.Date(2025, 10, 9, 0, 0, 0, 0, time.UTC)
+	earlier := later.AddDate(0, 0, -7)
+	for _, p := range []*repaymentDomain.Repayment{
+		makeRepayment(7, "110", later),
+		makeRepayment(7, "55.25", earlier),
+		makeRepayment(8, "1", earlier),
+	} {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if p.ID == 0 {
+			t.Fatalf("expected auto-increment ID")
+		}
+	}
+
+	got, err := repo.ListByLoanID(ctx, 7)
+	if err != nil {
+		t.Fatalf("ListByLoanID: %v", err)
+	}
+	if len(got) != 2 || !got[0].PaidAt.Equal(earlier) {
+		t.Fatalf("expected 2 rows oldest first, got %+v", got)
+	}
+	if got[0].Amount.String() != "55.25" || got[0].OutstandingAfter.String() != "990.5" {
+		t.Fatalf("decimal round-trip: amount=%s outstanding=%s", got[0].Amount, got[0].OutstandingAfter)
+	}
+}
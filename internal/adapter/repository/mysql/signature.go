@@ -0,0 +1,60 @@
+package mysql
+
+import (
+	"context"
+
+	sigDomain "amartha-backend-test/internal/domain/signature"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SignatureRepository struct{ db *gorm.DB }
+
+func NewSignatureRepository(db *gorm.DB) *SignatureRepository { return &SignatureRepository{db: db} }
+
+func (r *SignatureRepository) CreateEnvelope(ctx context.Context, e *sigDomain.Envelope) error {
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+func (r *SignatureRepository) GetByProviderTx(ctx context.Context, provider, txID string) (*sigDomain.Envelope, error) {
+	var out sigDomain.Envelope
+	res := r.db.WithContext(ctx).
+		Where("signature_provider = ? AND signature_tx_id = ?", provider, txID).
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *SignatureRepository) GetByProviderTxForUpdate(ctx context.Context, provider, txID string) (*sigDomain.Envelope, error) {
+	var out sigDomain.Envelope
+	res := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("signature_provider = ? AND signature_tx_id = ?", provider, txID).
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *SignatureRepository) GetLatestByLoanID(ctx context.Context, loanID uint64) (*sigDomain.Envelope, error) {
+	var out sigDomain.Envelope
+	res := r.db.WithContext(ctx).
+		Where("loan_id = ?", loanID).
+		Order("id DESC").
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *SignatureRepository) SaveEnvelope(ctx context.Context, e *sigDomain.Envelope) error {
+	return r.db.WithContext(ctx).Save(e).Error
+}
+
+func (r *SignatureRepository) GetEvent(ctx context.Context, provider, eventID string) (*sigDomain.Event, error) {
+	var out sigDomain.Event
+	res := r.db.WithContext(ctx).
+		Where("signature_provider = ? AND event_id = ?", provider, eventID).
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *SignatureRepository) CreateEvent(ctx context.Context, ev *sigDomain.Event) error {
+	return r.db.WithContext(ctx).Create(ev).Error
+}
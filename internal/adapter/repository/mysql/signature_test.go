@@ -0,0 +1,142 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sigDomain "amartha-backend-test/internal/domain/signature"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests (no engine specifics) ---
+type signatureEnvelopeSQLite struct {
+	ID                uint64         `gorm:"primaryKey;column:id;autoIncrement"`
+	EnvelopeID        string         `gorm:"size:64;uniqueIndex;column:envelope_id"`
+	LoanID            uint64         `gorm:"column:loan_id"`
+	SignatureProvider string         `gorm:"column:signature_provider;uniqueIndex:ux_sigenv_tx"`
+	SignatureTxID     string         `gorm:"column:signature_tx_id;uniqueIndex:ux_sigenv_tx"`
+	SignatureStatus   string         `gorm:"column:signature_status"`
+	DocumentURL       string         `gorm:"column:document_url"`
+	SigningURL        string         `gorm:"column:signing_url"`
+	SignedAt          *time.Time     `gorm:"column:signed_at"`
+	DocumentSHA256    *string        `gorm:"column:document_sha256"`
+	SignedDocumentKey *string        `gorm:"column:signed_document_key"`
+	RequestedBy       string         `gorm:"column:requested_by"`
+	CreatedAt         time.Time      `gorm:"column:created_at"`
+	UpdatedAt         time.Time      `gorm:"column:updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"column:deleted_at"`
+	DeletedBy         string         `gorm:"column:deleted_by"`
+}
+
+func (signatureEnvelopeSQLite) TableName() string { return "signature_envelopes" }
+
+type signatureEventSQLite struct {
+	ID                uint64    `gorm:"primaryKey;column:id;autoIncrement"`
+	SignatureProvider string    `gorm:"column:signature_provider;uniqueIndex:ux_sigevt"`
+	EventID           string    `gorm:"column:event_id;uniqueIndex:ux_sigevt"`
+	EnvelopeID        uint64    `gorm:"column:envelope_id"`
+	SignatureStatus   string    `gorm:"column:signature_status"`
+	Applied           bool      `gorm:"column:applied"`
+	OccurredAt        time.Time `gorm:"column:occurred_at"`
+	CreatedAt         time.Time `gorm:"column:created_at"`
+}
+
+func (signatureEventSQLite) TableName() string { return "signature_events" }
+
+// openSignatureTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openSignatureTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&signatureEnvelopeSQLite{}, &signatureEventSQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func TestSignature_Envelopes(t *testing.T) {
+	repo := NewSignatureRepository(openSignatureTestDB(t))
+	ctx := context.Background()
+
+	first := &sigDomain.Envelope{
+		EnvelopeID: strings.Repeat("a", 32), LoanID: 7, SignatureProvider: "fake", SignatureTxID: "tx-1",
+		SignatureStatus: sigDomain.StatusCancelled, DocumentURL: "http://docs/1", RequestedBy: strings.Repeat("e", 32),
+	}
+	second := &sigDomain.Envelope{
+		EnvelopeID: strings.Repeat("b", 32), LoanID: 7, SignatureProvider: "fake", SignatureTxID: "tx-2",
+		SignatureStatus: sigDomain.StatusPending, DocumentURL: "http://docs/2", RequestedBy: strings.Repeat("e", 32),
+	}
+	for _, e := range []*sigDomain.Envelope{first, second} {
+		if err := repo.CreateEnvelope(ctx, e); err != nil {
+			t.Fatalf("CreateEnvelope: %v", err)
+		}
+	}
+	dup := *second
+	dup.ID, dup.EnvelopeID = 0, strings.Repeat("c", 32)
+	if err := repo.CreateEnvelope(ctx, &dup); err == nil {
+		t.Fatalf("same provider tx twice should violate the unique key")
+	}
+
+	latest, err := repo.GetLatestByLoanID(ctx, 7)
+	if err != nil || latest.EnvelopeID != second.EnvelopeID {
+		t.Fatalf("GetLatestByLoanID = %+v, %v", latest, err)
+	}
+	if _, err := repo.GetLatestByLoanID(ctx, 8); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("no envelope: want ErrRecordNotFound, got %v", err)
+	}
+
+	got, err := repo.GetByProviderTx(ctx, "fake", "tx-2")
+	if err != nil || got.ID != second.ID {
+		t.Fatalf("GetByProviderTx = %+v, %v", got, err)
+	}
+	now, hash := time.Now().UTC().Truncate(time.Second), strings.Repeat("0", 64)
+	got.SignatureStatus, got.SignedAt, got.DocumentSHA256 = sigDomain.StatusSigned, &now, &hash
+	if err := repo.SaveEnvelope(ctx, got); err != nil {
+		t.Fatalf("SaveEnvelope: %v", err)
+	}
+	got, _ = repo.GetByProviderTxForUpdate(ctx, "fake", "tx-2")
+	if got.SignatureStatus != sigDomain.StatusSigned || got.SignedAt == nil || !got.SignedAt.Equal(now) || *got.DocumentSHA256 != hash {
+		t.Fatalf("after save: %+v", got)
+	}
+	if _, err := repo.GetByProviderTx(ctx, "other", "tx-2"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("other provider: want ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestSignature_Events(t *testing.T) {
+	repo := NewSignatureRepository(openSignatureTestDB(t))
+	ctx := context.Background()
+
+	if _, err := repo.GetEvent(ctx, "fake", "evt-1"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("new event: want ErrRecordNotFound, got %v", err)
+	}
+	ev := &sigDomain.Event{
+		SignatureProvider: "fake", EventID: "evt-1", EnvelopeID: 1,
+		SignatureStatus: sigDomain.StatusSigned, Applied: true, OccurredAt: time.Now().UTC(),
+	}
+	if err := repo.CreateEvent(ctx, ev); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	got, err := repo.GetEvent(ctx, "fake", "evt-1")
+	if err != nil || got.ID != ev.ID || !got.Applied {
+		t.Fatalf("GetEvent = %+v, %v", got, err)
+	}
+
+	replay := *ev
+	replay.ID = 0
+	if err := repo.CreateEvent(ctx, &replay); err == nil {
+		t.Fatalf("same provider event twice should violate the unique key")
+	}
+	// event ids are only unique per provider
+	replay.SignatureProvider = "other"
+	if err := repo.CreateEvent(ctx, &replay); err != nil {
+		t.Fatalf("other provider: %v", err)
+	}
+}
@@ -12,22 +12,36 @@ type GormUoW struct{ db *gorm.DB }
 
 func NewGormUoW(db *gorm.DB) *GormUoW { return &GormUoW{db: db} }
 
+// txRepos binds every repository to the same transaction handle.
+func txRepos(tx *gorm.DB) uow.Repos {
+	return uow.Repos{
+		Loans:         &LoanRepository{db: tx},
+		LoanHistory:   &LoanHistoryRepository{db: tx},
+		Approvals:     &ApprovalRepository{db: tx},
+		Investments:   &InvestmentRepository{db: tx},
+		Disbursements: &DisbursementRepository{db: tx},
+		Rejections:    &RejectionRepository{db: tx},
+		Schedules:     &RepaymentScheduleRepository{db: tx},
+		Repayments:    &RepaymentRepository{db: tx},
+		Payouts:       &PayoutRepository{db: tx},
+		Ledger:        &LedgerRepository{db: tx},
+		Photos:        &PhotoRepository{db: tx},
+		Signatures:    &SignatureRepository{db: tx},
+		Outbox:        &OutboxRepository{db: tx},
+		Webhooks:      &WebhookRepository{db: tx},
+		Notifications: &NotificationRepository{db: tx},
+	}
+}
+
 func (u *GormUoW) WithinTx(ctx context.Context, fn func(r uow.Repos) error) error {
 	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		r := uow.Repos{
-			Loans:     &LoanRepository{db: tx},
-			Approvals: &ApprovalRepository{db: tx},
-		}
-		return fn(r)
+		return fn(txRepos(tx))
 	})
 }
 
 func (u *GormUoW) WithinLoanTx(ctx context.Context, loanID string, fn func(r uow.Repos, l *loan.Loan) error) error {
 	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		r := uow.Repos{
-			Loans:     &LoanRepository{db: tx},
-			Approvals: &ApprovalRepository{db: tx},
-		}
+		r := txRepos(tx)
 		// lock the loan row up-front to prevent races
 		l, err := r.Loans.GetByLoanIDForUpdate(ctx, loanID)
 		if err != nil {
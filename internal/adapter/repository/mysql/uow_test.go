@@ -3,25 +3,31 @@ package mysql
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	approvalDomain "amartha-backend-test/internal/domain/approval"
+	ledgerDomain "amartha-backend-test/internal/domain/ledger"
 	loanDomain "amartha-backend-test/internal/domain/loan"
+	outboxDomain "amartha-backend-test/internal/domain/outbox"
 	"amartha-backend-test/internal/domain/uow"
+	ucCancellation "amartha-backend-test/internal/usecase/cancellation"
+	ucLoan "amartha-backend-test/internal/usecase/loan"
+	"amartha-backend-test/pkg/money"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-// openUowTestDB migrates both tables, so UoW can orchestrate both repos.
+// openUowTestDB migrates every table, so UoW can orchestrate all repos.
 func openUowTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	if err != nil {
 		t.Fatalf("open sqlite: %v", err)
 	}
-	if err := db.AutoMigrate(&loanSQLite{}, &approvalSQLite{}); err != nil {
+	if err := db.AutoMigrate(&loanSQLite{}, &approvalSQLite{}, &investmentSQLite{}, &disbursementSQLite{}, &stateTransitionSQLite{}, &journalEntrySQLite{}, &journalLineSQLite{}, &ledgerAccountSQLite{}, &outboxSQLite{}); err != nil {
 		t.Fatalf("auto-migrate: %v", err)
 	}
 	return db
@@ -31,9 +37,9 @@ func makeLoanDomain(loanID, borrowerID string) *loanDomain.Loan {
 	return &loanDomain.Loan{
 		LoanID:         loanID,
 		BorrowerID:     borrowerID,
-		Principal:      1_000_000.00,
-		Rate:           0.22,
-		ROI:            0.18,
+		Principal:      money.MustParse("1000000.00"),
+		Rate:           money.MustParse("0.22"),
+		ROI:            money.MustParse("0.18"),
 		State:          loanDomain.StateProposed,
 		StateUpdatedAt: time.Now().UTC(),
 	}
@@ -231,3 +237,212 @@ func TestGormUoW_WithinLoanTx_LoanNotFound(t *testing.T) {
 		t.Fatalf("expected error when loan not found")
 	}
 }
+
+func TestGormUoW_WithinLoanTx_InvestmentsShareTx(t *testing.T) {
+	db := openUowTestDB(t)
+	ctx := context.Background()
+
+	guow := NewGormUoW(db)
+	invRepo := NewInvestmentRepository(db)
+	ledgerRepo := NewLedgerRepository(db)
+
+	seed := &loanSQLite{
+		LoanID:         "LN-INV",
+		BorrowerID:     "BR-5",
+		Principal:      1_000_000,
+		State:          "approved",
+		StateUpdatedAt: time.Now().UTC(),
+	}
+	if err := db.Create(seed).Error; err != nil {
+		t.Fatalf("seed loan: %v", err)
+	}
+
+	sentinel := errors.New("stop")
+	_ = guow.WithinLoanTx(ctx, "LN-INV", func(rRepos uow.Repos, l *loanDomain.Loan) error {
+		inv := makeInvestment("INV-RB", l.ID, "500000")
+		if err := rRepos.Investments.Create(ctx, inv); err != nil {
+			return err
+		}
+		if err := rRepos.Ledger.Post(ctx, ledgerDomain.ForInvestment(l, inv, time.Now())); err != nil {
+			return err
+		}
+		// Visible inside the same tx
+		escrow, err := rRepos.Ledger.Balance(ctx, ledgerDomain.Escrow(l.LoanID))
+		if err != nil || !escrow.Equal(money.NewFromInt(500_000)) {
+			t.Fatalf("escrow inside tx: got %v err=%v", escrow, err)
+		}
+		return sentinel // force rollback
+	})
+
+	invs, err := invRepo.ListByLoanID(ctx, seed.ID)
+	if err != nil || len(invs) != 0 {
+		t.Fatalf("expected no investments after rollback, got %+v err=%v", invs, err)
+	}
+	escrow, err := ledgerRepo.Balance(ctx, ledgerDomain.Escrow("LN-INV"))
+	if err != nil || !escrow.IsZero() {
+		t.Fatalf("expected no postings after rollback, got %v err=%v", escrow, err)
+	}
+}
+
+func TestGormUoW_WithinLoanTx_DisbursementCommit(t *testing.T) {
+	db := openUowTestDB(t)
+	ctx := context.Background()
+
+	guow := NewGormUoW(db)
+	loanRepo := NewLoanRepository(db)
+	disbRepo := NewDisbursementRepository(db)
+
+	seed := &loanSQLite{
+		LoanID:         "LN-DISB",
+		BorrowerID:     "BR-6",
+		Principal:      1_000_000,
+		State:          "invested",
+		StateUpdatedAt: time.Now().UTC(),
+	}
+	if err := db.Create(seed).Error; err != nil {
+		t.Fatalf("seed loan: %v", err)
+	}
+
+	if err := guow.WithinLoanTx(ctx, "LN-DISB", func(rRepos uow.Repos, l *loanDomain.Loan) error {
+		if err := rRepos.Disbursements.Create(ctx, makeDisbursement("DSB-TX", l.ID, time.Now())); err != nil {
+			return err
+		}
+		l.State = loanDomain.StateDisbursed
+		return rRepos.Loans.Save(ctx, l)
+	}); err != nil {
+		t.Fatalf("WithinLoanTx commit err: %v", err)
+	}
+
+	gotLoan, err := loanRepo.GetByLoanID(ctx, "LN-DISB")
+	if err != nil || gotLoan.State != loanDomain.StateDisbursed {
+		t.Fatalf("loan not disbursed after commit: %+v err=%v", gotLoan, err)
+	}
+	if _, err := disbRepo.GetByLoanID(ctx, seed.ID); err != nil {
+		t.Fatalf("disbursement not visible after commit: %v", err)
+	}
+}
+
+func TestGormUoW_WithinLoanTx_HistoryRollsBackWithLoan(t *testing.T) {
+	db := openUowTestDB(t)
+	ctx := context.Background()
+
+	guow := NewGormUoW(db)
+	histRepo := NewLoanHistoryRepository(db)
+
+	seed := &loanSQLite{
+		LoanID:         "LN-HIST",
+		BorrowerID:     "BR-7",
+		Principal:      1_000_000,
+		State:          "proposed",
+		StateUpdatedAt: time.Now().UTC(),
+	}
+	if err := db.Create(seed).Error; err != nil {
+		t.Fatalf("seed loan: %v", err)
+	}
+
+	sentinel := errors.New("stop")
+	_ = guow.WithinLoanTx(ctx, "LN-HIST", func(rRepos uow.Repos, l *loanDomain.Loan) error {
+		tr, err := loanDomain.Lifecycle.Transition(l, loanDomain.StateApproved, loanDomain.TransitionMeta{Actor: "EMP-1"})
+		if err != nil {
+			return err
+		}
+		if err := rRepos.Loans.Save(ctx, l); err != nil {
+			return err
+		}
+		if err := rRepos.LoanHistory.Append(ctx, tr); err != nil {
+			return err
+		}
+		return sentinel // force rollback
+	})
+
+	got, err := histRepo.ListByLoanID(ctx, seed.ID)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("expected no history after rollback, got %+v err=%v", got, err)
+	}
+}
+
+func TestGormUoW_WithinLoanTx_OutboxFollowsLoan(t *testing.T) {
+	db := openUowTestDB(t)
+	ctx := context.Background()
+
+	guow := NewGormUoW(db)
+	outboxRepo := NewOutboxRepository(db)
+
+	seed := &loanSQLite{
+		LoanID:         "LN-OUTBOX",
+		BorrowerID:     "BR-8",
+		Principal:      1_000_000,
+		State:          "proposed",
+		StateUpdatedAt: time.Now().UTC(),
+	}
+	if err := db.Create(seed).Error; err != nil {
+		t.Fatalf("seed loan: %v", err)
+	}
+
+	approve := func(fail error) error {
+		return guow.WithinLoanTx(ctx, "LN-OUTBOX", func(rRepos uow.Repos, l *loanDomain.Loan) error {
+			if _, err := loanDomain.Lifecycle.Transition(l, loanDomain.StateApproved, loanDomain.TransitionMeta{Actor: "EMP-1"}); err != nil {
+				return err
+			}
+			if err := rRepos.Loans.Save(ctx, l); err != nil {
+				return err
+			}
+			m, err := outboxDomain.ForLoan(outboxDomain.LoanApproved, l)
+			if err != nil {
+				return err
+			}
+			if err := rRepos.Outbox.Append(ctx, m); err != nil {
+				return err
+			}
+			return fail
+		})
+	}
+	far := time.Now().Add(time.Hour)
+
+	// rolled back: no event without the state change
+	_ = approve(errors.New("stop"))
+	if got, err := outboxRepo.ListDue(ctx, far, 10); err != nil || len(got) != 0 {
+		t.Fatalf("expected no outbox rows after rollback, got %+v err=%v", got, err)
+	}
+
+	if err := approve(nil); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	got, err := outboxRepo.ListDue(ctx, far, 10)
+	if err != nil || len(got) != 1 || got[0].EventType != outboxDomain.LoanApproved || got[0].AggregateID != "LN-OUTBOX" {
+		t.Fatalf("expected one LoanApproved row, got %+v err=%v", got, err)
+	}
+}
+
+// A cancelled loan is a lifecycle state, not a deleted row: the borrower's
+// loan history still lists it.
+func TestGormUoW_CancelledLoanStaysInBorrowerList(t *testing.T) {
+	db := openUowTestDB(t)
+	ctx := context.Background()
+	borrower := strings.Repeat("b", 32)
+
+	loanRepo := NewLoanRepository(db)
+	for _, id := range []string{"LN-KEEP", "LN-CANCEL"} {
+		if err := loanRepo.Create(ctx, makeLoanDomain(id, borrower)); err != nil {
+			t.Fatalf("Create %s: %v", id, err)
+		}
+	}
+
+	if _, err := ucCancellation.NewUsecase(NewGormUoW(db)).Cancel(ctx, ucCancellation.CancelInput{LoanID: "LN-CANCEL", CancelledBy: strings.Repeat("e", 32)}); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	out, err := ucLoan.NewUsecase(loanRepo).
+		WithDetails(NewApprovalRepository(db), NewInvestmentRepository(db), NewDisbursementRepository(db)).
+		ListByBorrower(ctx, ucLoan.BorrowerLoansInput{BorrowerID: borrower})
+	if err != nil {
+		t.Fatalf("ListByBorrower: %v", err)
+	}
+	states := map[string]string{}
+	for _, it := range out.Items {
+		states[it.LoanID] = it.State
+	}
+	if len(states) != 2 || states["LN-CANCEL"] != string(loanDomain.StateCancelled) || states["LN-KEEP"] != string(loanDomain.StateProposed) {
+		t.Fatalf("borrower loans: %+v", states)
+	}
+}
@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	webhookDomain "amartha-backend-test/internal/domain/webhook"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct{ db *gorm.DB }
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository { return &WebhookRepository{db: db} }
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s *webhookDomain.Subscription) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+func (r *WebhookRepository) ListSubscriptionsByPartner(ctx context.Context, partnerID string) ([]webhookDomain.Subscription, error) {
+	var out []webhookDomain.Subscription
+	err := r.db.WithContext(ctx).
+		Where("partner_id = ?", partnerID).
+		Order("id ASC").
+		Find(&out).Error
+	return out, err
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]webhookDomain.Subscription, error) {
+	var out []webhookDomain.Subscription
+	err := r.db.WithContext(ctx).Order("id ASC").Find(&out).Error
+	return out, err
+}
+
+func (r *WebhookRepository) GetSubscriptionByID(ctx context.Context, id uint64) (*webhookDomain.Subscription, error) {
+	var out webhookDomain.Subscription
+	res := r.db.WithContext(ctx).Unscoped().
+		Where("id = ?", id).
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *WebhookRepository) GetDelivery(ctx context.Context, subscriptionID uint64, eventID string) (*webhookDomain.Delivery, error) {
+	var out webhookDomain.Delivery
+	res := r.db.WithContext(ctx).
+		Where("subscription_id = ? AND event_id = ?", subscriptionID, eventID).
+		First(&out)
+	return &out, res.Error
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *webhookDomain.Delivery) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+func (r *WebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhookDomain.Delivery, error) {
+	var out []webhookDomain.Delivery
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", webhookDomain.StatusPending, now.UTC()).
+		Order("id ASC").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *WebhookRepository) SaveDelivery(ctx context.Context, d *webhookDomain.Delivery) error {
+	return r.db.WithContext(ctx).Save(d).Error
+}
+
+func (r *WebhookRepository) SearchDeliveries(ctx context.Context, f webhookDomain.DeliveryFilter) ([]webhookDomain.Delivery, error) {
+	q := r.db.WithContext(ctx).Model(&webhookDomain.Delivery{})
+	if f.PartnerID != "" {
+		q = q.Where("partner_id = ?", f.PartnerID)
+	}
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.EventID != "" {
+		q = q.Where("event_id = ?", f.EventID)
+	}
+	if f.BeforeID > 0 {
+		q = q.Where("id < ?", f.BeforeID)
+	}
+	var out []webhookDomain.Delivery
+	err := q.Order("id DESC").Limit(f.Limit).Find(&out).Error
+	return out, err
+}
@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/outbox"
+	webhookDomain "amartha-backend-test/internal/domain/webhook"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// --- SQLite-friendly schema only for tests (no engine specifics) ---
+type webhookSubscriptionSQLite struct {
+	ID             uint64         `gorm:"primaryKey;column:id;autoIncrement"`
+	SubscriptionID string         `gorm:"size:32;uniqueIndex;column:subscription_id"`
+	PartnerID      string         `gorm:"column:partner_id"`
+	URL            string         `gorm:"column:url"`
+	Secret         string         `gorm:"column:secret"`
+	EventTypes     string         `gorm:"column:event_types"`
+	CreatedAt      time.Time      `gorm:"column:created_at"`
+	UpdatedAt      time.Time      `gorm:"column:updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"column:deleted_at"`
+	DeletedBy      *string        `gorm:"column:deleted_by"`
+}
+
+func (webhookSubscriptionSQLite) TableName() string { return "webhook_subscriptions" }
+
+type webhookDeliverySQLite struct {
+	ID             uint64     `gorm:"primaryKey;column:id;autoIncrement"`
+	DeliveryID     string     `gorm:"size:32;uniqueIndex;column:delivery_id"`
+	SubscriptionID uint64     `gorm:"column:subscription_id;uniqueIndex:ux_whdel"`
+	PartnerID      string     `gorm:"column:partner_id"`
+	EventID        string     `gorm:"column:event_id;uniqueIndex:ux_whdel"`
+	EventType      string     `gorm:"column:event_type"`
+	Payload        string     `gorm:"column:payload"`
+	Status         string     `gorm:"column:status"`
+	Attempts       uint16     `gorm:"column:attempts"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at"`
+	LastStatusCode *int       `gorm:"column:last_status_code"`
+	LastError      *string    `gorm:"column:last_error"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at"`
+}
+
+func (webhookDeliverySQLite) TableName() string { return "webhook_deliveries" }
+
+// openWebhookTestDB creates an in-memory sqlite DB and migrates ONLY the sqlite-safe schema.
+func openWebhookTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&webhookSubscriptionSQLite{}, &webhookDeliverySQLite{}); err != nil {
+		t.Fatalf("auto-migrate: %v", err)
+	}
+	return db
+}
+
+func TestWebhook_Subscriptions(t *testing.T) {
+	db := openWebhookTestDB(t)
+	repo := NewWebhookRepository(db)
+	ctx := context.Background()
+	partnerA, partnerB := strings.Repeat("a", 32), strings.Repeat("b", 32)
+
+	subs := []*webhookDomain.Subscription{
+		{SubscriptionID: strings.Repeat("1", 32), PartnerID: partnerA, URL: "https://a.example/hook", Secret: strings.Repeat("s", 64), EventTypes: "LoanApproved"},
+		{SubscriptionID: strings.Repeat("2", 32), PartnerID: partnerB, URL: "https://b.example/hook", Secret: strings.Repeat("s", 64), EventTypes: "LoanInvested"},
+		{SubscriptionID: strings.Repeat("3", 32), PartnerID: partnerA, URL: "https://a.example/other", Secret: strings.Repeat("s", 64), EventTypes: "LoanDisbursed"},
+	}
+	for _, s := range subs {
+		if err := repo.CreateSubscription(ctx, s); err != nil {
+			t.Fatalf("CreateSubscription: %v", err)
+		}
+	}
+
+	got, err := repo.ListSubscriptionsByPartner(ctx, partnerA)
+	if err != nil || len(got) != 2 || got[0].ID != subs[0].ID || got[1].ID != subs[2].ID {
+		t.Fatalf("ListSubscriptionsByPartner = %+v, %v", got, err)
+	}
+	if all, err := repo.ListSubscriptions(ctx); err != nil || len(all) != 3 {
+		t.Fatalf("ListSubscriptions = %+v, %v", all, err)
+	}
+
+	// a removed subscription drops out of listings but stays readable by id
+	if err := db.Delete(&webhookDomain.Subscription{}, subs[1].ID).Error; err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if all, _ := repo.ListSubscriptions(ctx); len(all) != 2 {
+		t.Fatalf("deleted subscription still listed: %+v", all)
+	}
+	if s, err := repo.GetSubscriptionByID(ctx, subs[1].ID); err != nil || !s.DeletedAt.Valid {
+		t.Fatalf("GetSubscriptionByID(deleted) = %+v, %v", s, err)
+	}
+	if _, err := repo.GetSubscriptionByID(ctx, 999); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("missing: want ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestWebhook_Deliveries(t *testing.T) {
+	repo := NewWebhookRepository(openWebhookTestDB(t))
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	partnerA, partnerB := strings.Repeat("a", 32), strings.Repeat("b", 32)
+
+	mk := func(c string, sub uint64, partner, event string, status webhookDomain.Status, next time.Time) *webhookDomain.Delivery {
+		return &webhookDomain.Delivery{
+			DeliveryID: strings.Repeat(c, 32), SubscriptionID: sub, PartnerID: partner, EventID: strings.Repeat(event, 32),
+			EventType: outbox.LoanApproved, Payload: `{}`, Status: status, NextAttemptAt: next,
+		}
+	}
+	due := mk("1", 1, partnerA, "e", webhookDomain.StatusPending, now.Add(-time.Minute))
+	later := mk("2", 2, partnerB, "e", webhookDomain.StatusPending, now.Add(time.Minute))
+	dead := mk("3", 1, partnerA, "f", webhookDomain.StatusDead, now.Add(-time.Hour))
+	for _, d := range []*webhookDomain.Delivery{due, later, dead} {
+		if err := repo.CreateDelivery(ctx, d); err != nil {
+			t.Fatalf("CreateDelivery: %v", err)
+		}
+	}
+	dup := *due
+	dup.ID, dup.DeliveryID = 0, strings.Repeat("9", 32)
+	if err := repo.CreateDelivery(ctx, &dup); err == nil {
+		t.Fatalf("same subscription and event twice should violate the unique key")
+	}
+
+	if got, err := repo.GetDelivery(ctx, 1, strings.Repeat("e", 32)); err != nil || got.ID != due.ID {
+		t.Fatalf("GetDelivery = %+v, %v", got, err)
+	}
+	if _, err := repo.GetDelivery(ctx, 2, strings.Repeat("f", 32)); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("missing: want ErrRecordNotFound, got %v", err)
+	}
+
+	got, err := repo.ListDueDeliveries(ctx, now, 10)
+	if err != nil || len(got) != 1 || got[0].ID != due.ID {
+		t.Fatalf("ListDueDeliveries = %+v, %v", got, err)
+	}
+	code := 200
+	got[0].Status, got[0].Attempts, got[0].LastStatusCode, got[0].DeliveredAt = webhookDomain.StatusDelivered, 1, &code, &now
+	if err := repo.SaveDelivery(ctx, &got[0]); err != nil {
+		t.Fatalf("SaveDelivery: %v", err)
+	}
+	if got, _ := repo.ListDueDeliveries(ctx, now.Add(time.Hour), 10); len(got) != 1 || got[0].ID != later.ID {
+		t.Fatalf("after save = %+v", got)
+	}
+
+	// newest first, filtered, paged by BeforeID
+	list, err := repo.SearchDeliveries(ctx, webhookDomain.DeliveryFilter{PartnerID: partnerA, Limit: 10})
+	if err != nil || len(list) != 2 || list[0].ID != dead.ID || list[1].ID != due.ID {
+		t.Fatalf("SearchDeliveries(partner) = %+v, %v", list, err)
+	}
+	if list, _ := repo.SearchDeliveries(ctx, webhookDomain.DeliveryFilter{Status: webhookDomain.StatusDead, Limit: 10}); len(list) != 1 || list[0].ID != dead.ID {
+		t.Fatalf("SearchDeliveries(status) = %+v", list)
+	}
+	if list, _ := repo.SearchDeliveries(ctx, webhookDomain.DeliveryFilter{EventID: strings.Repeat("e", 32), Limit: 10}); len(list) != 2 {
+		t.Fatalf("SearchDeliveries(event) = %+v", list)
+	}
+	if list, _ := repo.SearchDeliveries(ctx, webhookDomain.DeliveryFilter{BeforeID: dead.ID, Limit: 1}); len(list) != 1 || list[0].ID != later.ID {
+		t.Fatalf("SearchDeliveries(before) = %+v", list)
+	}
+}
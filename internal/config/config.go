@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/mail"
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -21,6 +23,68 @@ type Config struct {
 	RedisDB   int
 
 	IdempTTLSecs int
+
+	// Days a rejected borrower must wait before proposing a new loan
+	RejectionCooldownDays int
+
+	// Daily DPD recompute time, "HH:MM" in WIB (Asia/Jakarta)
+	DPDJobAt string
+
+	// Blob storage for uploaded files: "local" (served back via /blobs) or "s3"
+	BlobDriver     string
+	BlobLocalDir   string
+	BlobPublicURL  string // base of local signed URLs, e.g. http://localhost:8080/blobs
+	BlobSigningKey string // HMAC key for local signed URLs
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+
+	// Lifetime of signed photo URLs
+	PhotoURLTTLMinutes int
+	// Lifetime of signed loan agreement URLs
+	AgreementURLTTLMinutes int
+
+	// In-process e-signature provider ("fake"); registered only when the
+	// webhook secret is set
+	ESignFakeSecret     string
+	ESignFakeSigningURL string
+
+	// Loan event outbox: how often the relay polls, and how often a message
+	// is tried before it is marked FAILED
+	OutboxRelaySecs   int
+	OutboxMaxAttempts int
+
+	// Partner webhooks: how often the dispatcher polls, how often a delivery
+	// is tried before it is DEAD, and the per-request timeout
+	WebhookDispatchSecs int
+	WebhookMaxAttempts  int
+	WebhookTimeoutSecs  int
+	// Accept plain http and private/loopback partner hosts (dev only)
+	WebhookAllowInsecure bool
+
+	// Disburse loans with no tracked envelope on the client's signature_status
+	DisburseAllowUntracked bool
+
+	// Shared with the payment gateway, which signs top-up callbacks with it;
+	// unset refuses every callback
+	PaymentGatewaySecret string
+
+	// Investor emails: "file" writes .eml files under MailFileDir (dev),
+	// "smtp" sends through SMTPAddr
+	MailDriver   string
+	MailFileDir  string
+	MailFrom     string
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	// How often the notification dispatcher polls, how often an email is
+	// tried before it is FAILED, and how long its agreement link stays valid
+	NotifyDispatchSecs int
+	NotifyMaxAttempts  int
+	NotifyLinkTTLHours int
 }
 
 func getenv(k, d string) string {
@@ -41,6 +105,46 @@ func Load() *Config {
 
 		RedisAddr:    getenv("REDIS_ADDR", "redis:6379"),
 		IdempTTLSecs: 300,
+
+		RejectionCooldownDays: 30,
+		DPDJobAt:              getenv("DPD_JOB_AT", "00:30"),
+
+		BlobDriver:     getenv("BLOB_DRIVER", "local"),
+		BlobLocalDir:   getenv("BLOB_LOCAL_DIR", "data/blobs"),
+		BlobPublicURL:  getenv("BLOB_PUBLIC_URL", "http://localhost:8080/blobs"),
+		BlobSigningKey: os.Getenv("BLOB_SIGNING_KEY"),
+
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Region:    getenv("S3_REGION", "us-east-1"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+
+		PhotoURLTTLMinutes:     15,
+		AgreementURLTTLMinutes: 60,
+
+		ESignFakeSecret:     os.Getenv("ESIGN_FAKE_SECRET"),
+		ESignFakeSigningURL: getenv("ESIGN_FAKE_SIGNING_URL", "http://localhost:8080/esign"),
+
+		PaymentGatewaySecret: os.Getenv("PAYMENT_GATEWAY_SECRET"),
+
+		OutboxRelaySecs:   5,
+		OutboxMaxAttempts: 10,
+
+		WebhookDispatchSecs: 10,
+		WebhookMaxAttempts:  12,
+		WebhookTimeoutSecs:  10,
+
+		MailDriver:   getenv("MAIL_DRIVER", "file"),
+		MailFileDir:  getenv("MAIL_FILE_DIR", "data/mail"),
+		MailFrom:     getenv("MAIL_FROM", "Amartha <no-reply@amartha.test>"),
+		SMTPAddr:     os.Getenv("SMTP_ADDR"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+
+		NotifyDispatchSecs: 15,
+		NotifyMaxAttempts:  8,
+		NotifyLinkTTLHours: 168,
 	}
 	if v := os.Getenv("REDIS_DB"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
@@ -52,6 +156,71 @@ func Load() *Config {
 			c.IdempTTLSecs = n
 		}
 	}
+	if v := os.Getenv("REJECTION_COOLDOWN_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.RejectionCooldownDays = n
+		}
+	}
+	if v := os.Getenv("PHOTO_URL_TTL_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.PhotoURLTTLMinutes = n
+		}
+	}
+	if v := os.Getenv("AGREEMENT_URL_TTL_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.AgreementURLTTLMinutes = n
+		}
+	}
+	if v := os.Getenv("OUTBOX_RELAY_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.OutboxRelaySecs = n
+		}
+	}
+	if v := os.Getenv("OUTBOX_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.OutboxMaxAttempts = n
+		}
+	}
+	if v := os.Getenv("WEBHOOK_DISPATCH_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.WebhookDispatchSecs = n
+		}
+	}
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.WebhookMaxAttempts = n
+		}
+	}
+	if v := os.Getenv("WEBHOOK_TIMEOUT_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.WebhookTimeoutSecs = n
+		}
+	}
+	if v := os.Getenv("WEBHOOK_ALLOW_INSECURE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			c.WebhookAllowInsecure = b
+		}
+	}
+	if v := os.Getenv("DISBURSE_ALLOW_UNTRACKED_SIGNATURE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			c.DisburseAllowUntracked = b
+		}
+	}
+	if v := os.Getenv("NOTIFY_DISPATCH_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.NotifyDispatchSecs = n
+		}
+	}
+	if v := os.Getenv("NOTIFY_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.NotifyMaxAttempts = n
+		}
+	}
+	if v := os.Getenv("NOTIFY_LINK_TTL_HOURS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.NotifyLinkTTLHours = n
+		}
+	}
 	return c
 }
 
@@ -66,9 +235,85 @@ func (c *Config) Validate() error {
 	if c.AppPort == "" {
 		return errors.New("missing APP_PORT")
 	}
+	if c.RejectionCooldownDays < 0 {
+		return errors.New("REJECTION_COOLDOWN_DAYS must not be negative")
+	}
+	if _, err := time.Parse("15:04", c.DPDJobAt); err != nil {
+		return fmt.Errorf("invalid DPD_JOB_AT %q (want HH:MM)", c.DPDJobAt)
+	}
+	switch c.BlobDriver {
+	case "local":
+		if len(c.BlobSigningKey) < 16 {
+			return errors.New("BLOB_SIGNING_KEY must be at least 16 characters for BLOB_DRIVER=local")
+		}
+	case "s3":
+		if c.S3Endpoint == "" || c.S3Bucket == "" || c.S3AccessKey == "" || c.S3SecretKey == "" {
+			return errors.New("missing S3 config (S3_ENDPOINT/BUCKET/ACCESS_KEY/SECRET_KEY)")
+		}
+	default:
+		return fmt.Errorf("invalid BLOB_DRIVER %q (want local or s3)", c.BlobDriver)
+	}
+	if c.PhotoURLTTLMinutes <= 0 || c.PhotoURLTTLMinutes > 7*24*60 {
+		return errors.New("PHOTO_URL_TTL_MINUTES must be between 1 and 10080")
+	}
+	if c.AgreementURLTTLMinutes <= 0 || c.AgreementURLTTLMinutes > 7*24*60 {
+		return errors.New("AGREEMENT_URL_TTL_MINUTES must be between 1 and 10080")
+	}
+	if c.ESignFakeSecret != "" && len(c.ESignFakeSecret) < 16 {
+		return errors.New("ESIGN_FAKE_SECRET must be at least 16 characters")
+	}
+	if c.PaymentGatewaySecret != "" && len(c.PaymentGatewaySecret) < 16 {
+		return errors.New("PAYMENT_GATEWAY_SECRET must be at least 16 characters")
+	}
+	if c.OutboxRelaySecs <= 0 || c.OutboxRelaySecs > 3600 {
+		return errors.New("OUTBOX_RELAY_SECONDS must be between 1 and 3600")
+	}
+	if c.OutboxMaxAttempts <= 0 || c.OutboxMaxAttempts > 100 {
+		return errors.New("OUTBOX_MAX_ATTEMPTS must be between 1 and 100")
+	}
+	if c.WebhookDispatchSecs <= 0 || c.WebhookDispatchSecs > 3600 {
+		return errors.New("WEBHOOK_DISPATCH_SECONDS must be between 1 and 3600")
+	}
+	if c.WebhookMaxAttempts <= 0 || c.WebhookMaxAttempts > 100 {
+		return errors.New("WEBHOOK_MAX_ATTEMPTS must be between 1 and 100")
+	}
+	if c.WebhookTimeoutSecs <= 0 || c.WebhookTimeoutSecs > 60 {
+		return errors.New("WEBHOOK_TIMEOUT_SECONDS must be between 1 and 60")
+	}
+	switch c.MailDriver {
+	case "file":
+		if c.MailFileDir == "" {
+			return errors.New("MAIL_FILE_DIR is required for MAIL_DRIVER=file")
+		}
+	case "smtp":
+		if _, _, err := net.SplitHostPort(c.SMTPAddr); err != nil {
+			return fmt.Errorf("invalid SMTP_ADDR %q (want host:port)", c.SMTPAddr)
+		}
+	default:
+		return fmt.Errorf("invalid MAIL_DRIVER %q (want file or smtp)", c.MailDriver)
+	}
+	if _, err := mail.ParseAddress(c.MailFrom); err != nil {
+		return fmt.Errorf("invalid MAIL_FROM %q", c.MailFrom)
+	}
+	if c.NotifyDispatchSecs <= 0 || c.NotifyDispatchSecs > 3600 {
+		return errors.New("NOTIFY_DISPATCH_SECONDS must be between 1 and 3600")
+	}
+	if c.NotifyMaxAttempts <= 0 || c.NotifyMaxAttempts > 100 {
+		return errors.New("NOTIFY_MAX_ATTEMPTS must be between 1 and 100")
+	}
+	// S3 presigned URLs live at most 7 days
+	if c.NotifyLinkTTLHours <= 0 || c.NotifyLinkTTLHours > 7*24 {
+		return errors.New("NOTIFY_LINK_TTL_HOURS must be between 1 and 168")
+	}
 	return nil
 }
 
+// DPDJobClock returns the hour and minute of DPD_JOB_AT; call after Validate.
+func (c *Config) DPDJobClock() (hour, minute int) {
+	t, _ := time.Parse("15:04", c.DPDJobAt)
+	return t.Hour(), t.Minute()
+}
+
 func (c *Config) mysqlAddr() string { return net.JoinHostPort(c.MySQLHost, c.MySQLPort) }
 
 func (c *Config) MySQLDSN() string {
@@ -0,0 +1,129 @@
+// Package agreement renders loan agreements from versioned templates. A
+// published template is never edited: changes go into a new version, so a
+// stored version and hash always identify the exact text a borrower saw.
+package agreement
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/pkg/money"
+)
+
+// CurrentVersion is the template new agreements are rendered from.
+const CurrentVersion = "v1"
+
+// ContentType of rendered documents.
+const ContentType = "text/plain; charset=utf-8"
+
+var (
+	ErrUnknownVersion = errors.New("unknown agreement template version")
+	ErrNotGenerated   = errors.New("loan has no generated agreement")
+)
+
+//go:embed templates/*.tmpl
+var files embed.FS
+
+var funcs = template.FuncMap{"inc": func(i int) int { return i + 1 }}
+
+// Lender is one investor's part of the principal.
+type Lender struct {
+	InvestorID string
+	Amount     money.Decimal
+	Share      money.Decimal // percent of principal, 2 dp; shares sum to 100
+}
+
+// Terms is everything a template may print.
+type Terms struct {
+	Version        string
+	Date           time.Time
+	LoanID         string
+	BorrowerID     string
+	Principal      money.Decimal
+	Rate           money.Decimal
+	ROI            money.Decimal
+	Tenor          uint16
+	TenorUnit      loan.TenorUnit
+	InterestMethod loan.InterestMethod
+	Investors      []Lender
+}
+
+// Document is a rendered agreement.
+type Document struct {
+	Version string
+	Body    []byte
+	SHA256  string // hex of Body
+}
+
+// TermsFor collects l's terms and lenders. Investments by the same investor
+// are merged, in order of their first investment; shares are allocated so
+// they add up to exactly 100.
+func TermsFor(l *loan.Loan, invs []investment.Investment, date time.Time) Terms {
+	var lenders []Lender
+	at := map[string]int{}
+	for _, inv := range invs {
+		if inv.Status == investment.StatusReleased {
+			continue
+		}
+		i, ok := at[inv.InvestorID]
+		if !ok {
+			i = len(lenders)
+			at[inv.InvestorID] = i
+			lenders = append(lenders, Lender{InvestorID: inv.InvestorID, Amount: money.Zero})
+		}
+		lenders[i].Amount = lenders[i].Amount.Add(inv.Amount)
+	}
+	weights := make([]money.Decimal, len(lenders))
+	for i, ld := range lenders {
+		weights[i] = ld.Amount
+	}
+	if shares := money.NewFromInt(100).Allocate(weights, 2); shares != nil {
+		for i := range lenders {
+			lenders[i].Share = shares[i]
+		}
+	}
+	method := l.InterestMethod
+	if method == "" {
+		method = loan.InterestFlat
+	}
+	return Terms{
+		Date:           date.UTC(),
+		LoanID:         l.LoanID,
+		BorrowerID:     l.BorrowerID,
+		Principal:      l.Principal,
+		Rate:           l.Rate,
+		ROI:            l.ROI,
+		Tenor:          l.Tenor,
+		TenorUnit:      l.TenorUnit,
+		InterestMethod: method,
+		Investors:      lenders,
+	}
+}
+
+// Render fills template version with t.
+func Render(version string, t Terms) (*Document, error) {
+	tmpl, err := template.New(version+".tmpl").Funcs(funcs).ParseFS(files, "templates/"+version+".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownVersion, version)
+	}
+	t.Version = version
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, t); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return &Document{Version: version, Body: buf.Bytes(), SHA256: hex.EncodeToString(sum[:])}, nil
+}
+
+// Key is where a loan's agreement is stored in the blob store.
+func Key(loanID, version string) string {
+	return "agreements/" + loanID + "/agreement-" + version + ".txt"
+}
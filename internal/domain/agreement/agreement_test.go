@@ -0,0 +1,87 @@
+package agreement
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/pkg/money"
+)
+
+func fundedLoan() (*loan.Loan, []investment.Investment) {
+	l := &loan.Loan{
+		LoanID: "LN-1", BorrowerID: strings.Repeat("b", 32),
+		Principal: money.NewFromInt(100), Rate: money.MustParse("1.5"), ROI: money.MustParse("1.2"),
+		Tenor: 4, TenorUnit: loan.TenorWeek,
+	}
+	a, b, c := strings.Repeat("a", 32), strings.Repeat("c", 32), strings.Repeat("d", 32)
+	return l, []investment.Investment{
+		{InvestorID: a, Amount: money.NewFromInt(20), Status: investment.StatusHeld},
+		{InvestorID: b, Amount: money.NewFromInt(50), Status: investment.StatusReleased},
+		{InvestorID: c, Amount: money.MustParse("33.33"), Status: investment.StatusHeld},
+		{InvestorID: a, Amount: money.MustParse("46.67"), Status: investment.StatusHeld},
+	}
+}
+
+func TestTermsFor(t *testing.T) {
+	l, invs := fundedLoan()
+	terms := TermsFor(l, invs, time.Date(2026, 3, 1, 20, 0, 0, 0, time.FixedZone("WIB", 7*3600)))
+
+	if terms.InterestMethod != loan.InterestFlat || terms.Date.Location() != time.UTC {
+		t.Fatalf("defaults: %+v", terms)
+	}
+	// released investments drop out; repeat investors are merged in first-seen order
+	if len(terms.Investors) != 2 {
+		t.Fatalf("lenders = %+v", terms.Investors)
+	}
+	first, second := terms.Investors[0], terms.Investors[1]
+	if first.InvestorID != strings.Repeat("a", 32) || first.Amount.String() != "66.67" || first.Share.String() != "66.67" {
+		t.Fatalf("first lender: %+v", first)
+	}
+	if second.Share.String() != "33.33" || !first.Share.Add(second.Share).Equal(money.NewFromInt(100)) {
+		t.Fatalf("shares must sum to 100: %+v", terms.Investors)
+	}
+}
+
+func TestRender(t *testing.T) {
+	l, invs := fundedLoan()
+	terms := TermsFor(l, invs, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	doc, err := Render(CurrentVersion, terms)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	body := string(doc.Body)
+	for _, want := range []string{
+		"Template v1", "Agreement date : 2026-03-01", "Loan           : LN-1", "Borrower       : " + strings.Repeat("b", 32),
+		"Principal      : IDR 100.00", "1.5% per week, flat method", "Tenor          : 4 week(s)", "Investor return: 1.2% per week",
+		"1. " + strings.Repeat("a", 32) + "  IDR 66.67  (66.67%)", "2. " + strings.Repeat("d", 32) + "  IDR 33.33  (33.33%)",
+		"from 2 lender(s)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("missing %q in:\n%s", want, body)
+		}
+	}
+	sum := sha256.Sum256(doc.Body)
+	if doc.Version != "v1" || doc.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Fatalf("version/hash: %s %s", doc.Version, doc.SHA256)
+	}
+
+	// same terms, same bytes: the hash proves the text
+	again, _ := Render(CurrentVersion, terms)
+	if again.SHA256 != doc.SHA256 {
+		t.Fatalf("rendering is not deterministic")
+	}
+
+	if _, err := Render("v0", terms); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("unknown version: %v", err)
+	}
+	if got := Key("LN-1", "v1"); got != "agreements/LN-1/agreement-v1.txt" {
+		t.Fatalf("Key = %s", got)
+	}
+}
@@ -4,13 +4,24 @@ import (
 	"errors"
 	"time"
 
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/photo"
+	"amartha-backend-test/pkg/phash"
+
 	"gorm.io/gorm"
 )
 
 var (
-	ErrNotFound = errors.New("approval not found")
+	ErrNotFound       = errors.New("approval not found")
+	ErrLoanHasFunding = loan.ErrHasInvestments // the revoke edge's guard
+	ErrDuplicatePhoto = errors.New("photo is a near-duplicate of one used by another approval")
 )
 
+// PhotoBands is how many pieces a photo hash is indexed in. Two hashes within
+// photo.NearDuplicateDistance bits share at least one piece, so looking the
+// pieces up finds every near-duplicate without scanning all approvals.
+const PhotoBands = photo.NearDuplicateDistance + 1
+
 // Table: approvals (matches your DDL)
 type Approval struct {
 	// Internal numeric PK
@@ -18,7 +29,12 @@ type Approval struct {
 	// Public identifier (32-char lowercase hex)
 	ApprovalID string `gorm:"column:approval_id;type:char(32);not null;uniqueIndex:ux_approvals_approval_id_active"`
 	// FK to loans.id (numeric)
-	LoanID              uint64         `gorm:"column:loan_id;not null;index;uniqueIndex:ux_approvals_loan_active"`
+	LoanID uint64 `gorm:"column:loan_id;not null;index;uniqueIndex:ux_approvals_loan_active"`
+	// Photo uploaded through POST /photos; NULL on approvals that predate uploads
+	PhotoID *string `gorm:"column:photo_id;type:char(32);index"`
+	// Perceptual hash copied from the photo, compared across approvals
+	PhotoPHash *phash.Hash `gorm:"column:photo_phash"`
+	// Blob storage key of that photo (a bare URL on older approvals)
 	PhotoURL            string         `gorm:"column:photo_url;type:text;not null"`
 	ValidatorEmployeeID string         `gorm:"column:validator_employee_id;type:char(32);not null"`
 	ApprovalDate        time.Time      `gorm:"column:approval_date;type:date;not null"`
@@ -29,3 +45,25 @@ type Approval struct {
 }
 
 func (Approval) TableName() string { return "approvals" }
+
+// Table: approval_photo_bands (PhotoBands rows per approval with a photo hash)
+type PhotoBand struct {
+	// FK to approvals.id (numeric)
+	ApprovalID uint64 `gorm:"column:approval_id;primaryKey"`
+	Band       uint8  `gorm:"column:band;primaryKey;index:idx_approval_photo_bands_lookup,priority:1"`
+	Value      uint64 `gorm:"column:value;not null;index:idx_approval_photo_bands_lookup,priority:2"`
+}
+
+func (PhotoBand) TableName() string { return "approval_photo_bands" }
+
+// PhotoBandsOf returns the band rows of a, none if its photo has no hash.
+func PhotoBandsOf(a *Approval) []PhotoBand {
+	if a.PhotoPHash == nil {
+		return nil
+	}
+	out := make([]PhotoBand, 0, PhotoBands)
+	for i, v := range phash.Bands(*a.PhotoPHash, PhotoBands) {
+		out = append(out, PhotoBand{ApprovalID: a.ID, Band: uint8(i), Value: v})
+	}
+	return out
+}
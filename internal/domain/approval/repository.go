@@ -1,14 +1,31 @@
 package approval
 
-import "context"
+import (
+	"context"
+
+	"amartha-backend-test/pkg/phash"
+)
 
 type Repository interface {
-	// Create a new approval (DB uniqueness ensures at most one per loan)
+	// Create a new approval (DB uniqueness ensures at most one per loan),
+	// together with its photo hash bands
 	Create(ctx context.Context, a *Approval) error
 
 	// Get  approval by loan ID
 	GetByLoanID(ctx context.Context, loanID uint64) (*Approval, error)
 
+	// List approvals of the given loans (numeric loan IDs); loans without one are skipped
+	ListByLoanIDs(ctx context.Context, loanIDs []uint64) ([]Approval, error)
+
+	// SoftDeleteByLoanID stamps deleted_at and deleted_by on the loan's active approval
+	SoftDeleteByLoanID(ctx context.Context, loanID uint64, by string) error
+
 	// Get by public approval_id
 	GetByApprovalID(ctx context.Context, approvalID string) (*Approval, error)
+
+	// FindNearPhoto returns the oldest approval of another loan, revoked and
+	// cancelled ones included, whose photo hash is within maxDistance bits of
+	// h (gorm.ErrRecordNotFound if none). Only hashes sharing a band with h
+	// are compared, so maxDistance must be below PhotoBands.
+	FindNearPhoto(ctx context.Context, h phash.Hash, maxDistance int, exceptLoanID uint64) (*Approval, error)
 }
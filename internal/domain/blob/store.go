@@ -0,0 +1,50 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrNotFound     = errors.New("blob not found")
+	ErrInvalidKey   = errors.New("blob key must be a relative slash-separated path")
+	ErrBadSignature = errors.New("blob URL signature is invalid or expired")
+)
+
+// Store keeps uploaded files out of the database. Keys are relative,
+// slash-separated paths chosen by the caller (e.g. "photos/<id>.jpg").
+type Store interface {
+	// Put writes size bytes from r under key, replacing any previous blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the blob under key (ErrNotFound if there is none)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// SignedURL returns a URL that lets its holder read key until ttl elapses
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// ValidKey reports whether key is a clean relative path: no leading slash,
+// no empty, "." or ".." segments.
+func ValidKey(key string) bool {
+	if key == "" || len(key) > 512 {
+		return false
+	}
+	start := 0
+	for i := 0; i <= len(key); i++ {
+		if i < len(key) && key[i] != '/' {
+			if key[i] == '\\' || key[i] < 0x20 {
+				return false
+			}
+			continue
+		}
+		switch key[start:i] {
+		case "", ".", "..":
+			return false
+		}
+		start = i + 1
+	}
+	return true
+}
@@ -0,0 +1,24 @@
+package blob
+
+import "testing"
+
+func TestValidKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"photos/abc.jpg":     true,
+		"a":                  true,
+		"a/b/c.png":          true,
+		"":                   false,
+		"/photos/abc.jpg":    false,
+		"photos//abc.jpg":    false,
+		"photos/abc.jpg/":    false,
+		"../etc/passwd":      false,
+		"photos/./abc.jpg":   false,
+		"photos\\abc.jpg":    false,
+		"photos/\x00.jpg":    false,
+		"photos/../../x.jpg": false,
+	} {
+		if got := ValidKey(key); got != want {
+			t.Errorf("ValidKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package disbursement
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SignatureStatus string
+
+const (
+	SignaturePending   SignatureStatus = "PENDING"
+	SignatureSigned    SignatureStatus = "SIGNED"
+	SignatureCancelled SignatureStatus = "CANCELLED"
+)
+
+var (
+	ErrNotFound           = errors.New("disbursement not found")
+	ErrAgreementNotSigned = errors.New("agreement must be SIGNED before disbursement")
+)
+
+// Table: disbursements (matches your DDL)
+type Disbursement struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	DisbursementID string `gorm:"column:disbursement_id;type:char(32);not null;uniqueIndex:ux_disb_disbursement_id_active"`
+	// FK to loans.id (numeric)
+	LoanID             uint64          `gorm:"column:loan_id;not null;uniqueIndex:ux_disb_loan_active"`
+	SignedAgreementURL string          `gorm:"column:signed_agreement_url;type:text;not null"`
+	SignatureProvider  string          `gorm:"column:signature_provider;type:varchar(64);not null;uniqueIndex:ux_disb_sig_active"`
+	SignatureTxID      string          `gorm:"column:signature_tx_id;type:varchar(128);not null;uniqueIndex:ux_disb_sig_active"`
+	SignatureStatus    SignatureStatus `gorm:"column:signature_status;type:enum('PENDING','SIGNED','CANCELLED');not null;default:'PENDING'"`
+	SignedAt           *time.Time      `gorm:"column:signed_at;type:datetime"`
+	DocumentSHA256     *string         `gorm:"column:document_sha256;type:char(64)"`
+	OfficerEmployeeID  string          `gorm:"column:officer_employee_id;type:char(32);not null"`
+	DisbursementDate   time.Time       `gorm:"column:disbursement_date;type:date;not null"`
+	CreatedAt          time.Time       `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt          time.Time       `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt          gorm.DeletedAt  `gorm:"column:deleted_at;index"`
+	DeletedBy          *string         `gorm:"column:deleted_by;type:char(32);"`
+}
+
+func (Disbursement) TableName() string { return "disbursements" }
@@ -0,0 +1,17 @@
+package disbursement
+
+import "context"
+
+type Repository interface {
+	// Create a new disbursement (DB uniqueness ensures at most one per loan)
+	Create(ctx context.Context, d *Disbursement) error
+
+	// Get disbursement by loan ID
+	GetByLoanID(ctx context.Context, loanID uint64) (*Disbursement, error)
+
+	// List disbursements of the given loans (numeric loan IDs); loans without one are skipped
+	ListByLoanIDs(ctx context.Context, loanIDs []uint64) ([]Disbursement, error)
+
+	// Get by public disbursement_id
+	GetByDisbursementID(ctx context.Context, disbursementID string) (*Disbursement, error)
+}
@@ -0,0 +1,56 @@
+package investment
+
+import (
+	"errors"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotFound         = errors.New("investment not found")
+	ErrInvalidAmount    = errors.New("investment amount must be greater than zero")
+	ErrExceedsPrincipal = errors.New("investment exceeds remaining loan principal")
+)
+
+// Status tracks the wallet funds behind an investment: held in the loan's
+// escrow when placed, then converted at disbursement or released back to the
+// wallet if the loan is cancelled.
+type Status string
+
+const (
+	StatusHeld      Status = "held"
+	StatusReleased  Status = "released"
+	StatusConverted Status = "converted"
+)
+
+// Table: investments (matches your DDL)
+type Investment struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	InvestmentID string `gorm:"column:investment_id;type:char(32);not null;uniqueIndex:ux_investments_investment_id_active"`
+	// FK to loans.id (numeric)
+	LoanID     uint64 `gorm:"column:loan_id;not null;index:idx_investments_loan_active"`
+	InvestorID string `gorm:"column:investor_id;type:char(32);not null;index:idx_investments_investor_active"`
+	// Where the investor is notified about this loan; optional
+	InvestorEmail *string        `gorm:"column:investor_email;type:varchar(254)"`
+	Amount        money.Decimal  `gorm:"column:amount;type:decimal(18,2);not null"`
+	Status        Status         `gorm:"column:status;type:enum('held','released','converted');not null;default:held"`
+	CreatedAt     time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	DeletedBy     *string        `gorm:"column:deleted_by;type:char(32);"`
+}
+
+func (Investment) TableName() string { return "investments" }
+
+// Funding is a loan's investment progress: unreleased investments only.
+type Funding struct {
+	LoanID      uint64
+	Invested    money.Decimal
+	Investments int
+	Investors   int // distinct investor_id
+}
@@ -0,0 +1,21 @@
+package investment
+
+import "context"
+
+type Repository interface {
+	// Create a new investment row
+	Create(ctx context.Context, i *Investment) error
+
+	// List active investments of a loan (numeric loan ID), oldest first
+	ListByLoanID(ctx context.Context, loanID uint64) ([]Investment, error)
+
+	// List active investments of an investor, oldest first
+	ListByInvestorID(ctx context.Context, investorID string) ([]Investment, error)
+
+	// FundingByLoanIDs sums the unreleased investments of the given loans in
+	// one grouped query; loans without investments are skipped
+	FundingByLoanIDs(ctx context.Context, loanIDs []uint64) ([]Funding, error)
+
+	// UpdateStatusByLoanID moves a loan's investments in status from to status to
+	UpdateStatusByLoanID(ctx context.Context, loanID uint64, from, to Status) error
+}
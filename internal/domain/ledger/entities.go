@@ -0,0 +1,201 @@
+package ledger
+
+import (
+	"errors"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnbalanced     = errors.New("journal entry debits must equal credits")
+	ErrEmptyEntry     = errors.New("journal entry needs at least one debit and one credit")
+	ErrInvalidAmount  = errors.New("journal line amount must be positive with at most 2 decimal places")
+	ErrInvalidAccount = errors.New("invalid ledger account")
+	ErrImmutable      = errors.New("journal entries are immutable")
+)
+
+// AccountType is one of the fixed charts of accounts below.
+type AccountType string
+
+const (
+	// Liabilities (credit-normal): money the platform holds for someone else
+	AccountInvestorWallet AccountType = "investor_wallet" // owner: investor_id
+	AccountEscrow         AccountType = "escrow"          // owner: loan_id; investors' stake in a loan
+	// Assets (debit-normal)
+	AccountBorrowerReceivable AccountType = "borrower_receivable" // owner: loan_id; unpaid principal
+	AccountCash               AccountType = "cash"
+	// Revenue (credit-normal)
+	AccountPlatformFee AccountType = "platform_fee"
+)
+
+var AccountTypes = []AccountType{
+	AccountInvestorWallet, AccountEscrow, AccountBorrowerReceivable, AccountCash, AccountPlatformFee,
+}
+
+func (t AccountType) Valid() bool {
+	for _, v := range AccountTypes {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Owned reports whether the account is kept per investor or per loan.
+func (t AccountType) Owned() bool {
+	return t == AccountInvestorWallet || t == AccountEscrow || t == AccountBorrowerReceivable
+}
+
+// DebitNormal reports whether debits increase the account's balance.
+func (t AccountType) DebitNormal() bool {
+	return t == AccountBorrowerReceivable || t == AccountCash
+}
+
+// Account is an account type plus its owner ("" for platform-wide accounts).
+type Account struct {
+	Type    AccountType
+	OwnerID string
+}
+
+func InvestorWallet(investorID string) Account { return Account{AccountInvestorWallet, investorID} }
+func Escrow(loanID string) Account             { return Account{AccountEscrow, loanID} }
+func BorrowerReceivable(loanID string) Account { return Account{AccountBorrowerReceivable, loanID} }
+
+var (
+	Cash        = Account{Type: AccountCash}
+	PlatformFee = Account{Type: AccountPlatformFee}
+)
+
+// Validate checks the type is known and the owner matches it.
+func (a Account) Validate() error {
+	if !a.Type.Valid() || a.Type.Owned() == (a.OwnerID == "") {
+		return ErrInvalidAccount
+	}
+	return nil
+}
+
+type Direction string
+
+const (
+	Debit  Direction = "debit"
+	Credit Direction = "credit"
+)
+
+// EntryKind names the business event an entry records.
+type EntryKind string
+
+const (
+	KindTopUp        EntryKind = "topup"
+	KindInvestment   EntryKind = "investment"
+	KindRelease      EntryKind = "release"
+	KindDisbursement EntryKind = "disbursement"
+	KindRepayment    EntryKind = "repayment"
+)
+
+// Table: journal_entries (append-only; never updated or deleted)
+type Entry struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	EntryID string    `gorm:"column:entry_id;type:char(32);not null;uniqueIndex:ux_je_entry_id"`
+	Kind    EntryKind `gorm:"column:kind;type:varchar(32);not null;uniqueIndex:ux_je_ref"`
+	// Public id of the investment/disbursement/repayment that caused it
+	RefID string `gorm:"column:ref_id;type:char(32);not null;uniqueIndex:ux_je_ref"`
+	// FK to loans.id (numeric); nil for entries not tied to a loan
+	LoanID    *uint64   `gorm:"column:loan_id;index:idx_je_loan"`
+	PostedAt  time.Time `gorm:"column:posted_at;type:datetime;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	Lines     []Line    `gorm:"foreignKey:EntryID;references:ID"`
+}
+
+func (Entry) TableName() string { return "journal_entries" }
+
+// Table: journal_lines (one debit or credit of an entry)
+type Line struct {
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// FK to journal_entries.id (numeric)
+	EntryID   uint64        `gorm:"column:entry_id;not null;index:idx_jl_entry"`
+	Account   AccountType   `gorm:"column:account;type:varchar(32);not null;index:idx_jl_account"`
+	OwnerID   string        `gorm:"column:owner_id;type:varchar(32);not null;index:idx_jl_account"`
+	Direction Direction     `gorm:"column:direction;type:enum('debit','credit');not null"`
+	Amount    money.Decimal `gorm:"column:amount;type:decimal(18,2);not null"`
+}
+
+func (Line) TableName() string { return "journal_lines" }
+
+// Table: ledger_accounts (one row per account that has been locked; see Repository.Lock)
+type AccountRecord struct {
+	Account   AccountType `gorm:"column:account;type:varchar(32);primaryKey"`
+	OwnerID   string      `gorm:"column:owner_id;type:varchar(32);primaryKey"`
+	CreatedAt time.Time   `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (AccountRecord) TableName() string { return "ledger_accounts" }
+
+// Immutability: saving or deleting an existing entry or line is refused.
+func (Entry) BeforeUpdate(*gorm.DB) error { return ErrImmutable }
+func (Entry) BeforeDelete(*gorm.DB) error { return ErrImmutable }
+func (Line) BeforeUpdate(*gorm.DB) error  { return ErrImmutable }
+func (Line) BeforeDelete(*gorm.DB) error  { return ErrImmutable }
+
+// NewEntry starts an entry; add lines with Debit/Credit, then Validate.
+func NewEntry(kind EntryKind, refID string, loanID *uint64, at time.Time) *Entry {
+	return &Entry{Kind: kind, RefID: refID, LoanID: loanID, PostedAt: at.UTC()}
+}
+
+// Debit appends a debit line; zero amounts are skipped so callers can pass
+// optional parts (e.g. a fee) unconditionally.
+func (e *Entry) Debit(a Account, amount money.Decimal) *Entry { return e.add(a, Debit, amount) }
+
+// Credit appends a credit line; zero amounts are skipped.
+func (e *Entry) Credit(a Account, amount money.Decimal) *Entry { return e.add(a, Credit, amount) }
+
+func (e *Entry) add(a Account, d Direction, amount money.Decimal) *Entry {
+	if amount.IsZero() {
+		return e
+	}
+	e.Lines = append(e.Lines, Line{Account: a.Type, OwnerID: a.OwnerID, Direction: d, Amount: amount})
+	return e
+}
+
+// Validate enforces the double-entry invariants: known accounts, positive
+// cent amounts, at least one line per side and debits equal to credits.
+func (e *Entry) Validate() error {
+	debits, credits := money.Zero, money.Zero
+	var nd, nc int
+	for _, l := range e.Lines {
+		if err := (Account{l.Account, l.OwnerID}).Validate(); err != nil {
+			return err
+		}
+		if !l.Amount.IsPositive() || l.Amount.Places() > 2 {
+			return ErrInvalidAmount
+		}
+		switch l.Direction {
+		case Debit:
+			debits, nd = debits.Add(l.Amount), nd+1
+		case Credit:
+			credits, nc = credits.Add(l.Amount), nc+1
+		default:
+			return ErrInvalidAccount
+		}
+	}
+	if nd == 0 || nc == 0 {
+		return ErrEmptyEntry
+	}
+	if !debits.Equal(credits) {
+		return ErrUnbalanced
+	}
+	return nil
+}
+
+// Signed returns amount as it moves a's balance: positive on the account's
+// normal side, negative on the other.
+func Signed(t AccountType, d Direction, amount money.Decimal) money.Decimal {
+	if (d == Debit) == t.DebitNormal() {
+		return amount
+	}
+	return amount.Neg()
+}
@@ -0,0 +1,65 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+var at = time.Date(2025, 10, 10, 9, 0, 0, 0, time.UTC)
+
+func TestAccountValidate(t *testing.T) {
+	for _, a := range []Account{Cash, PlatformFee, InvestorWallet("inv"), Escrow("ln"), BorrowerReceivable("ln")} {
+		if err := a.Validate(); err != nil {
+			t.Fatalf("%+v: unexpected %v", a, err)
+		}
+	}
+	for _, a := range []Account{
+		{Type: "bank"},
+		{Type: AccountCash, OwnerID: "someone"}, // platform accounts have no owner
+		{Type: AccountInvestorWallet},           // per-investor accounts need one
+		Escrow(""),
+	} {
+		if err := a.Validate(); !errors.Is(err, ErrInvalidAccount) {
+			t.Fatalf("%+v: want ErrInvalidAccount, got %v", a, err)
+		}
+	}
+}
+
+func TestEntryValidate(t *testing.T) {
+	d := money.MustParse
+	cases := []struct {
+		name  string
+		entry *Entry
+		want  error
+	}{
+		{"balanced", NewEntry(KindInvestment, "x", nil, at).Debit(Cash, d("100")).Credit(Escrow("ln"), d("60")).Credit(Escrow("ln"), d("40")), nil},
+		{"zero lines are skipped", NewEntry(KindInvestment, "x", nil, at).Debit(Cash, d("1")).Credit(Escrow("ln"), d("1")).Credit(PlatformFee, money.Zero), nil},
+		{"unbalanced", NewEntry(KindInvestment, "x", nil, at).Debit(Cash, d("100")).Credit(Escrow("ln"), d("99.99")), ErrUnbalanced},
+		{"one-sided", NewEntry(KindInvestment, "x", nil, at).Debit(Cash, d("1")), ErrEmptyEntry},
+		{"empty", NewEntry(KindInvestment, "x", nil, at), ErrEmptyEntry},
+		{"negative", NewEntry(KindInvestment, "x", nil, at).Debit(Cash, d("-1")).Credit(Escrow("ln"), d("-1")), ErrInvalidAmount},
+		{"sub-cent", NewEntry(KindInvestment, "x", nil, at).Debit(Cash, d("0.001")).Credit(Escrow("ln"), d("0.001")), ErrInvalidAmount},
+		{"bad account", NewEntry(KindInvestment, "x", nil, at).Debit(Cash, d("1")).Credit(Escrow(""), d("1")), ErrInvalidAccount},
+	}
+	for _, c := range cases {
+		if err := c.entry.Validate(); !errors.Is(err, c.want) {
+			t.Fatalf("%s: want %v, got %v", c.name, c.want, err)
+		}
+	}
+}
+
+func TestSigned(t *testing.T) {
+	one := money.NewFromInt(1)
+	if Signed(AccountCash, Debit, one).String() != "1" || Signed(AccountCash, Credit, one).String() != "-1" {
+		t.Fatalf("cash is debit-normal")
+	}
+	if Signed(AccountInvestorWallet, Credit, one).String() != "1" || Signed(AccountEscrow, Debit, one).String() != "-1" {
+		t.Fatalf("wallet/escrow are credit-normal")
+	}
+	if Signed(AccountPlatformFee, Credit, one).String() != "1" || Signed(AccountBorrowerReceivable, Debit, one).String() != "1" {
+		t.Fatalf("fee credit-normal, receivable debit-normal")
+	}
+}
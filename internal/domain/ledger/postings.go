@@ -0,0 +1,93 @@
+package ledger
+
+import (
+	"time"
+
+	"amartha-backend-test/internal/domain/disbursement"
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/payout"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/money"
+)
+
+// ForTopUp: the payment gateway settled a top-up into the investor's wallet.
+//
+//	Dr cash                        amount
+//	Cr investor_wallet(investor)   amount
+func ForTopUp(investorID, gatewayRef string, amount money.Decimal, at time.Time) *Entry {
+	return NewEntry(KindTopUp, gatewayRef, nil, at).
+		Debit(Cash, amount).
+		Credit(InvestorWallet(investorID), amount)
+}
+
+// ForInvestment: the amount leaves the investor's wallet and is held in the
+// loan's escrow until the loan is disbursed (converted) or cancelled (released).
+//
+//	Dr investor_wallet(investor)   amount
+//	Cr escrow(loan)                amount
+func ForInvestment(l *loan.Loan, inv *investment.Investment, at time.Time) *Entry {
+	return NewEntry(KindInvestment, inv.InvestmentID, &l.ID, at).
+		Debit(InvestorWallet(inv.InvestorID), inv.Amount).
+		Credit(Escrow(l.LoanID), inv.Amount)
+}
+
+// ForRelease reverses ForInvestment when a held investment is given back.
+//
+//	Dr escrow(loan)                amount
+//	Cr investor_wallet(investor)   amount
+func ForRelease(l *loan.Loan, inv *investment.Investment, at time.Time) *Entry {
+	return NewEntry(KindRelease, inv.InvestmentID, &l.ID, at).
+		Debit(Escrow(l.LoanID), inv.Amount).
+		Credit(InvestorWallet(inv.InvestorID), inv.Amount)
+}
+
+// ForDisbursement: principal is paid out and the borrower now owes it.
+// Escrow keeps the investors' stake until principal comes back.
+//
+//	Dr borrower_receivable(loan)   principal
+//	Cr cash                        principal
+func ForDisbursement(l *loan.Loan, d *disbursement.Disbursement) *Entry {
+	return NewEntry(KindDisbursement, d.DisbursementID, &l.ID, d.DisbursementDate).
+		Debit(BorrowerReceivable(l.LoanID), l.Principal).
+		Credit(Cash, l.Principal)
+}
+
+// ForRepayment: the borrower's payment comes in, principal leaves the
+// receivable and the investors' escrow stake, each investor's payout lands in
+// their wallet, and fees plus the interest spread are platform revenue.
+//
+//	Dr cash                        amount
+//	Dr escrow(loan)                principal
+//	Cr borrower_receivable(loan)   principal
+//	Cr investor_wallet(investor)   payout total (one line per investor)
+//	Cr platform_fee                fee + interest not paid out
+func ForRepayment(l *loan.Loan, p *repayment.Repayment, payouts []payout.Payout) *Entry {
+	e := NewEntry(KindRepayment, p.RepaymentID, &l.ID, p.PaidAt).
+		Debit(Cash, p.Amount).
+		Debit(Escrow(l.LoanID), p.PrincipalPaid).
+		Credit(BorrowerReceivable(l.LoanID), p.PrincipalPaid)
+
+	// One wallet line per investor, in payout (investment ID) order
+	type share struct {
+		investorID string
+		total      money.Decimal
+	}
+	var shares []share
+	seen := map[string]int{}
+	interestPaidOut := money.Zero
+	for _, po := range payouts {
+		i, ok := seen[po.InvestorID]
+		if !ok {
+			i = len(shares)
+			seen[po.InvestorID] = i
+			shares = append(shares, share{investorID: po.InvestorID})
+		}
+		shares[i].total = shares[i].total.Add(po.Total)
+		interestPaidOut = interestPaidOut.Add(po.Interest)
+	}
+	for _, s := range shares {
+		e.Credit(InvestorWallet(s.investorID), s.total)
+	}
+	return e.Credit(PlatformFee, p.FeePaid.Add(p.InterestPaid).Sub(interestPaidOut))
+}
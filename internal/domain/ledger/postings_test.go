@@ -0,0 +1,114 @@
+package ledger
+
+import (
+	"testing"
+
+	"amartha-backend-test/internal/domain/disbursement"
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/payout"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/money"
+)
+
+// balances replays entries into per-account balances on each account's normal side.
+func balances(entries ...*Entry) map[Account]string {
+	sum := map[Account]money.Decimal{}
+	for _, e := range entries {
+		for _, l := range e.Lines {
+			a := Account{l.Account, l.OwnerID}
+			sum[a] = sum[a].Add(Signed(l.Account, l.Direction, l.Amount))
+		}
+	}
+	out := map[Account]string{}
+	for a, v := range sum {
+		out[a] = v.String()
+	}
+	return out
+}
+
+func TestPostings_LoanLifecycleBalances(t *testing.T) {
+	l := &loan.Loan{ID: 7, LoanID: "ln", Principal: money.NewFromInt(1000)}
+	inv := []*investment.Investment{
+		{ID: 1, InvestmentID: "i1", InvestorID: "alice", Amount: money.NewFromInt(600)},
+		{ID: 2, InvestmentID: "i2", InvestorID: "bob", Amount: money.NewFromInt(300)},
+		{ID: 3, InvestmentID: "i3", InvestorID: "alice", Amount: money.NewFromInt(100)},
+	}
+	entries := []*Entry{
+		ForTopUp("alice", "g1", money.NewFromInt(800), at),
+		ForTopUp("bob", "g2", money.NewFromInt(300), at),
+	}
+	for _, i := range inv {
+		entries = append(entries, ForInvestment(l, i, at))
+	}
+	entries = append(entries, ForDisbursement(l, &disbursement.Disbursement{DisbursementID: "d1", DisbursementDate: at}))
+
+	// 5 fee + 20 interest + 500 principal; investors get 10 of the interest
+	p := &repayment.Repayment{
+		RepaymentID: "r1", Amount: money.NewFromInt(525), PaidAt: at,
+		FeePaid: money.NewFromInt(5), InterestPaid: money.NewFromInt(20), PrincipalPaid: money.NewFromInt(500),
+	}
+	payouts := []payout.Payout{
+		{InvestmentID: 1, InvestorID: "alice", Principal: money.NewFromInt(300), Interest: money.NewFromInt(6), Total: money.NewFromInt(306)},
+		{InvestmentID: 2, InvestorID: "bob", Principal: money.NewFromInt(150), Interest: money.NewFromInt(3), Total: money.NewFromInt(153)},
+		{InvestmentID: 3, InvestorID: "alice", Principal: money.NewFromInt(50), Interest: money.NewFromInt(1), Total: money.NewFromInt(51)},
+	}
+	rep := ForRepayment(l, p, payouts)
+	entries = append(entries, rep)
+
+	for _, e := range entries {
+		if err := e.Validate(); err != nil {
+			t.Fatalf("%s %s: %v", e.Kind, e.RefID, err)
+		}
+		if e.Kind == KindTopUp {
+			continue
+		}
+		if e.LoanID == nil || *e.LoanID != 7 {
+			t.Fatalf("%s: loan id not set", e.Kind)
+		}
+	}
+	// alice's two investments collapse into one wallet line
+	wallets := 0
+	for _, line := range rep.Lines {
+		if line.Account == AccountInvestorWallet {
+			wallets++
+		}
+	}
+	if wallets != 2 {
+		t.Fatalf("want 2 wallet lines, got %d", wallets)
+	}
+
+	want := map[Account]string{
+		Cash:                     "625", // +1100 topped up, -1000 disbursed, +525 collected
+		Escrow("ln"):             "500", // investors' remaining stake
+		BorrowerReceivable("ln"): "500", // principal still owed
+		InvestorWallet("alice"):  "457", // 100 never invested + 357 paid out
+		InvestorWallet("bob"):    "153",
+		PlatformFee:              "15", // 5 fee + 10 interest spread
+	}
+	got := balances(entries...)
+	for a, w := range want {
+		if got[a] != w {
+			t.Fatalf("%+v balance = %s, want %s (all: %v)", a, got[a], w, got)
+		}
+	}
+}
+
+func TestPostings_HoldAndRelease(t *testing.T) {
+	l := &loan.Loan{ID: 7, LoanID: "ln", Principal: money.NewFromInt(1000)}
+	inv := &investment.Investment{InvestmentID: "i1", InvestorID: "alice", Amount: money.NewFromInt(400)}
+	entries := []*Entry{
+		ForTopUp("alice", "g1", money.NewFromInt(500), at),
+		ForInvestment(l, inv, at),
+	}
+	if got := balances(entries...); got[InvestorWallet("alice")] != "100" || got[Escrow("ln")] != "400" {
+		t.Fatalf("after hold: %v", got)
+	}
+	release := ForRelease(l, inv, at)
+	if err := release.Validate(); err != nil || release.Kind != KindRelease || release.RefID != "i1" {
+		t.Fatalf("release entry: %+v, %v", release, err)
+	}
+	if got := balances(append(entries, release)...); got[InvestorWallet("alice")] != "500" || got[Escrow("ln")] != "0" {
+		t.Fatalf("after release: %v", got)
+	}
+}
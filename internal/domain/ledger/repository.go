@@ -0,0 +1,21 @@
+package ledger
+
+import (
+	"context"
+
+	"amartha-backend-test/pkg/money"
+)
+
+type Repository interface {
+	// Post validates e and inserts it with its lines; entries are never changed afterwards
+	Post(ctx context.Context, e *Entry) error
+
+	// GetByRef returns the entry a business event posted, with its lines (gorm.ErrRecordNotFound if none)
+	GetByRef(ctx context.Context, kind EntryKind, refID string) (*Entry, error)
+
+	// Lock row-locks a until the surrounding transaction ends, so spends of the same wallet serialize
+	Lock(ctx context.Context, a Account) error
+
+	// Balance of an account on its normal side (credits minus debits for liabilities and revenue)
+	Balance(ctx context.Context, a Account) (money.Decimal, error)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"time"
 
+	"amartha-backend-test/pkg/money"
+
 	"gorm.io/gorm"
 )
 
@@ -15,28 +17,123 @@ const (
 	StateInvested  State = "invested"
 	StateDisbursed State = "disbursed"
 	StateRejected  State = "rejected"
+	StateRepaid    State = "repaid"
+	StateCancelled State = "cancelled"
+)
+
+// TenorUnit is the installment period; Rate is a percentage per period.
+type TenorUnit string
+
+const (
+	TenorWeek  TenorUnit = "week"
+	TenorMonth TenorUnit = "month"
+)
+
+// Valid reports whether u is a supported tenor unit.
+func (u TenorUnit) Valid() bool { return u == TenorWeek || u == TenorMonth }
+
+// InterestMethod selects how installments are computed from Rate.
+type InterestMethod string
+
+const (
+	// InterestFlat charges Rate on the original principal every period.
+	InterestFlat InterestMethod = "flat"
+	// InterestEffective charges Rate on the declining balance (annuity).
+	InterestEffective InterestMethod = "effective"
 )
 
+// Valid reports whether m is a supported interest method.
+func (m InterestMethod) Valid() bool { return m == InterestFlat || m == InterestEffective }
+
+// DPDBucket classifies a disbursed loan by days past due, following the
+// OJK TKB90 reporting buckets.
+type DPDBucket string
+
+const (
+	DPDCurrent DPDBucket = "current"
+	DPD1To30   DPDBucket = "1-30"
+	DPD31To60  DPDBucket = "31-60"
+	DPD61To90  DPDBucket = "61-90"
+	DPD90Plus  DPDBucket = "90+"
+)
+
+// DPDBuckets lists every bucket, least to most delinquent.
+var DPDBuckets = []DPDBucket{DPDCurrent, DPD1To30, DPD31To60, DPD61To90, DPD90Plus}
+
+// Valid reports whether b is a known bucket.
+func (b DPDBucket) Valid() bool {
+	for _, k := range DPDBuckets {
+		if b == k {
+			return true
+		}
+	}
+	return false
+}
+
+// BucketForDPD maps days past due to its bucket.
+func BucketForDPD(days int) DPDBucket {
+	switch {
+	case days <= 0:
+		return DPDCurrent
+	case days <= 30:
+		return DPD1To30
+	case days <= 60:
+		return DPD31To60
+	case days <= 90:
+		return DPD61To90
+	default:
+		return DPD90Plus
+	}
+}
+
 var (
 	ErrNotFound          = errors.New("loan not found")
 	ErrInvalidTransition = errors.New("invalid state transition")
 	ErrAlreadyApproved   = errors.New("loan already approved")
+	ErrAlreadyInvested   = errors.New("loan already invested")
+	ErrAlreadyDisbursed  = errors.New("loan already disbursed")
+	ErrAlreadyRejected   = errors.New("loan already rejected")
+	ErrAlreadyRepaid     = errors.New("loan already repaid")
+	ErrReapplyCooldown   = errors.New("borrower is in re-application cooldown")
+	ErrPendingLoan       = errors.New("borrower already has a pending loan")
+	ErrInvalidLoan       = errors.New("borrower_id must be 32 chars and principal positive")
+	ErrInvalidTenor      = errors.New("loan tenor must be a positive number of weeks or months")
+	ErrInvalidDPDBucket  = errors.New("dpd_bucket must be one of: current, 1-30, 31-60, 61-90, 90+")
+	ErrInvalidCursor     = errors.New("cursor is malformed or belongs to another sort")
+	ErrInvalidRange      = errors.New("range lower bound must not be after its upper bound")
+	ErrNotCancellable    = errors.New("only proposed, approved or invested loans can be cancelled")
+	ErrNotFullyFunded    = errors.New("loan is not fully funded")
+	ErrHasInvestments    = errors.New("approval cannot be revoked once the loan has investments")
+	ErrNotFullyRepaid    = errors.New("loan still has an outstanding balance")
 )
 
 type Loan struct {
 	ID             uint64         `gorm:"primaryKey;column:id" json:"-"`
 	LoanID         string         `gorm:"size:32;uniqueIndex:ux_loans_loan_id_active" json:"loan_id"`
 	BorrowerID     string         `gorm:"size:32;index:idx_loans_borrower_active" json:"borrower_id"`
-	Principal      float64        `gorm:"type:decimal(18,2)" json:"principal"`
-	Rate           float64        `gorm:"type:decimal(6,4)" json:"rate"`
-	ROI            float64        `gorm:"type:decimal(6,4)" json:"roi"`
-	AgreementLink  string         `gorm:"type:text" json:"agreement_link"`
-	State          State          `gorm:"type:enum('proposed','rejected','approved','invested','disbursed');default:'proposed'" json:"state"`
-	StateUpdatedAt time.Time      `gorm:"autoCreateTime" json:"state_updated_at"`
-	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
-	DeletedBy      string         `gorm:"size:32" json:"-"`
+	Principal      money.Decimal  `gorm:"type:decimal(18,2)" json:"principal"`
+	Rate           money.Decimal  `gorm:"type:decimal(6,4)" json:"rate"`
+	ROI            money.Decimal  `gorm:"type:decimal(6,4)" json:"roi"`
+	Tenor          uint16         `gorm:"type:smallint unsigned;not null" json:"tenor"`
+	TenorUnit      TenorUnit      `gorm:"type:enum('week','month');not null" json:"tenor_unit"`
+	InterestMethod InterestMethod `gorm:"type:enum('flat','effective');default:'flat'" json:"interest_method"`
+	// OutstandingBalance is interest + principal still owed; set at disbursement
+	OutstandingBalance money.Decimal `gorm:"type:decimal(18,2);not null;default:0" json:"outstanding_balance"`
+	// Days past due of the oldest unpaid installment, recomputed daily
+	DPD       uint16     `gorm:"column:dpd;type:smallint unsigned;not null;default:0" json:"dpd"`
+	DPDBucket DPDBucket  `gorm:"column:dpd_bucket;type:enum('current','1-30','31-60','61-90','90+');not null;default:'current'" json:"dpd_bucket"`
+	DPDAsOf   *time.Time `gorm:"column:dpd_as_of;type:date" json:"dpd_as_of,omitempty"`
+	// Blob key of the agreement rendered when the loan became invested
+	AgreementLink string `gorm:"type:text" json:"agreement_link"`
+	// Template version and SHA-256 of that document, to prove what was signed
+	AgreementVersion string         `gorm:"column:agreement_version;size:16" json:"agreement_version,omitempty"`
+	AgreementSHA256  string         `gorm:"column:agreement_sha256;size:64" json:"agreement_sha256,omitempty"`
+	State            State          `gorm:"type:enum('proposed','rejected','approved','invested','disbursed','repaid','cancelled');default:'proposed'" json:"state"`
+	StateUpdatedAt   time.Time      `gorm:"autoCreateTime" json:"state_updated_at"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedBy        string         `gorm:"size:32" json:"-"`
 }
 
 func (Loan) TableName() string { return "loans" }
@@ -0,0 +1,26 @@
+package loan
+
+import "testing"
+
+func TestBucketForDPD(t *testing.T) {
+	cases := map[int]DPDBucket{
+		-3: DPDCurrent, 0: DPDCurrent,
+		1: DPD1To30, 30: DPD1To30,
+		31: DPD31To60, 60: DPD31To60,
+		61: DPD61To90, 90: DPD61To90,
+		91: DPD90Plus, 400: DPD90Plus,
+	}
+	for days, want := range cases {
+		if got := BucketForDPD(days); got != want {
+			t.Errorf("BucketForDPD(%d) = %s, want %s", days, got, want)
+		}
+	}
+	for _, b := range DPDBuckets {
+		if !b.Valid() {
+			t.Errorf("%s should be valid", b)
+		}
+	}
+	if DPDBucket("91+").Valid() || DPDBucket("").Valid() {
+		t.Errorf("unknown buckets must be invalid")
+	}
+}
@@ -0,0 +1,26 @@
+package loan
+
+import "time"
+
+// TransitionMeta says who moved a loan and why, for the audit trail.
+type TransitionMeta struct {
+	Actor     string // employee / investor / borrower id, or "system"
+	Reason    string
+	RequestID string // Ax-Request-Id of the originating call
+	Facts     Facts  // read by the transition's guards; not recorded
+}
+
+// Table: loan_state_transitions (append-only audit of every state change)
+type StateTransition struct {
+	ID             uint64    `gorm:"column:id;primaryKey;autoIncrement" json:"-"`
+	LoanID         uint64    `gorm:"column:loan_id;not null;index:idx_lst_loan" json:"-"`
+	FromState      State     `gorm:"column:from_state;type:varchar(16);not null" json:"from"`
+	ToState        State     `gorm:"column:to_state;type:varchar(16);not null" json:"to"`
+	Actor          string    `gorm:"column:actor;type:varchar(64);not null" json:"actor"`
+	Reason         string    `gorm:"column:reason;type:text" json:"reason,omitempty"`
+	RequestID      string    `gorm:"column:request_id;type:varchar(64)" json:"request_id,omitempty"`
+	TransitionedAt time.Time `gorm:"column:transitioned_at;not null" json:"transitioned_at"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"-"`
+}
+
+func (StateTransition) TableName() string { return "loan_state_transitions" }
@@ -1,14 +1,133 @@
 package loan
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+// ListFilter narrows Repository.List; zero fields are ignored.
+type ListFilter struct {
+	State     State
+	DPDBucket DPDBucket
+	AfterID   uint64 // keyset cursor: only loans with id > AfterID
+	Limit     int    // required, > 0
+}
+
+// SortKey orders Repository.Search; ties are broken by id in the same direction.
+type SortKey string
+
+const (
+	SortCreatedAt      SortKey = "created_at"
+	SortStateUpdatedAt SortKey = "state_updated_at"
+	SortPrincipal      SortKey = "principal"
+)
+
+func (s SortKey) Valid() bool {
+	switch s {
+	case SortCreatedAt, SortStateUpdatedAt, SortPrincipal:
+		return true
+	}
+	return false
+}
+
+// Cursor is the last row of a page: Search resumes strictly after it in the
+// requested order. Only the value of the sort column is used.
+type Cursor struct {
+	ID        uint64
+	At        time.Time // created_at or state_updated_at
+	Principal money.Decimal
+}
+
+// CursorAt returns the cursor positioned on l for the given sort.
+func CursorAt(l *Loan, sort SortKey) Cursor {
+	c := Cursor{ID: l.ID}
+	switch sort {
+	case SortCreatedAt:
+		c.At = l.CreatedAt
+	case SortStateUpdatedAt:
+		c.At = l.StateUpdatedAt
+	case SortPrincipal:
+		c.Principal = l.Principal
+	}
+	return c
+}
+
+// SearchFilter narrows Repository.Search; zero fields are ignored. Principal
+// bounds are inclusive; time ranges are [from, to).
+type SearchFilter struct {
+	State      State
+	BorrowerID string
+	DPDBucket  DPDBucket
+
+	PrincipalMin, PrincipalMax       money.Decimal
+	CreatedFrom, CreatedTo           time.Time
+	StateUpdatedFrom, StateUpdatedTo time.Time
+
+	Sort  SortKey // required
+	Desc  bool
+	After *Cursor // nil for the first page
+	Limit int     // required, > 0
+}
+
+// Detail is a loan with its funding progress and the public fields of its
+// active approval and disbursement, read in one query. The approval and
+// disbursement fields are nil until those rows exist.
+type Detail struct {
+	Loan
+
+	// over investments that are not released
+	Invested    money.Decimal
+	Investments int
+	Investors   int
+
+	ApprovalID *string
+	PhotoID    *string
+	PhotoURL   *string
+	ApprovedAt *time.Time
+
+	DisbursementID     *string
+	SignedAgreementURL *string
+	SignatureStatus    *string
+	SignedAt           *time.Time
+	DisbursedAt        *time.Time
+}
 
 type Repository interface {
 	// Basic Case
 	Create(ctx context.Context, l *Loan) error
 	GetByLoanID(ctx context.Context, loanID string) (*Loan, error)
 	GetPendingLoanByBorrowerID(ctx context.Context, borrowerID string) (*Loan, error)
+	GetLatestRejectedLoanByBorrowerID(ctx context.Context, borrowerID string) (*Loan, error)
 	Save(ctx context.Context, l *Loan) error
 
 	// Spesific get loan with locking for update
 	GetByLoanIDForUpdate(ctx context.Context, loanID string) (*Loan, error)
+
+	// List returns loans matching f ordered by id ascending
+	List(ctx context.Context, f ListFilter) ([]Loan, error)
+
+	// ListByIDs returns the loans with the given numeric IDs in id order; unknown IDs are skipped
+	ListByIDs(ctx context.Context, ids []uint64) ([]Loan, error)
+
+	// GetDetailByLoanID returns the loan joined with its approval,
+	// disbursement and investment totals
+	GetDetailByLoanID(ctx context.Context, loanID string) (*Detail, error)
+
+	// Search returns loans matching f in f.Sort order, resuming after f.After
+	Search(ctx context.Context, f SearchFilter) ([]Loan, error)
+
+	// UpdateDelinquency writes only the DPD columns, so the daily job never
+	// overwrites a concurrent state change
+	UpdateDelinquency(ctx context.Context, id uint64, dpd uint16, bucket DPDBucket, asOf time.Time) error
+}
+
+// HistoryRepository persists the loan state transition audit trail.
+type HistoryRepository interface {
+	// Append one transition; call inside the same tx as the loan Save
+	Append(ctx context.Context, t *StateTransition) error
+
+	// List transitions of a loan (numeric loan ID), oldest first
+	ListByLoanID(ctx context.Context, loanID uint64) ([]StateTransition, error)
 }
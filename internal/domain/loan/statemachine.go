@@ -0,0 +1,260 @@
+package loan
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+// Facts are what guards need to know beyond the loan row. The usecase reads
+// them in the loan's transaction; fields a transition's guards ignore may
+// stay zero.
+type Facts struct {
+	Invested    money.Decimal // funds raised so far, including the investment at hand
+	Investments int           // investments recorded against the loan
+}
+
+// Guard vetoes a transition by returning a non-nil error.
+type Guard func(l *Loan, f Facts) error
+
+// FullyFunded lets a loan become invested only once its principal is raised.
+func FullyFunded(l *Loan, f Facts) error {
+	if !f.Invested.Equal(l.Principal) {
+		return ErrNotFullyFunded
+	}
+	return nil
+}
+
+// Unfunded lets an approval be revoked only while nobody has invested.
+func Unfunded(_ *Loan, f Facts) error {
+	if f.Investments > 0 {
+		return ErrHasInvestments
+	}
+	return nil
+}
+
+// FullyRepaid lets a loan become repaid only once nothing is outstanding.
+func FullyRepaid(l *Loan, _ Facts) error {
+	if !l.OutstandingBalance.IsZero() {
+		return ErrNotFullyRepaid
+	}
+	return nil
+}
+
+// Transition is one allowed edge of the loan lifecycle.
+type Transition struct {
+	From   State
+	To     State
+	Event  string  // verb shown in exported graphs, e.g. "approve"
+	Guards []Guard // evaluated in order before the state changes
+}
+
+// TransitionError is returned for every illegal move. It unwraps to the
+// specific sentinel (e.g. ErrAlreadyApproved) and to ErrInvalidTransition.
+type TransitionError struct {
+	From State
+	To   State
+	Err  error
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", e.Err, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() []error {
+	if errors.Is(e.Err, ErrInvalidTransition) {
+		return []error{e.Err}
+	}
+	return []error{e.Err, ErrInvalidTransition}
+}
+
+type edge struct{ from, to State }
+
+// StateMachine declares the allowed loan transitions and applies them.
+type StateMachine struct {
+	initial State
+	order   []Transition
+	edges   map[edge]Transition
+	// sentinel returned when a loan is asked to move into the state it is already in
+	already map[State]error
+	now     func() time.Time
+}
+
+// NewStateMachine builds a machine from an initial state and its transitions.
+func NewStateMachine(initial State, transitions ...Transition) *StateMachine {
+	m := &StateMachine{
+		initial: initial,
+		edges:   make(map[edge]Transition, len(transitions)),
+		already: map[State]error{},
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+	for _, t := range transitions {
+		m.order = append(m.order, t)
+		m.edges[edge{t.From, t.To}] = t
+	}
+	return m
+}
+
+// WithAlready registers the sentinel for a repeated move into state s.
+func (m *StateMachine) WithAlready(s State, err error) *StateMachine {
+	m.already[s] = err
+	return m
+}
+
+// WithClock overrides the time source used to stamp StateUpdatedAt.
+func (m *StateMachine) WithClock(now func() time.Time) *StateMachine {
+	m.now = now
+	return m
+}
+
+// Lifecycle is the loan lifecycle every usecase transitions through:
+// proposed → approved → invested → disbursed → repaid, with proposed → rejected,
+// approved → proposed when a validator revokes an unfunded approval, and
+// → cancelled until money has left escrow.
+var Lifecycle = NewStateMachine(StateProposed,
+	Transition{From: StateProposed, To: StateApproved, Event: "approve"},
+	Transition{From: StateProposed, To: StateRejected, Event: "reject"},
+	Transition{From: StateApproved, To: StateProposed, Event: "revoke", Guards: []Guard{Unfunded}},
+	Transition{From: StateApproved, To: StateInvested, Event: "invest", Guards: []Guard{FullyFunded}},
+	Transition{From: StateInvested, To: StateDisbursed, Event: "disburse"},
+	Transition{From: StateDisbursed, To: StateRepaid, Event: "repay", Guards: []Guard{FullyRepaid}},
+	Transition{From: StateProposed, To: StateCancelled, Event: "cancel"},
+	Transition{From: StateApproved, To: StateCancelled, Event: "cancel"},
+	Transition{From: StateInvested, To: StateCancelled, Event: "cancel"},
+).
+	WithAlready(StateApproved, ErrAlreadyApproved).
+	WithAlready(StateRejected, ErrAlreadyRejected).
+	WithAlready(StateInvested, ErrAlreadyInvested).
+	WithAlready(StateDisbursed, ErrAlreadyDisbursed).
+	WithAlready(StateRepaid, ErrAlreadyRepaid)
+
+// Init puts a new loan into the initial state and returns its first audit
+// record (from ""). The loan has no id yet: the caller sets LoanID once it is
+// created and appends the record in the same transaction.
+func (m *StateMachine) Init(l *Loan, meta TransitionMeta) *StateTransition {
+	l.State = m.initial
+	l.StateUpdatedAt = m.now()
+	return &StateTransition{
+		ToState:        m.initial,
+		Actor:          meta.Actor,
+		Reason:         meta.Reason,
+		RequestID:      meta.RequestID,
+		TransitionedAt: l.StateUpdatedAt,
+	}
+}
+
+// Can reports whether from → to is a declared edge (guards not evaluated).
+func (m *StateMachine) Can(from, to State) bool {
+	_, ok := m.edges[edge{from, to}]
+	return ok
+}
+
+// CheckEdge validates that l's state has an edge to the target state; guards
+// are not evaluated. Flows use it to refuse work up front, before the facts
+// the guards need exist.
+func (m *StateMachine) CheckEdge(l *Loan, to State) error {
+	_, err := m.edge(l, to)
+	return err
+}
+
+func (m *StateMachine) edge(l *Loan, to State) (Transition, error) {
+	t, ok := m.edges[edge{l.State, to}]
+	if !ok {
+		err := ErrInvalidTransition
+		if l.State == to {
+			if already, ok := m.already[to]; ok {
+				err = already
+			}
+		}
+		return t, &TransitionError{From: l.State, To: to, Err: err}
+	}
+	return t, nil
+}
+
+// Check validates l → to, including declared and extra guards, without mutating l.
+func (m *StateMachine) Check(l *Loan, to State, f Facts, extra ...Guard) error {
+	t, err := m.edge(l, to)
+	if err != nil {
+		return err
+	}
+	for _, g := range append(t.Guards, extra...) {
+		if err := g(l, f); err != nil {
+			return &TransitionError{From: l.State, To: to, Err: err}
+		}
+	}
+	return nil
+}
+
+// Transition moves l to the target state, stamps StateUpdatedAt and returns
+// the audit record the caller must append in the same transaction. The
+// edge's guards see meta.Facts.
+func (m *StateMachine) Transition(l *Loan, to State, meta TransitionMeta, extra ...Guard) (*StateTransition, error) {
+	if err := m.Check(l, to, meta.Facts, extra...); err != nil {
+		return nil, err
+	}
+	from := l.State
+	l.State = to
+	l.StateUpdatedAt = m.now()
+	return &StateTransition{
+		LoanID:         l.ID,
+		FromState:      from,
+		ToState:        to,
+		Actor:          meta.Actor,
+		Reason:         meta.Reason,
+		RequestID:      meta.RequestID,
+		TransitionedAt: l.StateUpdatedAt,
+	}, nil
+}
+
+// terminal returns states with no outgoing edge, sorted for stable output.
+func (m *StateMachine) terminal() []State {
+	out := map[State]bool{}
+	seen := map[State]bool{m.initial: true}
+	for _, t := range m.order {
+		out[t.From] = true
+		seen[t.From], seen[t.To] = true, true
+	}
+	var res []State
+	for s := range seen {
+		if !out[s] {
+			res = append(res, s)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// DOT renders the transition graph in Graphviz DOT.
+func (m *StateMachine) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph loan_lifecycle {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+	fmt.Fprintf(&b, "  %q [style=\"rounded,bold\"];\n", m.initial)
+	for _, s := range m.terminal() {
+		fmt.Fprintf(&b, "  %q [peripheries=2];\n", s)
+	}
+	for _, t := range m.order {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", t.From, t.To, t.Event)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the transition graph as a Mermaid stateDiagram.
+func (m *StateMachine) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "    [*] --> %s\n", m.initial)
+	for _, t := range m.order {
+		fmt.Fprintf(&b, "    %s --> %s: %s\n", t.From, t.To, t.Event)
+	}
+	for _, s := range m.terminal() {
+		fmt.Fprintf(&b, "    %s --> [*]\n", s)
+	}
+	return b.String()
+}
@@ -0,0 +1,206 @@
+package loan
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+func TestLifecycle_AllowedTransitions(t *testing.T) {
+	allowed := []struct{ from, to State }{
+		{StateProposed, StateApproved},
+		{StateProposed, StateRejected},
+		{StateApproved, StateInvested},
+		{StateApproved, StateProposed},
+		{StateInvested, StateDisbursed},
+		{StateDisbursed, StateRepaid},
+	}
+	for _, tt := range allowed {
+		if !Lifecycle.Can(tt.from, tt.to) {
+			t.Errorf("expected %s -> %s to be allowed", tt.from, tt.to)
+		}
+	}
+	if Lifecycle.Can(StateProposed, StateDisbursed) {
+		t.Errorf("proposed -> disbursed must not be allowed")
+	}
+}
+
+func TestStateMachine_Transition_StampsStateUpdatedAt(t *testing.T) {
+	at := time.Date(2025, 9, 6, 10, 0, 0, 0, time.UTC)
+	m := NewStateMachine(StateProposed,
+		Transition{From: StateProposed, To: StateApproved, Event: "approve"},
+	).WithClock(func() time.Time { return at })
+
+	l := &Loan{}
+	first := m.Init(l, TransitionMeta{Actor: "BRW-1", RequestID: "REQ-0"})
+	if l.State != StateProposed || !l.StateUpdatedAt.Equal(at) {
+		t.Fatalf("Init: got %+v", l)
+	}
+	if first.FromState != "" || first.ToState != StateProposed || first.Actor != "BRW-1" ||
+		first.RequestID != "REQ-0" || !first.TransitionedAt.Equal(at) {
+		t.Fatalf("Init record: %+v", first)
+	}
+
+	l.ID = 42
+	l.StateUpdatedAt = time.Time{}
+	tr, err := m.Transition(l, StateApproved, TransitionMeta{Actor: "EMP-1", Reason: "ok", RequestID: "REQ-1"})
+	if err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if l.State != StateApproved || !l.StateUpdatedAt.Equal(at) {
+		t.Fatalf("Transition did not apply/stamp: %+v", l)
+	}
+	// Audit record mirrors the move
+	if tr.LoanID != 42 || tr.FromState != StateProposed || tr.ToState != StateApproved ||
+		tr.Actor != "EMP-1" || tr.Reason != "ok" || tr.RequestID != "REQ-1" || !tr.TransitionedAt.Equal(at) {
+		t.Fatalf("unexpected transition record: %+v", tr)
+	}
+}
+
+func TestStateMachine_IllegalMoves_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to State
+		want     error
+	}{
+		{"repeat approve", StateApproved, StateApproved, ErrAlreadyApproved},
+		{"repeat reject", StateRejected, StateRejected, ErrAlreadyRejected},
+		{"repeat invest", StateInvested, StateInvested, ErrAlreadyInvested},
+		{"repeat disburse", StateDisbursed, StateDisbursed, ErrAlreadyDisbursed},
+		{"repeat repay", StateRepaid, StateRepaid, ErrAlreadyRepaid},
+		{"skip ahead", StateProposed, StateDisbursed, ErrInvalidTransition},
+		{"backwards", StateDisbursed, StateApproved, ErrInvalidTransition},
+		{"repay before disburse", StateInvested, StateRepaid, ErrInvalidTransition},
+		{"revoke after funding", StateInvested, StateProposed, ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Loan{State: tt.from}
+			tr, err := Lifecycle.Transition(l, tt.to, TransitionMeta{})
+			if tr != nil {
+				t.Fatalf("no audit record expected on error, got %+v", tr)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, err)
+			}
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Fatalf("every illegal move must also be ErrInvalidTransition, got %v", err)
+			}
+			var te *TransitionError
+			if !errors.As(err, &te) || te.From != tt.from || te.To != tt.to {
+				t.Fatalf("want *TransitionError{%s -> %s}, got %#v", tt.from, tt.to, err)
+			}
+			if l.State != tt.from {
+				t.Fatalf("state must not change on error, got %s", l.State)
+			}
+		})
+	}
+}
+
+func TestStateMachine_Guards(t *testing.T) {
+	errNoPrincipal := errors.New("principal required")
+	m := NewStateMachine(StateProposed,
+		Transition{From: StateProposed, To: StateApproved, Event: "approve", Guards: []Guard{
+			func(l *Loan, _ Facts) error {
+				if !l.Principal.IsPositive() {
+					return errNoPrincipal
+				}
+				return nil
+			},
+		}},
+	)
+
+	l := &Loan{State: StateProposed}
+	if _, err := m.Transition(l, StateApproved, TransitionMeta{}); !errors.Is(err, errNoPrincipal) || !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("declared guard: want %v wrapped in ErrInvalidTransition, got %v", errNoPrincipal, err)
+	}
+
+	// Extra call-site guard runs after declared guards
+	errVeto := errors.New("veto")
+	l.Principal = money.NewFromInt(1)
+	if err := m.Check(l, StateApproved, Facts{}, func(*Loan, Facts) error { return errVeto }); !errors.Is(err, errVeto) {
+		t.Fatalf("extra guard: want %v, got %v", errVeto, err)
+	}
+	if l.State != StateProposed {
+		t.Fatalf("Check must not mutate the loan")
+	}
+}
+
+func TestLifecycle_Export(t *testing.T) {
+	dot := Lifecycle.DOT()
+	for _, want := range []string{
+		"digraph loan_lifecycle {",
+		`"proposed" -> "approved" [label="approve"];`,
+		`"invested" -> "disbursed" [label="disburse"];`,
+		`"disbursed" -> "repaid" [label="repay"];`,
+		`"repaid" [peripheries=2];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+
+	mm := Lifecycle.Mermaid()
+	for _, want := range []string{
+		"stateDiagram-v2",
+		"[*] --> proposed",
+		"proposed --> rejected: reject",
+		"approved --> proposed: revoke",
+		"rejected --> [*]",
+	} {
+		if !strings.Contains(mm, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, mm)
+		}
+	}
+}
+
+func TestLifecycle_DeclaredGuards(t *testing.T) {
+	principal := money.NewFromInt(5_000_000)
+	tests := []struct {
+		name string
+		l    Loan
+		to   State
+		f    Facts
+		want error
+	}{
+		{"invest partly funded", Loan{State: StateApproved, Principal: principal}, StateInvested, Facts{Invested: money.NewFromInt(1_000_000)}, ErrNotFullyFunded},
+		{"invest fully funded", Loan{State: StateApproved, Principal: principal}, StateInvested, Facts{Invested: principal}, nil},
+		{"revoke with investments", Loan{State: StateApproved}, StateProposed, Facts{Investments: 1}, ErrHasInvestments},
+		{"revoke unfunded", Loan{State: StateApproved}, StateProposed, Facts{}, nil},
+		{"repay with balance left", Loan{State: StateDisbursed, OutstandingBalance: money.NewFromInt(1)}, StateRepaid, Facts{}, ErrNotFullyRepaid},
+		{"repay settled", Loan{State: StateDisbursed}, StateRepaid, Facts{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tt.l
+			err := Lifecycle.Check(&l, tt.to, tt.f)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("want allowed, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) || !errors.Is(err, ErrInvalidTransition) {
+				t.Fatalf("want %v wrapped in ErrInvalidTransition, got %v", tt.want, err)
+			}
+			// the edge itself exists; only the guard vetoes
+			if err := Lifecycle.CheckEdge(&l, tt.to); err != nil {
+				t.Fatalf("CheckEdge: %v", err)
+			}
+		})
+	}
+}
+
+func TestLifecycle_Cancel(t *testing.T) {
+	for s, want := range map[State]bool{
+		StateProposed: true, StateApproved: true, StateInvested: true,
+		StateDisbursed: false, StateRepaid: false, StateRejected: false, StateCancelled: false,
+	} {
+		if got := Lifecycle.Can(s, StateCancelled); got != want {
+			t.Errorf("Can(%s, cancelled) = %v, want %v", s, got, want)
+		}
+	}
+}
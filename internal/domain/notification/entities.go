@@ -0,0 +1,126 @@
+// Package notification sends templated messages to people (investors, for
+// now) and tracks each recipient's delivery.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+// TemplateLoanInvested tells an investor a loan they funded is fully
+// invested, with a link to the agreement letter.
+const TemplateLoanInvested = "loan_invested"
+
+const (
+	// RetryBase is the wait after the first failed send; it doubles per
+	// further failure up to RetryCap.
+	RetryBase = time.Minute
+	RetryCap  = 6 * time.Hour
+)
+
+var ErrUnknownTemplate = errors.New("unknown notification template")
+
+//go:embed templates/*.tmpl
+var files embed.FS
+
+// Status is where a recipient's notification is.
+type Status string
+
+const (
+	StatusPending Status = "PENDING"
+	StatusSent    Status = "SENT"
+	// StatusFailed notifications ran out of attempts; they stay for inspection.
+	StatusFailed Status = "FAILED"
+	// StatusSkipped recipients have no address on file; nothing is sent.
+	StatusSkipped Status = "SKIPPED"
+)
+
+// Backoff is the wait before the next send of a notification that has
+// failed attempts times (attempts >= 1).
+func Backoff(attempts uint16) time.Duration {
+	d := RetryBase
+	for i := uint16(1); i < attempts && d < RetryCap; i++ {
+		d *= 2
+	}
+	return min(d, RetryCap)
+}
+
+// Message is one rendered plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers a message; an error means it was not accepted.
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// Render fills template name with data and returns the message without a
+// recipient. Each template defines a "subject" and a "body".
+func Render(name string, data any) (*Message, error) {
+	tmpl, err := template.ParseFS(files, "templates/"+name+".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return nil, err
+	}
+	return &Message{Subject: subject.String(), Body: body.String()}, nil
+}
+
+// LoanInvested is the data of TemplateLoanInvested: one investor's part in
+// a fully invested loan. It is stored with the notification; AgreementURL
+// and LinkExpiresAt are filled in at send time, so every retry carries a
+// fresh link.
+type LoanInvested struct {
+	LoanID       string        `json:"loan_id"`
+	InvestorID   string        `json:"investor_id"`
+	Principal    money.Decimal `json:"principal"`
+	Amount       money.Decimal `json:"amount"`
+	ROI          money.Decimal `json:"roi"`
+	Tenor        uint16        `json:"tenor"`
+	TenorUnit    string        `json:"tenor_unit"`
+	AgreementKey string        `json:"agreement_key,omitempty"`
+
+	AgreementURL  string    `json:"-"`
+	LinkExpiresAt time.Time `json:"-"`
+}
+
+// Table: notifications (one row per event and recipient)
+type Notification struct {
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	NotificationID string `gorm:"column:notification_id;type:char(32);not null;uniqueIndex:ux_notifications_notification_id"`
+	// Outbox event that triggered it; a replayed event notifies nobody twice
+	EventID  string `gorm:"column:event_id;type:char(32);not null;uniqueIndex:ux_notifications_event_recipient"`
+	Template string `gorm:"column:template;type:varchar(64);not null"`
+	// Public id of the loan it is about
+	LoanID      string `gorm:"column:loan_id;type:char(32);not null;index:idx_notifications_loan"`
+	RecipientID string `gorm:"column:recipient_id;type:char(32);not null;uniqueIndex:ux_notifications_event_recipient"`
+	// nil when the recipient has no address on file (SKIPPED)
+	Email *string `gorm:"column:email;type:varchar(254)"`
+	// Template data as JSON
+	Data          string     `gorm:"column:data;type:json;not null"`
+	Status        Status     `gorm:"column:status;type:enum('PENDING','SENT','FAILED','SKIPPED');not null;default:'PENDING';index:idx_notifications_due,priority:1"`
+	Attempts      uint16     `gorm:"column:attempts;type:smallint unsigned;not null;default:0"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;type:datetime;not null;index:idx_notifications_due,priority:2"`
+	LastError     *string    `gorm:"column:last_error;type:text"`
+	SentAt        *time.Time `gorm:"column:sent_at;type:datetime"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Notification) TableName() string { return "notifications" }
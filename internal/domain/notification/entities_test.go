@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := map[uint16]time.Duration{
+		1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute,
+		9: 256 * time.Minute, 10: 6 * time.Hour, 500: 6 * time.Hour,
+	}
+	for n, want := range cases {
+		if got := Backoff(n); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestRender_LoanInvested(t *testing.T) {
+	data := LoanInvested{
+		LoanID: strings.Repeat("1", 32), Principal: money.NewFromInt(5_000_000), Amount: money.MustParse("1250000.5"),
+		ROI: money.MustParse("1.2"), Tenor: 12, TenorUnit: "month",
+		AgreementURL:  "https://blobs.test/agreements/x.txt?signature=s",
+		LinkExpiresAt: time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC),
+	}
+	m, err := Render(TemplateLoanInvested, data)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if m.Subject != "Loan "+data.LoanID+" is fully funded: your agreement letter" || m.To != "" {
+		t.Fatalf("subject = %q", m.Subject)
+	}
+	for _, want := range []string{
+		"IDR 5000000.00", "Your investment : IDR 1250000.50", "1.2% per month", "12 month(s)",
+		"until 2026-10-23 09:00 UTC:\n" + data.AgreementURL,
+	} {
+		if !strings.Contains(m.Body, want) {
+			t.Fatalf("body misses %q:\n%s", want, m.Body)
+		}
+	}
+
+	data.AgreementURL = ""
+	if m, _ := Render(TemplateLoanInvested, data); strings.Contains(m.Body, "available here") || !strings.Contains(m.Body, "being prepared") {
+		t.Fatalf("body without a link:\n%s", m.Body)
+	}
+	if _, err := Render("welcome", data); !errors.Is(err, ErrUnknownTemplate) {
+		t.Fatalf("want ErrUnknownTemplate, got %v", err)
+	}
+}
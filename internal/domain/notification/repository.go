@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Get the notification of an event to a recipient; gorm.ErrRecordNotFound when none
+	Get(ctx context.Context, eventID, recipientID string) (*Notification, error)
+
+	Create(ctx context.Context, n *Notification) error
+
+	// List up to limit pending notifications due at now, oldest first, locked
+	// (rows another dispatcher holds are skipped)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]Notification, error)
+
+	// Persist status, attempts, next_attempt_at, last_error and sent_at
+	Save(ctx context.Context, n *Notification) error
+
+	// List a loan's notifications (public loan id), oldest first
+	ListByLoanID(ctx context.Context, loanID string) ([]Notification, error)
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/money"
+)
+
+// EventType names a loan lifecycle event.
+type EventType string
+
+const (
+	LoanProposed        EventType = "LoanProposed"
+	LoanApproved        EventType = "LoanApproved"
+	LoanRejected        EventType = "LoanRejected"
+	LoanApprovalRevoked EventType = "LoanApprovalRevoked"
+	LoanInvested        EventType = "LoanInvested"
+	LoanDisbursed       EventType = "LoanDisbursed"
+	LoanRepaid          EventType = "LoanRepaid"
+	LoanCancelled       EventType = "LoanCancelled"
+)
+
+// Events lists every loan event, one per lifecycle transition, in lifecycle order.
+var Events = []EventType{
+	LoanProposed, LoanApproved, LoanRejected, LoanApprovalRevoked,
+	LoanInvested, LoanDisbursed, LoanRepaid, LoanCancelled,
+}
+
+// Status is where a message is in the relay.
+type Status string
+
+const (
+	// StatusPending rows are picked up by the relay once next_attempt_at passes.
+	StatusPending Status = "PENDING"
+	StatusSent    Status = "SENT"
+	// StatusFailed rows ran out of attempts; they stay for inspection.
+	StatusFailed Status = "FAILED"
+)
+
+const (
+	// RetryBase is the wait after the first failed publish; it doubles per
+	// further failure up to RetryCap.
+	RetryBase = 5 * time.Second
+	RetryCap  = time.Hour
+)
+
+// Backoff is the wait before the next publish of a message that has failed
+// attempts times (attempts >= 1).
+func Backoff(attempts uint16) time.Duration {
+	d := RetryBase
+	for i := uint16(1); i < attempts && d < RetryCap; i++ {
+		d *= 2
+	}
+	return min(d, RetryCap)
+}
+
+// Publisher delivers a message to whatever consumes loan events.
+type Publisher interface {
+	Publish(ctx context.Context, m Message) error
+}
+
+// Table: outbox (events written in the same transaction as the state change
+// they describe, published afterwards by the relay)
+type Message struct {
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex); consumers dedupe on it
+	EventID   string    `gorm:"column:event_id;type:char(32);not null;uniqueIndex:ux_outbox_event_id"`
+	EventType EventType `gorm:"column:event_type;type:varchar(32);not null"`
+	// Public id of the loan the event is about
+	AggregateID   string     `gorm:"column:aggregate_id;type:char(32);not null;index:idx_outbox_aggregate"`
+	Payload       string     `gorm:"column:payload;type:json;not null"`
+	Status        Status     `gorm:"column:status;type:enum('PENDING','SENT','FAILED');not null;default:'PENDING';index:idx_outbox_due,priority:1"`
+	Attempts      uint16     `gorm:"column:attempts;type:smallint unsigned;not null;default:0"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;type:datetime;not null;index:idx_outbox_due,priority:2"`
+	LastError     *string    `gorm:"column:last_error;type:text"`
+	SentAt        *time.Time `gorm:"column:sent_at;type:datetime"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Message) TableName() string { return "outbox" }
+
+// LoanEvent is the JSON payload of every loan lifecycle event.
+type LoanEvent struct {
+	EventID    string      `json:"event_id"`
+	Type       EventType   `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Loan       LoanPayload `json:"loan"`
+}
+
+// LoanPayload is the loan as it stood right after the change.
+type LoanPayload struct {
+	LoanID         string        `json:"loan_id"`
+	BorrowerID     string        `json:"borrower_id"`
+	State          string        `json:"state"`
+	Principal      money.Decimal `json:"principal"`
+	Rate           money.Decimal `json:"rate"`
+	ROI            money.Decimal `json:"roi"`
+	Tenor          uint16        `json:"tenor"`
+	TenorUnit      string        `json:"tenor_unit"`
+	InterestMethod string        `json:"interest_method"`
+}
+
+// ForLoan builds a pending message of type t about l, stamped with the
+// loan's last state change. Append it in the transaction that saves l.
+func ForLoan(t EventType, l *loan.Loan) (*Message, error) {
+	ev := LoanEvent{
+		EventID:    id.NewID32(),
+		Type:       t,
+		OccurredAt: l.StateUpdatedAt.UTC(),
+		Loan: LoanPayload{
+			LoanID:         l.LoanID,
+			BorrowerID:     l.BorrowerID,
+			State:          string(l.State),
+			Principal:      l.Principal,
+			Rate:           l.Rate,
+			ROI:            l.ROI,
+			Tenor:          l.Tenor,
+			TenorUnit:      string(l.TenorUnit),
+			InterestMethod: string(l.InterestMethod),
+		},
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		EventID:       ev.EventID,
+		EventType:     t,
+		AggregateID:   l.LoanID,
+		Payload:       string(b),
+		Status:        StatusPending,
+		NextAttemptAt: ev.OccurredAt,
+	}, nil
+}
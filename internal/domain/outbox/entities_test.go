@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/pkg/money"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := map[uint16]time.Duration{
+		1: 5 * time.Second, 2: 10 * time.Second, 3: 20 * time.Second,
+		10: 2560 * time.Second, 11: time.Hour, 200: time.Hour,
+	}
+	for n, want := range cases {
+		if got := Backoff(n); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestForLoan(t *testing.T) {
+	at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	l := &loan.Loan{
+		LoanID: strings.Repeat("a", 32), BorrowerID: strings.Repeat("b", 32),
+		Principal: money.MustParse("5000000"), Rate: money.MustParse("0.12"), ROI: money.MustParse("0.1"),
+		Tenor: 12, TenorUnit: loan.TenorMonth, InterestMethod: loan.InterestFlat,
+		State: loan.StateApproved, StateUpdatedAt: at,
+	}
+	m, err := ForLoan(LoanApproved, l)
+	if err != nil {
+		t.Fatalf("ForLoan: %v", err)
+	}
+	if len(m.EventID) != 32 || m.EventType != LoanApproved || m.AggregateID != l.LoanID ||
+		m.Status != StatusPending || !m.NextAttemptAt.Equal(at) {
+		t.Fatalf("message: %+v", m)
+	}
+
+	var ev LoanEvent
+	if err := json.Unmarshal([]byte(m.Payload), &ev); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if ev.EventID != m.EventID || ev.Type != LoanApproved || !ev.OccurredAt.Equal(at) ||
+		ev.Loan.State != "approved" || ev.Loan.Principal.String() != "5000000" || ev.Loan.TenorUnit != "month" {
+		t.Fatalf("payload: %+v", ev)
+	}
+}
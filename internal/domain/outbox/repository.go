@@ -0,0 +1,18 @@
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Append a message; call it inside the transaction of the change it describes
+	Append(ctx context.Context, m *Message) error
+
+	// List up to limit pending messages due at now, oldest first, locked
+	// (rows another relay holds are skipped)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]Message, error)
+
+	// Persist status, attempts, next_attempt_at, last_error and sent_at
+	Save(ctx context.Context, m *Message) error
+}
@@ -0,0 +1,67 @@
+package payout
+
+import (
+	"sort"
+
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/money"
+)
+
+// InvestorInterest is the part of interestPaid owed to investors. Borrowers
+// pay Rate and investors earn ROI (both percent per period), so investors get
+// interestPaid × ROI / Rate rounded to cents; the platform keeps the spread.
+// An ROI at or above Rate passes all interest through.
+func InvestorInterest(interestPaid, rate, roi money.Decimal) money.Decimal {
+	if !interestPaid.IsPositive() || !rate.IsPositive() || !roi.IsPositive() {
+		return money.Zero
+	}
+	if roi.GreaterThanOrEqual(rate) {
+		return interestPaid
+	}
+	return interestPaid.Mul(roi).Div(rate, 2)
+}
+
+// Distribute splits a repayment's principal and investor interest across the
+// loan's investments pro-rata to their amounts. Each part is allocated
+// separately with money.Allocate over investments ordered by ID, so leftover
+// cents land deterministically and every column sums to its distributable
+// amount. Investments whose share rounds to nothing get no row. PayoutID is
+// left for the caller to assign.
+func Distribute(l *loan.Loan, p *repayment.Repayment, investments []investment.Investment) ([]Payout, error) {
+	if len(investments) == 0 {
+		return nil, ErrNoInvestments
+	}
+	inv := make([]investment.Investment, len(investments))
+	copy(inv, investments)
+	sort.Slice(inv, func(i, j int) bool { return inv[i].ID < inv[j].ID })
+
+	weights := make([]money.Decimal, len(inv))
+	for i, it := range inv {
+		weights[i] = it.Amount
+	}
+	principal := p.PrincipalPaid.Allocate(weights, 2)
+	interest := InvestorInterest(p.InterestPaid, l.Rate, l.ROI).Allocate(weights, 2)
+	if principal == nil || interest == nil {
+		return nil, ErrNoInvestments
+	}
+
+	out := make([]Payout, 0, len(inv))
+	for i, it := range inv {
+		total := principal[i].Add(interest[i])
+		if total.IsZero() {
+			continue
+		}
+		out = append(out, Payout{
+			RepaymentID:  p.ID,
+			LoanID:       l.ID,
+			InvestmentID: it.ID,
+			InvestorID:   it.InvestorID,
+			Principal:    principal[i],
+			Interest:     interest[i],
+			Total:        total,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package payout
+
+import (
+	"errors"
+	"testing"
+
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/money"
+)
+
+func TestInvestorInterest(t *testing.T) {
+	d := money.MustParse
+	cases := []struct{ paid, rate, roi, want string }{
+		{"64500", "1.29", "0.90", "45000"},
+		{"100", "2.99", "1.29", "43.14"}, // 43.1438… rounds down
+		{"100", "1.29", "1.29", "100"},   // no spread
+		{"100", "1.29", "1.50", "100"},   // never more than was paid
+		{"0", "1.29", "0.90", "0"},
+		{"100", "0", "0.90", "0"},
+	}
+	for _, c := range cases {
+		if got := InvestorInterest(d(c.paid), d(c.rate), d(c.roi)); got.String() != c.want {
+			t.Fatalf("InvestorInterest(%s, %s, %s) = %s, want %s", c.paid, c.rate, c.roi, got, c.want)
+		}
+	}
+}
+
+func TestDistribute_ProRataWithDeterministicRemainder(t *testing.T) {
+	l := &loan.Loan{ID: 7, Rate: money.MustParse("1.29"), ROI: money.MustParse("0.90")}
+	p := &repayment.Repayment{ID: 3, LoanID: 7, PrincipalPaid: money.MustParse("100"), InterestPaid: money.MustParse("12.90")}
+	// passed newest first: Distribute must order by ID itself
+	invs := []investment.Investment{
+		{ID: 12, InvestorID: "c", Amount: money.NewFromInt(1_000_000)},
+		{ID: 11, InvestorID: "b", Amount: money.NewFromInt(1_000_000)},
+		{ID: 10, InvestorID: "a", Amount: money.NewFromInt(1_000_000)},
+	}
+
+	got, err := Distribute(l, p, invs)
+	if err != nil {
+		t.Fatalf("Distribute: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("want 3 payouts, got %d", len(got))
+	}
+	// 100 / 3 → 33.34, 33.33, 33.33; investor interest 12.90 × 0.90 / 1.29 = 9 → 3 each
+	want := []struct {
+		inv                 uint64
+		principal, interest string
+	}{{10, "33.34", "3"}, {11, "33.33", "3"}, {12, "33.33", "3"}}
+	principal, interest := money.Zero, money.Zero
+	for i, w := range want {
+		g := got[i]
+		if g.InvestmentID != w.inv || g.Principal.String() != w.principal || g.Interest.String() != w.interest {
+			t.Fatalf("payout %d = %+v, want %+v", i, g, w)
+		}
+		if g.RepaymentID != 3 || g.LoanID != 7 || !g.Total.Equal(g.Principal.Add(g.Interest)) {
+			t.Fatalf("payout %d keys/total: %+v", i, g)
+		}
+		principal, interest = principal.Add(g.Principal), interest.Add(g.Interest)
+	}
+	if principal.String() != "100" || interest.String() != "9" {
+		t.Fatalf("sums: principal=%s interest=%s", principal, interest)
+	}
+
+	// Same input in any order → same rows
+	again, _ := Distribute(l, p, []investment.Investment{invs[2], invs[0], invs[1]})
+	for i := range got {
+		if again[i].InvestmentID != got[i].InvestmentID || !again[i].Total.Equal(got[i].Total) {
+			t.Fatalf("not deterministic: %+v vs %+v", again[i], got[i])
+		}
+	}
+}
+
+func TestDistribute_SkipsZeroSharesAndRejectsNoInvestments(t *testing.T) {
+	l := &loan.Loan{ID: 7, Rate: money.MustParse("1.29"), ROI: money.MustParse("0.90")}
+	// fee-only payment: nothing to distribute
+	p := &repayment.Repayment{ID: 3, FeePaid: money.NewFromInt(5000)}
+	got, err := Distribute(l, p, []investment.Investment{{ID: 1, Amount: money.NewFromInt(5_000_000)}})
+	if err != nil || len(got) != 0 {
+		t.Fatalf("fee-only: got %+v, err %v", got, err)
+	}
+
+	// one cent across two investors: the larger stake takes it
+	p = &repayment.Repayment{ID: 4, PrincipalPaid: money.MustParse("0.01")}
+	got, err = Distribute(l, p, []investment.Investment{
+		{ID: 1, InvestorID: "a", Amount: money.NewFromInt(1_000_000)},
+		{ID: 2, InvestorID: "b", Amount: money.NewFromInt(4_000_000)},
+	})
+	if err != nil || len(got) != 1 || got[0].InvestorID != "b" || got[0].Principal.String() != "0.01" {
+		t.Fatalf("one cent: got %+v, err %v", got, err)
+	}
+
+	if _, err := Distribute(l, p, nil); !errors.Is(err, ErrNoInvestments) {
+		t.Fatalf("want ErrNoInvestments, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package payout
+
+import (
+	"errors"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+var ErrNoInvestments = errors.New("loan has no investments to distribute to")
+
+// Table: investor_payouts (one row per investment per repayment, append-only)
+type Payout struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	PayoutID string `gorm:"column:payout_id;type:char(32);not null;uniqueIndex:ux_payouts_payout_id"`
+	// FK to repayments.id (numeric)
+	RepaymentID uint64 `gorm:"column:repayment_id;not null;uniqueIndex:ux_payouts_repayment_investment"`
+	// FK to loans.id (numeric)
+	LoanID uint64 `gorm:"column:loan_id;not null;index:idx_payouts_loan"`
+	// FK to investments.id (numeric)
+	InvestmentID uint64 `gorm:"column:investment_id;not null;uniqueIndex:ux_payouts_repayment_investment"`
+	InvestorID   string `gorm:"column:investor_id;type:char(32);not null;index:idx_payouts_investor"`
+	// Investor's pro-rata cut of the principal and of the ROI interest in the repayment
+	Principal money.Decimal `gorm:"column:principal;type:decimal(18,2);not null"`
+	Interest  money.Decimal `gorm:"column:interest;type:decimal(18,2);not null"`
+	Total     money.Decimal `gorm:"column:total;type:decimal(18,2);not null"`
+	CreatedAt time.Time     `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Payout) TableName() string { return "investor_payouts" }
+
+// Sum is everything paid out so far on one investment.
+type Sum struct {
+	InvestmentID uint64
+	Principal    money.Decimal
+	Interest     money.Decimal
+	Total        money.Decimal
+}
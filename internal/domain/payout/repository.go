@@ -0,0 +1,15 @@
+package payout
+
+import "context"
+
+// Repository stores investor payouts (append-only).
+type Repository interface {
+	// CreateBatch inserts all payouts of one repayment in one statement
+	CreateBatch(ctx context.Context, items []Payout) error
+
+	// ListByLoanID returns a loan's payouts (numeric loan ID), oldest first
+	ListByLoanID(ctx context.Context, loanID uint64) ([]Payout, error)
+
+	// SumByInvestorID totals an investor's payouts per investment, in investment order
+	SumByInvestorID(ctx context.Context, investorID string) ([]Sum, error)
+}
@@ -0,0 +1,56 @@
+package photo
+
+import (
+	"errors"
+	"time"
+
+	"amartha-backend-test/pkg/phash"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxBytes caps a single field-visit photo.
+	MaxBytes = 10 << 20
+	// MaxPixels caps the decoded size, so a small file cannot expand into
+	// gigabytes of pixels.
+	MaxPixels = 50_000_000
+	// NearDuplicateDistance is the largest perceptual-hash distance, in bits
+	// of 64, at which two photos count as the same picture.
+	NearDuplicateDistance = 6
+)
+
+var (
+	ErrNotFound        = errors.New("photo not found")
+	ErrEmpty           = errors.New("photo is empty")
+	ErrTooLarge        = errors.New("photo exceeds 10 MiB or 50 megapixels")
+	ErrUnsupportedType = errors.New("photo must be a readable JPEG or PNG image")
+)
+
+// Extensions maps the accepted content types, as sniffed from the bytes, to
+// the extension of the stored blob.
+var Extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// Table: photos (one row per upload; the bytes live in the blob store)
+type Photo struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex); what ApproveLoan accepts
+	PhotoID    string `gorm:"column:photo_id;type:char(32);not null;uniqueIndex:ux_photos_photo_id_active"`
+	StorageKey string `gorm:"column:storage_key;type:varchar(255);not null"`
+	SHA256     string `gorm:"column:sha256;type:char(64);not null;index:idx_photos_sha256"`
+	// Perceptual hash of the decoded image; NULL on photos stored before it existed
+	PHash       *phash.Hash    `gorm:"column:phash"`
+	ContentType string         `gorm:"column:content_type;type:varchar(64);not null"`
+	SizeBytes   int64          `gorm:"column:size_bytes;not null"`
+	UploadedBy  string         `gorm:"column:uploaded_by;type:char(32);not null"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	DeletedBy   *string        `gorm:"column:deleted_by;type:char(32);"`
+}
+
+func (Photo) TableName() string { return "photos" }
@@ -0,0 +1,15 @@
+package photo
+
+import "context"
+
+type Repository interface {
+	// Create records an upload whose bytes are already in the blob store
+	Create(ctx context.Context, p *Photo) error
+
+	// Get by public photo_id (gorm.ErrRecordNotFound if none)
+	GetByPhotoID(ctx context.Context, photoID string) (*Photo, error)
+
+	// GetByPhotoIDForUpdate is GetByPhotoID with the row locked until the
+	// transaction ends, so two approvals citing one photo run one at a time
+	GetByPhotoIDForUpdate(ctx context.Context, photoID string) (*Photo, error)
+}
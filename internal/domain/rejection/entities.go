@@ -0,0 +1,62 @@
+package rejection
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReasonCode is a catalog entry; free text goes to Rejection.ReasonText.
+type ReasonCode string
+
+const (
+	ReasonIncompleteDocuments  ReasonCode = "INCOMPLETE_DOCUMENTS"
+	ReasonFieldVisitFailed     ReasonCode = "FIELD_VISIT_FAILED"
+	ReasonInsufficientCapacity ReasonCode = "INSUFFICIENT_REPAYMENT_CAPACITY"
+	ReasonOutOfServiceArea     ReasonCode = "OUT_OF_SERVICE_AREA"
+	ReasonFraudSuspected       ReasonCode = "FRAUD_SUSPECTED"
+	ReasonOther                ReasonCode = "OTHER"
+)
+
+// catalog maps each known reason code to a human-readable label.
+var catalog = map[ReasonCode]string{
+	ReasonIncompleteDocuments:  "Incomplete or invalid documents",
+	ReasonFieldVisitFailed:     "Field verification failed",
+	ReasonInsufficientCapacity: "Insufficient repayment capacity",
+	ReasonOutOfServiceArea:     "Borrower outside service area",
+	ReasonFraudSuspected:       "Suspected fraud",
+	ReasonOther:                "Other (see reason text)",
+}
+
+var (
+	ErrNotFound           = errors.New("rejection not found")
+	ErrUnknownReason      = errors.New("unknown rejection reason code")
+	ErrReasonTextRequired = errors.New("reason text is required for reason OTHER")
+)
+
+// Known reports whether code is part of the catalog.
+func (c ReasonCode) Known() bool { _, ok := catalog[c]; return ok }
+
+// Label returns the catalog label, or "" for unknown codes.
+func (c ReasonCode) Label() string { return catalog[c] }
+
+// Table: rejections
+type Rejection struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	RejectionID string `gorm:"column:rejection_id;type:char(32);not null;uniqueIndex:ux_rejections_rejection_id_active"`
+	// FK to loans.id (numeric)
+	LoanID              uint64         `gorm:"column:loan_id;not null;uniqueIndex:ux_rejections_loan_active"`
+	ReasonCode          ReasonCode     `gorm:"column:reason_code;type:varchar(64);not null"`
+	ReasonText          string         `gorm:"column:reason_text;type:text"`
+	ValidatorEmployeeID string         `gorm:"column:validator_employee_id;type:char(32);not null"`
+	RejectedAt          time.Time      `gorm:"column:rejected_at;not null"`
+	CreatedAt           time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt           gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	DeletedBy           *string        `gorm:"column:deleted_by;type:char(32);"`
+}
+
+func (Rejection) TableName() string { return "rejections" }
@@ -0,0 +1,11 @@
+package rejection
+
+import "context"
+
+type Repository interface {
+	// Create a new rejection (DB uniqueness ensures at most one per loan)
+	Create(ctx context.Context, r *Rejection) error
+
+	// Get rejection by loan ID
+	GetByLoanID(ctx context.Context, loanID uint64) (*Rejection, error)
+}
@@ -0,0 +1,114 @@
+package repayment
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+// Allocation is how one payment was spread over a loan's installments.
+type Allocation struct {
+	Fee       money.Decimal
+	Interest  money.Decimal
+	Principal money.Decimal
+	Lines     []AllocationLine // only installments that received money
+}
+
+// AllocationLine is the part of a payment applied to one installment.
+type AllocationLine struct {
+	InstallmentNo uint16
+	Fee           money.Decimal
+	Interest      money.Decimal
+	Principal     money.Decimal
+	Settled       bool // installment fully paid after this payment
+}
+
+// Outstanding sums what is still owed across items.
+func Outstanding(items []Installment) money.Decimal {
+	total := money.Zero
+	for _, it := range items {
+		total = total.Add(it.Outstanding())
+	}
+	return total
+}
+
+// DueBy sums what is still owed on the items that fall due on or before at.
+func DueBy(items []Installment, at time.Time) money.Decimal {
+	total := money.Zero
+	for _, it := range items {
+		if isDue(it, at) {
+			total = total.Add(it.Outstanding())
+		}
+	}
+	return total
+}
+
+// Allocate applies amount to the installments due by at, oldest first. Within
+// each installment fees are covered before interest and interest before
+// principal; only then does the remainder move to the next installment, so an
+// overdue installment is always cleared before a later one is touched.
+// Installments not yet due are never paid ahead: an amount above DueBy(items,
+// at) is refused with ErrOverpayment rather than spent on future periods.
+//
+// items are updated in place (paid columns, PaidAt on settlement) and must be
+// ordered by InstallmentNo.
+func Allocate(items []Installment, amount money.Decimal, at time.Time) (*Allocation, error) {
+	if len(items) == 0 {
+		return nil, ErrScheduleNotFound
+	}
+	if !amount.IsPositive() || amount.Places() > 2 {
+		return nil, ErrInvalidAmount
+	}
+	at = at.UTC()
+	if amount.GreaterThan(DueBy(items, at)) {
+		return nil, ErrOverpayment
+	}
+
+	out := &Allocation{}
+	left := amount
+	for k := range items {
+		if !left.IsPositive() {
+			break
+		}
+		it := &items[k]
+		if it.Settled() || !isDue(*it, at) {
+			continue
+		}
+		var line AllocationLine
+		line.InstallmentNo = it.InstallmentNo
+		line.Fee, left = take(left, it.FeeDue.Sub(it.FeePaid))
+		line.Interest, left = take(left, it.InterestDue.Sub(it.InterestPaid))
+		line.Principal, left = take(left, it.PrincipalDue.Sub(it.PrincipalPaid))
+
+		it.FeePaid = it.FeePaid.Add(line.Fee)
+		it.InterestPaid = it.InterestPaid.Add(line.Interest)
+		it.PrincipalPaid = it.PrincipalPaid.Add(line.Principal)
+		if it.Settled() {
+			it.PaidAt = &at
+			line.Settled = true
+		}
+
+		out.Fee = out.Fee.Add(line.Fee)
+		out.Interest = out.Interest.Add(line.Interest)
+		out.Principal = out.Principal.Add(line.Principal)
+		out.Lines = append(out.Lines, line)
+	}
+	return out, nil
+}
+
+// isDue reports whether it falls due on or before at's calendar day (UTC, like
+// the due_date column).
+func isDue(it Installment, at time.Time) bool {
+	return !it.DueDate.After(at.UTC())
+}
+
+// take covers as much of due as left allows and returns (applied, remaining).
+func take(left, due money.Decimal) (money.Decimal, money.Decimal) {
+	if !due.IsPositive() {
+		return money.Zero, left
+	}
+	if left.LessThan(due) {
+		return left, money.Zero
+	}
+	return due, left.Sub(due)
+}
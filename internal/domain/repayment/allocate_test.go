@@ -0,0 +1,136 @@
+package repayment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+func threeInstallments() []Installment {
+	mk := func(no uint16, fee, interest, principal string) Installment {
+		f, i, p := money.MustParse(fee), money.MustParse(interest), money.MustParse(principal)
+		return Installment{InstallmentNo: no, FeeDue: f, InterestDue: i, PrincipalDue: p, TotalDue: f.Add(i).Add(p)}
+	}
+	return []Installment{
+		mk(1, "5000", "10000", "100000"),
+		mk(2, "0", "10000", "100000"),
+		mk(3, "0", "10000", "100000"),
+	}
+}
+
+func TestAllocate_FeesThenInterestThenPrincipal(t *testing.T) {
+	items := threeInstallments()
+	at := time.Date(2025, 10, 1, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+
+	// 12000 covers the 5000 fee, then 7000 of the 10000 interest; no principal yet
+	a, err := Allocate(items, money.NewFromInt(12000), at)
+	if err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	if a.Fee.String() != "5000" || a.Interest.String() != "7000" || !a.Principal.IsZero() {
+		t.Fatalf("split = fee %s interest %s principal %s", a.Fee, a.Interest, a.Principal)
+	}
+	if len(a.Lines) != 1 || a.Lines[0].InstallmentNo != 1 || a.Lines[0].Settled {
+		t.Fatalf("lines = %+v", a.Lines)
+	}
+	if items[0].PaidAt != nil || items[0].Outstanding().String() != "103000" {
+		t.Fatalf("installment 1 after partial: %+v", items[0])
+	}
+}
+
+func TestAllocate_ClearsOldestBeforeNext(t *testing.T) {
+	items := threeInstallments()
+	at := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	// installment 1 needs 115000; the remaining 20000 goes to installment 2 (interest first)
+	a, err := Allocate(items, money.NewFromInt(135000), at)
+	if err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	if len(a.Lines) != 2 || !a.Lines[0].Settled || a.Lines[1].Settled {
+		t.Fatalf("lines = %+v", a.Lines)
+	}
+	if a.Lines[1].Interest.String() != "10000" || a.Lines[1].Principal.String() != "10000" {
+		t.Fatalf("installment 2 line = %+v", a.Lines[1])
+	}
+	if items[0].PaidAt == nil || !items[0].PaidAt.Equal(at) || items[1].PaidAt != nil {
+		t.Fatalf("paid_at: 1=%v 2=%v", items[0].PaidAt, items[1].PaidAt)
+	}
+	if got := Outstanding(items); got.String() != "200000" {
+		t.Fatalf("outstanding = %s, want 200000", got)
+	}
+	if a.Fee.Add(a.Interest).Add(a.Principal).String() != "135000" {
+		t.Fatalf("allocation does not add up: %+v", a)
+	}
+}
+
+func TestAllocate_FullPayoffSettlesEverything(t *testing.T) {
+	items := threeInstallments()
+	total := Outstanding(items)
+	if _, err := Allocate(items, total, time.Now()); err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	for _, it := range items {
+		if !it.Settled() || it.PaidAt == nil {
+			t.Fatalf("installment %d not settled: %+v", it.InstallmentNo, it)
+		}
+	}
+	if !Outstanding(items).IsZero() {
+		t.Fatalf("outstanding = %s after payoff", Outstanding(items))
+	}
+}
+
+func TestAllocate_OnlyDueInstallments(t *testing.T) {
+	items := threeInstallments()
+	for k := range items {
+		items[k].DueDate = time.Date(2025, time.Month(10+k), 1, 0, 0, 0, 0, time.UTC)
+	}
+	// 1 Nov, 06:00 WIB is still 31 Oct in UTC: only installment 1 is due
+	at := time.Date(2025, 11, 1, 6, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	if got := DueBy(items, at); got.String() != "115000" {
+		t.Fatalf("DueBy = %s, want 115000", got)
+	}
+
+	// paying installment 2 ahead of its date is refused, not prepaid
+	if _, err := Allocate(items, money.NewFromInt(115001), at); !errors.Is(err, ErrOverpayment) {
+		t.Fatalf("ahead of schedule: got %v", err)
+	}
+	if !items[0].FeePaid.IsZero() || !items[1].InterestPaid.IsZero() {
+		t.Fatalf("refused payment must not touch installments: %+v", items[:2])
+	}
+
+	// on its due day installment 2 can be paid, installment 3 still cannot
+	at = time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC)
+	a, err := Allocate(items, money.NewFromInt(225000), at)
+	if err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	if len(a.Lines) != 2 || !a.Lines[1].Settled || items[2].PaidAt != nil {
+		t.Fatalf("lines = %+v", a.Lines)
+	}
+	if _, err := Allocate(items, money.MustParse("0.01"), at); !errors.Is(err, ErrOverpayment) {
+		t.Fatalf("nothing due: got %v", err)
+	}
+}
+
+func TestAllocate_Rejects(t *testing.T) {
+	if _, err := Allocate(nil, money.NewFromInt(1), time.Now()); !errors.Is(err, ErrScheduleNotFound) {
+		t.Fatalf("empty schedule: got %v", err)
+	}
+	for _, amt := range []string{"0", "-1", "0.001"} {
+		if _, err := Allocate(threeInstallments(), money.MustParse(amt), time.Now()); !errors.Is(err, ErrInvalidAmount) {
+			t.Fatalf("amount %s: got %v", amt, err)
+		}
+	}
+
+	items := threeInstallments()
+	over := Outstanding(items).Add(money.MustParse("0.01"))
+	if _, err := Allocate(items, over, time.Now()); !errors.Is(err, ErrOverpayment) {
+		t.Fatalf("overpayment: got %v", err)
+	}
+	if !items[0].InterestPaid.IsZero() {
+		t.Fatalf("rejected payment must not touch installments: %+v", items[0])
+	}
+}
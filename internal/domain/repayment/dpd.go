@@ -0,0 +1,40 @@
+package repayment
+
+import (
+	"math"
+	"time"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/pkg/money"
+)
+
+// DaysPastDue counts calendar days from the oldest unpaid installment's due
+// date to asOf's calendar date (in asOf's location). It is 0 when nothing is
+// overdue; a due date equal to asOf is not yet late.
+func DaysPastDue(items []Installment, asOf time.Time) int {
+	y, m, d := asOf.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for _, it := range items {
+		if it.Settled() {
+			continue
+		}
+		dy, dm, dd := it.DueDate.Date()
+		due := time.Date(dy, dm, dd, 0, 0, 0, 0, time.UTC)
+		if !today.After(due) {
+			return 0
+		}
+		days := int(today.Sub(due).Hours() / 24)
+		if days > math.MaxUint16 {
+			days = math.MaxUint16
+		}
+		return days
+	}
+	return 0
+}
+
+// BucketExposure is the outstanding principal of disbursed loans in one DPD bucket.
+type BucketExposure struct {
+	Bucket               loan.DPDBucket
+	Loans                int64
+	OutstandingPrincipal money.Decimal
+}
@@ -0,0 +1,41 @@
+package repayment
+
+import (
+	"testing"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+func TestDaysPastDue(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	items := []Installment{
+		{InstallmentNo: 1, DueDate: day(9, 1), PrincipalDue: money.NewFromInt(100), PrincipalPaid: money.NewFromInt(100)},
+		{InstallmentNo: 2, DueDate: day(10, 1), PrincipalDue: money.NewFromInt(100), PrincipalPaid: money.NewFromInt(40)},
+		{InstallmentNo: 3, DueDate: day(11, 1), PrincipalDue: money.NewFromInt(100)},
+	}
+
+	cases := []struct {
+		name string
+		asOf time.Time
+		want int
+	}{
+		{"before first unpaid due", day(9, 20), 0},
+		{"on the due date", day(10, 1), 0},
+		{"one day late", day(10, 2), 1},
+		{"counts from oldest unpaid", day(11, 15), 45},
+		// 23:30 WIB on Oct 31 is still Oct 31 locally even though it is 16:30Z
+		{"uses asOf's calendar date", time.Date(2025, 10, 31, 23, 30, 0, 0, time.FixedZone("WIB", 7*3600)), 30},
+	}
+	for _, tt := range cases {
+		if got := DaysPastDue(items, tt.asOf); got != tt.want {
+			t.Errorf("%s: DaysPastDue = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	items[1].PrincipalPaid = money.NewFromInt(100)
+	items[2].PrincipalPaid = money.NewFromInt(100)
+	if got := DaysPastDue(items, day(12, 31)); got != 0 {
+		t.Errorf("fully paid schedule: DaysPastDue = %d, want 0", got)
+	}
+}
@@ -0,0 +1,71 @@
+package repayment
+
+import (
+	"errors"
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("repayment schedule not found")
+	ErrInvalidAmount    = errors.New("repayment amount must be positive with at most 2 decimal places")
+	ErrOverpayment      = errors.New("repayment amount exceeds the balance due by the payment date")
+)
+
+// Table: repayment_schedules (one row per installment, generated at disbursement)
+type Installment struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// FK to loans.id (numeric)
+	LoanID        uint64        `gorm:"column:loan_id;not null;uniqueIndex:ux_rs_loan_installment"`
+	InstallmentNo uint16        `gorm:"column:installment_no;type:smallint unsigned;not null;uniqueIndex:ux_rs_loan_installment"`
+	DueDate       time.Time     `gorm:"column:due_date;type:date;not null"`
+	PrincipalDue  money.Decimal `gorm:"column:principal_due;type:decimal(18,2);not null"`
+	InterestDue   money.Decimal `gorm:"column:interest_due;type:decimal(18,2);not null"`
+	// No product charges fees yet, so BuildSchedule leaves FeeDue at zero
+	FeeDue   money.Decimal `gorm:"column:fee_due;type:decimal(18,2);not null"`
+	TotalDue money.Decimal `gorm:"column:total_due;type:decimal(18,2);not null"`
+	// Running totals of what repayments have covered so far
+	PrincipalPaid money.Decimal `gorm:"column:principal_paid;type:decimal(18,2);not null"`
+	InterestPaid  money.Decimal `gorm:"column:interest_paid;type:decimal(18,2);not null"`
+	FeePaid       money.Decimal `gorm:"column:fee_paid;type:decimal(18,2);not null"`
+	// Set when the installment is fully covered
+	PaidAt    *time.Time `gorm:"column:paid_at;type:datetime"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Installment) TableName() string { return "repayment_schedules" }
+
+// Outstanding is what is still owed on this installment (fees + interest + principal).
+func (i Installment) Outstanding() money.Decimal {
+	return i.FeeDue.Sub(i.FeePaid).
+		Add(i.InterestDue.Sub(i.InterestPaid)).
+		Add(i.PrincipalDue.Sub(i.PrincipalPaid))
+}
+
+// Settled reports whether nothing is left to pay on this installment.
+func (i Installment) Settled() bool { return !i.Outstanding().IsPositive() }
+
+// Table: repayments (one row per collected payment; never updated)
+type Repayment struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	RepaymentID string `gorm:"column:repayment_id;type:char(32);not null;uniqueIndex:ux_repayments_repayment_id"`
+	// FK to loans.id (numeric)
+	LoanID uint64        `gorm:"column:loan_id;not null;index:idx_repayments_loan"`
+	Amount money.Decimal `gorm:"column:amount;type:decimal(18,2);not null"`
+	// How Amount was split by Allocate
+	FeePaid       money.Decimal `gorm:"column:fee_paid;type:decimal(18,2);not null"`
+	InterestPaid  money.Decimal `gorm:"column:interest_paid;type:decimal(18,2);not null"`
+	PrincipalPaid money.Decimal `gorm:"column:principal_paid;type:decimal(18,2);not null"`
+	// Loan outstanding balance right after this payment
+	OutstandingAfter    money.Decimal `gorm:"column:outstanding_after;type:decimal(18,2);not null"`
+	CollectorEmployeeID string        `gorm:"column:collector_employee_id;type:char(32);not null"`
+	PaidAt              time.Time     `gorm:"column:paid_at;type:datetime;not null"`
+	CreatedAt           time.Time     `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Repayment) TableName() string { return "repayments" }
@@ -0,0 +1,25 @@
+package repayment
+
+import "context"
+
+type Repository interface {
+	// CreateSchedule inserts all installments of a loan in one statement
+	CreateSchedule(ctx context.Context, items []Installment) error
+
+	// ListByLoanID returns a loan's installments (numeric loan ID), ordered by installment_no
+	ListByLoanID(ctx context.Context, loanID uint64) ([]Installment, error)
+
+	// UpdatePaid persists the paid columns (fee/interest/principal paid, paid_at) of items
+	UpdatePaid(ctx context.Context, items []Installment) error
+
+	// ExposureByBucket sums unpaid principal of disbursed loans per stored dpd_bucket
+	ExposureByBucket(ctx context.Context) ([]BucketExposure, error)
+}
+
+// PaymentRepository stores collected repayments (append-only).
+type PaymentRepository interface {
+	Create(ctx context.Context, p *Repayment) error
+
+	// ListByLoanID returns a loan's repayments (numeric loan ID), oldest first
+	ListByLoanID(ctx context.Context, loanID uint64) ([]Repayment, error)
+}
@@ -0,0 +1,108 @@
+package repayment
+
+import (
+	"fmt"
+	"time"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/pkg/money"
+)
+
+var (
+	hundred = money.NewFromInt(100)
+	one     = money.NewFromInt(1)
+)
+
+// BuildSchedule derives a loan's installments from Principal, Rate (percent per
+// period), Tenor and InterestMethod. The first installment falls due one period
+// after start; the last installment absorbs any rounding remainder so principal
+// parts always sum to Principal exactly.
+func BuildSchedule(l *loan.Loan, start time.Time) ([]Installment, error) {
+	if l.Tenor == 0 || !l.TenorUnit.Valid() {
+		return nil, loan.ErrInvalidTenor
+	}
+	method := l.InterestMethod
+	if method == "" {
+		method = loan.InterestFlat
+	}
+
+	n := int(l.Tenor)
+	r := l.Rate.Div(hundred, 8)
+	var parts [][2]money.Decimal // principal, interest
+	switch method {
+	case loan.InterestFlat:
+		parts = flat(l.Principal, r, n)
+	case loan.InterestEffective:
+		parts = effective(l.Principal, r, n)
+	default:
+		return nil, fmt.Errorf("unknown interest method %q", method)
+	}
+
+	start = start.UTC()
+	out := make([]Installment, n)
+	for i, p := range parts {
+		out[i] = Installment{
+			LoanID:        l.ID,
+			InstallmentNo: uint16(i + 1),
+			DueDate:       dueDate(start, l.TenorUnit, i+1),
+			PrincipalDue:  p[0],
+			InterestDue:   p[1],
+			TotalDue:      p[0].Add(p[1]),
+		}
+	}
+	return out, nil
+}
+
+// flat: equal principal parts, interest on the original principal every period.
+func flat(principal, r money.Decimal, n int) [][2]money.Decimal {
+	nDec := money.NewFromInt(int64(n))
+	each := principal.Div(nDec, 4).Truncate(2)
+	interest := principal.Mul(r).Round(2)
+
+	out := make([][2]money.Decimal, n)
+	remaining := principal
+	for i := range out {
+		p := each
+		if i == n-1 {
+			p = remaining
+		}
+		remaining = remaining.Sub(p)
+		out[i] = [2]money.Decimal{p, interest}
+	}
+	return out
+}
+
+// effective: annuity on the declining balance, PMT = P·r·(1+r)^n / ((1+r)^n − 1).
+func effective(principal, r money.Decimal, n int) [][2]money.Decimal {
+	if r.IsZero() {
+		return flat(principal, r, n)
+	}
+	f := one.Add(r).Pow(n)
+	payment := principal.Mul(r).Mul(f).Div(f.Sub(one), 2)
+
+	out := make([][2]money.Decimal, n)
+	balance := principal
+	for i := range out {
+		interest := balance.Mul(r).Round(2)
+		p := payment.Sub(interest)
+		if i == n-1 || p.GreaterThan(balance) {
+			p = balance
+		}
+		balance = balance.Sub(p)
+		out[i] = [2]money.Decimal{p, interest}
+	}
+	return out
+}
+
+// dueDate returns start + k periods; month ends are clamped (Jan 31 → Feb 28).
+func dueDate(start time.Time, unit loan.TenorUnit, k int) time.Time {
+	y, m, d := start.Date()
+	if unit == loan.TenorWeek {
+		return time.Date(y, m, d+7*k, 0, 0, 0, 0, time.UTC)
+	}
+	first := time.Date(y, m+time.Month(k), 1, 0, 0, 0, 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,144 @@
+package repayment
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/pkg/money"
+)
+
+func sumParts(items []Installment) (principal, interest money.Decimal) {
+	for _, it := range items {
+		principal = principal.Add(it.PrincipalDue)
+		interest = interest.Add(it.InterestDue)
+		if !it.TotalDue.Equal(it.PrincipalDue.Add(it.InterestDue)) {
+			panic("total_due must equal principal_due + interest_due")
+		}
+	}
+	return principal, interest
+}
+
+func TestBuildSchedule_FlatWeekly(t *testing.T) {
+	l := &loan.Loan{
+		ID: 7, Principal: money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"),
+		Tenor: 4, TenorUnit: loan.TenorWeek, InterestMethod: loan.InterestFlat,
+	}
+	start := time.Date(2025, 9, 1, 15, 30, 0, 0, time.UTC)
+
+	items, err := BuildSchedule(l, start)
+	if err != nil {
+		t.Fatalf("BuildSchedule: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("len = %d, want 4", len(items))
+	}
+	for i, it := range items {
+		if it.LoanID != 7 || it.InstallmentNo != uint16(i+1) {
+			t.Fatalf("installment %d: bad keys %+v", i, it)
+		}
+		if want := time.Date(2025, 9, 1+7*(i+1), 0, 0, 0, 0, time.UTC); !it.DueDate.Equal(want) {
+			t.Fatalf("installment %d due %s, want %s", i+1, it.DueDate, want)
+		}
+		if it.PrincipalDue.String() != "1250000" || it.InterestDue.String() != "75000" {
+			t.Fatalf("installment %d: principal=%s interest=%s", i+1, it.PrincipalDue, it.InterestDue)
+		}
+	}
+}
+
+func TestBuildSchedule_FlatRemainderOnLastInstallment(t *testing.T) {
+	l := &loan.Loan{
+		Principal: money.NewFromInt(5_000_000), Rate: money.MustParse("1.29"),
+		Tenor: 3, TenorUnit: loan.TenorMonth, // method defaults to flat
+	}
+	items, err := BuildSchedule(l, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("BuildSchedule: %v", err)
+	}
+	if items[0].PrincipalDue.String() != "1666666.66" || items[2].PrincipalDue.String() != "1666666.68" {
+		t.Fatalf("unexpected principal split: %s / %s", items[0].PrincipalDue, items[2].PrincipalDue)
+	}
+	p, i := sumParts(items)
+	if !p.Equal(l.Principal) || i.String() != "193500" {
+		t.Fatalf("sums: principal=%s interest=%s", p, i)
+	}
+	// month-end clamping: Jan 31 → Feb 28 → Mar 31 → Apr 30
+	for k, want := range []string{"2025-02-28", "2025-03-31", "2025-04-30"} {
+		if got := items[k].DueDate.Format("2006-01-02"); got != want {
+			t.Fatalf("due[%d] = %s, want %s", k, got, want)
+		}
+	}
+}
+
+func TestBuildSchedule_Effective(t *testing.T) {
+	l := &loan.Loan{
+		Principal: money.NewFromInt(1000), Rate: money.NewFromInt(1),
+		Tenor: 3, TenorUnit: loan.TenorMonth, InterestMethod: loan.InterestEffective,
+	}
+	items, err := BuildSchedule(l, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("BuildSchedule: %v", err)
+	}
+	want := [][3]string{ // principal, interest, total
+		{"330.02", "10", "340.02"},
+		{"333.32", "6.7", "340.02"},
+		{"336.66", "3.37", "340.03"},
+	}
+	for k, w := range want {
+		it := items[k]
+		if it.PrincipalDue.String() != w[0] || it.InterestDue.String() != w[1] || it.TotalDue.String() != w[2] {
+			t.Fatalf("installment %d = %s/%s/%s, want %v", k+1, it.PrincipalDue, it.InterestDue, it.TotalDue, w)
+		}
+	}
+	if p, _ := sumParts(items); !p.Equal(l.Principal) {
+		t.Fatalf("principal parts sum to %s", p)
+	}
+}
+
+func TestBuildSchedule_EffectiveLongWeeklyTenorBalances(t *testing.T) {
+	l := &loan.Loan{
+		Principal: money.NewFromInt(7_500_000), Rate: money.MustParse("2.99"),
+		Tenor: 50, TenorUnit: loan.TenorWeek, InterestMethod: loan.InterestEffective,
+	}
+	items, err := BuildSchedule(l, time.Now())
+	if err != nil {
+		t.Fatalf("BuildSchedule: %v", err)
+	}
+	p, i := sumParts(items)
+	if !p.Equal(l.Principal) {
+		t.Fatalf("principal parts sum to %s", p)
+	}
+	// declining balance must charge less interest than flat at the same rate
+	flatInterest := l.Principal.Mul(money.MustParse("0.0299")).Mul(money.NewFromInt(50))
+	if !i.LessThan(flatInterest) {
+		t.Fatalf("effective interest %s should be below flat %s", i, flatInterest)
+	}
+	for k, it := range items {
+		if it.PrincipalDue.IsNegative() || it.InterestDue.IsNegative() {
+			t.Fatalf("installment %d has negative parts: %+v", k+1, it)
+		}
+	}
+}
+
+func TestBuildSchedule_InvalidInput(t *testing.T) {
+	base := loan.Loan{Principal: money.NewFromInt(1000), Rate: money.NewFromInt(2), Tenor: 3, TenorUnit: loan.TenorWeek}
+
+	noTenor := base
+	noTenor.Tenor = 0
+	if _, err := BuildSchedule(&noTenor, time.Now()); !errors.Is(err, loan.ErrInvalidTenor) {
+		t.Fatalf("tenor 0: want ErrInvalidTenor, got %v", err)
+	}
+
+	badUnit := base
+	badUnit.TenorUnit = "day"
+	if _, err := BuildSchedule(&badUnit, time.Now()); !errors.Is(err, loan.ErrInvalidTenor) {
+		t.Fatalf("bad unit: want ErrInvalidTenor, got %v", err)
+	}
+
+	badMethod := base
+	badMethod.InterestMethod = "compound"
+	if _, err := BuildSchedule(&badMethod, time.Now()); err == nil {
+		t.Fatalf("bad method: want error")
+	}
+}
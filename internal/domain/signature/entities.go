@@ -0,0 +1,120 @@
+package signature
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status mirrors disbursements.signature_status.
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusSigned    Status = "SIGNED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// Valid reports whether s is a known status.
+func (s Status) Valid() bool {
+	return s == StatusPending || s == StatusSigned || s == StatusCancelled
+}
+
+// Advances reports whether moving from s to next is forward: PENDING may
+// become SIGNED or CANCELLED, and both of those are final.
+func (s Status) Advances(next Status) bool {
+	return s == StatusPending && (next == StatusSigned || next == StatusCancelled)
+}
+
+// SignatureHeader carries the hex HMAC-SHA256 of an inbound webhook body.
+const SignatureHeader = "Ax-Signature"
+
+var (
+	ErrNotFound         = errors.New("signature envelope not found")
+	ErrUnknownProvider  = errors.New("unknown signature provider")
+	ErrBadSignature     = errors.New("webhook signature mismatch")
+	ErrInvalidEvent     = errors.New("malformed signature event")
+	ErrEnvelopeExists   = errors.New("loan already has an open or signed envelope")
+	ErrLoanNotInvested  = errors.New("agreement can only be sent for signing once the loan is invested")
+	ErrEnvelopeMismatch = errors.New("signature transaction is not the loan's latest envelope")
+)
+
+// EnvelopeRequest is what a provider needs to collect a signature.
+type EnvelopeRequest struct {
+	Reference   string // our envelope_id, echoed back by providers that support it
+	DocumentURL string
+	SignerID    string // borrower id
+}
+
+// ProviderEnvelope is the provider's view of a signing request.
+type ProviderEnvelope struct {
+	TxID       string
+	Status     Status
+	SigningURL string
+}
+
+// WebhookEvent is a verified status change pushed by a provider.
+type WebhookEvent struct {
+	EventID    string
+	TxID       string
+	Status     Status
+	OccurredAt time.Time
+}
+
+// Provider is an e-signature vendor.
+type Provider interface {
+	// Name is the :provider path segment and the signature_provider value.
+	Name() string
+	CreateEnvelope(ctx context.Context, req EnvelopeRequest) (*ProviderEnvelope, error)
+	FetchStatus(ctx context.Context, txID string) (Status, error)
+	// DownloadSigned streams the signed document; only valid once SIGNED.
+	DownloadSigned(ctx context.Context, txID string) (io.ReadCloser, error)
+	// ParseWebhook verifies sig against body and decodes the event
+	// (ErrBadSignature, ErrInvalidEvent).
+	ParseWebhook(body []byte, sig string) (*WebhookEvent, error)
+}
+
+// Table: signature_envelopes (one row per signing request; a loan may have
+// several, only the latest counts)
+type Envelope struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	EnvelopeID        string     `gorm:"column:envelope_id;type:char(32);not null;uniqueIndex:ux_sigenv_envelope_id_active"`
+	LoanID            uint64     `gorm:"column:loan_id;not null;index:idx_sigenv_loan_id"`
+	SignatureProvider string     `gorm:"column:signature_provider;type:varchar(64);not null;uniqueIndex:ux_sigenv_tx_active"`
+	SignatureTxID     string     `gorm:"column:signature_tx_id;type:varchar(128);not null;uniqueIndex:ux_sigenv_tx_active"`
+	SignatureStatus   Status     `gorm:"column:signature_status;type:enum('PENDING','SIGNED','CANCELLED');not null;default:'PENDING'"`
+	DocumentURL       string     `gorm:"column:document_url;type:text;not null"`
+	SigningURL        string     `gorm:"column:signing_url;type:text;not null"`
+	SignedAt          *time.Time `gorm:"column:signed_at;type:datetime"`
+	DocumentSHA256    *string    `gorm:"column:document_sha256;type:char(64)"`
+	// Blob key of the signed copy pulled from the provider
+	SignedDocumentKey *string        `gorm:"column:signed_document_key;type:varchar(255)"`
+	RequestedBy       string         `gorm:"column:requested_by;type:char(32);not null"`
+	CreatedAt         time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt         gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	DeletedBy         *string        `gorm:"column:deleted_by;type:char(32);"`
+}
+
+func (Envelope) TableName() string { return "signature_envelopes" }
+
+// Table: signature_events (one row per provider event id; makes webhook
+// replays no-ops)
+type Event struct {
+	ID                uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	SignatureProvider string `gorm:"column:signature_provider;type:varchar(64);not null;uniqueIndex:ux_sigevt_provider_event"`
+	EventID           string `gorm:"column:event_id;type:varchar(128);not null;uniqueIndex:ux_sigevt_provider_event"`
+	EnvelopeID        uint64 `gorm:"column:envelope_id;not null"`
+	SignatureStatus   Status `gorm:"column:signature_status;type:enum('PENDING','SIGNED','CANCELLED');not null"`
+	// Applied is false when the event arrived out of order and was ignored
+	Applied    bool      `gorm:"column:applied;not null"`
+	OccurredAt time.Time `gorm:"column:occurred_at;type:datetime;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (Event) TableName() string { return "signature_events" }
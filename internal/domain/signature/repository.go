@@ -0,0 +1,26 @@
+package signature
+
+import "context"
+
+type Repository interface {
+	CreateEnvelope(ctx context.Context, e *Envelope) error
+
+	// Get the envelope a provider transaction belongs to
+	GetByProviderTx(ctx context.Context, provider, txID string) (*Envelope, error)
+
+	// GetByProviderTx with a FOR UPDATE lock on the envelope row; status
+	// changes go through it so concurrent events apply one at a time
+	GetByProviderTxForUpdate(ctx context.Context, provider, txID string) (*Envelope, error)
+
+	// Get the most recent envelope of a loan (numeric loan ID)
+	GetLatestByLoanID(ctx context.Context, loanID uint64) (*Envelope, error)
+
+	// Persist status, signed_at and document fields
+	SaveEnvelope(ctx context.Context, e *Envelope) error
+
+	// Get a recorded webhook event; gorm.ErrRecordNotFound when it is new
+	GetEvent(ctx context.Context, provider, eventID string) (*Event, error)
+
+	// Record a webhook event (DB uniqueness rejects a second copy)
+	CreateEvent(ctx context.Context, ev *Event) error
+}
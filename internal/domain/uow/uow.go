@@ -2,14 +2,38 @@ package uow
 
 import (
 	"amartha-backend-test/internal/domain/approval"
+	"amartha-backend-test/internal/domain/disbursement"
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/ledger"
 	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/notification"
+	"amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/payout"
+	"amartha-backend-test/internal/domain/photo"
+	"amartha-backend-test/internal/domain/rejection"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/domain/signature"
+	"amartha-backend-test/internal/domain/webhook"
 	"context"
 )
 
 // domain/uow/uow.go
 type Repos struct {
-	Loans     loan.Repository
-	Approvals approval.Repository
+	Loans         loan.Repository
+	LoanHistory   loan.HistoryRepository
+	Approvals     approval.Repository
+	Investments   investment.Repository
+	Disbursements disbursement.Repository
+	Rejections    rejection.Repository
+	Schedules     repayment.Repository
+	Repayments    repayment.PaymentRepository
+	Payouts       payout.Repository
+	Ledger        ledger.Repository
+	Photos        photo.Repository
+	Signatures    signature.Repository
+	Outbox        outbox.Repository
+	Webhooks      webhook.Repository
+	Notifications notification.Repository
 }
 
 type UnitOfWork interface {
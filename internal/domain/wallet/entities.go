@@ -0,0 +1,72 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"amartha-backend-test/pkg/money"
+)
+
+// An investor's wallet is the investor_wallet ledger account; this package
+// holds the rules around spending from it and crediting it.
+
+var (
+	ErrInsufficientBalance = errors.New("insufficient wallet balance")
+	ErrInvalidAmount       = errors.New("top-up amount must be positive with at most 2 decimal places")
+	// The gateway reference was already settled for another investor or amount
+	ErrTopUpMismatch = errors.New("gateway reference already settled with different details")
+	ErrBadSignature  = errors.New("top-up callback signature mismatch")
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of a top-up callback body,
+// keyed with the secret shared with the payment gateway.
+const SignatureHeader = "Ax-Signature"
+
+// VerifyCallback compares sig with the HMAC of body in constant time. An
+// empty secret verifies nothing.
+func VerifyCallback(secret, body []byte, sig string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimSpace(sig))
+	if err != nil {
+		return false
+	}
+	m := hmac.New(sha256.New, secret)
+	m.Write(body)
+	return hmac.Equal(got, m.Sum(nil))
+}
+
+// InsufficientBalanceError is returned when a spend exceeds the available
+// balance; errors.Is(err, ErrInsufficientBalance) matches it.
+type InsufficientBalanceError struct {
+	InvestorID string
+	Available  money.Decimal
+	Required   money.Decimal
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("insufficient wallet balance: available %s, required %s", e.Available, e.Required)
+}
+
+func (e *InsufficientBalanceError) Is(target error) bool { return target == ErrInsufficientBalance }
+
+// CheckFunds returns an *InsufficientBalanceError when available < required.
+func CheckFunds(investorID string, available, required money.Decimal) error {
+	if available.LessThan(required) {
+		return &InsufficientBalanceError{InvestorID: investorID, Available: available, Required: required}
+	}
+	return nil
+}
+
+// TopUpStatus is the settlement outcome the payment gateway reports.
+type TopUpStatus string
+
+const (
+	TopUpPaid   TopUpStatus = "PAID"
+	TopUpFailed TopUpStatus = "FAILED"
+)
@@ -0,0 +1,26 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+
+	"amartha-backend-test/pkg/money"
+)
+
+func TestCheckFunds(t *testing.T) {
+	if err := CheckFunds("alice", money.NewFromInt(100), money.NewFromInt(100)); err != nil {
+		t.Fatalf("exact balance should pass, got %v", err)
+	}
+
+	err := CheckFunds("alice", money.MustParse("99.99"), money.NewFromInt(100))
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("want ErrInsufficientBalance, got %v", err)
+	}
+	var ibe *InsufficientBalanceError
+	if !errors.As(err, &ibe) || ibe.InvestorID != "alice" || ibe.Available.String() != "99.99" || ibe.Required.String() != "100" {
+		t.Fatalf("typed error: %+v", ibe)
+	}
+	if err.Error() != "insufficient wallet balance: available 99.99, required 100" {
+		t.Fatalf("message: %s", err)
+	}
+}
@@ -0,0 +1,199 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"amartha-backend-test/internal/domain/outbox"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// TimestampHeader carries the unix seconds the delivery was signed at,
+	// like Ax-Request-At on inbound calls.
+	TimestampHeader = "Ax-Request-At"
+	// SignatureHeader carries Sign(secret, at, body).
+	SignatureHeader = "Ax-Signature"
+	// DeliveryHeader carries the delivery_id, stable across retries.
+	DeliveryHeader = "Ax-Delivery-Id"
+)
+
+const (
+	// RetryBase is the wait after the first failed delivery; it doubles per
+	// further failure up to RetryCap.
+	RetryBase = 30 * time.Second
+	RetryCap  = 6 * time.Hour
+)
+
+var (
+	ErrInvalidURL    = errors.New("webhook url must be an absolute http(s) url")
+	ErrInsecureURL   = errors.New("webhook url must use https")
+	ErrPrivateHost   = errors.New("webhook url must resolve to public addresses only")
+	ErrInvalidEvents = errors.New("webhook events must list known loan events")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Events is every loan event a partner can subscribe to.
+var Events = outbox.Events
+
+// Status is where a delivery is in its retries.
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusDelivered Status = "DELIVERED"
+	// StatusDead deliveries ran out of attempts (the dead-letter state); they
+	// stay for inspection.
+	StatusDead Status = "DEAD"
+)
+
+// Valid reports whether s is a known status.
+func (s Status) Valid() bool {
+	return s == StatusPending || s == StatusDelivered || s == StatusDead
+}
+
+// Sign is the hex HMAC-SHA256 of "<unix seconds>.<body>" under secret.
+// Binding the timestamp lets partners reject replays of old deliveries.
+func Sign(secret []byte, at time.Time, body []byte) string {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(strconv.FormatInt(at.Unix(), 10)))
+	m.Write([]byte{'.'})
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Backoff is the wait before the next try of a delivery that has failed
+// attempts times (attempts >= 1).
+func Backoff(attempts uint16) time.Duration {
+	d := RetryBase
+	for i := uint16(1); i < attempts && d < RetryCap; i++ {
+		d *= 2
+	}
+	return min(d, RetryCap)
+}
+
+// nonPublic are special-purpose ranges the netip predicates do not cover.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, incl. broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, may embed any IPv4
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// PublicAddr reports whether a is a globally routable unicast address, so
+// that webhooks cannot be aimed at loopback, link-local (cloud metadata) or
+// private networks.
+func PublicAddr(a netip.Addr) bool {
+	a = a.Unmap()
+	if !a.IsGlobalUnicast() || a.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(a) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolver looks up a host's addresses; *net.Resolver satisfies it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Request is one signed POST to a partner.
+type Request struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// Poster sends webhook requests. It returns the response status; a transport
+// failure is an error.
+type Poster interface {
+	Post(ctx context.Context, r Request) (int, error)
+}
+
+// Table: webhook_subscriptions (one row per partner endpoint)
+type Subscription struct {
+	// Internal numeric PK
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	SubscriptionID string `gorm:"column:subscription_id;type:char(32);not null;uniqueIndex:ux_whsub_subscription_id_active"`
+	PartnerID      string `gorm:"column:partner_id;type:char(32);not null;index:idx_whsub_partner"`
+	URL            string `gorm:"column:url;type:varchar(2048);not null"`
+	// HMAC key for SignatureHeader; shown to the partner once, at creation
+	Secret string `gorm:"column:secret;type:char(64);not null"`
+	// Comma-separated event types the partner wants
+	EventTypes string         `gorm:"column:event_types;type:varchar(255);not null"`
+	CreatedAt  time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	DeletedAt  gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	DeletedBy  *string        `gorm:"column:deleted_by;type:char(32);"`
+}
+
+func (Subscription) TableName() string { return "webhook_subscriptions" }
+
+// Events lists the subscribed event types.
+func (s *Subscription) Events() []outbox.EventType {
+	var out []outbox.EventType
+	for _, t := range strings.Split(s.EventTypes, ",") {
+		if t != "" {
+			out = append(out, outbox.EventType(t))
+		}
+	}
+	return out
+}
+
+// Wants reports whether the subscription's filter includes t.
+func (s *Subscription) Wants(t outbox.EventType) bool {
+	return slices.Contains(s.Events(), t)
+}
+
+// Table: webhook_deliveries (one row per subscription and event)
+type Delivery struct {
+	ID uint64 `gorm:"column:id;primaryKey;autoIncrement"`
+	// Public identifier (32-char lowercase hex)
+	DeliveryID     string           `gorm:"column:delivery_id;type:char(32);not null;uniqueIndex:ux_whdel_delivery_id"`
+	SubscriptionID uint64           `gorm:"column:subscription_id;not null;uniqueIndex:ux_whdel_subscription_event"`
+	PartnerID      string           `gorm:"column:partner_id;type:char(32);not null;index:idx_whdel_partner"`
+	EventID        string           `gorm:"column:event_id;type:char(32);not null;uniqueIndex:ux_whdel_subscription_event"`
+	EventType      outbox.EventType `gorm:"column:event_type;type:varchar(32);not null"`
+	Payload        string           `gorm:"column:payload;type:json;not null"`
+	Status         Status           `gorm:"column:status;type:enum('PENDING','DELIVERED','DEAD');not null;default:'PENDING';index:idx_whdel_due,priority:1"`
+	Attempts       uint16           `gorm:"column:attempts;type:smallint unsigned;not null;default:0"`
+	NextAttemptAt  time.Time        `gorm:"column:next_attempt_at;type:datetime;not null;index:idx_whdel_due,priority:2"`
+	// HTTP status of the last attempt; nil when it never got a response
+	LastStatusCode *int       `gorm:"column:last_status_code"`
+	LastError      *string    `gorm:"column:last_error;type:text"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at;type:datetime"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Delivery) TableName() string { return "webhook_deliveries" }
+
+// DeliveryFilter selects deliveries for inspection, newest first; BeforeID
+// continues a listing below that numeric id (0 starts at the newest).
+type DeliveryFilter struct {
+	PartnerID string
+	Status    Status
+	EventID   string
+	BeforeID  uint64
+	Limit     int
+}
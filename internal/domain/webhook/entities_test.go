@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/outbox"
+)
+
+func TestSign(t *testing.T) {
+	secret, body := []byte("0123456789abcdef"), []byte(`{"type":"LoanApproved"}`)
+	at := time.Unix(1759300000, 0)
+
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte("1759300000." + string(body)))
+	if got, want := Sign(secret, at, body), hex.EncodeToString(m.Sum(nil)); got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+	// the timestamp is part of what is signed
+	if Sign(secret, at.Add(time.Second), body) == Sign(secret, at, body) {
+		t.Fatalf("signature must change with the timestamp")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[uint16]time.Duration{
+		1: 30 * time.Second, 2: time.Minute, 3: 2 * time.Minute,
+		10: 15360 * time.Second, 11: 6 * time.Hour, 500: 6 * time.Hour,
+	}
+	for n, want := range cases {
+		if got := Backoff(n); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestSubscription_Wants(t *testing.T) {
+	s := &Subscription{EventTypes: "LoanApproved,LoanDisbursed"}
+	for ev, want := range map[outbox.EventType]bool{
+		outbox.LoanApproved: true, outbox.LoanDisbursed: true,
+		outbox.LoanProposed: false, outbox.LoanInvested: false,
+	} {
+		if got := s.Wants(ev); got != want {
+			t.Errorf("Wants(%s) = %v, want %v", ev, got, want)
+		}
+	}
+	if len((&Subscription{}).Events()) != 0 {
+		t.Errorf("empty filter should list no events")
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.215.14":        true,
+		"2606:4700::6810:84e5": true,
+		"127.0.0.1":            false,
+		"::1":                  false,
+		"169.254.169.254":      false, // cloud metadata
+		"fe80::1":              false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"fd00::1":              false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"::":                   false,
+		"255.255.255.255":      false,
+		"224.0.0.1":            false,
+		"::ffff:127.0.0.1":     false, // IPv4-mapped loopback
+		"64:ff9b::a9fe:a9fe":   false, // NAT64 of 169.254.169.254
+	} {
+		if got := PublicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("PublicAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	CreateSubscription(ctx context.Context, s *Subscription) error
+
+	// List a partner's subscriptions, oldest first
+	ListSubscriptionsByPartner(ctx context.Context, partnerID string) ([]Subscription, error)
+
+	// List every subscription (the fan-out filters them by event)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+
+	// Get by numeric ID, including soft-deleted ones
+	GetSubscriptionByID(ctx context.Context, id uint64) (*Subscription, error)
+
+	// Get the delivery of an event to a subscription; gorm.ErrRecordNotFound when none
+	GetDelivery(ctx context.Context, subscriptionID uint64, eventID string) (*Delivery, error)
+
+	CreateDelivery(ctx context.Context, d *Delivery) error
+
+	// List up to limit pending deliveries due at now, oldest first, locked
+	// (rows another dispatcher holds are skipped)
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]Delivery, error)
+
+	// Persist status, attempts, next_attempt_at, last_* and delivered_at
+	SaveDelivery(ctx context.Context, d *Delivery) error
+
+	// Search deliveries, newest first
+	SearchDeliveries(ctx context.Context, f DeliveryFilter) ([]Delivery, error)
+}
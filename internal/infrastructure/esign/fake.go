@@ -0,0 +1,167 @@
+// Package esign holds e-signature provider adapters.
+package esign
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	domainSig "amartha-backend-test/internal/domain/signature"
+	"amartha-backend-test/pkg/id"
+)
+
+// Sign is the webhook signature every provider adapter here expects in
+// Ax-Signature: hex HMAC-SHA256 of the raw body.
+func Sign(secret, body []byte) string {
+	m := hmac.New(sha256.New, secret)
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Verify compares sig with Sign(secret, body) in constant time.
+func Verify(secret, body []byte, sig string) bool {
+	got, err := hex.DecodeString(strings.TrimSpace(sig))
+	if err != nil {
+		return false
+	}
+	want, _ := hex.DecodeString(Sign(secret, body))
+	return hmac.Equal(got, want)
+}
+
+// webhookBody is the wire format of the fake provider's callbacks.
+type webhookBody struct {
+	EventID    string           `json:"event_id"`
+	TxID       string           `json:"tx_id"`
+	Status     domainSig.Status `json:"status"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+type fakeEnvelope struct {
+	req    domainSig.EnvelopeRequest
+	status domainSig.Status
+}
+
+// Fake is an in-process provider for development and tests: envelopes live
+// in memory and Complete produces the webhook a real vendor would send.
+type Fake struct {
+	name       string
+	secret     []byte
+	signingURL string
+	now        func() time.Time
+
+	mu        sync.Mutex
+	envelopes map[string]*fakeEnvelope
+}
+
+// NewFake registers under name; secret (at least 16 bytes) signs webhooks.
+func NewFake(name string, secret []byte, signingURL string) (*Fake, error) {
+	if name == "" {
+		return nil, errors.New("esign: provider name is required")
+	}
+	if len(secret) < 16 {
+		return nil, errors.New("esign: webhook secret must be at least 16 bytes")
+	}
+	return &Fake{
+		name:       name,
+		secret:     secret,
+		signingURL: strings.TrimRight(signingURL, "/"),
+		now:        time.Now,
+		envelopes:  map[string]*fakeEnvelope{},
+	}, nil
+}
+
+func (f *Fake) Name() string { return f.name }
+
+func (f *Fake) CreateEnvelope(ctx context.Context, req domainSig.EnvelopeRequest) (*domainSig.ProviderEnvelope, error) {
+	if req.DocumentURL == "" {
+		return nil, errors.New("esign: document url is required")
+	}
+	tx := "fake_" + id.NewID32()
+	f.mu.Lock()
+	f.envelopes[tx] = &fakeEnvelope{req: req, status: domainSig.StatusPending}
+	f.mu.Unlock()
+	return &domainSig.ProviderEnvelope{
+		TxID:       tx,
+		Status:     domainSig.StatusPending,
+		SigningURL: f.signingURL + "/" + tx,
+	}, nil
+}
+
+func (f *Fake) FetchStatus(ctx context.Context, txID string) (domainSig.Status, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	env, ok := f.envelopes[txID]
+	if !ok {
+		return "", domainSig.ErrNotFound
+	}
+	return env.status, nil
+}
+
+func (f *Fake) DownloadSigned(ctx context.Context, txID string) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	env, ok := f.envelopes[txID]
+	if !ok {
+		return nil, domainSig.ErrNotFound
+	}
+	if env.status != domainSig.StatusSigned {
+		return nil, fmt.Errorf("esign: envelope %s is %s, not SIGNED", txID, env.status)
+	}
+	doc := fmt.Sprintf("%%PDF-1.4\n%% fake signed copy\n%% tx %s\n%% signer %s\n%% source %s\n%%%%EOF\n",
+		txID, env.req.SignerID, env.req.DocumentURL)
+	return io.NopCloser(strings.NewReader(doc)), nil
+}
+
+func (f *Fake) ParseWebhook(body []byte, sig string) (*domainSig.WebhookEvent, error) {
+	if !Verify(f.secret, body, sig) {
+		return nil, domainSig.ErrBadSignature
+	}
+	var in webhookBody
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&in); err != nil {
+		return nil, fmt.Errorf("%w: %v", domainSig.ErrInvalidEvent, err)
+	}
+	if in.EventID == "" || in.TxID == "" || !in.Status.Valid() || in.OccurredAt.IsZero() {
+		return nil, domainSig.ErrInvalidEvent
+	}
+	return &domainSig.WebhookEvent{
+		EventID:    in.EventID,
+		TxID:       in.TxID,
+		Status:     in.Status,
+		OccurredAt: in.OccurredAt.UTC(),
+	}, nil
+}
+
+// Complete moves the envelope to status (as the signer would) and returns
+// the signed webhook body announcing it.
+func (f *Fake) Complete(txID string, status domainSig.Status) (body []byte, sig string, err error) {
+	f.mu.Lock()
+	env, ok := f.envelopes[txID]
+	if ok {
+		env.status = status
+	}
+	f.mu.Unlock()
+	if !ok {
+		return nil, "", domainSig.ErrNotFound
+	}
+	body, err = json.Marshal(webhookBody{
+		EventID:    "evt_" + id.NewID32(),
+		TxID:       txID,
+		Status:     status,
+		OccurredAt: f.now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return body, Sign(f.secret, body), nil
+}
@@ -0,0 +1,105 @@
+package esign
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	domainSig "amartha-backend-test/internal/domain/signature"
+)
+
+func newFake(t *testing.T) *Fake {
+	t.Helper()
+	f, err := NewFake("fake", []byte("0123456789abcdef"), "http://esign.local/sign/")
+	if err != nil {
+		t.Fatalf("NewFake: %v", err)
+	}
+	return f
+}
+
+func TestNewFake_Validates(t *testing.T) {
+	if _, err := NewFake("fake", []byte("short"), ""); err == nil {
+		t.Fatalf("short secret accepted")
+	}
+	if _, err := NewFake("", []byte("0123456789abcdef"), ""); err == nil {
+		t.Fatalf("empty name accepted")
+	}
+}
+
+func TestFake_Lifecycle(t *testing.T) {
+	f := newFake(t)
+	ctx := context.Background()
+
+	env, err := f.CreateEnvelope(ctx, domainSig.EnvelopeRequest{DocumentURL: "http://docs/a.pdf", SignerID: strings.Repeat("b", 32)})
+	if err != nil {
+		t.Fatalf("CreateEnvelope: %v", err)
+	}
+	if env.Status != domainSig.StatusPending || env.SigningURL != "http://esign.local/sign/"+env.TxID {
+		t.Fatalf("envelope: %+v", env)
+	}
+	if _, err := f.DownloadSigned(ctx, env.TxID); err == nil {
+		t.Fatalf("download before signing should fail")
+	}
+
+	body, sig, err := f.Complete(env.TxID, domainSig.StatusSigned)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if st, err := f.FetchStatus(ctx, env.TxID); err != nil || st != domainSig.StatusSigned {
+		t.Fatalf("FetchStatus = %s, %v", st, err)
+	}
+	ev, err := f.ParseWebhook(body, sig)
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if ev.TxID != env.TxID || ev.Status != domainSig.StatusSigned || ev.EventID == "" || ev.OccurredAt.IsZero() {
+		t.Fatalf("event: %+v", ev)
+	}
+
+	rc, err := f.DownloadSigned(ctx, env.TxID)
+	if err != nil {
+		t.Fatalf("DownloadSigned: %v", err)
+	}
+	doc, _ := io.ReadAll(rc)
+	if !strings.HasPrefix(string(doc), "%PDF-") || !strings.Contains(string(doc), env.TxID) {
+		t.Fatalf("signed doc: %q", doc)
+	}
+
+	if _, err := f.FetchStatus(ctx, "nope"); !errors.Is(err, domainSig.ErrNotFound) {
+		t.Fatalf("unknown tx: %v", err)
+	}
+	if _, _, err := f.Complete("nope", domainSig.StatusSigned); !errors.Is(err, domainSig.ErrNotFound) {
+		t.Fatalf("complete unknown tx: %v", err)
+	}
+}
+
+func TestFake_ParseWebhook_Rejects(t *testing.T) {
+	f := newFake(t)
+	body := []byte(`{"event_id":"e1","tx_id":"t1","status":"SIGNED","occurred_at":"2026-01-02T03:04:05Z"}`)
+
+	if _, err := f.ParseWebhook(body, Sign([]byte("fedcba9876543210"), body)); !errors.Is(err, domainSig.ErrBadSignature) {
+		t.Fatalf("wrong secret: %v", err)
+	}
+	if _, err := f.ParseWebhook(body, "zz"); !errors.Is(err, domainSig.ErrBadSignature) {
+		t.Fatalf("non-hex signature: %v", err)
+	}
+	tampered := []byte(strings.Replace(string(body), "SIGNED", "CANCELLED", 1))
+	if _, err := f.ParseWebhook(tampered, Sign(f.secret, body)); !errors.Is(err, domainSig.ErrBadSignature) {
+		t.Fatalf("tampered body: %v", err)
+	}
+	for _, bad := range []string{
+		`{"event_id":"e1","tx_id":"t1","status":"DONE","occurred_at":"2026-01-02T03:04:05Z"}`,
+		`{"event_id":"","tx_id":"t1","status":"SIGNED","occurred_at":"2026-01-02T03:04:05Z"}`,
+		`{"event_id":"e1","tx_id":"t1","status":"SIGNED"}`,
+		`not json`,
+	} {
+		if _, err := f.ParseWebhook([]byte(bad), Sign(f.secret, []byte(bad))); !errors.Is(err, domainSig.ErrInvalidEvent) {
+			t.Fatalf("%s: want ErrInvalidEvent, got %v", bad, err)
+		}
+	}
+	if ev, err := f.ParseWebhook(body, strings.ToUpper(Sign(f.secret, body))); err != nil || ev.EventID != "e1" {
+		t.Fatalf("upper-case hex: %+v, %v", ev, err)
+	}
+}
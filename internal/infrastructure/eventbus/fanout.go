@@ -0,0 +1,23 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+)
+
+// Fanout publishes every message to each of its publishers. A failure in one
+// does not stop the others; the joined errors make the relay retry the
+// message, so every publisher must tolerate seeing an event id twice.
+type Fanout []domainOutbox.Publisher
+
+func (f Fanout) Publish(ctx context.Context, m domainOutbox.Message) error {
+	var errs []error
+	for _, p := range f {
+		if err := p.Publish(ctx, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
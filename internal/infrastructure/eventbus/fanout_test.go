@@ -0,0 +1,31 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+)
+
+func TestFanout(t *testing.T) {
+	a, b := NewMemory(), NewMemory()
+	f := Fanout{a, b}
+	ctx := context.Background()
+
+	if err := f.Publish(ctx, domainOutbox.Message{EventID: "E-1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	boom := errors.New("webhooks down")
+	a.FailNext(boom)
+	if err := f.Publish(ctx, domainOutbox.Message{EventID: "E-2"}); !errors.Is(err, boom) {
+		t.Fatalf("want %v, got %v", boom, err)
+	}
+	// the failing publisher does not starve the others
+	if len(a.Published()) != 1 || len(b.Published()) != 2 || b.Published()[1].EventID != "E-2" {
+		t.Fatalf("a=%+v b=%+v", a.Published(), b.Published())
+	}
+	if err := (Fanout{}).Publish(ctx, domainOutbox.Message{EventID: "E-3"}); err != nil {
+		t.Fatalf("empty fanout: %v", err)
+	}
+}
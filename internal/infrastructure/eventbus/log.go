@@ -0,0 +1,17 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+)
+
+// Log writes each message to the standard logger; the default sink until a
+// broker is configured.
+type Log struct{}
+
+func (Log) Publish(ctx context.Context, m domainOutbox.Message) error {
+	log.Printf("event %s %s loan=%s payload=%s", m.EventType, m.EventID, m.AggregateID, m.Payload)
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package eventbus holds outbox.Publisher implementations.
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+)
+
+// Memory records published messages in order; for tests and local runs.
+type Memory struct {
+	mu        sync.Mutex
+	published []domainOutbox.Message
+	failures  []error
+}
+
+func NewMemory() *Memory { return &Memory{} }
+
+// FailNext makes the next len(errs) publishes return errs, in order.
+func (m *Memory) FailNext(errs ...error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures = append(m.failures, errs...)
+}
+
+func (m *Memory) Publish(ctx context.Context, msg domainOutbox.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.failures) > 0 {
+		err := m.failures[0]
+		m.failures = m.failures[1:]
+		return err
+	}
+	m.published = append(m.published, msg)
+	return nil
+}
+
+// Published returns a copy of what was published so far.
+func (m *Memory) Published() []domainOutbox.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]domainOutbox.Message(nil), m.published...)
+}
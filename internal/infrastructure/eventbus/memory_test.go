@@ -0,0 +1,38 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+)
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	ctx := context.Background()
+	boom := errors.New("broker down")
+
+	m.FailNext(boom)
+	if err := m.Publish(ctx, domainOutbox.Message{EventID: "E-1"}); !errors.Is(err, boom) {
+		t.Fatalf("first publish: want %v, got %v", boom, err)
+	}
+	for _, id := range []string{"E-1", "E-2"} {
+		if err := m.Publish(ctx, domainOutbox.Message{EventID: id}); err != nil {
+			t.Fatalf("publish %s: %v", id, err)
+		}
+	}
+	got := m.Published()
+	if len(got) != 2 || got[0].EventID != "E-1" || got[1].EventID != "E-2" {
+		t.Fatalf("Published = %+v", got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := m.Publish(cancelled, domainOutbox.Message{EventID: "E-3"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled ctx: want context.Canceled, got %v", err)
+	}
+	if len(m.Published()) != 2 {
+		t.Fatalf("cancelled publish must not be recorded")
+	}
+}
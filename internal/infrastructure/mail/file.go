@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"context"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"amartha-backend-test/internal/domain/notification"
+	"amartha-backend-test/pkg/id"
+)
+
+// File writes each message as an .eml file under a directory instead of
+// sending it; for development. Any mail client opens the files.
+type File struct {
+	dir  string
+	from *netmail.Address
+	now  func() time.Time
+}
+
+var _ notification.Sender = (*File)(nil)
+
+func NewFile(dir, from string) (*File, error) {
+	addr, err := parseAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &File{dir: dir, from: addr, now: time.Now}, nil
+}
+
+// Send writes to a temp file and renames it into place, so a reader
+// watching the directory never sees a partial message.
+func (f *File) Send(ctx context.Context, m notification.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := f.now()
+	msg, _, err := compose(f.from, m, now)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.dir, ".mail-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+	if _, err := tmp.Write(msg); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	name := now.UTC().Format("20060102T150405Z") + "-" + id.NewID32() + ".eml"
+	return os.Rename(tmp.Name(), filepath.Join(f.dir, name))
+}
@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/notification"
+)
+
+func TestFile_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	f, err := NewFile(dir, "no-reply@amartha.test")
+	if err != nil {
+		t.Fatalf("NewFile: %v", err)
+	}
+	f.now = func() time.Time { return time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC) }
+
+	err = f.Send(context.Background(), notification.Message{
+		To:      "investor@example.com",
+		Subject: "Loan funded\r\nBcc: everyone@example.com",
+		Body:    "Hello,\nyour agreement is ready.\n",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 || !strings.HasPrefix(files[0].Name(), "20261016T093000Z-") || !strings.HasSuffix(files[0].Name(), ".eml") {
+		t.Fatalf("files: %v", files)
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	msg, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	// line breaks in the subject cannot inject headers
+	if msg.Header.Get("Bcc") != "" || msg.Header.Get("Subject") != "Loan funded Bcc: everyone@example.com" {
+		t.Fatalf("headers: %v", msg.Header)
+	}
+	if msg.Header.Get("Date") != "Fri, 16 Oct 2026 09:30:00 +0000" || msg.Header.Get("From") != "<no-reply@amartha.test>" {
+		t.Fatalf("headers: %v", msg.Header)
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if string(body) != "Hello,\r\nyour agreement is ready.\r\n" {
+		t.Fatalf("body = %q", body)
+	}
+
+	if err := f.Send(context.Background(), notification.Message{To: "", Subject: "x"}); err == nil {
+		t.Fatalf("missing recipient: want an error")
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Fatalf("a failed send must leave no file: %v", files)
+	}
+}
@@ -0,0 +1,62 @@
+// Package mail holds notification.Sender implementations: SMTP for real
+// delivery and a file sink for development.
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"amartha-backend-test/internal/domain/notification"
+	"amartha-backend-test/pkg/id"
+)
+
+var ErrInvalidAddress = errors.New("invalid email address")
+
+func parseAddress(s string) (*netmail.Address, error) {
+	a, err := netmail.ParseAddress(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrInvalidAddress, s)
+	}
+	return a, nil
+}
+
+// compose renders m as an RFC 5322 message with a quoted-printable UTF-8
+// text body, and returns it with the parsed recipient.
+func compose(from *netmail.Address, m notification.Message, now time.Time) ([]byte, *netmail.Address, error) {
+	to, err := parseAddress(m.To)
+	if err != nil {
+		return nil, nil, err
+	}
+	// a subject is one header line; line breaks would start new headers
+	subject := strings.Join(strings.Fields(m.Subject), " ")
+	domain := from.Address[strings.LastIndexByte(from.Address, '@')+1:]
+
+	var buf bytes.Buffer
+	for _, h := range [][2]string{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", "<" + id.NewID32() + "@" + domain + ">"},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=utf-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	} {
+		buf.WriteString(h[0] + ": " + h[1] + "\r\n")
+	}
+	buf.WriteString("\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(m.Body)); err != nil {
+		return nil, nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), to, nil
+}
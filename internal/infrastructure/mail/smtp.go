@@ -0,0 +1,111 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+
+	"amartha-backend-test/internal/domain/notification"
+)
+
+// defaultSMTPTimeout bounds one whole SMTP conversation when the context
+// has no deadline.
+const defaultSMTPTimeout = 30 * time.Second
+
+type SMTPConfig struct {
+	Addr string // host:port
+	// Username and Password enable AUTH PLAIN, which net/smtp only allows
+	// over TLS or to localhost
+	Username string
+	Password string
+	From     string // e.g. "Amartha <no-reply@amartha.test>"
+	Timeout  time.Duration
+}
+
+// SMTP sends each message in its own SMTP session, upgrading to TLS when
+// the server offers STARTTLS.
+type SMTP struct {
+	addr    string
+	host    string
+	auth    smtp.Auth
+	from    *netmail.Address
+	timeout time.Duration
+	now     func() time.Time
+}
+
+var _ notification.Sender = (*SMTP)(nil)
+
+func NewSMTP(cfg SMTPConfig) (*SMTP, error) {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	from, err := parseAddress(cfg.From)
+	if err != nil {
+		return nil, err
+	}
+	s := &SMTP{addr: cfg.Addr, host: host, from: from, timeout: cfg.Timeout, now: time.Now}
+	if s.timeout <= 0 {
+		s.timeout = defaultSMTPTimeout
+	}
+	if cfg.Username != "" {
+		s.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+	}
+	return s, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, m notification.Message) error {
+	msg, to, err := compose(s.from, m, s.now())
+	if err != nil {
+		return err
+	}
+	conn, err := (&net.Dialer{Timeout: s.timeout}).DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(s.timeout)
+	}
+	_ = conn.SetDeadline(deadline)
+	// net/smtp knows no contexts; closing the connection aborts it
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,198 @@
+package mail
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/notification"
+)
+
+// received is one message accepted by fakeSMTP.
+type received struct {
+	auth string // decoded AUTH PLAIN credentials
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP is a minimal local SMTP server: enough of RFC 5321 for net/smtp,
+// with AUTH PLAIN and recipients it refuses.
+type fakeSMTP struct {
+	ln     net.Listener
+	refuse string // RCPT address answered with 550
+
+	mu   sync.Mutex
+	msgs []received
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) addr() string { return s.ln.Addr().String() }
+
+func (s *fakeSMTP) received() []received {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]received(nil), s.msgs...)
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	var cur received
+	_ = tp.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250-fake\r\n250-8BITMIME\r\n250 AUTH PLAIN")
+		case "AUTH":
+			b, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			cur.auth = string(b)
+			_ = tp.PrintfLine("235 ok")
+		case "MAIL":
+			cur.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			if i := strings.Index(cur.from, ">"); i >= 0 {
+				cur.from = cur.from[:i]
+			}
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			to := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			if to == s.refuse {
+				_ = tp.PrintfLine("550 no such user")
+				continue
+			}
+			cur.to = append(cur.to, to)
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			b, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			cur.data = string(b)
+			s.mu.Lock()
+			s.msgs = append(s.msgs, cur)
+			s.mu.Unlock()
+			cur = received{auth: cur.auth}
+			_ = tp.PrintfLine("250 queued")
+		case "RSET", "NOOP":
+			_ = tp.PrintfLine("250 ok")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSMTP_Send(t *testing.T) {
+	srv := newFakeSMTP(t)
+	s, err := NewSMTP(SMTPConfig{Addr: srv.addr(), Username: "mailer", Password: "pw", From: "Amartha <no-reply@amartha.test>"})
+	if err != nil {
+		t.Fatalf("NewSMTP: %v", err)
+	}
+
+	err = s.Send(context.Background(), notification.Message{
+		To:      "Investor One <investor@example.com>",
+		Subject: "Pinjaman didanai penuh – surat perjanjian",
+		Body:    "Hello,\nyour agreement: https://blobs.test/a.txt?signature=" + strings.Repeat("f", 80) + "\n",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	got := srv.received()
+	if len(got) != 1 {
+		t.Fatalf("received %d messages", len(got))
+	}
+	r := got[0]
+	if r.from != "no-reply@amartha.test" || len(r.to) != 1 || r.to[0] != "investor@example.com" || r.auth != "\x00mailer\x00pw" {
+		t.Fatalf("envelope: %+v", r)
+	}
+
+	msg, err := netmail.ReadMessage(strings.NewReader(r.data))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "Pinjaman didanai penuh – surat perjanjian" || msg.Header.Get("To") != `"Investor One" <investor@example.com>` ||
+		!strings.HasPrefix(msg.Header.Get("Message-ID"), "<") || !strings.HasSuffix(msg.Header.Get("Message-ID"), "@amartha.test>") {
+		t.Fatalf("headers: %v (subject %q)", msg.Header, subject)
+	}
+	body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if !strings.Contains(string(body), "signature="+strings.Repeat("f", 80)) {
+		t.Fatalf("long link must survive quoted-printable soft breaks:\n%s", body)
+	}
+}
+
+func TestSMTP_Send_Errors(t *testing.T) {
+	srv := newFakeSMTP(t)
+	srv.refuse = "gone@example.com"
+	s, err := NewSMTP(SMTPConfig{Addr: srv.addr(), From: "no-reply@amartha.test"})
+	if err != nil {
+		t.Fatalf("NewSMTP: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := s.Send(ctx, notification.Message{To: "gone@example.com", Subject: "x", Body: "x"}); err == nil || !strings.Contains(err.Error(), "550") {
+		t.Fatalf("refused recipient: want a 550 error, got %v", err)
+	}
+	if err := s.Send(ctx, notification.Message{To: "not an address", Subject: "x", Body: "x"}); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("bad recipient: want ErrInvalidAddress, got %v", err)
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := s.Send(cancelled, notification.Message{To: "investor@example.com", Subject: "x", Body: "x"}); err == nil {
+		t.Fatalf("cancelled ctx: want an error")
+	}
+	if len(srv.received()) != 0 {
+		t.Fatalf("nothing should have been accepted: %+v", srv.received())
+	}
+
+	// a server that never answers runs into the timeout
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer ln.Close()
+	slow, _ := NewSMTP(SMTPConfig{Addr: ln.Addr().String(), From: "no-reply@amartha.test", Timeout: 50 * time.Millisecond})
+	if err := slow.Send(ctx, notification.Message{To: "investor@example.com", Subject: "x", Body: "x"}); err == nil {
+		t.Fatalf("silent server: want a timeout")
+	}
+
+	for _, cfg := range []SMTPConfig{{Addr: "no-port", From: "a@b.test"}, {Addr: "localhost:25", From: "nobody"}} {
+		if _, err := NewSMTP(cfg); err == nil {
+			t.Fatalf("NewSMTP(%+v): want an error", cfg)
+		}
+	}
+}
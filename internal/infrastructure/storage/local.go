@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"amartha-backend-test/internal/domain/blob"
+)
+
+// Local stores blobs as files under a root directory. Its signed URLs point
+// at this service (baseURL, e.g. http://localhost:8080/blobs) and carry an
+// HMAC over the key and expiry; OpenSigned checks them before serving.
+type Local struct {
+	root    string
+	baseURL string
+	secret  []byte
+	now     func() time.Time
+}
+
+var _ blob.Store = (*Local)(nil)
+
+func NewLocal(root, baseURL string, secret []byte) (*Local, error) {
+	if len(secret) < 16 {
+		return nil, errors.New("local blob signing key must be at least 16 bytes")
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+	return &Local{root: root, baseURL: strings.TrimSuffix(baseURL, "/"), secret: secret, now: time.Now}, nil
+}
+
+func (l *Local) path(key string) string { return filepath.Join(l.root, filepath.FromSlash(key)) }
+
+// Put writes to a temp file in the target directory and renames it into
+// place, so readers never see a partial blob.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !blob.ValidKey(key) {
+		return blob.ErrInvalidKey
+	}
+	dst := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	n, err := io.Copy(tmp, io.LimitReader(r, size+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("blob %s: read %d bytes, want %d", key, n, size)
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !blob.ValidKey(key) {
+		return nil, blob.ErrInvalidKey
+	}
+	f, err := os.Open(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, blob.ErrNotFound
+	}
+	return f, err
+}
+
+func (l *Local) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if !blob.ValidKey(key) {
+		return "", blob.ErrInvalidKey
+	}
+	if ttl <= 0 {
+		return "", errors.New("signed URL ttl must be positive")
+	}
+	expires := strconv.FormatInt(l.now().Add(ttl).Unix(), 10)
+	segs := strings.Split(key, "/")
+	for i, s := range segs {
+		segs[i] = url.PathEscape(s)
+	}
+	q := url.Values{"expires": {expires}, "signature": {l.sign(key, expires)}}
+	return l.baseURL + "/" + strings.Join(segs, "/") + "?" + q.Encode(), nil
+}
+
+// OpenSigned opens key if expires and signature came from SignedURL and the
+// URL has not expired (ErrBadSignature otherwise).
+func (l *Local) OpenSigned(ctx context.Context, key, expires, signature string) (io.ReadCloser, error) {
+	at, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(l.sign(key, expires))) || l.now().Unix() > at {
+		return nil, blob.ErrBadSignature
+	}
+	return l.Get(ctx, key)
+}
+
+func (l *Local) sign(key, expires string) string {
+	m := hmac.New(sha256.New, l.secret)
+	m.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(m.Sum(nil))
+}
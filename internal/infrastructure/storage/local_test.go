@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/blob"
+)
+
+func newTestLocal(t *testing.T) *Local {
+	t.Helper()
+	l, err := NewLocal(filepath.Join(t.TempDir(), "blobs"), "http://localhost:8080/blobs/", []byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+	return l
+}
+
+func TestLocal_PutGet(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := context.Background()
+
+	if err := l.Put(ctx, "photos/p.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rc, err := l.Get(ctx, "photos/p.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	b, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(b) != "jpeg" {
+		t.Fatalf("Get = %q", b)
+	}
+
+	if _, err := l.Get(ctx, "photos/none.jpg"); !errors.Is(err, blob.ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	if err := l.Put(ctx, "../escape", strings.NewReader("x"), 1, ""); !errors.Is(err, blob.ErrInvalidKey) {
+		t.Fatalf("want ErrInvalidKey, got %v", err)
+	}
+
+	// a short body leaves nothing behind
+	if err := l.Put(ctx, "photos/short.jpg", strings.NewReader("ab"), 3, "image/jpeg"); err == nil {
+		t.Fatalf("short body should fail")
+	}
+	entries, _ := os.ReadDir(filepath.Join(l.root, "photos"))
+	if len(entries) != 1 {
+		t.Fatalf("photos dir has %d entries, want 1", len(entries))
+	}
+}
+
+func TestLocal_SignedURL(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := context.Background()
+	_ = l.Put(ctx, "photos/p.jpg", strings.NewReader("jpeg"), 4, "image/jpeg")
+
+	link, err := l.SignedURL(ctx, "photos/p.jpg", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	u, _ := url.Parse(link)
+	if u.Host != "localhost:8080" || u.Path != "/blobs/photos/p.jpg" {
+		t.Fatalf("unexpected URL %s", link)
+	}
+	exp, sig := u.Query().Get("expires"), u.Query().Get("signature")
+
+	rc, err := l.OpenSigned(ctx, "photos/p.jpg", exp, sig)
+	if err != nil {
+		t.Fatalf("OpenSigned: %v", err)
+	}
+	rc.Close()
+
+	for name, c := range map[string][3]string{
+		"other key":   {"photos/q.jpg", exp, sig},
+		"bumped time": {"photos/p.jpg", exp + "0", sig},
+		"bad sig":     {"photos/p.jpg", exp, strings.Repeat("0", 64)},
+		"no expiry":   {"photos/p.jpg", "", sig},
+	} {
+		if _, err := l.OpenSigned(ctx, c[0], c[1], c[2]); !errors.Is(err, blob.ErrBadSignature) {
+			t.Fatalf("%s: want ErrBadSignature, got %v", name, err)
+		}
+	}
+
+	l.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err := l.OpenSigned(ctx, "photos/p.jpg", exp, sig); !errors.Is(err, blob.ErrBadSignature) {
+		t.Fatalf("expired: want ErrBadSignature, got %v", err)
+	}
+
+	if _, err := NewLocal(t.TempDir(), "", []byte("short")); err == nil {
+		t.Fatalf("short signing key should be rejected")
+	}
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"amartha-backend-test/internal/domain/blob"
+)
+
+// S3Config points at an S3-compatible service (AWS S3, MinIO, ...).
+type S3Config struct {
+	Endpoint  string // scheme://host[:port], e.g. http://minio:9000
+	Region    string // us-east-1 for MinIO
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3 stores blobs as objects in one bucket, addressed path-style
+// (endpoint/bucket/key) so it works against MinIO without DNS tricks.
+type S3 struct {
+	base   *url.URL
+	bucket string
+	signer signer
+	client *http.Client
+	now    func() time.Time
+}
+
+var _ blob.Store = (*S3)(nil)
+
+// maxPresign is the longest validity SigV4 allows for a presigned URL.
+const maxPresign = 7 * 24 * time.Hour
+
+func NewS3(cfg S3Config) (*S3, error) {
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3 bucket and credentials are required")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3{
+		base:   u,
+		bucket: cfg.Bucket,
+		signer: signer{accessKey: cfg.AccessKey, secretKey: cfg.SecretKey, region: region, service: "s3"},
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.base
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	u.RawPath = ""
+	return &u
+}
+
+// Put buffers the body to sign its SHA-256; photos are small enough.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !blob.ValidKey(key) {
+		return blob.ErrInvalidKey
+	}
+	body, err := io.ReadAll(io.LimitReader(r, size+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(body)) != size {
+		return fmt.Errorf("blob %s: read %d bytes, want %d", key, len(body), size)
+	}
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Content-Sha256", hash)
+	s.signer.sign(req, hash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error("put", key, resp)
+	}
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !blob.ValidKey(key) {
+		return nil, blob.ErrInvalidKey
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	s.signer.sign(req, emptyPayloadHash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, blob.ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error("get", key, resp)
+	}
+}
+
+// SignedURL presigns a GET; the service itself enforces the expiry.
+func (s *S3) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if !blob.ValidKey(key) {
+		return "", blob.ErrInvalidKey
+	}
+	if ttl <= 0 || ttl > maxPresign {
+		return "", fmt.Errorf("signed URL ttl must be in (0, %s]", maxPresign)
+	}
+	return s.signer.presign(http.MethodGet, s.objectURL(key), s.now(), ttl).String(), nil
+}
+
+func s3Error(op, key string, resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: %s: %s", op, key, strconv.Itoa(resp.StatusCode), strings.TrimSpace(string(msg)))
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigAlgorithm    = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// hex SHA-256 of an empty body
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// signer implements AWS Signature Version 4 for S3-compatible services.
+type signer struct {
+	accessKey, secretKey string
+	region, service      string
+}
+
+// sign adds x-amz-date and Authorization to req. Host, Content-Type, Range
+// and every x-amz-* header are signed; req must already carry
+// x-amz-content-sha256 when the service requires it.
+func (s signer) sign(req *http.Request, payloadHash string, at time.Time) {
+	at = at.UTC()
+	req.Header.Set("X-Amz-Date", at.Format(amzDateFormat))
+
+	names, canonical := canonicalHeaders(req)
+	scope := s.scope(at)
+	creq := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonical,
+		names,
+		payloadHash,
+	}, "\n")
+	req.Header.Set("Authorization", sigAlgorithm+" Credential="+s.accessKey+"/"+scope+
+		", SignedHeaders="+names+", Signature="+s.signature(at, scope, creq))
+}
+
+// presign returns u with query-string authentication valid for expires.
+// Only the host header is signed, so any client can follow the URL.
+func (s signer) presign(method string, u *url.URL, at time.Time, expires time.Duration) *url.URL {
+	at = at.UTC()
+	scope := s.scope(at)
+	out := *u
+	q := out.Query()
+	q.Set("X-Amz-Algorithm", sigAlgorithm)
+	q.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	q.Set("X-Amz-Date", at.Format(amzDateFormat))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	creq := strings.Join([]string{
+		method,
+		canonicalURI(&out),
+		canonicalQuery(q),
+		"host:" + out.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	q.Set("X-Amz-Signature", s.signature(at, scope, creq))
+	out.RawQuery = canonicalQuery(q)
+	return &out
+}
+
+func (s signer) scope(at time.Time) string {
+	return at.Format("20060102") + "/" + s.region + "/" + s.service + "/aws4_request"
+}
+
+func (s signer) signature(at time.Time, scope, canonicalRequest string) string {
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	toSign := sigAlgorithm + "\n" + at.Format(amzDateFormat) + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), at.Format("20060102"))
+	for _, part := range []string{s.region, s.service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, toSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+// canonicalHeaders returns the signed header names and their canonical block.
+func canonicalHeaders(req *http.Request) (names, block string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	vals := map[string]string{"host": host}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if lk == "content-type" || lk == "range" || strings.HasPrefix(lk, "x-amz-") {
+			vals[lk] = strings.Join(v, ",")
+		}
+	}
+	keys := make([]string, 0, len(vals))
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k + ":" + strings.Join(strings.Fields(vals[k]), " ") + "\n")
+	}
+	return strings.Join(keys, ";"), b.String()
+}
+
+// canonicalURI escapes each path segment once, keeping the slashes.
+func canonicalURI(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+	parts := strings.Split(u.Path, "/")
+	for i, p := range parts {
+		parts[i] = awsEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		vs := append([]string(nil), q[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			pairs = append(pairs, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes everything except the RFC 3986 unreserved set.
+func awsEscape(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&15])
+	}
+	return b.String()
+}
@@ -0,0 +1,75 @@
+// Package webhookhttp delivers outbound webhooks over HTTP.
+package webhookhttp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	domainWebhook "amartha-backend-test/internal/domain/webhook"
+)
+
+// Client POSTs webhook requests as JSON. Redirects are not followed: a
+// partner endpoint that moved must be re-registered.
+type Client struct{ http *http.Client }
+
+// New builds a client that refuses to connect to non-public addresses,
+// checked on the resolved address at dial time so that a host re-pointed
+// after subscribing (DNS rebinding) is caught too. allowPrivate lifts that
+// for local development. No proxy is used: it would dial on our behalf.
+func New(timeout time.Duration, allowPrivate bool) *Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = publicOnly
+	}
+	return &Client{http: &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !domainWebhook.PublicAddr(ap.Addr()) {
+		return fmt.Errorf("%w: %s", domainWebhook.ErrPrivateHost, ap.Addr())
+	}
+	return nil
+}
+
+func (c *Client) Post(ctx context.Context, r domainWebhook.Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "amartha-webhooks/1")
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	// drain a little so the connection can be reused; the body is not used
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	return res.StatusCode, nil
+}
@@ -0,0 +1,69 @@
+package webhookhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domainWebhook "amartha-backend-test/internal/domain/webhook"
+)
+
+func TestClient_Post(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/hook", http.StatusFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer srv.Close()
+	c := New(100*time.Millisecond, true)
+
+	status, err := c.Post(context.Background(), domainWebhook.Request{
+		URL:     srv.URL + "/hook",
+		Headers: map[string]string{domainWebhook.SignatureHeader: "abc", domainWebhook.TimestampHeader: "1759300000"},
+		Body:    []byte(`{"type":"LoanApproved"}`),
+	})
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("Post = %d, %v", status, err)
+	}
+	if got.Method != http.MethodPost || got.Header.Get("Content-Type") != "application/json" ||
+		got.Header.Get(domainWebhook.SignatureHeader) != "abc" || got.Header.Get(domainWebhook.TimestampHeader) != "1759300000" ||
+		string(body) != `{"type":"LoanApproved"}` {
+		t.Fatalf("request: %s %v %s", got.Method, got.Header, body)
+	}
+
+	// redirects are reported, not followed
+	if status, err := c.Post(context.Background(), domainWebhook.Request{URL: srv.URL + "/moved"}); err != nil || status != http.StatusFound {
+		t.Fatalf("redirect: %d, %v", status, err)
+	}
+	if _, err := c.Post(context.Background(), domainWebhook.Request{URL: srv.URL + "/slow"}); err == nil {
+		t.Fatalf("timeout: want error")
+	}
+	if _, err := c.Post(context.Background(), domainWebhook.Request{URL: "://bad"}); err == nil {
+		t.Fatalf("bad url: want error")
+	}
+}
+
+func TestClient_Post_RefusesPrivateAddresses(t *testing.T) {
+	hit := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hit = true }))
+	defer srv.Close()
+
+	// the server is on loopback, as a rebound partner host would be
+	_, err := New(time.Second, false).Post(context.Background(), domainWebhook.Request{URL: srv.URL + "/hook"})
+	if !errors.Is(err, domainWebhook.ErrPrivateHost) || hit {
+		t.Fatalf("want ErrPrivateHost without a request, got %v (hit=%v)", err, hit)
+	}
+}
@@ -2,15 +2,19 @@ package approvalmock
 
 import (
 	domain "amartha-backend-test/internal/domain/approval"
+	"amartha-backend-test/pkg/phash"
 	"context"
 )
 
 // Repo is a function-backed mock that satisfies domain.Repository.
 // Only methods you need are included; add more as tests require.
 type Repo struct {
-	CreateFn          func(ctx context.Context, a *domain.Approval) error
-	GetByLoanIDFn     func(ctx context.Context, loanNumericID uint64) (*domain.Approval, error)
-	GetByApprovalIDFn func(ctx context.Context, approvalID string) (*domain.Approval, error)
+	CreateFn             func(ctx context.Context, a *domain.Approval) error
+	GetByLoanIDFn        func(ctx context.Context, loanNumericID uint64) (*domain.Approval, error)
+	ListByLoanIDsFn      func(ctx context.Context, loanNumericIDs []uint64) ([]domain.Approval, error)
+	GetByApprovalIDFn    func(ctx context.Context, approvalID string) (*domain.Approval, error)
+	SoftDeleteByLoanIDFn func(ctx context.Context, loanNumericID uint64, by string) error
+	FindNearPhotoFn      func(ctx context.Context, h phash.Hash, maxDistance int, exceptLoanID uint64) (*domain.Approval, error)
 }
 
 func (m *Repo) Create(ctx context.Context, l *domain.Approval) error {
@@ -27,9 +31,30 @@ func (m *Repo) GetByLoanID(ctx context.Context, loanNumericID uint64) (*domain.A
 	return nil, context.Canceled
 }
 
+func (m *Repo) ListByLoanIDs(ctx context.Context, loanNumericIDs []uint64) ([]domain.Approval, error) {
+	if m.ListByLoanIDsFn != nil {
+		return m.ListByLoanIDsFn(ctx, loanNumericIDs)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) SoftDeleteByLoanID(ctx context.Context, loanNumericID uint64, by string) error {
+	if m.SoftDeleteByLoanIDFn != nil {
+		return m.SoftDeleteByLoanIDFn(ctx, loanNumericID, by)
+	}
+	return nil
+}
+
 func (m *Repo) GetByApprovalID(ctx context.Context, approvalID string) (*domain.Approval, error) {
 	if m.GetByApprovalIDFn != nil {
 		return m.GetByApprovalIDFn(ctx, approvalID)
 	}
 	return nil, context.Canceled
 }
+
+func (m *Repo) FindNearPhoto(ctx context.Context, h phash.Hash, maxDistance int, exceptLoanID uint64) (*domain.Approval, error) {
+	if m.FindNearPhotoFn != nil {
+		return m.FindNearPhotoFn(ctx, h, maxDistance, exceptLoanID)
+	}
+	return nil, context.Canceled
+}
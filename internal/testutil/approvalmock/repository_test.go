@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	domain "amartha-backend-test/internal/domain/approval"
+	"amartha-backend-test/pkg/phash"
 )
 
 func TestRepo_Create(t *testing.T) {
@@ -81,6 +82,29 @@ func TestRepo_GetByLoanID(t *testing.T) {
 	}
 }
 
+func TestRepo_ListByLoanIDs(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		ListByLoanIDsFn: func(gotCtx context.Context, ids []uint64) ([]domain.Approval, error) {
+			if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+				t.Fatalf("ids mismatch: %v", ids)
+			}
+			return []domain.Approval{{ApprovalID: "APR-1", LoanID: 1}}, nil
+		},
+	}
+	got, err := m.ListByLoanIDs(ctx, []uint64{1, 2})
+	if err != nil || len(got) != 1 || got[0].ApprovalID != "APR-1" {
+		t.Fatalf("ListByLoanIDs: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.ListByLoanIDs(ctx, []uint64{1}); err != context.Canceled {
+		t.Fatalf("ListByLoanIDs default: want context.Canceled, got %v", err)
+	}
+}
+
 func TestRepo_GetByApprovalID(t *testing.T) {
 	ctx := context.Background()
 	want := &domain.Approval{ApprovalID: "APR-3", LoanID: 789}
@@ -120,3 +144,48 @@ func TestRepo_GetByApprovalID(t *testing.T) {
 		t.Fatalf("GetByApprovalID default: want nil, got %+v", got)
 	}
 }
+
+func TestRepo_SoftDeleteByLoanID(t *testing.T) {
+	ctx := context.Background()
+
+	called := false
+	m := &Repo{
+		SoftDeleteByLoanIDFn: func(gotCtx context.Context, loanNumericID uint64, by string) error {
+			called = loanNumericID == 42 && by == "EMP-1"
+			return nil
+		},
+	}
+	if err := m.SoftDeleteByLoanID(ctx, 42, "EMP-1"); err != nil || !called {
+		t.Fatalf("SoftDeleteByLoanID: called=%v err=%v", called, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.SoftDeleteByLoanID(ctx, 42, "EMP-1"); err != nil {
+		t.Fatalf("SoftDeleteByLoanID default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_FindNearPhoto(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Approval{ApprovalID: "APR-4"}
+
+	// Uses provided func
+	m := &Repo{
+		FindNearPhotoFn: func(gotCtx context.Context, h phash.Hash, maxDistance int, exceptLoanID uint64) (*domain.Approval, error) {
+			if h != 0x5a || maxDistance != 6 || exceptLoanID != 9 {
+				t.Fatalf("arg mismatch: %x %d %d", h, maxDistance, exceptLoanID)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.FindNearPhoto(ctx, 0x5a, 6, 9); err != nil || got != want {
+		t.Fatalf("FindNearPhoto: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.FindNearPhoto(ctx, 0x5a, 6, 9); err != context.Canceled || got != nil {
+		t.Fatalf("FindNearPhoto default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,39 @@
+package blobmock
+
+import (
+	"context"
+	"io"
+	"time"
+
+	domain "amartha-backend-test/internal/domain/blob"
+)
+
+var _ domain.Store = (*Store)(nil)
+
+// Store is a function-backed mock that satisfies domain.Store.
+type Store struct {
+	PutFn       func(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	GetFn       func(ctx context.Context, key string) (io.ReadCloser, error)
+	SignedURLFn func(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+func (m *Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if m.PutFn != nil {
+		return m.PutFn(ctx, key, r, size, contentType)
+	}
+	return nil
+}
+
+func (m *Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if m.GetFn != nil {
+		return m.GetFn(ctx, key)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Store) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if m.SignedURLFn != nil {
+		return m.SignedURLFn(ctx, key, ttl)
+	}
+	return "", context.Canceled
+}
@@ -0,0 +1,78 @@
+package blobmock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_Put(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Store{
+		PutFn: func(gotCtx context.Context, key string, r io.Reader, size int64, ct string) error {
+			if key != "k" || size != 1 || ct != "image/png" {
+				t.Fatalf("arg mismatch: %s %d %s", key, size, ct)
+			}
+			return wantErr
+		},
+	}
+	if err := m.Put(ctx, "k", strings.NewReader("x"), 1, "image/png"); !errors.Is(err, wantErr) {
+		t.Fatalf("Put: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Store{}
+	if err := m.Put(ctx, "k", strings.NewReader("x"), 1, "image/png"); err != nil {
+		t.Fatalf("Put default: want nil, got %v", err)
+	}
+}
+
+func TestStore_Get(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Store{
+		GetFn: func(gotCtx context.Context, key string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(key)), nil
+		},
+	}
+	rc, err := m.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if b, _ := io.ReadAll(rc); string(b) != "k" {
+		t.Fatalf("Get = %q", b)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Store{}
+	if rc, err := m.Get(ctx, "k"); err != context.Canceled || rc != nil {
+		t.Fatalf("Get default: want nil, context.Canceled; got %v, %v", rc, err)
+	}
+}
+
+func TestStore_SignedURL(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Store{
+		SignedURLFn: func(gotCtx context.Context, key string, ttl time.Duration) (string, error) {
+			return "https://blobs/" + key + "?ttl=" + ttl.String(), nil
+		},
+	}
+	if got, err := m.SignedURL(ctx, "k", time.Minute); err != nil || got != "https://blobs/k?ttl=1m0s" {
+		t.Fatalf("SignedURL: got %q, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Store{}
+	if got, err := m.SignedURL(ctx, "k", time.Minute); err != context.Canceled || got != "" {
+		t.Fatalf("SignedURL default: want \"\", context.Canceled; got %q, %v", got, err)
+	}
+}
@@ -0,0 +1,43 @@
+package disbursementmock
+
+import (
+	domain "amartha-backend-test/internal/domain/disbursement"
+	"context"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+// Only methods you need are included; add more as tests require.
+type Repo struct {
+	CreateFn              func(ctx context.Context, d *domain.Disbursement) error
+	GetByLoanIDFn         func(ctx context.Context, loanNumericID uint64) (*domain.Disbursement, error)
+	ListByLoanIDsFn       func(ctx context.Context, loanNumericIDs []uint64) ([]domain.Disbursement, error)
+	GetByDisbursementIDFn func(ctx context.Context, disbursementID string) (*domain.Disbursement, error)
+}
+
+func (m *Repo) Create(ctx context.Context, d *domain.Disbursement) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, d)
+	}
+	return nil
+}
+
+func (m *Repo) GetByLoanID(ctx context.Context, loanNumericID uint64) (*domain.Disbursement, error) {
+	if m.GetByLoanIDFn != nil {
+		return m.GetByLoanIDFn(ctx, loanNumericID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) ListByLoanIDs(ctx context.Context, loanNumericIDs []uint64) ([]domain.Disbursement, error) {
+	if m.ListByLoanIDsFn != nil {
+		return m.ListByLoanIDsFn(ctx, loanNumericIDs)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) GetByDisbursementID(ctx context.Context, disbursementID string) (*domain.Disbursement, error) {
+	if m.GetByDisbursementIDFn != nil {
+		return m.GetByDisbursementIDFn(ctx, disbursementID)
+	}
+	return nil, context.Canceled
+}
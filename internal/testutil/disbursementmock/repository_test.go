@@ -0,0 +1,105 @@
+package disbursementmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "amartha-backend-test/internal/domain/disbursement"
+)
+
+func TestRepo_Create(t *testing.T) {
+	ctx := context.Background()
+	d := &domain.Disbursement{DisbursementID: "DSB-1", LoanID: 1}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateFn: func(gotCtx context.Context, got *domain.Disbursement) error {
+			if got != d {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Create(ctx, d); !errors.Is(err, wantErr) {
+		t.Fatalf("Create: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.Create(ctx, d); err != nil {
+		t.Fatalf("Create default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_GetByLoanID(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Disbursement{DisbursementID: "DSB-2", LoanID: 456}
+
+	// Uses provided func
+	m := &Repo{
+		GetByLoanIDFn: func(gotCtx context.Context, id uint64) (*domain.Disbursement, error) {
+			if id != 456 {
+				t.Fatalf("loanNumericID mismatch: got %d", id)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetByLoanID(ctx, 456); err != nil || got != want {
+		t.Fatalf("GetByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.GetByLoanID(ctx, 456); err != context.Canceled || got != nil {
+		t.Fatalf("GetByLoanID default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_ListByLoanIDs(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		ListByLoanIDsFn: func(gotCtx context.Context, ids []uint64) ([]domain.Disbursement, error) {
+			if len(ids) != 1 || ids[0] != 4 {
+				t.Fatalf("ids mismatch: %v", ids)
+			}
+			return []domain.Disbursement{{DisbursementID: "DSB-4", LoanID: 4}}, nil
+		},
+	}
+	got, err := m.ListByLoanIDs(ctx, []uint64{4})
+	if err != nil || len(got) != 1 || got[0].DisbursementID != "DSB-4" {
+		t.Fatalf("ListByLoanIDs: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.ListByLoanIDs(ctx, []uint64{4}); err != context.Canceled {
+		t.Fatalf("ListByLoanIDs default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_GetByDisbursementID(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Disbursement{DisbursementID: "DSB-3"}
+
+	// Uses provided func
+	m := &Repo{
+		GetByDisbursementIDFn: func(gotCtx context.Context, id string) (*domain.Disbursement, error) {
+			if id != "DSB-3" {
+				t.Fatalf("disbursementID mismatch: got %s", id)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetByDisbursementID(ctx, "DSB-3"); err != nil || got != want {
+		t.Fatalf("GetByDisbursementID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.GetByDisbursementID(ctx, "DSB-3"); err != context.Canceled || got != nil {
+		t.Fatalf("GetByDisbursementID default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,51 @@
+package investmentmock
+
+import (
+	domain "amartha-backend-test/internal/domain/investment"
+	"context"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+// Only methods you need are included; add more as tests require.
+type Repo struct {
+	CreateFn               func(ctx context.Context, i *domain.Investment) error
+	ListByLoanIDFn         func(ctx context.Context, loanNumericID uint64) ([]domain.Investment, error)
+	ListByInvestorIDFn     func(ctx context.Context, investorID string) ([]domain.Investment, error)
+	FundingByLoanIDsFn     func(ctx context.Context, loanNumericIDs []uint64) ([]domain.Funding, error)
+	UpdateStatusByLoanIDFn func(ctx context.Context, loanNumericID uint64, from, to domain.Status) error
+}
+
+func (m *Repo) Create(ctx context.Context, i *domain.Investment) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, i)
+	}
+	return nil
+}
+
+func (m *Repo) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]domain.Investment, error) {
+	if m.ListByLoanIDFn != nil {
+		return m.ListByLoanIDFn(ctx, loanNumericID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) ListByInvestorID(ctx context.Context, investorID string) ([]domain.Investment, error) {
+	if m.ListByInvestorIDFn != nil {
+		return m.ListByInvestorIDFn(ctx, investorID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) FundingByLoanIDs(ctx context.Context, loanNumericIDs []uint64) ([]domain.Funding, error) {
+	if m.FundingByLoanIDsFn != nil {
+		return m.FundingByLoanIDsFn(ctx, loanNumericIDs)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) UpdateStatusByLoanID(ctx context.Context, loanNumericID uint64, from, to domain.Status) error {
+	if m.UpdateStatusByLoanIDFn != nil {
+		return m.UpdateStatusByLoanIDFn(ctx, loanNumericID, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package investmentmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "amartha-backend-test/internal/domain/investment"
+)
+
+func TestRepo_Create(t *testing.T) {
+	ctx := context.Background()
+	i := &domain.Investment{InvestmentID: "INV-1", LoanID: 1}
+
+	// Uses provided func
+	called := false
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateFn: func(gotCtx context.Context, got *domain.Investment) error {
+			called = true
+			if gotCtx != ctx {
+				t.Fatalf("ctx mismatch")
+			}
+			if got != i {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Create(ctx, i); !errors.Is(err, wantErr) {
+		t.Fatalf("Create: want %v, got %v", wantErr, err)
+	}
+	if !called {
+		t.Fatalf("CreateFn not called")
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.Create(ctx, i); err != nil {
+		t.Fatalf("Create default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_ListByLoanID(t *testing.T) {
+	ctx := context.Background()
+	want := []domain.Investment{{InvestmentID: "INV-2", LoanID: 5}}
+
+	// Uses provided func
+	m := &Repo{
+		ListByLoanIDFn: func(gotCtx context.Context, id uint64) ([]domain.Investment, error) {
+			if id != 5 {
+				t.Fatalf("loanNumericID mismatch: got %d", id)
+			}
+			return want, nil
+		},
+	}
+	got, err := m.ListByLoanID(ctx, 5)
+	if err != nil || len(got) != 1 || got[0].InvestmentID != "INV-2" {
+		t.Fatalf("ListByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.ListByLoanID(ctx, 5); err != context.Canceled {
+		t.Fatalf("ListByLoanID default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_ListByInvestorID(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		ListByInvestorIDFn: func(gotCtx context.Context, investorID string) ([]domain.Investment, error) {
+			if investorID != "INVESTOR-1" {
+				t.Fatalf("investorID mismatch: got %s", investorID)
+			}
+			return []domain.Investment{{InvestmentID: "INV-3", InvestorID: investorID}}, nil
+		},
+	}
+	got, err := m.ListByInvestorID(ctx, "INVESTOR-1")
+	if err != nil || len(got) != 1 || got[0].InvestmentID != "INV-3" {
+		t.Fatalf("ListByInvestorID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.ListByInvestorID(ctx, "INVESTOR-1"); err != context.Canceled {
+		t.Fatalf("ListByInvestorID default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_FundingByLoanIDs(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		FundingByLoanIDsFn: func(gotCtx context.Context, ids []uint64) ([]domain.Funding, error) {
+			if len(ids) != 1 || ids[0] != 5 {
+				t.Fatalf("ids mismatch: %v", ids)
+			}
+			return []domain.Funding{{LoanID: 5, Investments: 2}}, nil
+		},
+	}
+	got, err := m.FundingByLoanIDs(ctx, []uint64{5})
+	if err != nil || len(got) != 1 || got[0].Investments != 2 {
+		t.Fatalf("FundingByLoanIDs: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.FundingByLoanIDs(ctx, []uint64{5}); err != context.Canceled {
+		t.Fatalf("FundingByLoanIDs default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_UpdateStatusByLoanID(t *testing.T) {
+	ctx := context.Background()
+
+	wantErr := errors.New("boom")
+	m := &Repo{
+		UpdateStatusByLoanIDFn: func(gotCtx context.Context, id uint64, from, to domain.Status) error {
+			if id != 5 || from != domain.StatusHeld || to != domain.StatusConverted {
+				t.Fatalf("arg mismatch: %d %s→%s", id, from, to)
+			}
+			return wantErr
+		},
+	}
+	if err := m.UpdateStatusByLoanID(ctx, 5, domain.StatusHeld, domain.StatusConverted); !errors.Is(err, wantErr) {
+		t.Fatalf("UpdateStatusByLoanID: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.UpdateStatusByLoanID(ctx, 5, domain.StatusHeld, domain.StatusConverted); err != nil {
+		t.Fatalf("UpdateStatusByLoanID default: want nil, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package ledgermock
+
+import (
+	domain "amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/pkg/money"
+	"context"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+// Post validates like the real adapter, so unbalanced entries still fail in tests.
+type Repo struct {
+	PostFn     func(ctx context.Context, e *domain.Entry) error
+	GetByRefFn func(ctx context.Context, kind domain.EntryKind, refID string) (*domain.Entry, error)
+	LockFn     func(ctx context.Context, a domain.Account) error
+	BalanceFn  func(ctx context.Context, a domain.Account) (money.Decimal, error)
+}
+
+func (m *Repo) Post(ctx context.Context, e *domain.Entry) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if m.PostFn != nil {
+		return m.PostFn(ctx, e)
+	}
+	return nil
+}
+
+func (m *Repo) GetByRef(ctx context.Context, kind domain.EntryKind, refID string) (*domain.Entry, error) {
+	if m.GetByRefFn != nil {
+		return m.GetByRefFn(ctx, kind, refID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) Lock(ctx context.Context, a domain.Account) error {
+	if m.LockFn != nil {
+		return m.LockFn(ctx, a)
+	}
+	return nil
+}
+
+func (m *Repo) Balance(ctx context.Context, a domain.Account) (money.Decimal, error) {
+	if m.BalanceFn != nil {
+		return m.BalanceFn(ctx, a)
+	}
+	return money.Zero, context.Canceled
+}
@@ -0,0 +1,72 @@
+package ledgermock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/pkg/money"
+)
+
+func TestRepo(t *testing.T) {
+	ctx := context.Background()
+	at := time.Now()
+	balanced := func() *domain.Entry {
+		return domain.NewEntry(domain.KindInvestment, "x", nil, at).
+			Debit(domain.Cash, money.NewFromInt(1)).
+			Credit(domain.Escrow("ln"), money.NewFromInt(1))
+	}
+
+	wantErr := errors.New("boom")
+	m := &Repo{
+		PostFn: func(gotCtx context.Context, e *domain.Entry) error {
+			if e.RefID != "x" {
+				t.Fatalf("arg mismatch: %+v", e)
+			}
+			return wantErr
+		},
+		GetByRefFn: func(gotCtx context.Context, kind domain.EntryKind, refID string) (*domain.Entry, error) {
+			return &domain.Entry{Kind: kind, RefID: refID}, nil
+		},
+		LockFn: func(gotCtx context.Context, a domain.Account) error { return wantErr },
+		BalanceFn: func(gotCtx context.Context, a domain.Account) (money.Decimal, error) {
+			if a != domain.Escrow("ln") {
+				t.Fatalf("arg mismatch: %+v", a)
+			}
+			return money.NewFromInt(5), nil
+		},
+	}
+	if err := m.Post(ctx, balanced()); !errors.Is(err, wantErr) {
+		t.Fatalf("Post: want %v, got %v", wantErr, err)
+	}
+	if got, err := m.Balance(ctx, domain.Escrow("ln")); err != nil || got.String() != "5" {
+		t.Fatalf("Balance: got %v, err %v", got, err)
+	}
+	if got, err := m.GetByRef(ctx, domain.KindTopUp, "g"); err != nil || got.RefID != "g" {
+		t.Fatalf("GetByRef: got %+v, err %v", got, err)
+	}
+	if err := m.Lock(ctx, domain.Cash); !errors.Is(err, wantErr) {
+		t.Fatalf("Lock: want %v, got %v", wantErr, err)
+	}
+
+	// Defaults: Post → validate then nil, Lock → nil, GetByRef/Balance → context.Canceled
+	m = &Repo{}
+	if err := m.Post(ctx, balanced()); err != nil {
+		t.Fatalf("Post default: want nil, got %v", err)
+	}
+	unbalanced := balanced().Credit(domain.PlatformFee, money.NewFromInt(1))
+	if err := m.Post(ctx, unbalanced); !errors.Is(err, domain.ErrUnbalanced) {
+		t.Fatalf("Post unbalanced: want ErrUnbalanced, got %v", err)
+	}
+	if err := m.Lock(ctx, domain.Cash); err != nil {
+		t.Fatalf("Lock default: want nil, got %v", err)
+	}
+	if _, err := m.GetByRef(ctx, domain.KindTopUp, "g"); err != context.Canceled {
+		t.Fatalf("GetByRef default: want context.Canceled, got %v", err)
+	}
+	if _, err := m.Balance(ctx, domain.Cash); err != context.Canceled {
+		t.Fatalf("Balance default: want context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package loanmock
+
+import (
+	domain "amartha-backend-test/internal/domain/loan"
+	"context"
+)
+
+// HistoryRepo is a function-backed mock that satisfies domain.HistoryRepository.
+type HistoryRepo struct {
+	AppendFn       func(ctx context.Context, t *domain.StateTransition) error
+	ListByLoanIDFn func(ctx context.Context, loanNumericID uint64) ([]domain.StateTransition, error)
+}
+
+func (m *HistoryRepo) Append(ctx context.Context, t *domain.StateTransition) error {
+	if m.AppendFn != nil {
+		return m.AppendFn(ctx, t)
+	}
+	return nil
+}
+
+func (m *HistoryRepo) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]domain.StateTransition, error) {
+	if m.ListByLoanIDFn != nil {
+		return m.ListByLoanIDFn(ctx, loanNumericID)
+	}
+	return nil, context.Canceled
+}
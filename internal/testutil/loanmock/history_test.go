@@ -0,0 +1,58 @@
+package loanmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "amartha-backend-test/internal/domain/loan"
+)
+
+func TestHistoryRepo_Append(t *testing.T) {
+	ctx := context.Background()
+	tr := &domain.StateTransition{LoanID: 1, ToState: domain.StateApproved}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &HistoryRepo{
+		AppendFn: func(gotCtx context.Context, got *domain.StateTransition) error {
+			if got != tr {
+				t.Fatalf("Append arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Append(ctx, tr); !errors.Is(err, wantErr) {
+		t.Fatalf("Append: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &HistoryRepo{}
+	if err := m.Append(ctx, tr); err != nil {
+		t.Fatalf("Append default: want nil, got %v", err)
+	}
+}
+
+func TestHistoryRepo_ListByLoanID(t *testing.T) {
+	ctx := context.Background()
+	want := []domain.StateTransition{{LoanID: 3}}
+
+	// Uses provided func
+	m := &HistoryRepo{
+		ListByLoanIDFn: func(gotCtx context.Context, id uint64) ([]domain.StateTransition, error) {
+			if id != 3 {
+				t.Fatalf("ListByLoanID id mismatch: got %d", id)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.ListByLoanID(ctx, 3); err != nil || len(got) != 1 {
+		t.Fatalf("ListByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &HistoryRepo{}
+	if _, err := m.ListByLoanID(ctx, 3); err != context.Canceled {
+		t.Fatalf("ListByLoanID default: want context.Canceled, got %v", err)
+	}
+}
@@ -3,16 +3,23 @@ package loanmock
 import (
 	domain "amartha-backend-test/internal/domain/loan"
 	"context"
+	"time"
 )
 
 // Repo is a function-backed mock that satisfies domain.Repository.
 // Only methods you need are included; add more as tests require.
 type Repo struct {
-	CreateFn                     func(ctx context.Context, l *domain.Loan) error
-	GetByLoanIDFn                func(ctx context.Context, loanID string) (*domain.Loan, error)
-	SaveFn                       func(ctx context.Context, l *domain.Loan) error
-	GetPendingLoanByBorrowerIDFn func(ctx context.Context, borrowerID string) (*domain.Loan, error)
-	GetByLoanIDForUpdateFn       func(ctx context.Context, loanID string) (*domain.Loan, error)
+	CreateFn                            func(ctx context.Context, l *domain.Loan) error
+	GetByLoanIDFn                       func(ctx context.Context, loanID string) (*domain.Loan, error)
+	SaveFn                              func(ctx context.Context, l *domain.Loan) error
+	GetPendingLoanByBorrowerIDFn        func(ctx context.Context, borrowerID string) (*domain.Loan, error)
+	GetLatestRejectedLoanByBorrowerIDFn func(ctx context.Context, borrowerID string) (*domain.Loan, error)
+	GetByLoanIDForUpdateFn              func(ctx context.Context, loanID string) (*domain.Loan, error)
+	ListFn                              func(ctx context.Context, f domain.ListFilter) ([]domain.Loan, error)
+	ListByIDsFn                         func(ctx context.Context, ids []uint64) ([]domain.Loan, error)
+	GetDetailByLoanIDFn                 func(ctx context.Context, loanID string) (*domain.Detail, error)
+	SearchFn                            func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error)
+	UpdateDelinquencyFn                 func(ctx context.Context, id uint64, dpd uint16, bucket domain.DPDBucket, asOf time.Time) error
 }
 
 func (m *Repo) Create(ctx context.Context, l *domain.Loan) error {
@@ -41,9 +48,51 @@ func (m *Repo) GetPendingLoanByBorrowerID(ctx context.Context, borrowerID string
 	return nil, context.Canceled
 }
 
+func (m *Repo) GetLatestRejectedLoanByBorrowerID(ctx context.Context, borrowerID string) (*domain.Loan, error) {
+	if m.GetLatestRejectedLoanByBorrowerIDFn != nil {
+		return m.GetLatestRejectedLoanByBorrowerIDFn(ctx, borrowerID)
+	}
+	return nil, context.Canceled
+}
+
 func (m *Repo) GetByLoanIDForUpdate(ctx context.Context, borrowerID string) (*domain.Loan, error) {
 	if m.GetByLoanIDForUpdateFn != nil {
 		return m.GetByLoanIDForUpdateFn(ctx, borrowerID)
 	}
 	return nil, context.Canceled
 }
+
+func (m *Repo) List(ctx context.Context, f domain.ListFilter) ([]domain.Loan, error) {
+	if m.ListFn != nil {
+		return m.ListFn(ctx, f)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) ListByIDs(ctx context.Context, ids []uint64) ([]domain.Loan, error) {
+	if m.ListByIDsFn != nil {
+		return m.ListByIDsFn(ctx, ids)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) GetDetailByLoanID(ctx context.Context, loanID string) (*domain.Detail, error) {
+	if m.GetDetailByLoanIDFn != nil {
+		return m.GetDetailByLoanIDFn(ctx, loanID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) Search(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+	if m.SearchFn != nil {
+		return m.SearchFn(ctx, f)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) UpdateDelinquency(ctx context.Context, id uint64, dpd uint16, bucket domain.DPDBucket, asOf time.Time) error {
+	if m.UpdateDelinquencyFn != nil {
+		return m.UpdateDelinquencyFn(ctx, id, dpd, bucket, asOf)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	domain "amartha-backend-test/internal/domain/loan"
 )
@@ -193,3 +194,143 @@ func TestRepo_GetByLoanIDForUpdate(t *testing.T) {
 		t.Fatalf("GetByLoanIDForUpdate default: want nil loan, got %+v", got)
 	}
 }
+
+func TestRepo_GetLatestRejectedLoanByBorrowerID(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Loan{LoanID: "LN-6", State: domain.StateRejected}
+
+	// Uses provided func
+	m := &Repo{
+		GetLatestRejectedLoanByBorrowerIDFn: func(gotCtx context.Context, borrowerID string) (*domain.Loan, error) {
+			if borrowerID != "BR-6" {
+				t.Fatalf("GetLatestRejected borrowerID mismatch: got %s", borrowerID)
+			}
+			return want, nil
+		},
+	}
+	got, err := m.GetLatestRejectedLoanByBorrowerID(ctx, "BR-6")
+	if err != nil || got != want {
+		t.Fatalf("GetLatestRejectedLoanByBorrowerID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	got, err = m.GetLatestRejectedLoanByBorrowerID(ctx, "BR-6")
+	if err != context.Canceled || got != nil {
+		t.Fatalf("GetLatestRejected default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_List(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		ListFn: func(gotCtx context.Context, f domain.ListFilter) ([]domain.Loan, error) {
+			if f.DPDBucket != domain.DPD90Plus || f.Limit != 10 {
+				t.Fatalf("List filter mismatch: %+v", f)
+			}
+			return []domain.Loan{{LoanID: "LN-7"}}, nil
+		},
+	}
+	got, err := m.List(ctx, domain.ListFilter{DPDBucket: domain.DPD90Plus, Limit: 10})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("List: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.List(ctx, domain.ListFilter{}); err != context.Canceled {
+		t.Fatalf("List default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_ListByIDs(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		ListByIDsFn: func(gotCtx context.Context, ids []uint64) ([]domain.Loan, error) {
+			if len(ids) != 2 || ids[0] != 3 || ids[1] != 5 {
+				t.Fatalf("ids mismatch: %v", ids)
+			}
+			return []domain.Loan{{ID: 3}, {ID: 5}}, nil
+		},
+	}
+	got, err := m.ListByIDs(ctx, []uint64{3, 5})
+	if err != nil || len(got) != 2 {
+		t.Fatalf("ListByIDs: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.ListByIDs(ctx, nil); err != context.Canceled {
+		t.Fatalf("ListByIDs default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_GetDetailByLoanID(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		GetDetailByLoanIDFn: func(gotCtx context.Context, loanID string) (*domain.Detail, error) {
+			if loanID != "LN-1" {
+				t.Fatalf("loanID mismatch: %q", loanID)
+			}
+			return &domain.Detail{Loan: domain.Loan{LoanID: loanID}, Investors: 2}, nil
+		},
+	}
+	got, err := m.GetDetailByLoanID(ctx, "LN-1")
+	if err != nil || got.LoanID != "LN-1" || got.Investors != 2 {
+		t.Fatalf("GetDetailByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.GetDetailByLoanID(ctx, "LN-1"); err != context.Canceled {
+		t.Fatalf("GetDetailByLoanID default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_Search(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		SearchFn: func(gotCtx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			if f.BorrowerID != "B-1" || f.Sort != domain.SortPrincipal || !f.Desc || f.Limit != 10 {
+				t.Fatalf("Search filter mismatch: %+v", f)
+			}
+			return []domain.Loan{{LoanID: "LN-7"}}, nil
+		},
+	}
+	got, err := m.Search(ctx, domain.SearchFilter{BorrowerID: "B-1", Sort: domain.SortPrincipal, Desc: true, Limit: 10})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Search: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.Search(ctx, domain.SearchFilter{}); err != context.Canceled {
+		t.Fatalf("Search default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_UpdateDelinquency(t *testing.T) {
+	ctx := context.Background()
+	asOf := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	called := false
+	m := &Repo{
+		UpdateDelinquencyFn: func(gotCtx context.Context, id uint64, dpd uint16, bucket domain.DPDBucket, at time.Time) error {
+			called = id == 7 && dpd == 45 && bucket == domain.DPD31To60 && at.Equal(asOf)
+			return nil
+		},
+	}
+	if err := m.UpdateDelinquency(ctx, 7, 45, domain.DPD31To60, asOf); err != nil || !called {
+		t.Fatalf("UpdateDelinquency: called=%v err=%v", called, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.UpdateDelinquency(ctx, 7, 0, domain.DPDCurrent, asOf); err != nil {
+		t.Fatalf("UpdateDelinquency default: want nil, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package notificationmock
+
+import (
+	domain "amartha-backend-test/internal/domain/notification"
+	"context"
+	"time"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+type Repo struct {
+	GetFn          func(ctx context.Context, eventID, recipientID string) (*domain.Notification, error)
+	CreateFn       func(ctx context.Context, n *domain.Notification) error
+	ListDueFn      func(ctx context.Context, now time.Time, limit int) ([]domain.Notification, error)
+	SaveFn         func(ctx context.Context, n *domain.Notification) error
+	ListByLoanIDFn func(ctx context.Context, loanID string) ([]domain.Notification, error)
+}
+
+func (m *Repo) Get(ctx context.Context, eventID, recipientID string) (*domain.Notification, error) {
+	if m.GetFn != nil {
+		return m.GetFn(ctx, eventID, recipientID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) Create(ctx context.Context, n *domain.Notification) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, n)
+	}
+	return nil
+}
+
+func (m *Repo) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Notification, error) {
+	if m.ListDueFn != nil {
+		return m.ListDueFn(ctx, now, limit)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) Save(ctx context.Context, n *domain.Notification) error {
+	if m.SaveFn != nil {
+		return m.SaveFn(ctx, n)
+	}
+	return nil
+}
+
+func (m *Repo) ListByLoanID(ctx context.Context, loanID string) ([]domain.Notification, error) {
+	if m.ListByLoanIDFn != nil {
+		return m.ListByLoanIDFn(ctx, loanID)
+	}
+	return nil, context.Canceled
+}
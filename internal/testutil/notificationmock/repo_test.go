@@ -0,0 +1,130 @@
+package notificationmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "amartha-backend-test/internal/domain/notification"
+)
+
+func TestRepo_Get(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Repo{
+		GetFn: func(gotCtx context.Context, eventID, recipientID string) (*domain.Notification, error) {
+			if eventID != "E-1" || recipientID != "I-1" {
+				t.Fatalf("args mismatch")
+			}
+			return &domain.Notification{NotificationID: "N-1"}, nil
+		},
+	}
+	if got, err := m.Get(ctx, "E-1", "I-1"); err != nil || got.NotificationID != "N-1" {
+		t.Fatalf("Get: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.Get(ctx, "E-1", "I-1"); err != context.Canceled || got != nil {
+		t.Fatalf("Get default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_Create(t *testing.T) {
+	ctx := context.Background()
+	n := &domain.Notification{NotificationID: "N-2"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateFn: func(gotCtx context.Context, got *domain.Notification) error {
+			if got != n {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Create(ctx, n); !errors.Is(err, wantErr) {
+		t.Fatalf("Create: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.Create(ctx, n); err != nil {
+		t.Fatalf("Create default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_ListDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	// Uses provided func
+	m := &Repo{
+		ListDueFn: func(gotCtx context.Context, gotNow time.Time, limit int) ([]domain.Notification, error) {
+			if !gotNow.Equal(now) || limit != 5 {
+				t.Fatalf("args mismatch")
+			}
+			return []domain.Notification{{NotificationID: "N-3"}}, nil
+		},
+	}
+	if got, err := m.ListDue(ctx, now, 5); err != nil || len(got) != 1 || got[0].NotificationID != "N-3" {
+		t.Fatalf("ListDue: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.ListDue(ctx, now, 5); err != context.Canceled || got != nil {
+		t.Fatalf("ListDue default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_Save(t *testing.T) {
+	ctx := context.Background()
+	n := &domain.Notification{NotificationID: "N-4"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		SaveFn: func(gotCtx context.Context, got *domain.Notification) error {
+			if got != n {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Save(ctx, n); !errors.Is(err, wantErr) {
+		t.Fatalf("Save: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.Save(ctx, n); err != nil {
+		t.Fatalf("Save default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_ListByLoanID(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Repo{
+		ListByLoanIDFn: func(gotCtx context.Context, loanID string) ([]domain.Notification, error) {
+			if loanID != "L-1" {
+				t.Fatalf("args mismatch")
+			}
+			return []domain.Notification{{NotificationID: "N-5"}}, nil
+		},
+	}
+	if got, err := m.ListByLoanID(ctx, "L-1"); err != nil || len(got) != 1 || got[0].NotificationID != "N-5" {
+		t.Fatalf("ListByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.ListByLoanID(ctx, "L-1"); err != context.Canceled || got != nil {
+		t.Fatalf("ListByLoanID default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,35 @@
+package outboxmock
+
+import (
+	domain "amartha-backend-test/internal/domain/outbox"
+	"context"
+	"time"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+type Repo struct {
+	AppendFn  func(ctx context.Context, m *domain.Message) error
+	ListDueFn func(ctx context.Context, now time.Time, limit int) ([]domain.Message, error)
+	SaveFn    func(ctx context.Context, m *domain.Message) error
+}
+
+func (m *Repo) Append(ctx context.Context, msg *domain.Message) error {
+	if m.AppendFn != nil {
+		return m.AppendFn(ctx, msg)
+	}
+	return nil
+}
+
+func (m *Repo) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Message, error) {
+	if m.ListDueFn != nil {
+		return m.ListDueFn(ctx, now, limit)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) Save(ctx context.Context, msg *domain.Message) error {
+	if m.SaveFn != nil {
+		return m.SaveFn(ctx, msg)
+	}
+	return nil
+}
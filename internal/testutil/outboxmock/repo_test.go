@@ -0,0 +1,85 @@
+package outboxmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "amartha-backend-test/internal/domain/outbox"
+)
+
+func TestRepo_Append(t *testing.T) {
+	ctx := context.Background()
+	msg := &domain.Message{EventID: "E-1"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		AppendFn: func(gotCtx context.Context, got *domain.Message) error {
+			if got != msg {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Append(ctx, msg); !errors.Is(err, wantErr) {
+		t.Fatalf("Append: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.Append(ctx, msg); err != nil {
+		t.Fatalf("Append default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_ListDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	want := []domain.Message{{EventID: "E-2"}}
+
+	// Uses provided func
+	m := &Repo{
+		ListDueFn: func(gotCtx context.Context, at time.Time, limit int) ([]domain.Message, error) {
+			if !at.Equal(now) || limit != 5 {
+				t.Fatalf("args mismatch: %s, %d", at, limit)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.ListDue(ctx, now, 5); err != nil || len(got) != 1 || got[0].EventID != "E-2" {
+		t.Fatalf("ListDue: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.ListDue(ctx, now, 5); err != context.Canceled || got != nil {
+		t.Fatalf("ListDue default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_Save(t *testing.T) {
+	ctx := context.Background()
+	msg := &domain.Message{EventID: "E-3"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		SaveFn: func(gotCtx context.Context, got *domain.Message) error {
+			if got != msg {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Save(ctx, msg); !errors.Is(err, wantErr) {
+		t.Fatalf("Save: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.Save(ctx, msg); err != nil {
+		t.Fatalf("Save default: want nil, got %v", err)
+	}
+}
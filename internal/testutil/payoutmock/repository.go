@@ -0,0 +1,34 @@
+package payoutmock
+
+import (
+	domain "amartha-backend-test/internal/domain/payout"
+	"context"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+type Repo struct {
+	CreateBatchFn     func(ctx context.Context, items []domain.Payout) error
+	ListByLoanIDFn    func(ctx context.Context, loanNumericID uint64) ([]domain.Payout, error)
+	SumByInvestorIDFn func(ctx context.Context, investorID string) ([]domain.Sum, error)
+}
+
+func (m *Repo) CreateBatch(ctx context.Context, items []domain.Payout) error {
+	if m.CreateBatchFn != nil {
+		return m.CreateBatchFn(ctx, items)
+	}
+	return nil
+}
+
+func (m *Repo) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]domain.Payout, error) {
+	if m.ListByLoanIDFn != nil {
+		return m.ListByLoanIDFn(ctx, loanNumericID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) SumByInvestorID(ctx context.Context, investorID string) ([]domain.Sum, error) {
+	if m.SumByInvestorIDFn != nil {
+		return m.SumByInvestorIDFn(ctx, investorID)
+	}
+	return nil, context.Canceled
+}
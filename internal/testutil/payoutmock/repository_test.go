@@ -0,0 +1,51 @@
+package payoutmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "amartha-backend-test/internal/domain/payout"
+)
+
+func TestRepo(t *testing.T) {
+	ctx := context.Background()
+
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateBatchFn: func(gotCtx context.Context, items []domain.Payout) error {
+			if len(items) != 1 || items[0].LoanID != 3 {
+				t.Fatalf("arg mismatch: %+v", items)
+			}
+			return wantErr
+		},
+		ListByLoanIDFn: func(gotCtx context.Context, id uint64) ([]domain.Payout, error) {
+			return []domain.Payout{{LoanID: id}}, nil
+		},
+	}
+	if err := m.CreateBatch(ctx, []domain.Payout{{LoanID: 3}}); !errors.Is(err, wantErr) {
+		t.Fatalf("CreateBatch: want %v, got %v", wantErr, err)
+	}
+	if got, err := m.ListByLoanID(ctx, 3); err != nil || len(got) != 1 {
+		t.Fatalf("ListByLoanID: got %+v, err %v", got, err)
+	}
+
+	m.SumByInvestorIDFn = func(gotCtx context.Context, investorID string) ([]domain.Sum, error) {
+		return []domain.Sum{{InvestmentID: 9}}, nil
+	}
+	if got, err := m.SumByInvestorID(ctx, "alice"); err != nil || len(got) != 1 || got[0].InvestmentID != 9 {
+		t.Fatalf("SumByInvestorID: got %+v, err %v", got, err)
+	}
+
+	// Defaults: CreateBatch → nil, ListByLoanID/SumByInvestorID → context.Canceled
+	m = &Repo{}
+	if err := m.CreateBatch(ctx, nil); err != nil {
+		t.Fatalf("CreateBatch default: want nil, got %v", err)
+	}
+	if _, err := m.ListByLoanID(ctx, 3); err != context.Canceled {
+		t.Fatalf("ListByLoanID default: want context.Canceled, got %v", err)
+	}
+	if _, err := m.SumByInvestorID(ctx, "alice"); err != context.Canceled {
+		t.Fatalf("SumByInvestorID default: want context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package photomock
+
+import (
+	domain "amartha-backend-test/internal/domain/photo"
+	"context"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+type Repo struct {
+	CreateFn       func(ctx context.Context, p *domain.Photo) error
+	GetByPhotoIDFn func(ctx context.Context, photoID string) (*domain.Photo, error)
+
+	GetByPhotoIDForUpdateFn func(ctx context.Context, photoID string) (*domain.Photo, error)
+}
+
+func (m *Repo) Create(ctx context.Context, p *domain.Photo) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, p)
+	}
+	return nil
+}
+
+func (m *Repo) GetByPhotoID(ctx context.Context, photoID string) (*domain.Photo, error) {
+	if m.GetByPhotoIDFn != nil {
+		return m.GetByPhotoIDFn(ctx, photoID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) GetByPhotoIDForUpdate(ctx context.Context, photoID string) (*domain.Photo, error) {
+	if m.GetByPhotoIDForUpdateFn != nil {
+		return m.GetByPhotoIDForUpdateFn(ctx, photoID)
+	}
+	return nil, context.Canceled
+}
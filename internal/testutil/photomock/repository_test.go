@@ -0,0 +1,82 @@
+package photomock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "amartha-backend-test/internal/domain/photo"
+)
+
+func TestRepo_Create(t *testing.T) {
+	ctx := context.Background()
+	p := &domain.Photo{PhotoID: "P-1"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateFn: func(gotCtx context.Context, got *domain.Photo) error {
+			if got != p {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Create(ctx, p); !errors.Is(err, wantErr) {
+		t.Fatalf("Create: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.Create(ctx, p); err != nil {
+		t.Fatalf("Create default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_GetByPhotoID(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Photo{PhotoID: "P-2"}
+
+	// Uses provided func
+	m := &Repo{
+		GetByPhotoIDFn: func(gotCtx context.Context, id string) (*domain.Photo, error) {
+			if id != "P-2" {
+				t.Fatalf("photoID mismatch: got %s", id)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetByPhotoID(ctx, "P-2"); err != nil || got != want {
+		t.Fatalf("GetByPhotoID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.GetByPhotoID(ctx, "P-2"); err != context.Canceled || got != nil {
+		t.Fatalf("GetByPhotoID default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_GetByPhotoIDForUpdate(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Photo{PhotoID: "P-3"}
+
+	// Uses provided func
+	m := &Repo{
+		GetByPhotoIDForUpdateFn: func(gotCtx context.Context, id string) (*domain.Photo, error) {
+			if id != "P-3" {
+				t.Fatalf("photoID mismatch: got %s", id)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetByPhotoIDForUpdate(ctx, "P-3"); err != nil || got != want {
+		t.Fatalf("GetByPhotoIDForUpdate: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.GetByPhotoIDForUpdate(ctx, "P-3"); err != context.Canceled || got != nil {
+		t.Fatalf("GetByPhotoIDForUpdate default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,27 @@
+package rejectionmock
+
+import (
+	domain "amartha-backend-test/internal/domain/rejection"
+	"context"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+// Only methods you need are included; add more as tests require.
+type Repo struct {
+	CreateFn      func(ctx context.Context, r *domain.Rejection) error
+	GetByLoanIDFn func(ctx context.Context, loanNumericID uint64) (*domain.Rejection, error)
+}
+
+func (m *Repo) Create(ctx context.Context, r *domain.Rejection) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, r)
+	}
+	return nil
+}
+
+func (m *Repo) GetByLoanID(ctx context.Context, loanNumericID uint64) (*domain.Rejection, error) {
+	if m.GetByLoanIDFn != nil {
+		return m.GetByLoanIDFn(ctx, loanNumericID)
+	}
+	return nil, context.Canceled
+}
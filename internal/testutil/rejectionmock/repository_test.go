@@ -0,0 +1,58 @@
+package rejectionmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "amartha-backend-test/internal/domain/rejection"
+)
+
+func TestRepo_Create(t *testing.T) {
+	ctx := context.Background()
+	r := &domain.Rejection{RejectionID: "RJ-1", LoanID: 1}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateFn: func(gotCtx context.Context, got *domain.Rejection) error {
+			if got != r {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.Create(ctx, r); !errors.Is(err, wantErr) {
+		t.Fatalf("Create: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.Create(ctx, r); err != nil {
+		t.Fatalf("Create default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_GetByLoanID(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Rejection{RejectionID: "RJ-2", LoanID: 456}
+
+	// Uses provided func
+	m := &Repo{
+		GetByLoanIDFn: func(gotCtx context.Context, id uint64) (*domain.Rejection, error) {
+			if id != 456 {
+				t.Fatalf("loanNumericID mismatch: got %d", id)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetByLoanID(ctx, 456); err != nil || got != want {
+		t.Fatalf("GetByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.GetByLoanID(ctx, 456); err != context.Canceled || got != nil {
+		t.Fatalf("GetByLoanID default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,63 @@
+package repaymentmock
+
+import (
+	domain "amartha-backend-test/internal/domain/repayment"
+	"context"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+// Only methods you need are included; add more as tests require.
+type Repo struct {
+	CreateScheduleFn   func(ctx context.Context, items []domain.Installment) error
+	ListByLoanIDFn     func(ctx context.Context, loanNumericID uint64) ([]domain.Installment, error)
+	UpdatePaidFn       func(ctx context.Context, items []domain.Installment) error
+	ExposureByBucketFn func(ctx context.Context) ([]domain.BucketExposure, error)
+}
+
+func (m *Repo) CreateSchedule(ctx context.Context, items []domain.Installment) error {
+	if m.CreateScheduleFn != nil {
+		return m.CreateScheduleFn(ctx, items)
+	}
+	return nil
+}
+
+func (m *Repo) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]domain.Installment, error) {
+	if m.ListByLoanIDFn != nil {
+		return m.ListByLoanIDFn(ctx, loanNumericID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) UpdatePaid(ctx context.Context, items []domain.Installment) error {
+	if m.UpdatePaidFn != nil {
+		return m.UpdatePaidFn(ctx, items)
+	}
+	return nil
+}
+
+func (m *Repo) ExposureByBucket(ctx context.Context) ([]domain.BucketExposure, error) {
+	if m.ExposureByBucketFn != nil {
+		return m.ExposureByBucketFn(ctx)
+	}
+	return nil, context.Canceled
+}
+
+// PaymentRepo is a function-backed mock that satisfies domain.PaymentRepository.
+type PaymentRepo struct {
+	CreateFn       func(ctx context.Context, p *domain.Repayment) error
+	ListByLoanIDFn func(ctx context.Context, loanNumericID uint64) ([]domain.Repayment, error)
+}
+
+func (m *PaymentRepo) Create(ctx context.Context, p *domain.Repayment) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, p)
+	}
+	return nil
+}
+
+func (m *PaymentRepo) ListByLoanID(ctx context.Context, loanNumericID uint64) ([]domain.Repayment, error) {
+	if m.ListByLoanIDFn != nil {
+		return m.ListByLoanIDFn(ctx, loanNumericID)
+	}
+	return nil, context.Canceled
+}
@@ -0,0 +1,130 @@
+package repaymentmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "amartha-backend-test/internal/domain/repayment"
+)
+
+func TestRepo_CreateSchedule(t *testing.T) {
+	ctx := context.Background()
+	items := []domain.Installment{{LoanID: 1, InstallmentNo: 1}}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateScheduleFn: func(gotCtx context.Context, got []domain.Installment) error {
+			if len(got) != 1 || got[0].LoanID != 1 {
+				t.Fatalf("arg mismatch: %+v", got)
+			}
+			return wantErr
+		},
+	}
+	if err := m.CreateSchedule(ctx, items); !errors.Is(err, wantErr) {
+		t.Fatalf("CreateSchedule: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.CreateSchedule(ctx, items); err != nil {
+		t.Fatalf("CreateSchedule default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_ListByLoanID(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Repo{
+		ListByLoanIDFn: func(gotCtx context.Context, id uint64) ([]domain.Installment, error) {
+			if id != 5 {
+				t.Fatalf("loanNumericID mismatch: got %d", id)
+			}
+			return []domain.Installment{{LoanID: 5, InstallmentNo: 1}}, nil
+		},
+	}
+	got, err := m.ListByLoanID(ctx, 5)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("ListByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.ListByLoanID(ctx, 5); err != context.Canceled {
+		t.Fatalf("ListByLoanID default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_UpdatePaid(t *testing.T) {
+	ctx := context.Background()
+
+	called := false
+	m := &Repo{
+		UpdatePaidFn: func(gotCtx context.Context, got []domain.Installment) error {
+			called = len(got) == 2
+			return nil
+		},
+	}
+	if err := m.UpdatePaid(ctx, make([]domain.Installment, 2)); err != nil || !called {
+		t.Fatalf("UpdatePaid: called=%v err=%v", called, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.UpdatePaid(ctx, nil); err != nil {
+		t.Fatalf("UpdatePaid default: want nil, got %v", err)
+	}
+}
+
+func TestPaymentRepo(t *testing.T) {
+	ctx := context.Background()
+
+	wantErr := errors.New("boom")
+	m := &PaymentRepo{
+		CreateFn: func(gotCtx context.Context, p *domain.Repayment) error {
+			if p.LoanID != 3 {
+				t.Fatalf("arg mismatch: %+v", p)
+			}
+			return wantErr
+		},
+		ListByLoanIDFn: func(gotCtx context.Context, id uint64) ([]domain.Repayment, error) {
+			return []domain.Repayment{{LoanID: id}}, nil
+		},
+	}
+	if err := m.Create(ctx, &domain.Repayment{LoanID: 3}); !errors.Is(err, wantErr) {
+		t.Fatalf("Create: want %v, got %v", wantErr, err)
+	}
+	if got, err := m.ListByLoanID(ctx, 3); err != nil || len(got) != 1 {
+		t.Fatalf("ListByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Defaults: Create → nil, ListByLoanID → context.Canceled
+	m = &PaymentRepo{}
+	if err := m.Create(ctx, &domain.Repayment{}); err != nil {
+		t.Fatalf("Create default: want nil, got %v", err)
+	}
+	if _, err := m.ListByLoanID(ctx, 3); err != context.Canceled {
+		t.Fatalf("ListByLoanID default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_ExposureByBucket(t *testing.T) {
+	ctx := context.Background()
+
+	m := &Repo{
+		ExposureByBucketFn: func(gotCtx context.Context) ([]domain.BucketExposure, error) {
+			return []domain.BucketExposure{{Bucket: "90+", Loans: 1}}, nil
+		},
+	}
+	if got, err := m.ExposureByBucket(ctx); err != nil || len(got) != 1 {
+		t.Fatalf("ExposureByBucket: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.ExposureByBucket(ctx); err != context.Canceled {
+		t.Fatalf("ExposureByBucket default: want context.Canceled, got %v", err)
+	}
+}
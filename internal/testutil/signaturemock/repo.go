@@ -0,0 +1,66 @@
+package signaturemock
+
+import (
+	domain "amartha-backend-test/internal/domain/signature"
+	"context"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+type Repo struct {
+	CreateEnvelopeFn           func(ctx context.Context, e *domain.Envelope) error
+	GetByProviderTxFn          func(ctx context.Context, provider, txID string) (*domain.Envelope, error)
+	GetByProviderTxForUpdateFn func(ctx context.Context, provider, txID string) (*domain.Envelope, error)
+	GetLatestByLoanIDFn        func(ctx context.Context, loanID uint64) (*domain.Envelope, error)
+	SaveEnvelopeFn             func(ctx context.Context, e *domain.Envelope) error
+	GetEventFn                 func(ctx context.Context, provider, eventID string) (*domain.Event, error)
+	CreateEventFn              func(ctx context.Context, ev *domain.Event) error
+}
+
+func (m *Repo) CreateEnvelope(ctx context.Context, e *domain.Envelope) error {
+	if m.CreateEnvelopeFn != nil {
+		return m.CreateEnvelopeFn(ctx, e)
+	}
+	return nil
+}
+
+func (m *Repo) GetByProviderTx(ctx context.Context, provider, txID string) (*domain.Envelope, error) {
+	if m.GetByProviderTxFn != nil {
+		return m.GetByProviderTxFn(ctx, provider, txID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) GetByProviderTxForUpdate(ctx context.Context, provider, txID string) (*domain.Envelope, error) {
+	if m.GetByProviderTxForUpdateFn != nil {
+		return m.GetByProviderTxForUpdateFn(ctx, provider, txID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) GetLatestByLoanID(ctx context.Context, loanID uint64) (*domain.Envelope, error) {
+	if m.GetLatestByLoanIDFn != nil {
+		return m.GetLatestByLoanIDFn(ctx, loanID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) SaveEnvelope(ctx context.Context, e *domain.Envelope) error {
+	if m.SaveEnvelopeFn != nil {
+		return m.SaveEnvelopeFn(ctx, e)
+	}
+	return nil
+}
+
+func (m *Repo) GetEvent(ctx context.Context, provider, eventID string) (*domain.Event, error) {
+	if m.GetEventFn != nil {
+		return m.GetEventFn(ctx, provider, eventID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) CreateEvent(ctx context.Context, ev *domain.Event) error {
+	if m.CreateEventFn != nil {
+		return m.CreateEventFn(ctx, ev)
+	}
+	return nil
+}
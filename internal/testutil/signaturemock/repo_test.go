@@ -0,0 +1,180 @@
+package signaturemock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domain "amartha-backend-test/internal/domain/signature"
+)
+
+func TestRepo_CreateEnvelope(t *testing.T) {
+	ctx := context.Background()
+	e := &domain.Envelope{EnvelopeID: "E-1"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateEnvelopeFn: func(gotCtx context.Context, got *domain.Envelope) error {
+			if got != e {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.CreateEnvelope(ctx, e); !errors.Is(err, wantErr) {
+		t.Fatalf("CreateEnvelope: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.CreateEnvelope(ctx, e); err != nil {
+		t.Fatalf("CreateEnvelope default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_GetByProviderTx(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Envelope{EnvelopeID: "E-2"}
+
+	// Uses provided func
+	m := &Repo{
+		GetByProviderTxFn: func(gotCtx context.Context, provider, txID string) (*domain.Envelope, error) {
+			if provider != "fake" || txID != "tx-2" {
+				t.Fatalf("args mismatch: %s %s", provider, txID)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetByProviderTx(ctx, "fake", "tx-2"); err != nil || got != want {
+		t.Fatalf("GetByProviderTx: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.GetByProviderTx(ctx, "fake", "tx-2"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetByProviderTx default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_GetByProviderTxForUpdate(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Envelope{EnvelopeID: "E-2"}
+
+	// Uses provided func
+	m := &Repo{
+		GetByProviderTxForUpdateFn: func(gotCtx context.Context, provider, txID string) (*domain.Envelope, error) {
+			if provider != "fake" || txID != "tx-2" {
+				t.Fatalf("args mismatch: %s %s", provider, txID)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetByProviderTxForUpdate(ctx, "fake", "tx-2"); err != nil || got != want {
+		t.Fatalf("GetByProviderTxForUpdate: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.GetByProviderTxForUpdate(ctx, "fake", "tx-2"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetByProviderTxForUpdate default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_GetLatestByLoanID(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Envelope{EnvelopeID: "E-3"}
+
+	// Uses provided func
+	m := &Repo{
+		GetLatestByLoanIDFn: func(gotCtx context.Context, loanID uint64) (*domain.Envelope, error) {
+			if loanID != 3 {
+				t.Fatalf("loanID mismatch: got %d", loanID)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetLatestByLoanID(ctx, 3); err != nil || got != want {
+		t.Fatalf("GetLatestByLoanID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.GetLatestByLoanID(ctx, 3); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetLatestByLoanID default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_SaveEnvelope(t *testing.T) {
+	ctx := context.Background()
+	e := &domain.Envelope{EnvelopeID: "E-4"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		SaveEnvelopeFn: func(gotCtx context.Context, got *domain.Envelope) error {
+			if got != e {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.SaveEnvelope(ctx, e); !errors.Is(err, wantErr) {
+		t.Fatalf("SaveEnvelope: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.SaveEnvelope(ctx, e); err != nil {
+		t.Fatalf("SaveEnvelope default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_GetEvent(t *testing.T) {
+	ctx := context.Background()
+	want := &domain.Event{EventID: "evt-5"}
+
+	// Uses provided func
+	m := &Repo{
+		GetEventFn: func(gotCtx context.Context, provider, eventID string) (*domain.Event, error) {
+			if provider != "fake" || eventID != "evt-5" {
+				t.Fatalf("args mismatch: %s %s", provider, eventID)
+			}
+			return want, nil
+		},
+	}
+	if got, err := m.GetEvent(ctx, "fake", "evt-5"); err != nil || got != want {
+		t.Fatalf("GetEvent: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if _, err := m.GetEvent(ctx, "fake", "evt-5"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetEvent default: want context.Canceled, got %v", err)
+	}
+}
+
+func TestRepo_CreateEvent(t *testing.T) {
+	ctx := context.Background()
+	ev := &domain.Event{EventID: "evt-6"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateEventFn: func(gotCtx context.Context, got *domain.Event) error {
+			if got != ev {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.CreateEvent(ctx, ev); !errors.Is(err, wantErr) {
+		t.Fatalf("CreateEvent: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.CreateEvent(ctx, ev); err != nil {
+		t.Fatalf("CreateEvent default: want nil, got %v", err)
+	}
+}
@@ -16,7 +16,7 @@ func TestUoW_WithinTx_Happy(t *testing.T) {
 
 	loans := &loanmock.Repo{}
 	apprs := &approvalmock.Repo{}
-	repos := uow.Repos{Loans: loans, Approvals: apprs}
+	repos := uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs}
 
 	innerCalled := false
 	m := &UoW{
@@ -74,7 +74,7 @@ func TestUoW_WithinLoanTx_Happy(t *testing.T) {
 
 	loans := &loanmock.Repo{}
 	apprs := &approvalmock.Repo{}
-	repos := uow.Repos{Loans: loans, Approvals: apprs}
+	repos := uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs}
 	lock := &loan.Loan{ID: 7, LoanID: "LN-7"}
 
 	innerCalled := false
@@ -0,0 +1,83 @@
+package webhookmock
+
+import (
+	domain "amartha-backend-test/internal/domain/webhook"
+	"context"
+	"time"
+)
+
+// Repo is a function-backed mock that satisfies domain.Repository.
+type Repo struct {
+	CreateSubscriptionFn         func(ctx context.Context, s *domain.Subscription) error
+	ListSubscriptionsByPartnerFn func(ctx context.Context, partnerID string) ([]domain.Subscription, error)
+	ListSubscriptionsFn          func(ctx context.Context) ([]domain.Subscription, error)
+	GetSubscriptionByIDFn        func(ctx context.Context, id uint64) (*domain.Subscription, error)
+	GetDeliveryFn                func(ctx context.Context, subscriptionID uint64, eventID string) (*domain.Delivery, error)
+	CreateDeliveryFn             func(ctx context.Context, d *domain.Delivery) error
+	ListDueDeliveriesFn          func(ctx context.Context, now time.Time, limit int) ([]domain.Delivery, error)
+	SaveDeliveryFn               func(ctx context.Context, d *domain.Delivery) error
+	SearchDeliveriesFn           func(ctx context.Context, f domain.DeliveryFilter) ([]domain.Delivery, error)
+}
+
+func (m *Repo) CreateSubscription(ctx context.Context, s *domain.Subscription) error {
+	if m.CreateSubscriptionFn != nil {
+		return m.CreateSubscriptionFn(ctx, s)
+	}
+	return nil
+}
+
+func (m *Repo) ListSubscriptionsByPartner(ctx context.Context, partnerID string) ([]domain.Subscription, error) {
+	if m.ListSubscriptionsByPartnerFn != nil {
+		return m.ListSubscriptionsByPartnerFn(ctx, partnerID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	if m.ListSubscriptionsFn != nil {
+		return m.ListSubscriptionsFn(ctx)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) GetSubscriptionByID(ctx context.Context, id uint64) (*domain.Subscription, error) {
+	if m.GetSubscriptionByIDFn != nil {
+		return m.GetSubscriptionByIDFn(ctx, id)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) GetDelivery(ctx context.Context, subscriptionID uint64, eventID string) (*domain.Delivery, error) {
+	if m.GetDeliveryFn != nil {
+		return m.GetDeliveryFn(ctx, subscriptionID, eventID)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) CreateDelivery(ctx context.Context, d *domain.Delivery) error {
+	if m.CreateDeliveryFn != nil {
+		return m.CreateDeliveryFn(ctx, d)
+	}
+	return nil
+}
+
+func (m *Repo) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.Delivery, error) {
+	if m.ListDueDeliveriesFn != nil {
+		return m.ListDueDeliveriesFn(ctx, now, limit)
+	}
+	return nil, context.Canceled
+}
+
+func (m *Repo) SaveDelivery(ctx context.Context, d *domain.Delivery) error {
+	if m.SaveDeliveryFn != nil {
+		return m.SaveDeliveryFn(ctx, d)
+	}
+	return nil
+}
+
+func (m *Repo) SearchDeliveries(ctx context.Context, f domain.DeliveryFilter) ([]domain.Delivery, error) {
+	if m.SearchDeliveriesFn != nil {
+		return m.SearchDeliveriesFn(ctx, f)
+	}
+	return nil, context.Canceled
+}
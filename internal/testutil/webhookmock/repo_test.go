@@ -0,0 +1,221 @@
+package webhookmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "amartha-backend-test/internal/domain/webhook"
+)
+
+func TestRepo_CreateSubscription(t *testing.T) {
+	ctx := context.Background()
+	s := &domain.Subscription{SubscriptionID: "S-1"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateSubscriptionFn: func(gotCtx context.Context, got *domain.Subscription) error {
+			if got != s {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.CreateSubscription(ctx, s); !errors.Is(err, wantErr) {
+		t.Fatalf("CreateSubscription: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.CreateSubscription(ctx, s); err != nil {
+		t.Fatalf("CreateSubscription default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_CreateDelivery(t *testing.T) {
+	ctx := context.Background()
+	d := &domain.Delivery{DeliveryID: "D-1"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		CreateDeliveryFn: func(gotCtx context.Context, got *domain.Delivery) error {
+			if got != d {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.CreateDelivery(ctx, d); !errors.Is(err, wantErr) {
+		t.Fatalf("CreateDelivery: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.CreateDelivery(ctx, d); err != nil {
+		t.Fatalf("CreateDelivery default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_SaveDelivery(t *testing.T) {
+	ctx := context.Background()
+	d := &domain.Delivery{DeliveryID: "D-2"}
+
+	// Uses provided func
+	wantErr := errors.New("boom")
+	m := &Repo{
+		SaveDeliveryFn: func(gotCtx context.Context, got *domain.Delivery) error {
+			if got != d {
+				t.Fatalf("arg mismatch")
+			}
+			return wantErr
+		},
+	}
+	if err := m.SaveDelivery(ctx, d); !errors.Is(err, wantErr) {
+		t.Fatalf("SaveDelivery: want %v, got %v", wantErr, err)
+	}
+
+	// Default (nil func) → no-op, nil error
+	m = &Repo{}
+	if err := m.SaveDelivery(ctx, d); err != nil {
+		t.Fatalf("SaveDelivery default: want nil, got %v", err)
+	}
+}
+
+func TestRepo_ListSubscriptionsByPartner(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Repo{
+		ListSubscriptionsByPartnerFn: func(gotCtx context.Context, partnerID string) ([]domain.Subscription, error) {
+			if partnerID != "P-1" {
+				t.Fatalf("args mismatch")
+			}
+			return []domain.Subscription{{SubscriptionID: "S-2"}}, nil
+		},
+	}
+	if got, err := m.ListSubscriptionsByPartner(ctx, "P-1"); err != nil || len(got) != 1 || got[0].SubscriptionID != "S-2" {
+		t.Fatalf("ListSubscriptionsByPartner: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.ListSubscriptionsByPartner(ctx, "P-1"); err != context.Canceled || got != nil {
+		t.Fatalf("ListSubscriptionsByPartner default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_ListSubscriptions(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Repo{
+		ListSubscriptionsFn: func(gotCtx context.Context) ([]domain.Subscription, error) {
+			return []domain.Subscription{{SubscriptionID: "S-3"}}, nil
+		},
+	}
+	if got, err := m.ListSubscriptions(ctx); err != nil || len(got) != 1 || got[0].SubscriptionID != "S-3" {
+		t.Fatalf("ListSubscriptions: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.ListSubscriptions(ctx); err != context.Canceled || got != nil {
+		t.Fatalf("ListSubscriptions default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_GetSubscriptionByID(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Repo{
+		GetSubscriptionByIDFn: func(gotCtx context.Context, id uint64) (*domain.Subscription, error) {
+			if id != 7 {
+				t.Fatalf("args mismatch")
+			}
+			return &domain.Subscription{ID: 7}, nil
+		},
+	}
+	if got, err := m.GetSubscriptionByID(ctx, 7); err != nil || got.ID != 7 {
+		t.Fatalf("GetSubscriptionByID: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.GetSubscriptionByID(ctx, 7); err != context.Canceled || got != nil {
+		t.Fatalf("GetSubscriptionByID default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_GetDelivery(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Repo{
+		GetDeliveryFn: func(gotCtx context.Context, subID uint64, eventID string) (*domain.Delivery, error) {
+			if subID != 7 || eventID != "E-1" {
+				t.Fatalf("args mismatch")
+			}
+			return &domain.Delivery{DeliveryID: "D-3"}, nil
+		},
+	}
+	if got, err := m.GetDelivery(ctx, 7, "E-1"); err != nil || got.DeliveryID != "D-3" {
+		t.Fatalf("GetDelivery: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.GetDelivery(ctx, 7, "E-1"); err != context.Canceled || got != nil {
+		t.Fatalf("GetDelivery default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_ListDueDeliveries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	// Uses provided func
+	m := &Repo{
+		ListDueDeliveriesFn: func(gotCtx context.Context, at time.Time, limit int) ([]domain.Delivery, error) {
+			if !at.Equal(now) || limit != 5 {
+				t.Fatalf("args mismatch")
+			}
+			return []domain.Delivery{{DeliveryID: "D-4"}}, nil
+		},
+	}
+	if got, err := m.ListDueDeliveries(ctx, now, 5); err != nil || len(got) != 1 || got[0].DeliveryID != "D-4" {
+		t.Fatalf("ListDueDeliveries: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.ListDueDeliveries(ctx, now, 5); err != context.Canceled || got != nil {
+		t.Fatalf("ListDueDeliveries default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
+
+func TestRepo_SearchDeliveries(t *testing.T) {
+	ctx := context.Background()
+
+	// Uses provided func
+	m := &Repo{
+		SearchDeliveriesFn: func(gotCtx context.Context, f domain.DeliveryFilter) ([]domain.Delivery, error) {
+			if f.PartnerID != "P-2" {
+				t.Fatalf("args mismatch")
+			}
+			return []domain.Delivery{{DeliveryID: "D-5"}}, nil
+		},
+	}
+	if got, err := m.SearchDeliveries(ctx, domain.DeliveryFilter{PartnerID: "P-2"}); err != nil || len(got) != 1 || got[0].DeliveryID != "D-5" {
+		t.Fatalf("SearchDeliveries: got %+v, err %v", got, err)
+	}
+
+	// Default (nil func) → context.Canceled
+	m = &Repo{}
+	if got, err := m.SearchDeliveries(ctx, domain.DeliveryFilter{PartnerID: "P-2"}); err != context.Canceled || got != nil {
+		t.Fatalf("SearchDeliveries default: want nil, context.Canceled; got %+v, %v", got, err)
+	}
+}
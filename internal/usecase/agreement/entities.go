@@ -0,0 +1,11 @@
+package agreement
+
+import "time"
+
+type AgreementDTO struct {
+	LoanID          string    `json:"loan_id"`
+	TemplateVersion string    `json:"template_version"`
+	SHA256          string    `json:"sha256"`
+	URL             string    `json:"url"` // signed, read-only
+	URLExpiresAt    time.Time `json:"url_expires_at"`
+}
@@ -0,0 +1,52 @@
+package agreement
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainAgreement "amartha-backend-test/internal/domain/agreement"
+	"amartha-backend-test/internal/domain/blob"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	loans domainLoan.Repository
+	store blob.Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewUsecase: ttl is how long the signed agreement URLs stay valid.
+func NewUsecase(loans domainLoan.Repository, store blob.Store, ttl time.Duration) *Usecase {
+	return &Usecase{loans: loans, store: store, ttl: ttl, now: time.Now}
+}
+
+// Get returns the loan's agreement with a freshly signed URL, and the
+// template version and hash recorded when it was rendered.
+func (u *Usecase) Get(ctx context.Context, loanID string) (*AgreementDTO, error) {
+	l, err := u.loans.GetByLoanID(ctx, loanID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+	if l.AgreementLink == "" {
+		return nil, domainAgreement.ErrNotGenerated
+	}
+	expires := u.now().Add(u.ttl).UTC().Truncate(time.Second)
+	link, err := u.store.SignedURL(ctx, l.AgreementLink, u.ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &AgreementDTO{
+		LoanID:          l.LoanID,
+		TemplateVersion: l.AgreementVersion,
+		SHA256:          l.AgreementSHA256,
+		URL:             link,
+		URLExpiresAt:    expires,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package agreement
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	domainAgreement "amartha-backend-test/internal/domain/agreement"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/testutil/blobmock"
+	"amartha-backend-test/internal/testutil/loanmock"
+
+	"gorm.io/gorm"
+)
+
+func TestUsecase_Get(t *testing.T) {
+	hash := strings.Repeat("0", 64)
+	loans := &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			switch loanID {
+			case "LN-1":
+				return &domainLoan.Loan{LoanID: "LN-1", AgreementLink: "agreements/LN-1/agreement-v1.txt", AgreementVersion: "v1", AgreementSHA256: hash}, nil
+			case "LN-2":
+				return &domainLoan.Loan{LoanID: "LN-2"}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	store := &blobmock.Store{
+		SignedURLFn: func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+			if ttl != time.Hour {
+				t.Fatalf("ttl = %s", ttl)
+			}
+			return "http://blobs/" + key + "?sig", nil
+		},
+	}
+	uc := NewUsecase(loans, store, time.Hour)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	dto, err := uc.Get(context.Background(), "LN-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if dto.URL != "http://blobs/agreements/LN-1/agreement-v1.txt?sig" || dto.TemplateVersion != "v1" || dto.SHA256 != hash ||
+		!dto.URLExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("dto: %+v", dto)
+	}
+	if _, err := uc.Get(context.Background(), "LN-2"); !errors.Is(err, domainAgreement.ErrNotGenerated) {
+		t.Fatalf("no agreement yet: %v", err)
+	}
+	if _, err := uc.Get(context.Background(), "LN-3"); !errors.Is(err, domainLoan.ErrNotFound) {
+		t.Fatalf("missing loan: %v", err)
+	}
+}
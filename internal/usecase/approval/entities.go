@@ -6,7 +6,7 @@ import (
 
 type ApproveInput struct {
 	LoanID              string
-	PhotoURL            string
+	PhotoID             string    // issued by POST /photos
 	ValidatorEmployeeID string    // 32-char hex
 	ApprovalDate        time.Time // date-only is fine; store .UTC()
 }
@@ -14,6 +14,22 @@ type ApproveInput struct {
 type ApprovalDTO struct {
 	ApprovalID string    `json:"approval_id"`
 	LoanID     string    `json:"loan_id"`
-	PhotoURL   string    `json:"photo_url"`
+	PhotoID    string    `json:"photo_id"`
+	PhotoURL   string    `json:"photo_url"`   // blob key; GET /photos/:photo_id signs a link
 	ApprovedAt time.Time `json:"approved_at"` // equals input date @ 00:00:00 UTC
 }
+
+type RevokeInput struct {
+	LoanID              string
+	Reason              string // why the approval was wrong; kept in the loan history
+	ValidatorEmployeeID string // 32-char hex; stored in deleted_by
+}
+
+type RevocationDTO struct {
+	ApprovalID          string    `json:"approval_id"`
+	LoanID              string    `json:"loan_id"`
+	LoanState           string    `json:"loan_state"` // proposed
+	Reason              string    `json:"reason"`
+	ValidatorEmployeeID string    `json:"validator_employee_id"`
+	RevokedAt           time.Time `json:"revoked_at"`
+}
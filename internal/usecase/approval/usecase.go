@@ -2,18 +2,24 @@ package approval
 
 import (
 	"errors"
+	"fmt"
 	"log"
-	"time"
+	"strings"
 
 	domainApproval "amartha-backend-test/internal/domain/approval"
 	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	domainPhoto "amartha-backend-test/internal/domain/photo"
 	"amartha-backend-test/internal/domain/uow"
 	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/requestctx"
 	"context"
 
 	"gorm.io/gorm"
 )
 
+var ErrReasonRequired = errors.New("a reason is required to revoke an approval")
+
 type Usecase struct {
 	loanRepo     domainLoan.Repository
 	approvalRepo domainApproval.Repository
@@ -38,13 +44,13 @@ func (u *Usecase) Approve(ctx context.Context, in ApproveInput) (*ApprovalDTO, e
 			return domainLoan.ErrNotFound
 		}
 
-		// State guard: only proposed → approved
-		if l.State != domainLoan.StateProposed {
-			// If already approved, surface a clear error
-			if l.State == domainLoan.StateApproved {
-				return domainLoan.ErrAlreadyApproved
-			}
-			return domainLoan.ErrInvalidTransition
+		// State guard: only proposed → approved (ErrAlreadyApproved when repeated)
+		tr, err := domainLoan.Lifecycle.Transition(l, domainLoan.StateApproved, domainLoan.TransitionMeta{
+			Actor:     in.ValidatorEmployeeID,
+			RequestID: requestctx.RequestID(ctx),
+		})
+		if err != nil {
+			return err
 		}
 
 		if _, err := r.Approvals.GetByLoanID(ctx, l.ID); err != nil {
@@ -58,11 +64,36 @@ func (u *Usecase) Approve(ctx context.Context, in ApproveInput) (*ApprovalDTO, e
 			return domainLoan.ErrAlreadyApproved
 		}
 
+		// Only photos this service stored count as field-visit evidence. The
+		// photo row stays locked until commit, so a concurrent approval of
+		// another loan with the same photo waits and then sees this one.
+		p, err := r.Photos.GetByPhotoIDForUpdate(ctx, in.PhotoID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainPhoto.ErrNotFound
+			}
+			return err
+		}
+		// The same picture must not back two loans, even after the other
+		// approval was revoked. Photos stored before hashing existed have no
+		// hash and are not compared.
+		if p.PHash != nil {
+			dup, err := r.Approvals.FindNearPhoto(ctx, *p.PHash, domainPhoto.NearDuplicateDistance, l.ID)
+			if err == nil {
+				return fmt.Errorf("%w (approval %s)", domainApproval.ErrDuplicatePhoto, dup.ApprovalID)
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+
 		// Insert approval
 		a := &domainApproval.Approval{
 			ApprovalID:          id.NewID32(),
 			LoanID:              l.ID, // numeric FK
-			PhotoURL:            in.PhotoURL,
+			PhotoID:             &p.PhotoID,
+			PhotoPHash:          p.PHash,
+			PhotoURL:            p.StorageKey,
 			ValidatorEmployeeID: in.ValidatorEmployeeID,
 			ApprovalDate:        in.ApprovalDate.UTC(),
 		}
@@ -70,16 +101,25 @@ func (u *Usecase) Approve(ctx context.Context, in ApproveInput) (*ApprovalDTO, e
 			return err
 		}
 
-		// Update loan → approved
-		l.State = domainLoan.StateApproved
-		l.StateUpdatedAt = time.Now().UTC()
+		// Persist loan → approved, with its audit row and outbox event
 		if err := r.Loans.Save(ctx, l); err != nil {
 			return err
 		}
+		if err := r.LoanHistory.Append(ctx, tr); err != nil {
+			return err
+		}
+		ev, err := domainOutbox.ForLoan(domainOutbox.LoanApproved, l)
+		if err != nil {
+			return err
+		}
+		if err := r.Outbox.Append(ctx, ev); err != nil {
+			return err
+		}
 
 		dto = &ApprovalDTO{
 			ApprovalID: a.ApprovalID,
 			LoanID:     l.LoanID, // public id
+			PhotoID:    p.PhotoID,
 			PhotoURL:   a.PhotoURL,
 			ApprovedAt: a.ApprovalDate,
 		}
@@ -91,3 +131,80 @@ func (u *Usecase) Approve(ctx context.Context, in ApproveInput) (*ApprovalDTO, e
 	}
 	return dto, nil
 }
+
+// Revoke undoes an approval that no investor has funded yet: the approval row
+// is soft-deleted and the loan goes back to proposed. It holds the loan lock,
+// so an investment cannot slip in between the check and the state change.
+func (u *Usecase) Revoke(ctx context.Context, in RevokeInput) (*RevocationDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	var dto *RevocationDTO
+
+	err := u.uow.WithinLoanTx(ctx, in.LoanID, func(r uow.Repos, l *domainLoan.Loan) error {
+		invs, err := r.Investments.ListByLoanID(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+
+		// State guard: only approved → proposed, and only with no investments
+		tr, err := domainLoan.Lifecycle.Transition(l, domainLoan.StateProposed, domainLoan.TransitionMeta{
+			Actor:     in.ValidatorEmployeeID,
+			Reason:    "approval revoked: " + reason,
+			RequestID: requestctx.RequestID(ctx),
+			Facts:     domainLoan.Facts{Investments: len(invs)},
+		})
+		if err != nil {
+			return err
+		}
+
+		a, err := r.Approvals.GetByLoanID(ctx, l.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainApproval.ErrNotFound
+			}
+			return err
+		}
+		if err := r.Approvals.SoftDeleteByLoanID(ctx, l.ID, in.ValidatorEmployeeID); err != nil {
+			return err
+		}
+
+		// Persist loan → proposed, with its audit row
+		if err := r.Loans.Save(ctx, l); err != nil {
+			return err
+		}
+		if err := r.LoanHistory.Append(ctx, tr); err != nil {
+			return err
+		}
+		ev, err := domainOutbox.ForLoan(domainOutbox.LoanApprovalRevoked, l)
+		if err != nil {
+			return err
+		}
+		if err := r.Outbox.Append(ctx, ev); err != nil {
+			return err
+		}
+
+		dto = &RevocationDTO{
+			ApprovalID:          a.ApprovalID,
+			LoanID:              l.LoanID, // public id
+			LoanState:           string(l.State),
+			Reason:              reason,
+			ValidatorEmployeeID: in.ValidatorEmployeeID,
+			RevokedAt:           l.StateUpdatedAt,
+		}
+		return nil
+	})
+
+	if err != nil {
+		// WithinLoanTx surfaces the raw lookup error when the loan row is missing
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+	return dto, nil
+}
@@ -3,15 +3,24 @@ package approval
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"amartha-backend-test/internal/domain/approval"
+	"amartha-backend-test/internal/domain/investment"
 	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/photo"
 	"amartha-backend-test/internal/domain/uow"
 	"amartha-backend-test/internal/testutil/approvalmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
 	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/photomock"
 	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/pkg/phash"
+	"amartha-backend-test/pkg/requestctx"
 
 	"gorm.io/gorm"
 )
@@ -20,10 +29,18 @@ func TestUsecase_Approve(t *testing.T) {
 	now := time.Date(2025, 9, 6, 10, 0, 0, 0, time.UTC)
 	in := ApproveInput{
 		LoanID:              "LN-123",
-		PhotoURL:            "https://img/x.jpg",
+		PhotoID:             "PH-1",
 		ValidatorEmployeeID: "EMP-9",
 		ApprovalDate:        now,
 	}
+	photos := &photomock.Repo{
+		GetByPhotoIDForUpdateFn: func(ctx context.Context, photoID string) (*photo.Photo, error) {
+			if photoID != "PH-1" {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return &photo.Photo{PhotoID: "PH-1", StorageKey: "photos/PH-1.jpg"}, nil
+		},
+	}
 
 	newProposedLoan := func() *loan.Loan {
 		return &loan.Loan{ID: 777, LoanID: "LN-123", State: loan.StateProposed}
@@ -54,7 +71,7 @@ func TestUsecase_Approve(t *testing.T) {
 						return nil, gorm.ErrRecordNotFound
 					},
 					CreateFn: func(ctx context.Context, a *approval.Approval) error {
-						if a.LoanID != 777 || a.PhotoURL != in.PhotoURL {
+						if a.LoanID != 777 || a.PhotoID == nil || *a.PhotoID != "PH-1" || a.PhotoURL != "photos/PH-1.jpg" {
 							t.Fatalf("approval mismatch: %+v", a)
 						}
 						return nil
@@ -62,7 +79,7 @@ func TestUsecase_Approve(t *testing.T) {
 				}
 				tx := &uowmock.UoW{
 					WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-						return fn(uow.Repos{Loans: loans, Approvals: apprs})
+						return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Photos: photos, Outbox: &outboxmock.Repo{}})
 					},
 				}
 				return NewUsecase(loans, apprs, tx)
@@ -75,9 +92,43 @@ func TestUsecase_Approve(t *testing.T) {
 				if dto.LoanID != "LN-123" {
 					return errors.New("dto LoanID mismatch")
 				}
+				if dto.PhotoID != "PH-1" || dto.PhotoURL != "photos/PH-1.jpg" {
+					return errors.New("dto photo mismatch")
+				}
 				return nil
 			},
 		},
+		{
+			name: "photo not issued by the store",
+			setup: func() *Usecase {
+				loans := &loanmock.Repo{
+					GetByLoanIDForUpdateFn: func(context.Context, string) (*loan.Loan, error) {
+						return newProposedLoan(), nil
+					},
+					SaveFn: func(context.Context, *loan.Loan) error {
+						t.Fatalf("loan saved despite unknown photo")
+						return nil
+					},
+				}
+				apprs := &approvalmock.Repo{
+					GetByLoanIDFn: func(context.Context, uint64) (*approval.Approval, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				}
+				unknown := &photomock.Repo{
+					GetByPhotoIDForUpdateFn: func(context.Context, string) (*photo.Photo, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				}
+				tx := &uowmock.UoW{
+					WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
+						return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Photos: unknown, Outbox: &outboxmock.Repo{}})
+					},
+				}
+				return NewUsecase(loans, apprs, tx)
+			},
+			wantErr: photo.ErrNotFound,
+		},
 		{
 			name: "loan not found",
 			setup: func() *Usecase {
@@ -89,7 +140,7 @@ func TestUsecase_Approve(t *testing.T) {
 				apprs := &approvalmock.Repo{}
 				tx := &uowmock.UoW{
 					WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-						return fn(uow.Repos{Loans: loans, Approvals: apprs})
+						return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Outbox: &outboxmock.Repo{}})
 					},
 				}
 				return NewUsecase(loans, apprs, tx)
@@ -107,7 +158,7 @@ func TestUsecase_Approve(t *testing.T) {
 				apprs := &approvalmock.Repo{}
 				tx := &uowmock.UoW{
 					WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-						return fn(uow.Repos{Loans: loans, Approvals: apprs})
+						return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Outbox: &outboxmock.Repo{}})
 					},
 				}
 				return NewUsecase(loans, apprs, tx)
@@ -129,7 +180,7 @@ func TestUsecase_Approve(t *testing.T) {
 				}
 				tx := &uowmock.UoW{
 					WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
-						return fn(uow.Repos{Loans: loans, Approvals: apprs})
+						return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Outbox: &outboxmock.Repo{}})
 					},
 				}
 				return NewUsecase(loans, apprs, tx)
@@ -165,3 +216,224 @@ func TestUsecase_Approve(t *testing.T) {
 		})
 	}
 }
+
+func anyPhoto(ctx context.Context, photoID string) (*photo.Photo, error) {
+	return &photo.Photo{PhotoID: photoID, StorageKey: "photos/" + photoID + ".jpg"}, nil
+}
+
+func TestUsecase_Approve_RecordsHistoryAndEvent(t *testing.T) {
+	const reqID = "0123456789abcdef0123456789abcdef"
+	var got *loan.StateTransition
+
+	loans := &loanmock.Repo{
+		GetByLoanIDForUpdateFn: func(ctx context.Context, loanID string) (*loan.Loan, error) {
+			return &loan.Loan{ID: 777, LoanID: loanID, State: loan.StateProposed}, nil
+		},
+	}
+	apprs := &approvalmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, id uint64) (*approval.Approval, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	history := &loanmock.HistoryRepo{
+		AppendFn: func(ctx context.Context, tr *loan.StateTransition) error {
+			got = tr
+			return nil
+		},
+	}
+	var event *outbox.Message
+	events := &outboxmock.Repo{
+		AppendFn: func(ctx context.Context, m *outbox.Message) error {
+			event = m
+			return nil
+		},
+	}
+	tx := &uowmock.UoW{
+		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
+			return fn(uow.Repos{Loans: loans, LoanHistory: history, Approvals: apprs, Photos: &photomock.Repo{GetByPhotoIDForUpdateFn: anyPhoto}, Outbox: events})
+		},
+	}
+
+	ctx := requestctx.WithRequestID(context.Background(), reqID)
+	if _, err := NewUsecase(loans, apprs, tx).Approve(ctx, ApproveInput{
+		LoanID:              "LN-1",
+		PhotoID:             "PH-1",
+		ValidatorEmployeeID: "EMP-9",
+		ApprovalDate:        time.Now(),
+	}); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if got == nil {
+		t.Fatalf("history row not appended")
+	}
+	if got.LoanID != 777 || got.FromState != loan.StateProposed || got.ToState != loan.StateApproved ||
+		got.Actor != "EMP-9" || got.RequestID != reqID {
+		t.Fatalf("unexpected history row: %+v", got)
+	}
+	if event == nil || event.EventType != outbox.LoanApproved || event.AggregateID != "LN-1" || !strings.Contains(event.Payload, `"state":"approved"`) {
+		t.Fatalf("unexpected outbox event: %+v", event)
+	}
+}
+
+func TestUsecase_Approve_HistoryErrorRollsBack(t *testing.T) {
+	sentinel := errors.New("audit insert failed")
+	loans := &loanmock.Repo{
+		GetByLoanIDForUpdateFn: func(ctx context.Context, loanID string) (*loan.Loan, error) {
+			return &loan.Loan{ID: 1, State: loan.StateProposed}, nil
+		},
+	}
+	apprs := &approvalmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, id uint64) (*approval.Approval, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	history := &loanmock.HistoryRepo{
+		AppendFn: func(context.Context, *loan.StateTransition) error { return sentinel },
+	}
+	tx := &uowmock.UoW{
+		WithinTxFn: func(ctx context.Context, fn func(r uow.Repos) error) error {
+			return fn(uow.Repos{Loans: loans, LoanHistory: history, Approvals: apprs, Photos: &photomock.Repo{GetByPhotoIDForUpdateFn: anyPhoto}, Outbox: &outboxmock.Repo{}})
+		},
+	}
+	if _, err := NewUsecase(loans, apprs, tx).Approve(context.Background(), ApproveInput{LoanID: "LN-1"}); !errors.Is(err, sentinel) {
+		t.Fatalf("want %v, got %v", sentinel, err)
+	}
+}
+
+func TestUsecase_Approve_DuplicatePhoto(t *testing.T) {
+	h := phash.Hash(0x5a5a)
+	// run approves LN-1 with a hashed photo, over approvals already holding
+	// existing (nil: none near it); it returns the error and what was created
+	run := func(existing *approval.Approval) (*approval.Approval, error) {
+		var created *approval.Approval
+		loans := &loanmock.Repo{
+			GetByLoanIDForUpdateFn: func(ctx context.Context, loanID string) (*loan.Loan, error) {
+				return &loan.Loan{ID: 1, LoanID: loanID, State: loan.StateProposed}, nil
+			},
+		}
+		apprs := &approvalmock.Repo{
+			GetByLoanIDFn: func(context.Context, uint64) (*approval.Approval, error) { return nil, gorm.ErrRecordNotFound },
+			FindNearPhotoFn: func(ctx context.Context, got phash.Hash, maxDistance int, exceptLoanID uint64) (*approval.Approval, error) {
+				if got != h || maxDistance != photo.NearDuplicateDistance || exceptLoanID != 1 {
+					t.Fatalf("FindNearPhoto(%x, %d, %d)", got, maxDistance, exceptLoanID)
+				}
+				if existing == nil {
+					return nil, gorm.ErrRecordNotFound
+				}
+				return existing, nil
+			},
+			CreateFn: func(ctx context.Context, a *approval.Approval) error { created = a; return nil },
+		}
+		photos := &photomock.Repo{
+			GetByPhotoIDForUpdateFn: func(ctx context.Context, id string) (*photo.Photo, error) {
+				return &photo.Photo{PhotoID: id, StorageKey: "photos/" + id + ".jpg", PHash: &h}, nil
+			},
+		}
+		tx := uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Approvals: apprs, Photos: photos, Outbox: &outboxmock.Repo{}})
+		})
+		_, err := NewUsecase(loans, apprs, tx).Approve(context.Background(), ApproveInput{
+			LoanID: "LN-1", PhotoID: "PH-2", ValidatorEmployeeID: "EMP-9", ApprovalDate: time.Now(),
+		})
+		return created, err
+	}
+
+	created, err := run(nil)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if created == nil || created.PhotoPHash == nil || *created.PhotoPHash != h {
+		t.Fatalf("hash not copied onto the approval: %+v", created)
+	}
+
+	created, err = run(&approval.Approval{ApprovalID: "APR-OTHER"})
+	if !errors.Is(err, approval.ErrDuplicatePhoto) || !strings.Contains(err.Error(), "APR-OTHER") {
+		t.Fatalf("want ErrDuplicatePhoto naming APR-OTHER, got %v", err)
+	}
+	if created != nil {
+		t.Fatalf("duplicate must not create an approval")
+	}
+}
+
+func TestUsecase_Revoke(t *testing.T) {
+	in := RevokeInput{LoanID: "LN-123", Reason: "  wrong loan after field visit ", ValidatorEmployeeID: "EMP-9"}
+	held := []investment.Investment{{InvestmentID: "IV-1", Status: investment.StatusHeld}}
+
+	// run revokes a loan in state s carrying invs, recording what was written
+	type writes struct {
+		saved    *loan.Loan
+		history  *loan.StateTransition
+		events   []*outbox.Message
+		deleteBy string
+	}
+	run := func(s loan.State, invs []investment.Investment, in RevokeInput) (*RevocationDTO, *writes, error) {
+		w := &writes{}
+		repos := uow.Repos{
+			Loans: &loanmock.Repo{SaveFn: func(ctx context.Context, l *loan.Loan) error { w.saved = l; return nil }},
+			LoanHistory: &loanmock.HistoryRepo{AppendFn: func(ctx context.Context, tr *loan.StateTransition) error {
+				w.history = tr
+				return nil
+			}},
+			Approvals: &approvalmock.Repo{
+				GetByLoanIDFn: func(ctx context.Context, id uint64) (*approval.Approval, error) {
+					return &approval.Approval{ApprovalID: "AP-1", LoanID: id}, nil
+				},
+				SoftDeleteByLoanIDFn: func(ctx context.Context, id uint64, by string) error { w.deleteBy = by; return nil },
+			},
+			Investments: &investmentmock.Repo{
+				ListByLoanIDFn: func(ctx context.Context, id uint64) ([]investment.Investment, error) { return invs, nil },
+			},
+			Outbox: &outboxmock.Repo{AppendFn: func(ctx context.Context, m *outbox.Message) error {
+				w.events = append(w.events, m)
+				return nil
+			}},
+		}
+		tx := uowmock.New().WithWithinLoanTx(func(ctx context.Context, loanID string, fn func(uow.Repos, *loan.Loan) error) error {
+			return fn(repos, &loan.Loan{ID: 777, LoanID: loanID, State: s})
+		})
+		dto, err := NewUsecase(nil, nil, tx).Revoke(context.Background(), in)
+		return dto, w, err
+	}
+
+	dto, w, err := run(loan.StateApproved, nil, in)
+	if err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if dto.ApprovalID != "AP-1" || dto.LoanState != "proposed" || dto.Reason != "wrong loan after field visit" {
+		t.Fatalf("dto: %+v", dto)
+	}
+	if w.saved == nil || w.saved.State != loan.StateProposed || w.deleteBy != "EMP-9" {
+		t.Fatalf("writes: %+v", w)
+	}
+	if w.history == nil || w.history.FromState != loan.StateApproved || w.history.Reason != "approval revoked: wrong loan after field visit" {
+		t.Fatalf("history: %+v", w.history)
+	}
+	if len(w.events) != 1 || w.events[0].EventType != outbox.LoanApprovalRevoked || w.events[0].AggregateID != in.LoanID {
+		t.Fatalf("events: %+v", w.events)
+	}
+
+	// an investment already placed blocks the revoke, and nothing is written
+	_, w, err = run(loan.StateApproved, held, in)
+	if !errors.Is(err, approval.ErrLoanHasFunding) || !errors.Is(err, loan.ErrInvalidTransition) {
+		t.Fatalf("funded: want ErrLoanHasFunding, got %v", err)
+	}
+	if w.saved != nil || w.deleteBy != "" || len(w.events) != 0 {
+		t.Fatalf("funded: wrote %+v", w)
+	}
+
+	for _, s := range []loan.State{loan.StateProposed, loan.StateInvested, loan.StateDisbursed} {
+		if _, _, err := run(s, nil, in); !errors.Is(err, loan.ErrInvalidTransition) {
+			t.Fatalf("%s: want ErrInvalidTransition, got %v", s, err)
+		}
+	}
+	if _, _, err := run(loan.StateApproved, nil, RevokeInput{LoanID: "LN-123", Reason: "  "}); !errors.Is(err, ErrReasonRequired) {
+		t.Fatalf("blank reason: want ErrReasonRequired, got %v", err)
+	}
+
+	missing := uowmock.New().WithWithinLoanTx(func(context.Context, string, func(uow.Repos, *loan.Loan) error) error {
+		return gorm.ErrRecordNotFound
+	})
+	if _, err := NewUsecase(nil, nil, missing).Revoke(context.Background(), in); !errors.Is(err, loan.ErrNotFound) {
+		t.Fatalf("missing: want ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package cancellation
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+type CancelInput struct {
+	LoanID      string
+	CancelledBy string // 32-char hex; stored in deleted_by
+}
+
+type RefundDTO struct {
+	InvestmentID string        `json:"investment_id"`
+	InvestorID   string        `json:"investor_id"`
+	Amount       money.Decimal `json:"amount"`
+}
+
+type CancellationDTO struct {
+	LoanID      string    `json:"loan_id"`
+	State       string    `json:"state"` // state the loan was cancelled in
+	CancelledBy string    `json:"cancelled_by"`
+	CancelledAt time.Time `json:"cancelled_at"`
+	// held investments given back to the investors' wallets
+	Refunds       []RefundDTO   `json:"refunds"`
+	RefundedTotal money.Decimal `json:"refunded_total"`
+}
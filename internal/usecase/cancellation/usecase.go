@@ -0,0 +1,111 @@
+package cancellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/pkg/money"
+	"amartha-backend-test/pkg/requestctx"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	uow uow.UnitOfWork
+}
+
+// NewUsecase: UoW for the locked cancel flow.
+func NewUsecase(tx uow.UnitOfWork) *Usecase { return &Usecase{uow: tx} }
+
+// Cancel moves a loan that has not been disbursed to cancelled,
+// soft-deletes its approval and releases every held investment back to its
+// investor's wallet. The history row and the
+// LoanCancelled event commit with it, or none of it does.
+func (u *Usecase) Cancel(ctx context.Context, in CancelInput) (*CancellationDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	var dto *CancellationDTO
+
+	err := u.uow.WithinLoanTx(ctx, in.LoanID, func(r uow.Repos, l *domainLoan.Loan) error {
+		// State guard: once money has left escrow the loan can only run its course
+		from := l.State
+		tr, err := domainLoan.Lifecycle.Transition(l, domainLoan.StateCancelled, domainLoan.TransitionMeta{
+			Actor:     in.CancelledBy,
+			Reason:    "cancelled",
+			RequestID: requestctx.RequestID(ctx),
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", domainLoan.ErrNotCancellable, err)
+		}
+		at := l.StateUpdatedAt
+
+		// Refund: reverse each hold out of escrow, then mark them released
+		invs, err := r.Investments.ListByLoanID(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		refunds := []RefundDTO{}
+		total := money.Zero
+		for i := range invs {
+			inv := &invs[i]
+			if inv.Status != domainInvestment.StatusHeld {
+				continue
+			}
+			if err := r.Ledger.Post(ctx, domainLedger.ForRelease(l, inv, at)); err != nil {
+				return err
+			}
+			refunds = append(refunds, RefundDTO{InvestmentID: inv.InvestmentID, InvestorID: inv.InvestorID, Amount: inv.Amount})
+			total = total.Add(inv.Amount)
+		}
+		if len(refunds) > 0 {
+			if err := r.Investments.UpdateStatusByLoanID(ctx, l.ID, domainInvestment.StatusHeld, domainInvestment.StatusReleased); err != nil {
+				return err
+			}
+		}
+
+		// Persist loan → cancelled with its audit row and event, then soft-delete
+		// the approval (no-op while proposed); the loan stays readable
+		if err := r.Loans.Save(ctx, l); err != nil {
+			return err
+		}
+		if err := r.LoanHistory.Append(ctx, tr); err != nil {
+			return err
+		}
+		ev, err := domainOutbox.ForLoan(domainOutbox.LoanCancelled, l)
+		if err != nil {
+			return err
+		}
+		if err := r.Outbox.Append(ctx, ev); err != nil {
+			return err
+		}
+		if err := r.Approvals.SoftDeleteByLoanID(ctx, l.ID, in.CancelledBy); err != nil {
+			return err
+		}
+
+		dto = &CancellationDTO{
+			LoanID:        l.LoanID, // public id
+			State:         string(from),
+			CancelledBy:   in.CancelledBy,
+			CancelledAt:   at,
+			Refunds:       refunds,
+			RefundedTotal: total,
+		}
+		return nil
+	})
+
+	if err != nil {
+		// WithinLoanTx surfaces the raw lookup error when the loan row is missing
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+	return dto, nil
+}
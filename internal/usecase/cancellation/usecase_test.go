@@ -0,0 +1,179 @@
+package cancellation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/approvalmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+const employee = "eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+
+// world records every write the cancel flow makes.
+type world struct {
+	posted          []*ledger.Entry
+	released        bool
+	approvalDeleted string // deleted_by
+	saved           loan.State
+	history         []*loan.StateTransition
+	events          []*outbox.Message
+	postErr         error
+}
+
+func (w *world) tx(l *loan.Loan, invs []investment.Investment) *uowmock.UoW {
+	repos := uow.Repos{
+		Loans: &loanmock.Repo{
+			SaveFn: func(ctx context.Context, l *loan.Loan) error {
+				w.saved = l.State
+				return nil
+			},
+		},
+		LoanHistory: &loanmock.HistoryRepo{AppendFn: func(ctx context.Context, tr *loan.StateTransition) error {
+			w.history = append(w.history, tr)
+			return nil
+		}},
+		Outbox: &outboxmock.Repo{AppendFn: func(ctx context.Context, m *outbox.Message) error {
+			w.events = append(w.events, m)
+			return nil
+		}},
+		Approvals: &approvalmock.Repo{
+			SoftDeleteByLoanIDFn: func(ctx context.Context, loanID uint64, by string) error {
+				w.approvalDeleted = by
+				return nil
+			},
+		},
+		Investments: &investmentmock.Repo{
+			ListByLoanIDFn: func(ctx context.Context, loanID uint64) ([]investment.Investment, error) { return invs, nil },
+			UpdateStatusByLoanIDFn: func(ctx context.Context, loanID uint64, from, to investment.Status) error {
+				w.released = from == investment.StatusHeld && to == investment.StatusReleased
+				return nil
+			},
+		},
+		Ledger: &ledgermock.Repo{
+			PostFn: func(ctx context.Context, e *ledger.Entry) error {
+				if w.postErr != nil {
+					return w.postErr
+				}
+				w.posted = append(w.posted, e)
+				return nil
+			},
+		},
+	}
+	return uowmock.New().WithWithinLoanTx(func(ctx context.Context, loanID string, fn func(uow.Repos, *loan.Loan) error) error {
+		return fn(repos, l)
+	})
+}
+
+func TestUsecase_Cancel_RefundsHeldInvestments(t *testing.T) {
+	l := &loan.Loan{ID: 7, LoanID: "LN-7", State: loan.StateInvested, Principal: money.NewFromInt(1_000_000)}
+	invs := []investment.Investment{
+		{InvestmentID: "IV-1", InvestorID: "A", Amount: money.NewFromInt(600_000), Status: investment.StatusHeld},
+		{InvestmentID: "IV-2", InvestorID: "B", Amount: money.NewFromInt(400_000), Status: investment.StatusHeld},
+		{InvestmentID: "IV-0", InvestorID: "C", Amount: money.NewFromInt(5), Status: investment.StatusReleased},
+	}
+	w := &world{}
+
+	dto, err := NewUsecase(w.tx(l, invs)).Cancel(context.Background(), CancelInput{LoanID: "LN-7", CancelledBy: employee})
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if len(dto.Refunds) != 2 || !dto.RefundedTotal.Equal(l.Principal) || dto.State != "invested" || dto.CancelledBy != employee {
+		t.Fatalf("dto: %+v", dto)
+	}
+	if !w.released || w.approvalDeleted != employee {
+		t.Fatalf("writes: %+v", w)
+	}
+
+	// the move goes through the lifecycle: state, audit row and event
+	if w.saved != loan.StateCancelled || l.State != loan.StateCancelled || !dto.CancelledAt.Equal(l.StateUpdatedAt) {
+		t.Fatalf("saved %s, loan %+v", w.saved, l)
+	}
+	if len(w.history) != 1 || w.history[0].LoanID != 7 || w.history[0].FromState != loan.StateInvested ||
+		w.history[0].ToState != loan.StateCancelled || w.history[0].Actor != employee {
+		t.Fatalf("history %+v", w.history)
+	}
+	if len(w.events) != 1 || w.events[0].EventType != outbox.LoanCancelled || w.events[0].AggregateID != "LN-7" {
+		t.Fatalf("events %+v", w.events)
+	}
+
+	// one release per held investment, and together they empty the escrow
+	if len(w.posted) != 2 {
+		t.Fatalf("posted %d entries, want 2", len(w.posted))
+	}
+	escrow := money.Zero
+	for _, e := range w.posted {
+		if e.Kind != ledger.KindRelease {
+			t.Fatalf("posted %s entry", e.Kind)
+		}
+		for _, ln := range e.Lines {
+			if ln.Account == ledger.AccountEscrow && ln.Direction == ledger.Debit {
+				escrow = escrow.Add(ln.Amount)
+			}
+		}
+	}
+	if !escrow.Equal(l.Principal) {
+		t.Fatalf("escrow debited %s, want %s", escrow, l.Principal)
+	}
+}
+
+func TestUsecase_Cancel_ProposedHasNothingToRefund(t *testing.T) {
+	w := &world{}
+	l := &loan.Loan{ID: 1, LoanID: "LN-1", State: loan.StateProposed}
+	dto, err := NewUsecase(w.tx(l, nil)).Cancel(context.Background(), CancelInput{LoanID: "LN-1", CancelledBy: employee})
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if len(dto.Refunds) != 0 || !dto.RefundedTotal.IsZero() || w.released || len(w.posted) != 0 || w.saved != loan.StateCancelled {
+		t.Fatalf("dto %+v, writes %+v", dto, w)
+	}
+}
+
+func TestUsecase_Cancel_Errors(t *testing.T) {
+	for _, s := range []loan.State{loan.StateDisbursed, loan.StateRepaid, loan.StateRejected} {
+		w := &world{}
+		l := &loan.Loan{ID: 1, LoanID: "LN-1", State: s}
+		if _, err := NewUsecase(w.tx(l, nil)).Cancel(context.Background(), CancelInput{LoanID: "LN-1"}); !errors.Is(err, loan.ErrNotCancellable) ||
+			!errors.Is(err, loan.ErrInvalidTransition) {
+			t.Fatalf("%s: want ErrNotCancellable, got %v", s, err)
+		}
+		if w.approvalDeleted != "" || w.saved != "" || len(w.history) != 0 || len(w.events) != 0 {
+			t.Fatalf("%s: wrote %+v", s, w)
+		}
+	}
+
+	// a failed ledger post aborts before anything is saved or deleted
+	boom := errors.New("boom")
+	w := &world{postErr: boom}
+	l := &loan.Loan{ID: 1, LoanID: "LN-1", State: loan.StateApproved}
+	invs := []investment.Investment{{InvestmentID: "IV-1", InvestorID: "A", Amount: money.NewFromInt(1), Status: investment.StatusHeld}}
+	if _, err := NewUsecase(w.tx(l, invs)).Cancel(context.Background(), CancelInput{LoanID: "LN-1"}); !errors.Is(err, boom) {
+		t.Fatalf("want boom, got %v", err)
+	}
+	if w.released || w.saved != "" || w.approvalDeleted != "" {
+		t.Fatalf("wrote after failure: %+v", w)
+	}
+
+	missing := uowmock.New().WithWithinLoanTx(func(context.Context, string, func(uow.Repos, *loan.Loan) error) error {
+		return gorm.ErrRecordNotFound
+	})
+	if _, err := NewUsecase(missing).Cancel(context.Background(), CancelInput{LoanID: "LN-X"}); !errors.Is(err, loan.ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	if _, err := NewUsecase(nil).Cancel(context.Background(), CancelInput{LoanID: "LN-X"}); !errors.Is(err, loan.ErrInvalidTransition) {
+		t.Fatalf("nil uow: want ErrInvalidTransition, got %v", err)
+	}
+}
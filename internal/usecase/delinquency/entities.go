@@ -0,0 +1,33 @@
+package delinquency
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+// RecomputeResult summarises one run of the daily DPD job.
+type RecomputeResult struct {
+	AsOf     string         `json:"as_of"` // YYYY-MM-DD
+	Loans    int            `json:"loans"`
+	Changed  int            `json:"changed"`
+	Failed   int            `json:"failed"`
+	ByBucket map[string]int `json:"by_bucket"`
+}
+
+type BucketDTO struct {
+	Bucket               string        `json:"bucket"`
+	Loans                int64         `json:"loans"`
+	OutstandingPrincipal money.Decimal `json:"outstanding_principal"`
+}
+
+// TKB90DTO follows OJK: TKB90 = 100% − TWP90, where TWP90 is the share of
+// outstanding principal more than 90 days past due.
+type TKB90DTO struct {
+	TotalOutstanding  money.Decimal `json:"total_outstanding"`
+	Outstanding90Plus money.Decimal `json:"outstanding_90_plus"`
+	TWP90             money.Decimal `json:"twp90"` // percent, 2 dp
+	TKB90             money.Decimal `json:"tkb90"` // percent, 2 dp
+	Buckets           []BucketDTO   `json:"buckets"`
+	GeneratedAt       time.Time     `json:"generated_at"`
+}
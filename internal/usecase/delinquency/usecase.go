@@ -0,0 +1,104 @@
+package delinquency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/money"
+)
+
+// batchSize bounds how many loans the daily job loads per query.
+const batchSize = 500
+
+var hundred = money.NewFromInt(100)
+
+type Usecase struct {
+	loanRepo     domainLoan.Repository
+	scheduleRepo domainRepayment.Repository
+	now          func() time.Time
+}
+
+func NewUsecase(loans domainLoan.Repository, schedules domainRepayment.Repository) *Usecase {
+	return &Usecase{loanRepo: loans, scheduleRepo: schedules, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// Recompute refreshes DPD and bucket of every disbursed loan as of asOf's
+// calendar date. A failing loan does not stop the run; its error is joined
+// into the returned error and counted in Failed.
+func (u *Usecase) Recompute(ctx context.Context, asOf time.Time) (*RecomputeResult, error) {
+	res := &RecomputeResult{AsOf: asOf.Format("2006-01-02"), ByBucket: map[string]int{}}
+	var errs []error
+	var after uint64
+	for {
+		loans, err := u.loanRepo.List(ctx, domainLoan.ListFilter{
+			State: domainLoan.StateDisbursed, AfterID: after, Limit: batchSize,
+		})
+		if err != nil {
+			return res, errors.Join(append(errs, err)...)
+		}
+		for i := range loans {
+			l := &loans[i]
+			bucket, changed, err := u.recomputeOne(ctx, l, asOf)
+			res.Loans++
+			if err != nil {
+				res.Failed++
+				errs = append(errs, fmt.Errorf("loan %s: %w", l.LoanID, err))
+				continue
+			}
+			res.ByBucket[string(bucket)]++
+			if changed {
+				res.Changed++
+			}
+		}
+		if len(loans) < batchSize {
+			break
+		}
+		after = loans[len(loans)-1].ID
+	}
+	return res, errors.Join(errs...)
+}
+
+func (u *Usecase) recomputeOne(ctx context.Context, l *domainLoan.Loan, asOf time.Time) (domainLoan.DPDBucket, bool, error) {
+	items, err := u.scheduleRepo.ListByLoanID(ctx, l.ID)
+	if err != nil {
+		return "", false, err
+	}
+	dpd := domainRepayment.DaysPastDue(items, asOf)
+	bucket := domainLoan.BucketForDPD(dpd)
+	if err := u.loanRepo.UpdateDelinquency(ctx, l.ID, uint16(dpd), bucket, asOf); err != nil {
+		return "", false, err
+	}
+	return bucket, l.DPDBucket != bucket, nil
+}
+
+// TKB90 reports the portfolio's TKB90 from the buckets stored by the last run.
+func (u *Usecase) TKB90(ctx context.Context) (*TKB90DTO, error) {
+	rows, err := u.scheduleRepo.ExposureByBucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byBucket := make(map[domainLoan.DPDBucket]domainRepayment.BucketExposure, len(rows))
+	for _, r := range rows {
+		byBucket[r.Bucket] = r
+	}
+
+	out := &TKB90DTO{GeneratedAt: u.now(), Buckets: make([]BucketDTO, 0, len(domainLoan.DPDBuckets))}
+	for _, b := range domainLoan.DPDBuckets {
+		e := byBucket[b]
+		out.Buckets = append(out.Buckets, BucketDTO{Bucket: string(b), Loans: e.Loans, OutstandingPrincipal: e.OutstandingPrincipal})
+		out.TotalOutstanding = out.TotalOutstanding.Add(e.OutstandingPrincipal)
+	}
+	out.Outstanding90Plus = byBucket[domainLoan.DPD90Plus].OutstandingPrincipal
+
+	// An empty portfolio has nothing in default
+	out.TWP90 = money.Zero
+	if out.TotalOutstanding.IsPositive() {
+		out.TWP90 = out.Outstanding90Plus.Mul(hundred).Div(out.TotalOutstanding, 2)
+	}
+	out.TKB90 = hundred.Sub(out.TWP90)
+	return out, nil
+}
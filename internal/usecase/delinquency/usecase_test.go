@@ -0,0 +1,132 @@
+package delinquency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/repaymentmock"
+	"amartha-backend-test/pkg/money"
+)
+
+func unpaid(due time.Time) []repayment.Installment {
+	return []repayment.Installment{{InstallmentNo: 1, DueDate: due, PrincipalDue: money.NewFromInt(100)}}
+}
+
+func TestRecompute_UpdatesEveryDisbursedLoan(t *testing.T) {
+	asOf := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	dues := map[uint64]time.Time{
+		1: asOf.AddDate(0, 0, 5),   // not yet due → current
+		2: asOf.AddDate(0, 0, -10), // 10 days late
+		3: asOf.AddDate(0, 0, -95), // 95 days late
+	}
+
+	var filters []loan.ListFilter
+	updated := map[uint64]loan.DPDBucket{}
+	loans := &loanmock.Repo{
+		ListFn: func(ctx context.Context, f loan.ListFilter) ([]loan.Loan, error) {
+			filters = append(filters, f)
+			if f.AfterID > 0 {
+				return nil, nil
+			}
+			return []loan.Loan{
+				{ID: 1, LoanID: "LN-1", DPDBucket: loan.DPDCurrent},
+				{ID: 2, LoanID: "LN-2", DPDBucket: loan.DPDCurrent},
+				{ID: 3, LoanID: "LN-3", DPDBucket: loan.DPD90Plus},
+			}, nil
+		},
+		UpdateDelinquencyFn: func(ctx context.Context, id uint64, dpd uint16, b loan.DPDBucket, at time.Time) error {
+			if !at.Equal(asOf) {
+				t.Fatalf("asOf not forwarded: %v", at)
+			}
+			updated[id] = b
+			return nil
+		},
+	}
+	schedules := &repaymentmock.Repo{
+		ListByLoanIDFn: func(ctx context.Context, id uint64) ([]repayment.Installment, error) { return unpaid(dues[id]), nil },
+	}
+
+	res, err := NewUsecase(loans, schedules).Recompute(context.Background(), asOf)
+	if err != nil {
+		t.Fatalf("Recompute: %v", err)
+	}
+	if len(filters) != 1 || filters[0].State != loan.StateDisbursed || filters[0].Limit != batchSize {
+		t.Fatalf("filters: %+v", filters)
+	}
+	if updated[1] != loan.DPDCurrent || updated[2] != loan.DPD1To30 || updated[3] != loan.DPD90Plus {
+		t.Fatalf("buckets: %+v", updated)
+	}
+	if res.Loans != 3 || res.Changed != 1 || res.Failed != 0 || res.AsOf != "2025-12-31" || res.ByBucket["1-30"] != 1 {
+		t.Fatalf("result: %+v", res)
+	}
+}
+
+func TestRecompute_ContinuesPastFailures(t *testing.T) {
+	boom := errors.New("schedule read failed")
+	loans := &loanmock.Repo{
+		ListFn: func(ctx context.Context, f loan.ListFilter) ([]loan.Loan, error) {
+			return []loan.Loan{{ID: 1, LoanID: "LN-1"}, {ID: 2, LoanID: "LN-2"}}, nil
+		},
+	}
+	schedules := &repaymentmock.Repo{
+		ListByLoanIDFn: func(ctx context.Context, id uint64) ([]repayment.Installment, error) {
+			if id == 1 {
+				return nil, boom
+			}
+			return nil, nil
+		},
+	}
+
+	res, err := NewUsecase(loans, schedules).Recompute(context.Background(), time.Now())
+	if !errors.Is(err, boom) {
+		t.Fatalf("want joined %v, got %v", boom, err)
+	}
+	if res.Loans != 2 || res.Failed != 1 || res.ByBucket["current"] != 1 {
+		t.Fatalf("result: %+v", res)
+	}
+}
+
+func TestRecompute_ListError(t *testing.T) {
+	_, err := NewUsecase(&loanmock.Repo{}, &repaymentmock.Repo{}).Recompute(context.Background(), time.Now())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
+
+func TestTKB90(t *testing.T) {
+	schedules := &repaymentmock.Repo{
+		ExposureByBucketFn: func(ctx context.Context) ([]repayment.BucketExposure, error) {
+			return []repayment.BucketExposure{
+				{Bucket: loan.DPDCurrent, Loans: 8, OutstandingPrincipal: money.NewFromInt(9_000_000)},
+				{Bucket: loan.DPD31To60, Loans: 1, OutstandingPrincipal: money.NewFromInt(500_000)},
+				{Bucket: loan.DPD90Plus, Loans: 1, OutstandingPrincipal: money.NewFromInt(250_000)},
+			}, nil
+		},
+	}
+	dto, err := NewUsecase(&loanmock.Repo{}, schedules).TKB90(context.Background())
+	if err != nil {
+		t.Fatalf("TKB90: %v", err)
+	}
+	// 250k / 9.75M = 2.564…% → TWP90 2.56, TKB90 97.44
+	if dto.TotalOutstanding.String() != "9750000" || dto.TWP90.String() != "2.56" || dto.TKB90.String() != "97.44" {
+		t.Fatalf("ratios: %+v", dto)
+	}
+	if len(dto.Buckets) != 5 || dto.Buckets[1].Bucket != "1-30" || dto.Buckets[1].Loans != 0 {
+		t.Fatalf("all buckets must be listed in order: %+v", dto.Buckets)
+	}
+}
+
+func TestTKB90_EmptyPortfolio(t *testing.T) {
+	schedules := &repaymentmock.Repo{
+		ExposureByBucketFn: func(ctx context.Context) ([]repayment.BucketExposure, error) { return nil, nil },
+	}
+	dto, err := NewUsecase(&loanmock.Repo{}, schedules).TKB90(context.Background())
+	if err != nil || dto.TKB90.String() != "100" || !dto.TWP90.IsZero() {
+		t.Fatalf("empty portfolio: %+v err=%v", dto, err)
+	}
+}
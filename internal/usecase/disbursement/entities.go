@@ -0,0 +1,31 @@
+package disbursement
+
+import (
+	"time"
+)
+
+type DisburseInput struct {
+	LoanID             string
+	SignedAgreementURL string
+	SignatureProvider  string
+	SignatureTxID      string
+	SignatureStatus    string     // must be SIGNED
+	SignedAt           *time.Time // optional; defaults to now
+	DocumentSHA256     string     // optional; 64-char hex
+	OfficerEmployeeID  string     // 32-char hex
+	DisbursementDate   time.Time  // date-only is fine; store .UTC()
+}
+
+type DisbursementDTO struct {
+	DisbursementID     string    `json:"disbursement_id"`
+	LoanID             string    `json:"loan_id"`
+	SignedAgreementURL string    `json:"signed_agreement_url"`
+	SignatureProvider  string    `json:"signature_provider"`
+	SignatureTxID      string    `json:"signature_tx_id"`
+	SignatureStatus    string    `json:"signature_status"`
+	SignedAt           time.Time `json:"signed_at"`
+	DocumentSHA256     string    `json:"document_sha256,omitempty"`
+	OfficerEmployeeID  string    `json:"officer_employee_id"`
+	DisbursedAt        time.Time `json:"disbursed_at"` // equals input date @ 00:00:00 UTC
+	Installments       int       `json:"installments"` // rows written to repayment_schedules
+}
@@ -0,0 +1,172 @@
+package disbursement
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainDisbursement "amartha-backend-test/internal/domain/disbursement"
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	domainSignature "amartha-backend-test/internal/domain/signature"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/requestctx"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	disbursementRepo domainDisbursement.Repository
+	uow              uow.UnitOfWork
+	untracked        bool
+}
+
+// NewUsecase: disbursements repo for plain reads, UoW for the locked disburse flow.
+func NewUsecase(disbursements domainDisbursement.Repository, tx uow.UnitOfWork) *Usecase {
+	return &Usecase{disbursementRepo: disbursements, uow: tx}
+}
+
+// WithUntrackedSignatures lets a loan with no envelope be disbursed on the
+// client's word that the agreement is signed, for agreements signed outside
+// the e-signature flow.
+func (u *Usecase) WithUntrackedSignatures(allow bool) *Usecase {
+	u.untracked = allow
+	return u
+}
+
+func (u *Usecase) Disburse(ctx context.Context, in DisburseInput) (*DisbursementDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	// Money only leaves once the borrower has signed the agreement
+	if domainDisbursement.SignatureStatus(in.SignatureStatus) != domainDisbursement.SignatureSigned {
+		return nil, domainDisbursement.ErrAgreementNotSigned
+	}
+	var dto *DisbursementDTO
+
+	err := u.uow.WithinLoanTx(ctx, in.LoanID, func(r uow.Repos, l *domainLoan.Loan) error {
+		// State guard: only invested → disbursed (ErrAlreadyDisbursed when repeated)
+		tr, err := domainLoan.Lifecycle.Transition(l, domainLoan.StateDisbursed, domainLoan.TransitionMeta{
+			Actor:     in.OfficerEmployeeID,
+			RequestID: requestctx.RequestID(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.Disbursements.GetByLoanID(ctx, l.ID); err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				// real query error → surface upward
+				return err
+			}
+		} else {
+			// found one → already disbursed (business rule)
+			return domainLoan.ErrAlreadyDisbursed
+		}
+
+		signedAt := time.Now().UTC()
+		if in.SignedAt != nil {
+			signedAt = in.SignedAt.UTC()
+		}
+		var docHash *string
+		if in.DocumentSHA256 != "" {
+			docHash = &in.DocumentSHA256
+		}
+		// The loan's latest envelope decides; the client's word only counts
+		// for loans signed outside the e-signature flow, when allowed
+		env, err := r.Signatures.GetLatestByLoanID(ctx, l.ID)
+		switch {
+		case err == nil:
+			if env.SignatureProvider != in.SignatureProvider || env.SignatureTxID != in.SignatureTxID {
+				return domainSignature.ErrEnvelopeMismatch
+			}
+			if env.SignatureStatus != domainSignature.StatusSigned {
+				return domainDisbursement.ErrAgreementNotSigned
+			}
+			signedAt, docHash = *env.SignedAt, env.DocumentSHA256
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		case !u.untracked:
+			return domainDisbursement.ErrAgreementNotSigned
+		}
+
+		// Insert disbursement
+		d := &domainDisbursement.Disbursement{
+			DisbursementID:     id.NewID32(),
+			LoanID:             l.ID, // numeric FK
+			SignedAgreementURL: in.SignedAgreementURL,
+			SignatureProvider:  in.SignatureProvider,
+			SignatureTxID:      in.SignatureTxID,
+			SignatureStatus:    domainDisbursement.SignatureSigned,
+			SignedAt:           &signedAt,
+			DocumentSHA256:     docHash,
+			OfficerEmployeeID:  in.OfficerEmployeeID,
+			DisbursementDate:   in.DisbursementDate.UTC(),
+		}
+		if err := r.Disbursements.Create(ctx, d); err != nil {
+			return err
+		}
+
+		// Installments start counting from the disbursement date
+		schedule, err := domainRepayment.BuildSchedule(l, d.DisbursementDate)
+		if err != nil {
+			return err
+		}
+		if err := r.Schedules.CreateSchedule(ctx, schedule); err != nil {
+			return err
+		}
+		l.OutstandingBalance = domainRepayment.Outstanding(schedule)
+		if err := r.Ledger.Post(ctx, domainLedger.ForDisbursement(l, d)); err != nil {
+			return err
+		}
+		// Held wallet funds are now a loan position
+		if err := r.Investments.UpdateStatusByLoanID(ctx, l.ID, domainInvestment.StatusHeld, domainInvestment.StatusConverted); err != nil {
+			return err
+		}
+
+		// Persist loan → disbursed, with its audit row and outbox event
+		if err := r.Loans.Save(ctx, l); err != nil {
+			return err
+		}
+		if err := r.LoanHistory.Append(ctx, tr); err != nil {
+			return err
+		}
+		ev, err := domainOutbox.ForLoan(domainOutbox.LoanDisbursed, l)
+		if err != nil {
+			return err
+		}
+		if err := r.Outbox.Append(ctx, ev); err != nil {
+			return err
+		}
+
+		dto = &DisbursementDTO{
+			DisbursementID:     d.DisbursementID,
+			LoanID:             l.LoanID, // public id
+			SignedAgreementURL: d.SignedAgreementURL,
+			SignatureProvider:  d.SignatureProvider,
+			SignatureTxID:      d.SignatureTxID,
+			SignatureStatus:    string(d.SignatureStatus),
+			SignedAt:           signedAt,
+			OfficerEmployeeID:  d.OfficerEmployeeID,
+			DisbursedAt:        d.DisbursementDate,
+			Installments:       len(schedule),
+		}
+		if docHash != nil {
+			dto.DocumentSHA256 = *docHash
+		}
+		return nil
+	})
+
+	if err != nil {
+		// WithinLoanTx surfaces the raw lookup error when the loan row is missing
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+	return dto, nil
+}
@@ -0,0 +1,391 @@
+package disbursement
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/disbursement"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/domain/signature"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/disbursementmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/repaymentmock"
+	"amartha-backend-test/internal/testutil/signaturemock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+// untracked is a signature repo that knows no envelope: the client's word
+// on the agreement is all there is.
+func untracked() *signaturemock.Repo {
+	return &signaturemock.Repo{
+		GetLatestByLoanIDFn: func(context.Context, uint64) (*signature.Envelope, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+}
+
+// tracked knows exactly env, as its loan's latest envelope.
+func tracked(env *signature.Envelope) *signaturemock.Repo {
+	return &signaturemock.Repo{
+		GetLatestByLoanIDFn: func(ctx context.Context, loanID uint64) (*signature.Envelope, error) {
+			if loanID != env.LoanID {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return env, nil
+		},
+	}
+}
+
+// signedEnvelope is loan 777's SIGNED envelope for fake/TX-1.
+func signedEnvelope() *signature.Envelope {
+	signedAt, hash := time.Date(2025, 9, 9, 8, 0, 0, 0, time.UTC), "ab12"
+	return &signature.Envelope{LoanID: 777, SignatureProvider: "fake", SignatureTxID: "TX-1",
+		SignatureStatus: signature.StatusSigned, SignedAt: &signedAt, DocumentSHA256: &hash}
+}
+
+func TestUsecase_Disburse(t *testing.T) {
+	now := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	signedIn := func() DisburseInput {
+		return DisburseInput{
+			LoanID:             "LN-123",
+			SignedAgreementURL: "https://docs/agreement-signed.pdf",
+			SignatureProvider:  "fake",
+			SignatureTxID:      "TX-1",
+			SignatureStatus:    string(disbursement.SignatureSigned),
+			DocumentSHA256:     "ab12",
+			OfficerEmployeeID:  "EMP-7",
+			DisbursementDate:   now,
+		}
+	}
+	newInvestedLoan := func() *loan.Loan {
+		return &loan.Loan{
+			ID: 777, LoanID: "LN-123", State: loan.StateInvested,
+			Principal: money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"),
+			Tenor: 4, TenorUnit: loan.TenorWeek,
+		}
+	}
+	lockedTx := func(l *loan.Loan, loans *loanmock.Repo, disbs *disbursementmock.Repo, sigs ...*signaturemock.Repo) *uowmock.UoW {
+		envelopes := tracked(signedEnvelope())
+		if len(sigs) > 0 {
+			envelopes = sigs[0]
+		}
+		return &uowmock.UoW{
+			WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *loan.Loan) error) error {
+				return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Disbursements: disbs, Schedules: &repaymentmock.Repo{}, Ledger: &ledgermock.Repo{}, Investments: &investmentmock.Repo{}, Signatures: envelopes, Outbox: &outboxmock.Repo{}}, l)
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		in      DisburseInput
+		setup   func() *Usecase
+		wantErr error
+		check   func(*DisbursementDTO) error
+	}{
+		{
+			name: "happy path invested -> disbursed",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				loans := &loanmock.Repo{
+					SaveFn: func(ctx context.Context, l *loan.Loan) error {
+						if l.State != loan.StateDisbursed {
+							t.Fatalf("expected state=disbursed, got %s", l.State)
+						}
+						return nil
+					},
+				}
+				disbs := &disbursementmock.Repo{
+					GetByLoanIDFn: func(ctx context.Context, id uint64) (*disbursement.Disbursement, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					CreateFn: func(ctx context.Context, d *disbursement.Disbursement) error {
+						if d.LoanID != 777 || d.SignatureStatus != disbursement.SignatureSigned || d.SignedAt == nil {
+							t.Fatalf("disbursement mismatch: %+v", d)
+						}
+						if d.DocumentSHA256 == nil || *d.DocumentSHA256 != "ab12" {
+							t.Fatalf("document hash not persisted: %+v", d.DocumentSHA256)
+						}
+						return nil
+					},
+				}
+				return NewUsecase(disbs, lockedTx(newInvestedLoan(), loans, disbs))
+			},
+			check: func(dto *DisbursementDTO) error {
+				if dto.LoanID != "LN-123" || !dto.DisbursedAt.Equal(now) || dto.Installments != 4 {
+					return errors.New("dto mismatch")
+				}
+				return nil
+			},
+		},
+		{
+			name: "agreement not signed",
+			in: func() DisburseInput {
+				in := signedIn()
+				in.SignatureStatus = string(disbursement.SignaturePending)
+				return in
+			}(),
+			setup:   func() *Usecase { return NewUsecase(nil, &uowmock.UoW{}) },
+			wantErr: disbursement.ErrAgreementNotSigned,
+		},
+		{
+			name: "tracked envelope supplies signed_at and hash",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				signedAt, hash := now.Add(-time.Hour), "cd34"
+				disbs := &disbursementmock.Repo{
+					GetByLoanIDFn: func(context.Context, uint64) (*disbursement.Disbursement, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					CreateFn: func(ctx context.Context, d *disbursement.Disbursement) error {
+						if !d.SignedAt.Equal(signedAt) || d.DocumentSHA256 == nil || *d.DocumentSHA256 != hash {
+							t.Fatalf("envelope facts not used: %+v", d)
+						}
+						return nil
+					},
+				}
+				env := &signature.Envelope{LoanID: 777, SignatureProvider: "fake", SignatureTxID: "TX-1",
+					SignatureStatus: signature.StatusSigned, SignedAt: &signedAt, DocumentSHA256: &hash}
+				return NewUsecase(disbs, lockedTx(newInvestedLoan(), &loanmock.Repo{}, disbs, tracked(env)))
+			},
+			check: func(dto *DisbursementDTO) error {
+				if dto.DocumentSHA256 != "cd34" || !dto.SignedAt.Equal(now.Add(-time.Hour)) {
+					return errors.New("dto should carry the envelope's hash and signed_at")
+				}
+				return nil
+			},
+		},
+		{
+			name: "tracked envelope still pending",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				disbs := &disbursementmock.Repo{
+					GetByLoanIDFn: func(context.Context, uint64) (*disbursement.Disbursement, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				}
+				env := &signature.Envelope{LoanID: 777, SignatureProvider: "fake", SignatureTxID: "TX-1", SignatureStatus: signature.StatusPending}
+				return NewUsecase(disbs, lockedTx(newInvestedLoan(), &loanmock.Repo{}, disbs, tracked(env)))
+			},
+			wantErr: disbursement.ErrAgreementNotSigned,
+		},
+		{
+			name: "request names another transaction",
+			in: func() DisburseInput {
+				in := signedIn()
+				in.SignatureTxID = "TX-made-up"
+				return in
+			}(),
+			setup: func() *Usecase {
+				disbs := &disbursementmock.Repo{
+					GetByLoanIDFn: func(context.Context, uint64) (*disbursement.Disbursement, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				}
+				return NewUsecase(disbs, lockedTx(newInvestedLoan(), &loanmock.Repo{}, disbs))
+			},
+			wantErr: signature.ErrEnvelopeMismatch,
+		},
+		{
+			name: "no envelope",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				disbs := &disbursementmock.Repo{
+					GetByLoanIDFn: func(context.Context, uint64) (*disbursement.Disbursement, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+					CreateFn: func(context.Context, *disbursement.Disbursement) error {
+						t.Fatalf("disbursed without a signed envelope")
+						return nil
+					},
+				}
+				return NewUsecase(disbs, lockedTx(newInvestedLoan(), &loanmock.Repo{}, disbs, untracked()))
+			},
+			wantErr: disbursement.ErrAgreementNotSigned,
+		},
+		{
+			name: "no envelope, untracked signatures allowed",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				disbs := &disbursementmock.Repo{
+					GetByLoanIDFn: func(context.Context, uint64) (*disbursement.Disbursement, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				}
+				return NewUsecase(disbs, lockedTx(newInvestedLoan(), &loanmock.Repo{}, disbs, untracked())).WithUntrackedSignatures(true)
+			},
+			check: func(dto *DisbursementDTO) error {
+				if dto.SignatureTxID != "TX-1" || dto.DocumentSHA256 != "ab12" {
+					return errors.New("dto should carry the client's signature facts")
+				}
+				return nil
+			},
+		},
+		{
+			name: "loan still approved",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				l := newInvestedLoan()
+				l.State = loan.StateApproved
+				return NewUsecase(nil, lockedTx(l, &loanmock.Repo{}, &disbursementmock.Repo{}))
+			},
+			wantErr: loan.ErrInvalidTransition,
+		},
+		{
+			name: "loan already disbursed",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				l := newInvestedLoan()
+				l.State = loan.StateDisbursed
+				return NewUsecase(nil, lockedTx(l, &loanmock.Repo{}, &disbursementmock.Repo{}))
+			},
+			wantErr: loan.ErrAlreadyDisbursed,
+		},
+		{
+			name: "disbursement row already exists",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				disbs := &disbursementmock.Repo{
+					GetByLoanIDFn: func(context.Context, uint64) (*disbursement.Disbursement, error) {
+						return &disbursement.Disbursement{DisbursementID: "EXIST"}, nil
+					},
+				}
+				return NewUsecase(disbs, lockedTx(newInvestedLoan(), &loanmock.Repo{}, disbs))
+			},
+			wantErr: loan.ErrAlreadyDisbursed,
+		},
+		{
+			name: "loan without tenor cannot get a schedule",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				l := newInvestedLoan()
+				l.Tenor = 0
+				disbs := &disbursementmock.Repo{
+					GetByLoanIDFn: func(context.Context, uint64) (*disbursement.Disbursement, error) {
+						return nil, gorm.ErrRecordNotFound
+					},
+				}
+				return NewUsecase(disbs, lockedTx(l, &loanmock.Repo{}, disbs))
+			},
+			wantErr: loan.ErrInvalidTenor,
+		},
+		{
+			name: "loan not found",
+			in:   signedIn(),
+			setup: func() *Usecase {
+				tx := &uowmock.UoW{
+					WithinLoanTxFn: func(context.Context, string, func(uow.Repos, *loan.Loan) error) error {
+						return gorm.ErrRecordNotFound
+					},
+				}
+				return NewUsecase(nil, tx)
+			},
+			wantErr: loan.ErrNotFound,
+		},
+		{
+			name:    "nil UoW",
+			in:      signedIn(),
+			setup:   func() *Usecase { return NewUsecase(nil, nil) },
+			wantErr: loan.ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			uc := tt.setup()
+			dto, err := uc.Disburse(context.Background(), tt.in)
+
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want err=%v, got %v", tt.wantErr, err)
+			}
+			if tt.check != nil && err == nil {
+				if cerr := tt.check(dto); cerr != nil {
+					t.Fatalf("dto check failed: %v", cerr)
+				}
+			}
+		})
+	}
+}
+
+func TestUsecase_Disburse_WritesSchedule(t *testing.T) {
+	disbursedOn := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	l := &loan.Loan{
+		ID: 777, LoanID: "LN-123", State: loan.StateInvested,
+		Principal: money.NewFromInt(1_000_000), Rate: money.NewFromInt(2),
+		Tenor: 2, TenorUnit: loan.TenorMonth, InterestMethod: loan.InterestFlat,
+	}
+	disbs := &disbursementmock.Repo{
+		GetByLoanIDFn: func(context.Context, uint64) (*disbursement.Disbursement, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+
+	var written []repayment.Installment
+	schedules := &repaymentmock.Repo{
+		CreateScheduleFn: func(ctx context.Context, items []repayment.Installment) error {
+			written = items
+			return nil
+		},
+	}
+	var appended []*outbox.Message
+	events := &outboxmock.Repo{AppendFn: func(ctx context.Context, m *outbox.Message) error { appended = append(appended, m); return nil }}
+	tx := &uowmock.UoW{
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *loan.Loan) error) error {
+			return fn(uow.Repos{Loans: &loanmock.Repo{}, LoanHistory: &loanmock.HistoryRepo{}, Disbursements: disbs, Schedules: schedules, Ledger: &ledgermock.Repo{}, Investments: &investmentmock.Repo{}, Signatures: tracked(signedEnvelope()), Outbox: events}, l)
+		},
+	}
+
+	_, err := NewUsecase(disbs, tx).Disburse(context.Background(), DisburseInput{
+		LoanID:            "LN-123",
+		SignatureProvider: "fake",
+		SignatureTxID:     "TX-1",
+		SignatureStatus:   string(disbursement.SignatureSigned),
+		OfficerEmployeeID: "EMP-7",
+		DisbursementDate:  disbursedOn,
+	})
+	if err != nil {
+		t.Fatalf("Disburse: %v", err)
+	}
+	if len(written) != 2 || written[0].LoanID != 777 {
+		t.Fatalf("schedule not written: %+v", written)
+	}
+	if got := written[0].DueDate.Format("2006-01-02"); got != "2025-10-10" {
+		t.Fatalf("first due date = %s, want 2025-10-10", got)
+	}
+	if written[1].TotalDue.String() != "520000" {
+		t.Fatalf("installment 2 total = %s, want 520000", written[1].TotalDue)
+	}
+	if l.OutstandingBalance.String() != "1040000" {
+		t.Fatalf("outstanding balance = %s, want 1040000", l.OutstandingBalance)
+	}
+	if len(appended) != 1 || appended[0].EventType != outbox.LoanDisbursed || !strings.Contains(appended[0].Payload, `"state":"disbursed"`) {
+		t.Fatalf("want one LoanDisbursed event, got %+v", appended)
+	}
+
+	// A failing schedule insert must fail the whole disbursement
+	boom := errors.New("insert failed")
+	schedules.CreateScheduleFn = func(context.Context, []repayment.Installment) error { return boom }
+	l.State = loan.StateInvested
+	if _, err := NewUsecase(disbs, tx).Disburse(context.Background(), DisburseInput{
+		LoanID: "LN-123", SignatureProvider: "fake", SignatureTxID: "TX-1",
+		SignatureStatus: string(disbursement.SignatureSigned), DisbursementDate: disbursedOn,
+	}); !errors.Is(err, boom) {
+		t.Fatalf("want %v, got %v", boom, err)
+	}
+}
@@ -0,0 +1,77 @@
+// Package dispatch drains a table of due rows for the outbox relay and the
+// webhook and notification dispatchers. Rows are claimed in a short
+// transaction and sent outside it, so a slow receiver never holds row locks.
+package dispatch
+
+import (
+	"context"
+	"time"
+
+	"amartha-backend-test/internal/domain/uow"
+)
+
+const (
+	// BatchSize bounds how many rows one claim takes when Queue.Batch is 0.
+	BatchSize = 20
+	// Lease is how long a claimed row is hidden from other runs; a crashed
+	// run's rows come back after it.
+	Lease = 5 * time.Minute
+)
+
+// Queue is one table of rows waiting to be sent.
+type Queue[T any] struct {
+	// Batch is how many rows one claim takes (0 means BatchSize).
+	Batch int
+	// ListDue lists up to limit rows due at now, locked by r's transaction.
+	ListDue func(ctx context.Context, r uow.Repos, now time.Time, limit int) ([]T, error)
+	// Claim saves row with its next attempt moved to until, in r's transaction.
+	Claim func(ctx context.Context, r uow.Repos, row *T, until time.Time) error
+	// Send tries row once and records the outcome on it; an error stops the run.
+	Send func(ctx context.Context, row *T) error
+	// Save persists row after Send.
+	Save func(ctx context.Context, row *T) error
+}
+
+// Run sends every row of q due at now, one claimed batch at a time, until a
+// batch comes back short. Sent rows are rescheduled past now or settled by
+// Send, so the next claim never sees them again; rows left unsent when ctx
+// ends come back once their lease does.
+func Run[T any](ctx context.Context, tx uow.UnitOfWork, now time.Time, q Queue[T]) error {
+	limit := q.Batch
+	if limit <= 0 {
+		limit = BatchSize
+	}
+	for {
+		var batch []T
+		err := tx.WithinTx(ctx, func(r uow.Repos) error {
+			var err error
+			batch, err = q.ListDue(ctx, r, now, limit)
+			if err != nil {
+				return err
+			}
+			for i := range batch {
+				if err := q.Claim(ctx, r, &batch[i], now.Add(Lease)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := q.Send(ctx, &batch[i]); err != nil {
+				return err
+			}
+			if err := q.Save(ctx, &batch[i]); err != nil {
+				return err
+			}
+		}
+		if len(batch) < limit {
+			return nil
+		}
+	}
+}
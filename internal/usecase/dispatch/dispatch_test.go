@@ -0,0 +1,125 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/uowmock"
+)
+
+// row is a minimal queued item; done rows are never due again.
+type row struct {
+	id   int
+	next time.Time
+	done bool
+}
+
+// queue is an in-memory table that records whether a transaction is open
+// while rows are sent.
+type queue struct {
+	rows  []*row
+	inTx  bool
+	sent  []int
+	fail  error
+	saved int
+}
+
+func newQueue(n int, due time.Time) *queue {
+	q := &queue{}
+	for i := 0; i < n; i++ {
+		q.rows = append(q.rows, &row{id: i, next: due})
+	}
+	return q
+}
+
+func (q *queue) tx() *uowmock.UoW {
+	return uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+		q.inTx = true
+		defer func() { q.inTx = false }()
+		return fn(uow.Repos{})
+	})
+}
+
+func (q *queue) spec(t *testing.T, batch int) Queue[row] {
+	put := func(r *row) {
+		for _, x := range q.rows {
+			if x.id == r.id {
+				*x = *r
+			}
+		}
+	}
+	return Queue[row]{
+		Batch: batch,
+		ListDue: func(ctx context.Context, _ uow.Repos, now time.Time, limit int) ([]row, error) {
+			var out []row
+			for _, r := range q.rows {
+				if !r.done && !r.next.After(now) && len(out) < limit {
+					out = append(out, *r)
+				}
+			}
+			return out, nil
+		},
+		Claim: func(ctx context.Context, _ uow.Repos, r *row, until time.Time) error {
+			r.next = until
+			put(r)
+			return nil
+		},
+		Send: func(ctx context.Context, r *row) error {
+			if q.inTx {
+				t.Fatalf("row %d sent inside the claiming transaction", r.id)
+			}
+			if q.fail != nil {
+				return q.fail
+			}
+			q.sent = append(q.sent, r.id)
+			r.done = true
+			return nil
+		},
+		Save: func(ctx context.Context, r *row) error {
+			q.saved++
+			put(r)
+			return nil
+		},
+	}
+}
+
+func TestRun_DrainsInBatchesOutsideTheClaim(t *testing.T) {
+	now := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	q := newQueue(BatchSize+3, now)
+	q.rows = append(q.rows, &row{id: 99, next: now.Add(time.Minute)})
+
+	if err := Run(context.Background(), q.tx(), now, q.spec(t, 0)); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(q.sent) != BatchSize+3 || q.saved != BatchSize+3 {
+		t.Fatalf("sent %d, saved %d", len(q.sent), q.saved)
+	}
+	if last := q.rows[len(q.rows)-1]; last.done || !last.next.Equal(now.Add(time.Minute)) {
+		t.Fatalf("not yet due must be left alone: %+v", last)
+	}
+}
+
+func TestRun_StopsAndLeavesClaimsLeased(t *testing.T) {
+	now := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	// a failing send stops the run; the claimed row stays hidden until its lease ends
+	q := newQueue(2, now)
+	q.fail = errors.New("lookup failed")
+	if err := Run(context.Background(), q.tx(), now, q.spec(t, 5)); !errors.Is(err, q.fail) {
+		t.Fatalf("want %v, got %v", q.fail, err)
+	}
+	if q.saved != 0 || !q.rows[1].next.Equal(now.Add(Lease)) {
+		t.Fatalf("claims: saved %d, row %+v", q.saved, q.rows[1])
+	}
+
+	// a cancelled run claims but sends nothing
+	q = newQueue(1, now)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Run(ctx, q.tx(), now, q.spec(t, 5)); !errors.Is(err, context.Canceled) || len(q.sent) != 0 {
+		t.Fatalf("cancelled: %v, sent %v", err, q.sent)
+	}
+}
@@ -0,0 +1,26 @@
+package investment
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+type InvestInput struct {
+	LoanID     string
+	InvestorID string // 32-char hex
+	// InvestorEmail receives the agreement link once the loan is fully
+	// invested; optional
+	InvestorEmail string
+	Amount        money.Decimal
+}
+
+type InvestmentDTO struct {
+	InvestmentID  string        `json:"investment_id"`
+	LoanID        string        `json:"loan_id"`
+	InvestorID    string        `json:"investor_id"`
+	Amount        money.Decimal `json:"amount"`
+	TotalInvested money.Decimal `json:"total_invested"`
+	LoanState     string        `json:"loan_state"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
@@ -0,0 +1,169 @@
+package investment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"amartha-backend-test/internal/domain/agreement"
+	"amartha-backend-test/internal/domain/blob"
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	domainWallet "amartha-backend-test/internal/domain/wallet"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/requestctx"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	investmentRepo domainInvestment.Repository
+	uow            uow.UnitOfWork
+	agreements     blob.Store
+}
+
+// NewUsecase: investments repo for plain reads, UoW for the locked invest flow.
+func NewUsecase(investments domainInvestment.Repository, tx uow.UnitOfWork) *Usecase {
+	return &Usecase{investmentRepo: investments, uow: tx}
+}
+
+// WithAgreements renders the loan agreement into store when an investment
+// makes the loan invested.
+func (u *Usecase) WithAgreements(store blob.Store) *Usecase {
+	u.agreements = store
+	return u
+}
+
+func (u *Usecase) Invest(ctx context.Context, in InvestInput) (*InvestmentDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	if !in.Amount.IsPositive() {
+		return nil, domainInvestment.ErrInvalidAmount
+	}
+	var dto *InvestmentDTO
+
+	err := u.uow.WithinLoanTx(ctx, in.LoanID, func(r uow.Repos, l *domainLoan.Loan) error {
+		// State guard: only loans that can still move to invested accept investments
+		if err := domainLoan.Lifecycle.CheckEdge(l, domainLoan.StateInvested); err != nil {
+			return err
+		}
+
+		// Funds raised so far = the loan's escrow balance
+		invested, err := r.Ledger.Balance(ctx, domainLedger.Escrow(l.LoanID))
+		if err != nil {
+			return err
+		}
+		total := invested.Add(in.Amount)
+		if total.GreaterThan(l.Principal) {
+			return domainInvestment.ErrExceedsPrincipal
+		}
+
+		// Funds come from the investor's wallet; lock it so parallel investments
+		// on other loans cannot spend the same balance
+		wallet := domainLedger.InvestorWallet(in.InvestorID)
+		if err := r.Ledger.Lock(ctx, wallet); err != nil {
+			return err
+		}
+		available, err := r.Ledger.Balance(ctx, wallet)
+		if err != nil {
+			return err
+		}
+		if err := domainWallet.CheckFunds(in.InvestorID, available, in.Amount); err != nil {
+			return err
+		}
+
+		// Insert investment
+		inv := &domainInvestment.Investment{
+			InvestmentID: id.NewID32(),
+			LoanID:       l.ID, // numeric FK
+			InvestorID:   in.InvestorID,
+			Amount:       in.Amount,
+			Status:       domainInvestment.StatusHeld,
+		}
+		if in.InvestorEmail != "" {
+			inv.InvestorEmail = &in.InvestorEmail
+		}
+		if err := r.Investments.Create(ctx, inv); err != nil {
+			return err
+		}
+		if err := r.Ledger.Post(ctx, domainLedger.ForInvestment(l, inv, time.Now())); err != nil {
+			return err
+		}
+
+		// Fully funded → invested; the edge's guard keeps it approved until then
+		tr, err := domainLoan.Lifecycle.Transition(l, domainLoan.StateInvested, domainLoan.TransitionMeta{
+			Actor:     in.InvestorID,
+			Reason:    "fully funded",
+			RequestID: requestctx.RequestID(ctx),
+			Facts:     domainLoan.Facts{Invested: total},
+		})
+		if err != nil && !errors.Is(err, domainLoan.ErrNotFullyFunded) {
+			return err
+		}
+		if tr != nil {
+			if u.agreements != nil {
+				if err := u.writeAgreement(ctx, r, l); err != nil {
+					return err
+				}
+			}
+			if err := r.Loans.Save(ctx, l); err != nil {
+				return err
+			}
+			if err := r.LoanHistory.Append(ctx, tr); err != nil {
+				return err
+			}
+			ev, err := domainOutbox.ForLoan(domainOutbox.LoanInvested, l)
+			if err != nil {
+				return err
+			}
+			if err := r.Outbox.Append(ctx, ev); err != nil {
+				return err
+			}
+		}
+
+		dto = &InvestmentDTO{
+			InvestmentID:  inv.InvestmentID,
+			LoanID:        l.LoanID, // public id
+			InvestorID:    inv.InvestorID,
+			Amount:        inv.Amount,
+			TotalInvested: total,
+			LoanState:     string(l.State),
+			CreatedAt:     inv.CreatedAt,
+		}
+		return nil
+	})
+
+	if err != nil {
+		// WithinLoanTx surfaces the raw lookup error when the loan row is missing
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+	return dto, nil
+}
+
+// writeAgreement renders the agreement from the current template with the
+// loan's lenders (including the investment just placed), stores it, and
+// records its key, template version and hash on l.
+func (u *Usecase) writeAgreement(ctx context.Context, r uow.Repos, l *domainLoan.Loan) error {
+	invs, err := r.Investments.ListByLoanID(ctx, l.ID)
+	if err != nil {
+		return err
+	}
+	doc, err := agreement.Render(agreement.CurrentVersion, agreement.TermsFor(l, invs, time.Now()))
+	if err != nil {
+		return err
+	}
+	key := agreement.Key(l.LoanID, doc.Version)
+	if err := u.agreements.Put(ctx, key, bytes.NewReader(doc.Body), int64(len(doc.Body)), agreement.ContentType); err != nil {
+		return err
+	}
+	l.AgreementLink, l.AgreementVersion, l.AgreementSHA256 = key, doc.Version, doc.SHA256
+	return nil
+}
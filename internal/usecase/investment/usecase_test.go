@@ -0,0 +1,339 @@
+package investment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"amartha-backend-test/internal/domain/agreement"
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/domain/wallet"
+	"amartha-backend-test/internal/testutil/blobmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+func TestUsecase_Invest(t *testing.T) {
+	const investorID = "iiiiiiiiiiiiiiiiiiiiiiiiiiiiiiii"
+
+	newApprovedLoan := func() *loan.Loan {
+		return &loan.Loan{ID: 777, LoanID: "LN-123", Principal: money.NewFromInt(5_000_000), State: loan.StateApproved}
+	}
+
+	// lockedTx feeds l into WithinLoanTx together with the given repos.
+	lockedTx := func(l *loan.Loan, loans *loanmock.Repo, invs *investmentmock.Repo, books *ledgermock.Repo) *uowmock.UoW {
+		return &uowmock.UoW{
+			WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *loan.Loan) error) error {
+				return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Investments: invs, Ledger: books, Outbox: &outboxmock.Repo{}}, l)
+			},
+		}
+	}
+	// escrow reports sum as the loan's funded amount, a well-funded investor wallet,
+	// and collects postings into posted.
+	escrow := func(sum string, posted *[]*ledger.Entry) *ledgermock.Repo {
+		return &ledgermock.Repo{
+			BalanceFn: func(ctx context.Context, a ledger.Account) (money.Decimal, error) {
+				switch a {
+				case ledger.Escrow("LN-123"):
+					return money.MustParse(sum), nil
+				case ledger.InvestorWallet(investorID):
+					return money.NewFromInt(100_000_000), nil
+				}
+				t.Fatalf("balance of unexpected account %+v", a)
+				return money.Zero, nil
+			},
+			PostFn: func(ctx context.Context, e *ledger.Entry) error {
+				if posted != nil {
+					*posted = append(*posted, e)
+				}
+				return nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		amount  string
+		setup   func() *Usecase
+		wantErr error
+		check   func(*InvestmentDTO) error
+	}{
+		{
+			name:   "partial investment keeps loan approved",
+			amount: "1000000",
+			setup: func() *Usecase {
+				loans := &loanmock.Repo{
+					SaveFn: func(ctx context.Context, l *loan.Loan) error {
+						t.Fatalf("Save must not be called on partial funding")
+						return nil
+					},
+				}
+				var posted []*ledger.Entry
+				t.Cleanup(func() {
+					if len(posted) != 1 || posted[0].Kind != ledger.KindInvestment || len(posted[0].Lines) != 2 {
+						t.Fatalf("expected one investment posting, got %+v", posted)
+					}
+				})
+				invs := &investmentmock.Repo{
+					CreateFn: func(ctx context.Context, i *investment.Investment) error {
+						if i.LoanID != 777 || i.InvestorID != investorID || i.Status != investment.StatusHeld || !i.Amount.Equal(money.NewFromInt(1_000_000)) ||
+							i.InvestorEmail == nil || *i.InvestorEmail != "investor@example.com" {
+							t.Fatalf("investment mismatch: %+v", i)
+						}
+						return nil
+					},
+				}
+				return NewUsecase(invs, lockedTx(newApprovedLoan(), loans, invs, escrow("2000000", &posted)))
+			},
+			check: func(dto *InvestmentDTO) error {
+				if !dto.TotalInvested.Equal(money.NewFromInt(3_000_000)) || dto.LoanState != string(loan.StateApproved) {
+					return errors.New("unexpected totals/state")
+				}
+				return nil
+			},
+		},
+		{
+			name:   "final investment flips loan to invested",
+			amount: "0.10",
+			setup: func() *Usecase {
+				loans := &loanmock.Repo{
+					SaveFn: func(ctx context.Context, l *loan.Loan) error {
+						if l.State != loan.StateInvested || l.StateUpdatedAt.IsZero() {
+							t.Fatalf("expected invested with timestamp, got %+v", l)
+						}
+						return nil
+					},
+				}
+				invs := &investmentmock.Repo{}
+				// exact decimal sum must match principal to the cent
+				return NewUsecase(invs, lockedTx(newApprovedLoan(), loans, invs, escrow("4999999.90", nil)))
+			},
+			check: func(dto *InvestmentDTO) error {
+				if dto.LoanState != string(loan.StateInvested) || !dto.TotalInvested.Equal(money.NewFromInt(5_000_000)) {
+					return errors.New("loan not invested")
+				}
+				return nil
+			},
+		},
+		{
+			name:   "amount above remaining principal",
+			amount: "3000001",
+			setup: func() *Usecase {
+				invs := &investmentmock.Repo{
+					CreateFn: func(ctx context.Context, i *investment.Investment) error {
+						t.Fatalf("Create must not be called when exceeding principal")
+						return nil
+					},
+				}
+				return NewUsecase(invs, lockedTx(newApprovedLoan(), &loanmock.Repo{}, invs, escrow("2000000", nil)))
+			},
+			wantErr: investment.ErrExceedsPrincipal,
+		},
+		{
+			name:   "insufficient wallet balance",
+			amount: "1000000",
+			setup: func() *Usecase {
+				locked := false
+				books := &ledgermock.Repo{
+					LockFn: func(ctx context.Context, a ledger.Account) error {
+						locked = a == ledger.InvestorWallet(investorID)
+						return nil
+					},
+					BalanceFn: func(ctx context.Context, a ledger.Account) (money.Decimal, error) {
+						if a.Type == ledger.AccountInvestorWallet {
+							if !locked {
+								t.Fatalf("wallet read before it was locked")
+							}
+							return money.MustParse("999999.99"), nil
+						}
+						return money.Zero, nil
+					},
+					PostFn: func(ctx context.Context, e *ledger.Entry) error {
+						t.Fatalf("nothing may be posted without funds")
+						return nil
+					},
+				}
+				invs := &investmentmock.Repo{
+					CreateFn: func(ctx context.Context, i *investment.Investment) error {
+						t.Fatalf("Create must not be called without funds")
+						return nil
+					},
+				}
+				return NewUsecase(invs, lockedTx(newApprovedLoan(), &loanmock.Repo{}, invs, books))
+			},
+			wantErr: wallet.ErrInsufficientBalance,
+		},
+		{
+			name:   "loan not approved",
+			amount: "1000",
+			setup: func() *Usecase {
+				l := newApprovedLoan()
+				l.State = loan.StateProposed
+				return NewUsecase(nil, lockedTx(l, &loanmock.Repo{}, &investmentmock.Repo{}, &ledgermock.Repo{}))
+			},
+			wantErr: loan.ErrInvalidTransition,
+		},
+		{
+			name:   "loan not found",
+			amount: "1000",
+			setup: func() *Usecase {
+				tx := &uowmock.UoW{
+					WithinLoanTxFn: func(context.Context, string, func(uow.Repos, *loan.Loan) error) error {
+						return gorm.ErrRecordNotFound
+					},
+				}
+				return NewUsecase(nil, tx)
+			},
+			wantErr: loan.ErrNotFound,
+		},
+		{
+			name:   "escrow balance error",
+			amount: "1000",
+			setup: func() *Usecase {
+				invs := &investmentmock.Repo{}
+				// default Balance → context.Canceled
+				return NewUsecase(invs, lockedTx(newApprovedLoan(), &loanmock.Repo{}, invs, &ledgermock.Repo{}))
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name:    "non-positive amount",
+			amount:  "0",
+			setup:   func() *Usecase { return NewUsecase(nil, &uowmock.UoW{}) },
+			wantErr: investment.ErrInvalidAmount,
+		},
+		{
+			name:    "nil UoW",
+			amount:  "1000",
+			setup:   func() *Usecase { return NewUsecase(nil, nil) },
+			wantErr: loan.ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			uc := tt.setup()
+			dto, err := uc.Invest(context.Background(), InvestInput{
+				LoanID:        "LN-123",
+				InvestorID:    investorID,
+				InvestorEmail: "investor@example.com",
+				Amount:        money.MustParse(tt.amount),
+			})
+
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want err=%v, got %v", tt.wantErr, err)
+			}
+			if tt.check != nil && err == nil {
+				if cerr := tt.check(dto); cerr != nil {
+					t.Fatalf("dto check failed: %v", cerr)
+				}
+			}
+		})
+	}
+}
+
+func TestUsecase_Invest_WritesAgreement(t *testing.T) {
+	const investorID = "iiiiiiiiiiiiiiiiiiiiiiiiiiiiiiii"
+	l := &loan.Loan{ID: 777, LoanID: "LN-123", BorrowerID: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		Principal: money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"), Tenor: 4, TenorUnit: loan.TenorWeek,
+		State: loan.StateApproved}
+
+	var placed []investment.Investment
+	invs := &investmentmock.Repo{
+		CreateFn: func(ctx context.Context, i *investment.Investment) error {
+			placed = append(placed, *i)
+			return nil
+		},
+		ListByLoanIDFn: func(ctx context.Context, loanID uint64) ([]investment.Investment, error) {
+			prior := investment.Investment{InvestorID: "jjjjjjjjjjjjjjjjjjjjjjjjjjjjjjjj", Amount: money.NewFromInt(4_000_000), Status: investment.StatusHeld}
+			return append([]investment.Investment{prior}, placed...), nil
+		},
+	}
+	books := &ledgermock.Repo{
+		BalanceFn: func(ctx context.Context, a ledger.Account) (money.Decimal, error) {
+			if a == ledger.Escrow("LN-123") {
+				return money.NewFromInt(4_000_000), nil
+			}
+			return money.NewFromInt(100_000_000), nil
+		},
+	}
+	var saved *loan.Loan
+	loans := &loanmock.Repo{SaveFn: func(ctx context.Context, l *loan.Loan) error { saved = l; return nil }}
+	stored := map[string][]byte{}
+	store := &blobmock.Store{
+		PutFn: func(ctx context.Context, key string, r io.Reader, size int64, ct string) error {
+			b, _ := io.ReadAll(r)
+			if int64(len(b)) != size || ct != agreement.ContentType {
+				t.Fatalf("put %s: %d bytes declared %d, type %s", key, len(b), size, ct)
+			}
+			stored[key] = b
+			return nil
+		},
+	}
+	var appended []*outbox.Message
+	events := &outboxmock.Repo{AppendFn: func(ctx context.Context, m *outbox.Message) error { appended = append(appended, m); return nil }}
+	tx := &uowmock.UoW{
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *loan.Loan) error) error {
+			return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Investments: invs, Ledger: books, Outbox: events}, l)
+		},
+	}
+	uc := NewUsecase(invs, tx).WithAgreements(store)
+
+	if _, err := uc.Invest(context.Background(), InvestInput{LoanID: "LN-123", InvestorID: investorID, Amount: money.NewFromInt(1_000_000)}); err != nil {
+		t.Fatalf("Invest: %v", err)
+	}
+	if saved == nil || saved.State != loan.StateInvested {
+		t.Fatalf("loan not saved as invested: %+v", saved)
+	}
+	if len(appended) != 1 || appended[0].EventType != outbox.LoanInvested || appended[0].AggregateID != "LN-123" {
+		t.Fatalf("want one LoanInvested event, got %+v", appended)
+	}
+	doc := stored[saved.AgreementLink]
+	if saved.AgreementLink != "agreements/LN-123/agreement-v1.txt" || len(doc) == 0 {
+		t.Fatalf("agreement not stored at the loan's link: %q (%v)", saved.AgreementLink, stored)
+	}
+	sum := sha256.Sum256(doc)
+	if saved.AgreementVersion != agreement.CurrentVersion || saved.AgreementSHA256 != hex.EncodeToString(sum[:]) {
+		t.Fatalf("version/hash: %q %q", saved.AgreementVersion, saved.AgreementSHA256)
+	}
+	// both lenders, with the new investment, and their shares
+	for _, want := range []string{"jjjjjjjjjjjjjjjjjjjjjjjjjjjjjjjj  IDR 4000000.00  (80.00%)", investorID + "  IDR 1000000.00  (20.00%)"} {
+		if !strings.Contains(string(doc), want) {
+			t.Fatalf("agreement missing %q:\n%s", want, doc)
+		}
+	}
+
+	// a partial investment renders nothing
+	stored = map[string][]byte{}
+	l.State, placed = loan.StateApproved, nil
+	books.BalanceFn = func(ctx context.Context, a ledger.Account) (money.Decimal, error) {
+		if a == ledger.Escrow("LN-123") {
+			return money.Zero, nil
+		}
+		return money.NewFromInt(100_000_000), nil
+	}
+	if _, err := uc.Invest(context.Background(), InvestInput{LoanID: "LN-123", InvestorID: investorID, Amount: money.NewFromInt(1_000_000)}); err != nil {
+		t.Fatalf("partial Invest: %v", err)
+	}
+	if len(stored) != 0 || len(appended) != 1 {
+		t.Fatalf("partial funding stored an agreement (%v) or an event (%d)", stored, len(appended))
+	}
+}
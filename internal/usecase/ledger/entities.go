@@ -0,0 +1,19 @@
+package ledger
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+type BalanceInput struct {
+	Account string // one of ledger.AccountTypes
+	OwnerID string // investor_id or loan_id for owned accounts; empty for cash/platform_fee
+}
+
+type BalanceDTO struct {
+	Account string        `json:"account"`
+	OwnerID string        `json:"owner_id,omitempty"`
+	Balance money.Decimal `json:"balance"` // on the account's normal side
+	AsOf    time.Time     `json:"as_of"`
+}
@@ -0,0 +1,29 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+)
+
+type Usecase struct {
+	ledgerRepo domainLedger.Repository
+}
+
+func NewUsecase(entries domainLedger.Repository) *Usecase {
+	return &Usecase{ledgerRepo: entries}
+}
+
+// Balance reads an account balance straight from the journal lines.
+func (u *Usecase) Balance(ctx context.Context, in BalanceInput) (*BalanceDTO, error) {
+	a := domainLedger.Account{Type: domainLedger.AccountType(in.Account), OwnerID: in.OwnerID}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	bal, err := u.ledgerRepo.Balance(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	return &BalanceDTO{Account: in.Account, OwnerID: in.OwnerID, Balance: bal, AsOf: time.Now().UTC()}, nil
+}
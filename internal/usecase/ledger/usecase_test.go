@@ -0,0 +1,45 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/pkg/money"
+)
+
+func TestBalance(t *testing.T) {
+	books := &ledgermock.Repo{
+		BalanceFn: func(ctx context.Context, a domainLedger.Account) (money.Decimal, error) {
+			if a != domainLedger.InvestorWallet("alice") {
+				t.Fatalf("account mismatch: %+v", a)
+			}
+			return money.MustParse("306.5"), nil
+		},
+	}
+	dto, err := NewUsecase(books).Balance(context.Background(), BalanceInput{Account: "investor_wallet", OwnerID: "alice"})
+	if err != nil {
+		t.Fatalf("Balance: %v", err)
+	}
+	if dto.Balance.String() != "306.5" || dto.Account != "investor_wallet" || dto.OwnerID != "alice" || dto.AsOf.IsZero() {
+		t.Fatalf("dto: %+v", dto)
+	}
+}
+
+func TestBalance_Errors(t *testing.T) {
+	uc := NewUsecase(&ledgermock.Repo{}) // default Balance → context.Canceled
+	for _, in := range []BalanceInput{
+		{Account: "bank"},
+		{Account: "cash", OwnerID: "alice"},
+		{Account: "escrow"},
+	} {
+		if _, err := uc.Balance(context.Background(), in); !errors.Is(err, domainLedger.ErrInvalidAccount) {
+			t.Fatalf("%+v: want ErrInvalidAccount, got %v", in, err)
+		}
+	}
+	if _, err := uc.Balance(context.Background(), BalanceInput{Account: "cash"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("repo error: want context.Canceled, got %v", err)
+	}
+}
@@ -1,22 +1,132 @@
 package loan
 
 import (
+	"fmt"
+	"strings"
 	"time"
+
+	"amartha-backend-test/pkg/money"
 )
 
 type CreateLoanInput struct {
-	BorrowerID string  `json:"borrower_id"`
-	Principal  float64 `json:"principal"`
-	Rate       float64 `json:"rate"`
-	ROI        float64 `json:"roi"`
+	BorrowerID string        `json:"borrower_id"`
+	Principal  money.Decimal `json:"principal"`
+	Rate       money.Decimal `json:"rate"`
+	ROI        money.Decimal `json:"roi"`
+	// Tenor counts installments of TenorUnit ("week" | "month")
+	Tenor          uint16 `json:"tenor"`
+	TenorUnit      string `json:"tenor_unit"`
+	InterestMethod string `json:"interest_method"` // "flat" (default) | "effective"
 }
 
 type LoanDTO struct {
-	LoanID     string    `json:"loan_id"`
-	BorrowerID string    `json:"borrower_id"`
-	Principal  float64   `json:"principal"`
-	Rate       float64   `json:"rate"`
-	ROI        float64   `json:"roi"`
-	State      string    `json:"state"`
-	CreatedAt  time.Time `json:"created_at"`
+	LoanID         string        `json:"loan_id"`
+	BorrowerID     string        `json:"borrower_id"`
+	Principal      money.Decimal `json:"principal"`
+	Rate           money.Decimal `json:"rate"`
+	ROI            money.Decimal `json:"roi"`
+	Tenor          uint16        `json:"tenor"`
+	TenorUnit      string        `json:"tenor_unit"`
+	InterestMethod string        `json:"interest_method"`
+	// Interest + principal still owed; zero until disbursed
+	OutstandingBalance money.Decimal `json:"outstanding_balance"`
+	// Set for disbursed loans only; refreshed by the daily DPD job
+	DPD       *uint16   `json:"dpd,omitempty"`
+	DPDBucket string    `json:"dpd_bucket,omitempty"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Sub-objects, present only on reads that ask for them
+	Approval     *ApprovalSummaryDTO     `json:"approval,omitempty"`
+	Funding      *FundingDTO             `json:"funding,omitempty"`
+	Disbursement *DisbursementSummaryDTO `json:"disbursement,omitempty"`
+}
+
+type ApprovalSummaryDTO struct {
+	ApprovalID string    `json:"approval_id"`
+	PhotoID    string    `json:"photo_id,omitempty"` // empty on approvals that predate photo uploads
+	PhotoURL   string    `json:"photo_url"`
+	ApprovedAt time.Time `json:"approved_at"`
+}
+
+// FundingDTO is investment progress; unreleased investments only.
+type FundingDTO struct {
+	InvestedAmount    money.Decimal `json:"invested_amount"`
+	RemainingAmount   money.Decimal `json:"remaining_amount"`
+	InvestorCount     int           `json:"investor_count"`
+	InvestmentCount   int           `json:"investment_count"`
+	FundingPercentage money.Decimal `json:"funding_percentage"` // of principal, 2 dp
+}
+
+type DisbursementSummaryDTO struct {
+	DisbursementID     string     `json:"disbursement_id"`
+	SignedAgreementURL string     `json:"signed_agreement_url"`
+	SignatureStatus    string     `json:"signature_status"`
+	SignedAt           *time.Time `json:"signed_at,omitempty"`
+	DisbursedAt        time.Time  `json:"disbursed_at"`
+}
+
+// List page size bounds for GET /loans
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 100
+)
+
+// DefaultListSort is newest first
+const DefaultListSort = "-created_at"
+
+// ListInput filters GET /loans; zero fields are ignored. Principal bounds are
+// inclusive, time ranges are [from, to).
+type ListInput struct {
+	State            string // optional; one of loan.States
+	BorrowerID       string
+	DPDBucket        string // optional; one of loan.DPDBuckets
+	PrincipalMin     money.Decimal
+	PrincipalMax     money.Decimal
+	CreatedFrom      time.Time
+	CreatedTo        time.Time
+	StateUpdatedFrom time.Time
+	StateUpdatedTo   time.Time
+	Sort             string // created_at | state_updated_at | principal, "-" prefix for descending
+	Cursor           string // NextCursor of the previous page, same Sort
+	Limit            int    // 1..MaxListLimit; DefaultListLimit when 0
+}
+
+type BorrowerLoansInput struct {
+	BorrowerID string // 32-char hex
+	Cursor     string // NextCursor of the previous page
+	Limit      int    // 1..MaxListLimit; DefaultListLimit when 0
+}
+
+// Expand selects the sub-objects a read attaches to each LoanDTO.
+type Expand struct {
+	Approval     bool
+	Funding      bool
+	Disbursement bool
+}
+
+// ParseExpand reads a comma-separated ?expand= value: approval, investments
+// (funding progress) and disbursement. Unknown names are rejected.
+func ParseExpand(s string) (Expand, error) {
+	var x Expand
+	for _, name := range strings.Split(s, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "approval":
+			x.Approval = true
+		case "investments":
+			x.Funding = true
+		case "disbursement":
+			x.Disbursement = true
+		default:
+			return Expand{}, fmt.Errorf("%w: %q", ErrInvalidExpand, name)
+		}
+	}
+	return x, nil
+}
+
+type LoanListDTO struct {
+	Items []LoanDTO `json:"items"`
+	// Opaque; pass as ?cursor= with the same sort for the next page. Empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -2,57 +2,143 @@ package loan
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"amartha-backend-test/internal/domain/approval"
+	"amartha-backend-test/internal/domain/disbursement"
+	"amartha-backend-test/internal/domain/investment"
 	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
 	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/money"
+	"amartha-backend-test/pkg/requestctx"
 
 	"gorm.io/gorm"
 )
 
-type Usecase struct{ repo loan.Repository }
+var (
+	hundred = money.NewFromInt(100)
+
+	errDetailsDisabled = errors.New("loan details need WithDetails")
+
+	ErrInvalidExpand = errors.New("expand must list approval, investments or disbursement")
+)
+
+type Usecase struct {
+	repo loan.Repository
+	// how long a rejected borrower must wait before proposing again (0 = no cooldown)
+	rejectionCooldown time.Duration
+
+	// read-side repos for the approval / funding / disbursement sub-objects
+	approvals     approval.Repository
+	investments   investment.Repository
+	disbursements disbursement.Repository
+
+	// when set, Create records the loan and its LoanProposed event in one tx
+	uow uow.UnitOfWork
+}
 
 func NewUsecase(r loan.Repository) *Usecase { return &Usecase{repo: r} }
 
+// WithRejectionCooldown enables the re-application cooldown after a rejection.
+func (u *Usecase) WithRejectionCooldown(d time.Duration) *Usecase {
+	u.rejectionCooldown = d
+	return u
+}
+
+// WithDetails enables the sub-objects (approval, funding, disbursement) on reads.
+func (u *Usecase) WithDetails(approvals approval.Repository, investments investment.Repository, disbursements disbursement.Repository) *Usecase {
+	u.approvals, u.investments, u.disbursements = approvals, investments, disbursements
+	return u
+}
+
+// WithOutbox makes Create append a LoanProposed event and the loan's first
+// history row in the loan's transaction.
+func (u *Usecase) WithOutbox(tx uow.UnitOfWork) *Usecase {
+	u.uow = tx
+	return u
+}
+
 func (u *Usecase) Create(ctx context.Context, in CreateLoanInput) (*LoanDTO, error) {
-	if in.BorrowerID == "" || len(in.BorrowerID) != 32 || in.Principal <= 0 {
-		return nil, errors.New("invalid input")
+	if in.BorrowerID == "" || len(in.BorrowerID) != 32 || !in.Principal.IsPositive() {
+		return nil, loan.ErrInvalidLoan
+	}
+	method := loan.InterestMethod(in.InterestMethod)
+	if method == "" {
+		method = loan.InterestFlat
+	}
+	if in.Tenor == 0 || !loan.TenorUnit(in.TenorUnit).Valid() || !method.Valid() {
+		return nil, loan.ErrInvalidTenor
 	}
 
 	// Block if the borrower already has a pending (proposed) loan.
 	pending, err := u.repo.GetPendingLoanByBorrowerID(ctx, in.BorrowerID)
 	switch {
 	case err == nil:
-		return nil, fmt.Errorf("borrower %s already has a pending loan: %s", in.BorrowerID, pending.LoanID)
+		return nil, fmt.Errorf("%w: borrower %s already has a pending loan: %s", loan.ErrPendingLoan, in.BorrowerID, pending.LoanID)
 	case !errors.Is(err, gorm.ErrRecordNotFound):
 		return nil, err
 	}
 
+	// Block if the borrower was rejected within the cooldown window.
+	if u.rejectionCooldown > 0 {
+		rejected, err := u.repo.GetLatestRejectedLoanByBorrowerID(ctx, in.BorrowerID)
+		switch {
+		case err == nil:
+			if until := rejected.StateUpdatedAt.Add(u.rejectionCooldown); time.Now().UTC().Before(until) {
+				return nil, fmt.Errorf("%w: borrower %s may re-apply after %s",
+					loan.ErrReapplyCooldown, in.BorrowerID, until.UTC().Format(time.RFC3339))
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, err
+		}
+	}
+
 	l := &loan.Loan{
 		LoanID:         id.NewID32(),
 		BorrowerID:     in.BorrowerID,
 		Principal:      in.Principal,
 		Rate:           in.Rate,
 		ROI:            in.ROI,
-		State:          loan.StateProposed,
-		StateUpdatedAt: time.Now().UTC(),
+		Tenor:          in.Tenor,
+		TenorUnit:      loan.TenorUnit(in.TenorUnit),
+		InterestMethod: method,
 	}
+	tr := loan.Lifecycle.Init(l, loan.TransitionMeta{
+		Actor:     in.BorrowerID,
+		RequestID: requestctx.RequestID(ctx),
+	})
 
-	if err := u.repo.Create(ctx, l); err != nil {
+	if u.uow == nil {
+		if err := u.repo.Create(ctx, l); err != nil {
+			return nil, err
+		}
+		return toDTO(l), nil
+	}
+	err = u.uow.WithinTx(ctx, func(r uow.Repos) error {
+		if err := r.Loans.Create(ctx, l); err != nil {
+			return err
+		}
+		tr.LoanID = l.ID
+		if err := r.LoanHistory.Append(ctx, tr); err != nil {
+			return err
+		}
+		ev, err := outbox.ForLoan(outbox.LoanProposed, l)
+		if err != nil {
+			return err
+		}
+		return r.Outbox.Append(ctx, ev)
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	return &LoanDTO{
-		LoanID:     l.LoanID,
-		BorrowerID: l.BorrowerID,
-		Principal:  l.Principal,
-		Rate:       l.Rate,
-		ROI:        l.ROI,
-		State:      string(l.State),
-		CreatedAt:  l.CreatedAt,
-	}, nil
+	return toDTO(l), nil
 }
 
 func (u *Usecase) Get(ctx context.Context, loanID string) (*LoanDTO, error) {
@@ -60,13 +146,280 @@ func (u *Usecase) Get(ctx context.Context, loanID string) (*LoanDTO, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &LoanDTO{
-		LoanID:     l.LoanID,
-		BorrowerID: l.BorrowerID,
-		Principal:  l.Principal,
-		Rate:       l.Rate,
-		ROI:        l.ROI,
-		State:      string(l.State),
-		CreatedAt:  l.CreatedAt,
-	}, nil
+	return toDTO(l), nil
+}
+
+// GetDetail is Get with the sub-objects selected by x, read with the loan in
+// a single query. A zero x is the plain loan.
+func (u *Usecase) GetDetail(ctx context.Context, loanID string, x Expand) (*LoanDTO, error) {
+	if x == (Expand{}) {
+		return u.Get(ctx, loanID)
+	}
+	d, err := u.repo.GetDetailByLoanID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	dto := toDTO(&d.Loan)
+	if x.Approval && d.ApprovalID != nil {
+		dto.Approval = &ApprovalSummaryDTO{ApprovalID: *d.ApprovalID, PhotoID: deref(d.PhotoID), PhotoURL: deref(d.PhotoURL)}
+		if d.ApprovedAt != nil {
+			dto.Approval.ApprovedAt = *d.ApprovedAt
+		}
+	}
+	if x.Funding {
+		dto.Funding = toFundingDTO(&d.Loan, investment.Funding{
+			LoanID: d.ID, Invested: d.Invested, Investments: d.Investments, Investors: d.Investors,
+		})
+	}
+	if x.Disbursement && d.DisbursementID != nil {
+		dto.Disbursement = &DisbursementSummaryDTO{
+			DisbursementID:     *d.DisbursementID,
+			SignedAgreementURL: deref(d.SignedAgreementURL),
+			SignatureStatus:    deref(d.SignatureStatus),
+			SignedAt:           d.SignedAt,
+		}
+		if d.DisbursedAt != nil {
+			dto.Disbursement.DisbursedAt = *d.DisbursedAt
+		}
+	}
+	return dto, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// List returns one page of loans in the requested order. A dpd_bucket filter
+// implies disbursed loans, the only ones the daily job classifies.
+func (u *Usecase) List(ctx context.Context, in ListInput) (*LoanListDTO, error) {
+	rows, next, err := u.search(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return toListDTO(rows, next), nil
+}
+
+// ListByBorrower returns one page of the borrower's loans, newest first and
+// cancelled ones included, each with its approval, funding progress and
+// disbursement.
+func (u *Usecase) ListByBorrower(ctx context.Context, in BorrowerLoansInput) (*LoanListDTO, error) {
+	rows, next, err := u.search(ctx, ListInput{BorrowerID: in.BorrowerID, Cursor: in.Cursor, Limit: in.Limit})
+	if err != nil {
+		return nil, err
+	}
+	out := toListDTO(rows, next)
+	if err := u.attach(ctx, rows, out.Items, Expand{Approval: true, Funding: true, Disbursement: true}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// search runs one page of a listing and returns its rows and the next page's cursor.
+func (u *Usecase) search(ctx context.Context, in ListInput) ([]loan.Loan, string, error) {
+	f := loan.SearchFilter{
+		State:            loan.State(in.State),
+		BorrowerID:       in.BorrowerID,
+		PrincipalMin:     in.PrincipalMin,
+		PrincipalMax:     in.PrincipalMax,
+		CreatedFrom:      in.CreatedFrom,
+		CreatedTo:        in.CreatedTo,
+		StateUpdatedFrom: in.StateUpdatedFrom,
+		StateUpdatedTo:   in.StateUpdatedTo,
+		Limit:            in.Limit,
+	}
+	if f.Limit <= 0 || f.Limit > MaxListLimit {
+		f.Limit = DefaultListLimit
+	}
+	if in.DPDBucket != "" {
+		f.DPDBucket = loan.DPDBucket(in.DPDBucket)
+		if !f.DPDBucket.Valid() {
+			return nil, "", loan.ErrInvalidDPDBucket
+		}
+		if f.State != "" && f.State != loan.StateDisbursed {
+			return nil, "", fmt.Errorf("%w (only disbursed loans have one)", loan.ErrInvalidDPDBucket)
+		}
+		f.State = loan.StateDisbursed
+	}
+	if (!f.PrincipalMax.IsZero() && f.PrincipalMin.GreaterThan(f.PrincipalMax)) ||
+		(!f.CreatedTo.IsZero() && f.CreatedFrom.After(f.CreatedTo)) ||
+		(!f.StateUpdatedTo.IsZero() && f.StateUpdatedFrom.After(f.StateUpdatedTo)) {
+		return nil, "", loan.ErrInvalidRange
+	}
+
+	sort := in.Sort
+	if sort == "" {
+		sort = DefaultListSort
+	}
+	f.Desc = strings.HasPrefix(sort, "-")
+	f.Sort = loan.SortKey(strings.TrimPrefix(sort, "-"))
+	if !f.Sort.Valid() {
+		return nil, "", fmt.Errorf("unsupported sort %q", in.Sort)
+	}
+	if in.Cursor != "" {
+		c, err := decodeCursor(in.Cursor, sort)
+		if err != nil {
+			return nil, "", err
+		}
+		f.After = c
+	}
+
+	// one extra row tells whether another page follows
+	want := f.Limit
+	f.Limit++
+	rows, err := u.repo.Search(ctx, f)
+	if err != nil {
+		return nil, "", err
+	}
+	var next string
+	if len(rows) > want {
+		rows = rows[:want]
+		next = encodeCursor(loan.CursorAt(&rows[want-1], f.Sort), sort)
+	}
+	return rows, next, nil
+}
+
+func toListDTO(rows []loan.Loan, next string) *LoanListDTO {
+	out := &LoanListDTO{Items: make([]LoanDTO, 0, len(rows)), NextCursor: next}
+	for i := range rows {
+		out.Items = append(out.Items, *toDTO(&rows[i]))
+	}
+	return out
+}
+
+// attach fills the sub-objects selected by x on items (items[i] is rows[i]),
+// one batched query per kind.
+func (u *Usecase) attach(ctx context.Context, rows []loan.Loan, items []LoanDTO, x Expand) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if u.approvals == nil || u.investments == nil || u.disbursements == nil {
+		return errDetailsDisabled
+	}
+	ids := make([]uint64, len(rows))
+	at := make(map[uint64]*LoanDTO, len(rows))
+	for i := range rows {
+		ids[i] = rows[i].ID
+		at[rows[i].ID] = &items[i]
+	}
+
+	if x.Approval {
+		list, err := u.approvals.ListByLoanIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, a := range list {
+			at[a.LoanID].Approval = &ApprovalSummaryDTO{ApprovalID: a.ApprovalID, PhotoID: deref(a.PhotoID), PhotoURL: a.PhotoURL, ApprovedAt: a.ApprovalDate}
+		}
+	}
+	if x.Funding {
+		list, err := u.investments.FundingByLoanIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		byLoan := make(map[uint64]investment.Funding, len(list))
+		for _, f := range list {
+			byLoan[f.LoanID] = f
+		}
+		for i := range rows {
+			items[i].Funding = toFundingDTO(&rows[i], byLoan[rows[i].ID])
+		}
+	}
+	if x.Disbursement {
+		list, err := u.disbursements.ListByLoanIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, d := range list {
+			at[d.LoanID].Disbursement = &DisbursementSummaryDTO{
+				DisbursementID:     d.DisbursementID,
+				SignedAgreementURL: d.SignedAgreementURL,
+				SignatureStatus:    string(d.SignatureStatus),
+				SignedAt:           d.SignedAt,
+				DisbursedAt:        d.DisbursementDate,
+			}
+		}
+	}
+	return nil
+}
+
+func toFundingDTO(l *loan.Loan, f investment.Funding) *FundingDTO {
+	dto := &FundingDTO{
+		InvestedAmount:  f.Invested,
+		RemainingAmount: l.Principal.Sub(f.Invested),
+		InvestorCount:   f.Investors,
+		InvestmentCount: f.Investments,
+	}
+	if dto.RemainingAmount.IsNegative() {
+		dto.RemainingAmount = money.Zero
+	}
+	if l.Principal.IsPositive() {
+		dto.FundingPercentage = f.Invested.Mul(hundred).Div(l.Principal, 2)
+	}
+	return dto
+}
+
+// cursorToken is the opaque ?cursor= value: the last row's id and sort value,
+// tagged with the sort it was issued for.
+type cursorToken struct {
+	Sort      string         `json:"s"`
+	ID        uint64         `json:"id"`
+	At        *time.Time     `json:"at,omitempty"`
+	Principal *money.Decimal `json:"p,omitempty"`
+}
+
+func encodeCursor(c loan.Cursor, sort string) string {
+	tok := cursorToken{Sort: sort, ID: c.ID}
+	if strings.TrimPrefix(sort, "-") == string(loan.SortPrincipal) {
+		tok.Principal = &c.Principal
+	} else {
+		tok.At = &c.At
+	}
+	b, _ := json.Marshal(tok)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s, sort string) (*loan.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, loan.ErrInvalidCursor
+	}
+	var tok cursorToken
+	if err := json.Unmarshal(b, &tok); err != nil || tok.Sort != sort || tok.ID == 0 {
+		return nil, loan.ErrInvalidCursor
+	}
+	c := &loan.Cursor{ID: tok.ID}
+	switch {
+	case tok.Principal != nil:
+		c.Principal = *tok.Principal
+	case tok.At != nil:
+		c.At = *tok.At
+	default:
+		return nil, loan.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func toDTO(l *loan.Loan) *LoanDTO {
+	dto := &LoanDTO{
+		LoanID:             l.LoanID,
+		BorrowerID:         l.BorrowerID,
+		Principal:          l.Principal,
+		Rate:               l.Rate,
+		ROI:                l.ROI,
+		Tenor:              l.Tenor,
+		TenorUnit:          string(l.TenorUnit),
+		InterestMethod:     string(l.InterestMethod),
+		OutstandingBalance: l.OutstandingBalance,
+		State:              string(l.State),
+		CreatedAt:          l.CreatedAt,
+	}
+	// Delinquency only means something while repayments are running
+	if l.State == loan.StateDisbursed {
+		dpd := l.DPD
+		dto.DPD, dto.DPDBucket = &dpd, string(l.DPDBucket)
+	}
+	return dto
 }
@@ -1,9 +1,21 @@
 package loan
 
 import (
+	domainApproval "amartha-backend-test/internal/domain/approval"
+	domainDisb "amartha-backend-test/internal/domain/disbursement"
+	domainInv "amartha-backend-test/internal/domain/investment"
 	domain "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/approvalmock"
+	"amartha-backend-test/internal/testutil/disbursementmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
 	loanmock "amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/pkg/money"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -33,8 +45,9 @@ func TestCreate_Success_NoPendingLoan(t *testing.T) {
 
 	in := CreateLoanInput{
 		BorrowerID: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
-		Principal:  5_000_000,
-		Rate:       0.22, ROI: 0.18,
+		Principal:  money.NewFromInt(5_000_000),
+		Rate:       money.MustParse("0.22"), ROI: money.MustParse("0.18"),
+		Tenor: 25, TenorUnit: "week",
 	}
 	dto, err := uc.Create(context.Background(), in)
 	if err != nil {
@@ -61,9 +74,9 @@ func TestCreate_Rejects_WhenPendingLoanExists(t *testing.T) {
 			return &domain.Loan{
 				LoanID:         existingLoanID,
 				BorrowerID:     borrowerID,
-				Principal:      1_000_000,
-				Rate:           0.22,
-				ROI:            0.18,
+				Principal:      money.NewFromInt(1_000_000),
+				Rate:           money.MustParse("0.22"),
+				ROI:            money.MustParse("0.18"),
 				State:          domain.StateProposed,
 				StateUpdatedAt: time.Now().UTC(),
 				CreatedAt:      time.Now().UTC(),
@@ -78,8 +91,9 @@ func TestCreate_Rejects_WhenPendingLoanExists(t *testing.T) {
 
 	_, err := uc.Create(context.Background(), CreateLoanInput{
 		BorrowerID: borrowerID,
-		Principal:  7_000_000,
-		Rate:       0.21, ROI: 0.17,
+		Principal:  money.NewFromInt(7_000_000),
+		Rate:       money.MustParse("0.21"), ROI: money.MustParse("0.17"),
+		Tenor: 25, TenorUnit: "week",
 	})
 	if err == nil {
 		t.Fatalf("expected error due to existing pending loan, got nil")
@@ -97,7 +111,7 @@ func TestGet_Success(t *testing.T) {
 		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domain.Loan, error) {
 			return &domain.Loan{
 				LoanID: LID, BorrowerID: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
-				Principal: 1_000_000, Rate: 0.22, ROI: 0.18,
+				Principal: money.NewFromInt(1_000_000), Rate: money.MustParse("0.22"), ROI: money.MustParse("0.18"),
 				State: domain.StateProposed, CreatedAt: now,
 			}, nil
 		},
@@ -114,9 +128,463 @@ func TestGet_Success(t *testing.T) {
 func TestCreate_InvalidInput(t *testing.T) {
 	uc := NewUsecase(&loanmock.Repo{})
 	_, err := uc.Create(context.Background(), CreateLoanInput{
-		BorrowerID: "short", Principal: 0, Rate: 0.2, ROI: 0.1,
+		BorrowerID: "short", Principal: money.NewFromInt(0), Rate: money.MustParse("0.2"), ROI: money.MustParse("0.1"),
 	})
 	if err == nil {
 		t.Fatal("want error")
 	}
 }
+
+func TestCreate_Rejects_WithinRejectionCooldown(t *testing.T) {
+	const borrowerID = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+
+	uc := NewUsecase(&loanmock.Repo{
+		GetPendingLoanByBorrowerIDFn: func(ctx context.Context, id string) (*domain.Loan, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		GetLatestRejectedLoanByBorrowerIDFn: func(ctx context.Context, id string) (*domain.Loan, error) {
+			return &domain.Loan{
+				LoanID:         "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				BorrowerID:     borrowerID,
+				State:          domain.StateRejected,
+				StateUpdatedAt: time.Now().UTC().Add(-24 * time.Hour),
+			}, nil
+		},
+		CreateFn: func(ctx context.Context, l *domain.Loan) error {
+			t.Fatalf("Create must not be called during cooldown")
+			return nil
+		},
+	}).WithRejectionCooldown(30 * 24 * time.Hour)
+
+	_, err := uc.Create(context.Background(), CreateLoanInput{
+		BorrowerID: borrowerID, Principal: money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"), ROI: money.MustParse("1.0"),
+		Tenor: 12, TenorUnit: "month",
+	})
+	if !errors.Is(err, domain.ErrReapplyCooldown) {
+		t.Fatalf("want ErrReapplyCooldown, got %v", err)
+	}
+}
+
+func TestCreate_Allows_AfterRejectionCooldown(t *testing.T) {
+	uc := NewUsecase(&loanmock.Repo{
+		GetPendingLoanByBorrowerIDFn: func(ctx context.Context, id string) (*domain.Loan, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		GetLatestRejectedLoanByBorrowerIDFn: func(ctx context.Context, id string) (*domain.Loan, error) {
+			return &domain.Loan{
+				State:          domain.StateRejected,
+				StateUpdatedAt: time.Now().UTC().Add(-31 * 24 * time.Hour),
+			}, nil
+		},
+	}).WithRejectionCooldown(30 * 24 * time.Hour)
+
+	if _, err := uc.Create(context.Background(), CreateLoanInput{
+		BorrowerID: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Principal: money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"), ROI: money.MustParse("1.0"),
+		Tenor: 12, TenorUnit: "month",
+	}); err != nil {
+		t.Fatalf("Create err: %v", err)
+	}
+}
+
+func TestCreate_RejectionLookupError(t *testing.T) {
+	uc := NewUsecase(&loanmock.Repo{
+		GetPendingLoanByBorrowerIDFn: func(ctx context.Context, id string) (*domain.Loan, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		// GetLatestRejectedLoanByBorrowerID default → context.Canceled
+	}).WithRejectionCooldown(time.Hour)
+
+	_, err := uc.Create(context.Background(), CreateLoanInput{
+		BorrowerID: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Principal: money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"), ROI: money.MustParse("1.0"),
+		Tenor: 12, TenorUnit: "month",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
+
+func TestCreate_InvalidTenor(t *testing.T) {
+	uc := NewUsecase(&loanmock.Repo{
+		CreateFn: func(ctx context.Context, l *domain.Loan) error {
+			t.Fatalf("Create must not be called with an invalid tenor")
+			return nil
+		},
+	})
+	base := CreateLoanInput{
+		BorrowerID: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		Principal:  money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"), ROI: money.NewFromInt(1),
+		Tenor: 12, TenorUnit: "month",
+	}
+	for name, mutate := range map[string]func(*CreateLoanInput){
+		"zero tenor":     func(in *CreateLoanInput) { in.Tenor = 0 },
+		"unknown unit":   func(in *CreateLoanInput) { in.TenorUnit = "day" },
+		"unknown method": func(in *CreateLoanInput) { in.InterestMethod = "compound" },
+	} {
+		in := base
+		mutate(&in)
+		if _, err := uc.Create(context.Background(), in); !errors.Is(err, domain.ErrInvalidTenor) {
+			t.Fatalf("%s: want ErrInvalidTenor, got %v", name, err)
+		}
+	}
+}
+
+func TestCreate_DefaultsToFlatInterest(t *testing.T) {
+	var saved *domain.Loan
+	uc := NewUsecase(&loanmock.Repo{
+		GetPendingLoanByBorrowerIDFn: func(ctx context.Context, id string) (*domain.Loan, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		CreateFn: func(ctx context.Context, l *domain.Loan) error { saved = l; return nil },
+	})
+	dto, err := uc.Create(context.Background(), CreateLoanInput{
+		BorrowerID: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		Principal:  money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"), ROI: money.NewFromInt(1),
+		Tenor: 50, TenorUnit: "week",
+	})
+	if err != nil {
+		t.Fatalf("Create err: %v", err)
+	}
+	if saved.Tenor != 50 || saved.TenorUnit != domain.TenorWeek || saved.InterestMethod != domain.InterestFlat {
+		t.Fatalf("unexpected loan: %+v", saved)
+	}
+	if dto.Tenor != 50 || dto.TenorUnit != "week" || dto.InterestMethod != "flat" {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+}
+
+func TestCreate_WithOutbox_AppendsLoanProposed(t *testing.T) {
+	reads := &loanmock.Repo{
+		GetPendingLoanByBorrowerIDFn: func(ctx context.Context, id string) (*domain.Loan, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		CreateFn: func(ctx context.Context, l *domain.Loan) error {
+			t.Fatalf("with an outbox the loan must be created in the tx")
+			return nil
+		},
+	}
+	var created *domain.Loan
+	var events []*domainOutbox.Message
+	var history []*domain.StateTransition
+	var appendErr error
+	tx := uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+		return fn(uow.Repos{
+			Loans: &loanmock.Repo{CreateFn: func(ctx context.Context, l *domain.Loan) error { l.ID = 7; created = l; return nil }},
+			LoanHistory: &loanmock.HistoryRepo{AppendFn: func(ctx context.Context, tr *domain.StateTransition) error {
+				history = append(history, tr)
+				return nil
+			}},
+			Outbox: &outboxmock.Repo{AppendFn: func(ctx context.Context, m *domainOutbox.Message) error {
+				events = append(events, m)
+				return appendErr
+			}},
+		})
+	})
+	uc := NewUsecase(reads).WithOutbox(tx)
+	in := CreateLoanInput{
+		BorrowerID: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		Principal:  money.NewFromInt(5_000_000), Rate: money.MustParse("1.5"), ROI: money.NewFromInt(1),
+		Tenor: 50, TenorUnit: "week",
+	}
+
+	dto, err := uc.Create(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Create err: %v", err)
+	}
+	if created == nil || len(events) != 1 || events[0].EventType != domainOutbox.LoanProposed || events[0].AggregateID != dto.LoanID {
+		t.Fatalf("created %+v, events %+v", created, events)
+	}
+	// the proposal is the first row of the loan's history
+	if len(history) != 1 || history[0].LoanID != 7 || history[0].FromState != "" || history[0].ToState != domain.StateProposed ||
+		history[0].Actor != in.BorrowerID || !history[0].TransitionedAt.Equal(created.StateUpdatedAt) {
+		t.Fatalf("history %+v", history)
+	}
+
+	// the event failing to append fails the create (and rolls the loan back)
+	appendErr = errors.New("db down")
+	if _, err := uc.Create(context.Background(), in); !errors.Is(err, appendErr) {
+		t.Fatalf("want append error, got %v", err)
+	}
+}
+
+func TestList_DPDBucketImpliesDisbursed(t *testing.T) {
+	var got domain.SearchFilter
+	uc := NewUsecase(&loanmock.Repo{
+		SearchFn: func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			got = f
+			return []domain.Loan{
+				{LoanID: "LN-1", State: domain.StateDisbursed, DPD: 95, DPDBucket: domain.DPD90Plus},
+			}, nil
+		},
+	})
+	out, err := uc.List(context.Background(), ListInput{DPDBucket: "90+"})
+	if err != nil {
+		t.Fatalf("List err: %v", err)
+	}
+	if got.State != domain.StateDisbursed || got.DPDBucket != domain.DPD90Plus || got.Limit != DefaultListLimit+1 {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	if len(out.Items) != 1 || out.Items[0].DPD == nil || *out.Items[0].DPD != 95 || out.Items[0].DPDBucket != "90+" {
+		t.Fatalf("unexpected items: %+v", out.Items)
+	}
+	if out.NextCursor != "" {
+		t.Fatalf("single page should have no cursor: %q", out.NextCursor)
+	}
+
+	if _, err := uc.List(context.Background(), ListInput{DPDBucket: "90+", State: "approved"}); !errors.Is(err, domain.ErrInvalidDPDBucket) {
+		t.Fatalf("bucket on approved loans: want ErrInvalidDPDBucket, got %v", err)
+	}
+}
+
+func TestList_NoBucketAndLimits(t *testing.T) {
+	var got domain.SearchFilter
+	uc := NewUsecase(&loanmock.Repo{
+		SearchFn: func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			got = f
+			return []domain.Loan{{LoanID: "LN-1", State: domain.StateProposed, DPDBucket: domain.DPDCurrent}}, nil
+		},
+	})
+	out, err := uc.List(context.Background(), ListInput{Limit: 10})
+	if err != nil {
+		t.Fatalf("List err: %v", err)
+	}
+	if got.State != "" || got.DPDBucket != "" || got.Limit != 11 || got.Sort != domain.SortCreatedAt || !got.Desc || got.After != nil {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	// DPD is hidden for loans that are not being repaid
+	if out.Items[0].DPD != nil || out.Items[0].DPDBucket != "" {
+		t.Fatalf("dpd should be omitted: %+v", out.Items[0])
+	}
+
+	if _, err := uc.List(context.Background(), ListInput{Limit: 1000}); err != nil || got.Limit != DefaultListLimit+1 {
+		t.Fatalf("oversized limit should fall back to default, got %d (%v)", got.Limit, err)
+	}
+	if _, err := uc.List(context.Background(), ListInput{DPDBucket: "91+"}); !errors.Is(err, domain.ErrInvalidDPDBucket) {
+		t.Fatalf("want ErrInvalidDPDBucket, got %v", err)
+	}
+}
+
+func TestList_Filters(t *testing.T) {
+	var got domain.SearchFilter
+	uc := NewUsecase(&loanmock.Repo{
+		SearchFn: func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			got = f
+			return nil, nil
+		},
+	})
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	in := ListInput{
+		State:          "approved",
+		BorrowerID:     "B-1",
+		PrincipalMin:   money.NewFromInt(5_000_000),
+		PrincipalMax:   money.NewFromInt(10_000_000),
+		CreatedFrom:    from,
+		CreatedTo:      from.AddDate(0, 1, 0),
+		StateUpdatedTo: from,
+		Sort:           "principal",
+	}
+	if _, err := uc.List(context.Background(), in); err != nil {
+		t.Fatalf("List err: %v", err)
+	}
+	if got.State != domain.StateApproved || got.BorrowerID != "B-1" || got.Sort != domain.SortPrincipal || got.Desc ||
+		!got.PrincipalMin.Equal(in.PrincipalMin) || !got.PrincipalMax.Equal(in.PrincipalMax) ||
+		!got.CreatedFrom.Equal(from) || !got.CreatedTo.Equal(in.CreatedTo) || !got.StateUpdatedTo.Equal(from) {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+
+	for name, bad := range map[string]ListInput{
+		"principal": {PrincipalMin: money.NewFromInt(2), PrincipalMax: money.NewFromInt(1)},
+		"created":   {CreatedFrom: from, CreatedTo: from.Add(-time.Second)},
+		"updated":   {StateUpdatedFrom: from, StateUpdatedTo: from.Add(-time.Second)},
+	} {
+		if _, err := uc.List(context.Background(), bad); !errors.Is(err, domain.ErrInvalidRange) {
+			t.Fatalf("%s: want ErrInvalidRange, got %v", name, err)
+		}
+	}
+}
+
+func TestList_CursorPaging(t *testing.T) {
+	at := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	all := []domain.Loan{
+		{ID: 9, LoanID: "LN-9", Principal: money.NewFromInt(7_000_000), CreatedAt: at.Add(3 * time.Hour)},
+		{ID: 4, LoanID: "LN-4", Principal: money.NewFromInt(6_000_000), CreatedAt: at.Add(2 * time.Hour)},
+		{ID: 7, LoanID: "LN-7", Principal: money.NewFromInt(5_000_000), CreatedAt: at.Add(1 * time.Hour)},
+	}
+	var got domain.SearchFilter
+	uc := NewUsecase(&loanmock.Repo{
+		SearchFn: func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			got = f
+			start := 0
+			if f.After != nil {
+				for i := range all {
+					if all[i].ID == f.After.ID {
+						start = i + 1
+					}
+				}
+			}
+			return all[start:min(start+f.Limit, len(all))], nil
+		},
+	})
+
+	page, err := uc.List(context.Background(), ListInput{Sort: "-principal", Limit: 2})
+	if err != nil {
+		t.Fatalf("page 1: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[1].LoanID != "LN-4" || page.NextCursor == "" {
+		t.Fatalf("page 1: %+v", page)
+	}
+
+	page, err = uc.List(context.Background(), ListInput{Sort: "-principal", Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("page 2: %v", err)
+	}
+	if got.After == nil || got.After.ID != 4 || !got.After.Principal.Equal(money.NewFromInt(6_000_000)) {
+		t.Fatalf("cursor not decoded into the filter: %+v", got.After)
+	}
+	if len(page.Items) != 1 || page.Items[0].LoanID != "LN-7" || page.NextCursor != "" {
+		t.Fatalf("page 2: %+v", page)
+	}
+
+	// a cursor only resumes the sort it was issued for
+	first, _ := uc.List(context.Background(), ListInput{Limit: 1})
+	if first.NextCursor == "" {
+		t.Fatalf("expected a created_at cursor")
+	}
+	for _, in := range []ListInput{
+		{Sort: "created_at", Cursor: first.NextCursor},
+		{Cursor: "not-a-cursor"},
+		{Cursor: "e30"}, // {}
+	} {
+		if _, err := uc.List(context.Background(), in); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Fatalf("%+v: want ErrInvalidCursor, got %v", in, err)
+		}
+	}
+	if _, err := uc.List(context.Background(), ListInput{Cursor: first.NextCursor}); err != nil {
+		t.Fatalf("created_at cursor on default sort: %v", err)
+	}
+	if got.After == nil || got.After.ID != 9 || !got.After.At.Equal(at.Add(3*time.Hour)) {
+		t.Fatalf("created_at cursor: %+v", got.After)
+	}
+}
+
+func TestListByBorrower_AttachesDetails(t *testing.T) {
+	borrower := strings.Repeat("b", 32)
+	day := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	var got domain.SearchFilter
+	loans := &loanmock.Repo{
+		SearchFn: func(ctx context.Context, f domain.SearchFilter) ([]domain.Loan, error) {
+			got = f
+			return []domain.Loan{
+				{ID: 3, LoanID: "LN-3", BorrowerID: borrower, State: domain.StateDisbursed, Principal: money.NewFromInt(5_000_000)},
+				{ID: 1, LoanID: "LN-1", BorrowerID: borrower, State: domain.StateProposed, Principal: money.NewFromInt(3_000_000)},
+			}, nil
+		},
+	}
+	approvals := &approvalmock.Repo{
+		ListByLoanIDsFn: func(ctx context.Context, ids []uint64) ([]domainApproval.Approval, error) {
+			if len(ids) != 2 || ids[0] != 3 || ids[1] != 1 {
+				t.Fatalf("approvals queried for %v", ids)
+			}
+			return []domainApproval.Approval{{ApprovalID: "AP-3", LoanID: 3, PhotoURL: "https://x/p.jpg", ApprovalDate: day}}, nil
+		},
+	}
+	investments := &investmentmock.Repo{
+		FundingByLoanIDsFn: func(ctx context.Context, ids []uint64) ([]domainInv.Funding, error) {
+			return []domainInv.Funding{{LoanID: 3, Invested: money.NewFromInt(5_000_000), Investments: 3, Investors: 2}}, nil
+		},
+	}
+	disbursements := &disbursementmock.Repo{
+		ListByLoanIDsFn: func(ctx context.Context, ids []uint64) ([]domainDisb.Disbursement, error) {
+			return []domainDisb.Disbursement{{
+				DisbursementID: "DB-3", LoanID: 3, SignedAgreementURL: "https://x/a.pdf",
+				SignatureStatus: domainDisb.SignatureSigned, DisbursementDate: day,
+			}}, nil
+		},
+	}
+
+	out, err := NewUsecase(loans).WithDetails(approvals, investments, disbursements).
+		ListByBorrower(context.Background(), BorrowerLoansInput{BorrowerID: borrower})
+	if err != nil {
+		t.Fatalf("ListByBorrower: %v", err)
+	}
+	if got.BorrowerID != borrower || got.Sort != domain.SortCreatedAt || !got.Desc {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	if len(out.Items) != 2 {
+		t.Fatalf("items: %+v", out.Items)
+	}
+	disbursed, proposed := out.Items[0], out.Items[1]
+	if disbursed.Approval == nil || disbursed.Approval.ApprovalID != "AP-3" ||
+		disbursed.Disbursement == nil || disbursed.Disbursement.SignatureStatus != "SIGNED" {
+		t.Fatalf("disbursed loan details: %+v", disbursed)
+	}
+	if f := disbursed.Funding; f == nil || f.InvestorCount != 2 || f.InvestmentCount != 3 ||
+		!f.RemainingAmount.IsZero() || f.FundingPercentage.String() != "100" {
+		t.Fatalf("disbursed funding: %+v", disbursed.Funding)
+	}
+	if proposed.Approval != nil || proposed.Disbursement != nil {
+		t.Fatalf("proposed loan has details: %+v", proposed)
+	}
+	if f := proposed.Funding; f == nil || !f.InvestedAmount.IsZero() || !f.RemainingAmount.Equal(money.NewFromInt(3_000_000)) {
+		t.Fatalf("proposed funding: %+v", proposed.Funding)
+	}
+
+	if _, err := NewUsecase(loans).ListByBorrower(context.Background(), BorrowerLoansInput{BorrowerID: borrower}); err == nil {
+		t.Fatalf("expected an error without detail repositories")
+	}
+}
+
+func TestParseExpand(t *testing.T) {
+	x, err := ParseExpand("approval, investments,disbursement")
+	if err != nil || x != (Expand{Approval: true, Funding: true, Disbursement: true}) {
+		t.Fatalf("ParseExpand = %+v, %v", x, err)
+	}
+	if x, err := ParseExpand(""); err != nil || x != (Expand{}) {
+		t.Fatalf("empty = %+v, %v", x, err)
+	}
+	if _, err := ParseExpand("approval,loan"); !errors.Is(err, ErrInvalidExpand) {
+		t.Fatalf("want ErrInvalidExpand, got %v", err)
+	}
+}
+
+func TestGetDetail(t *testing.T) {
+	day := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	disbID, url, status := "DB-1", "https://x/a.pdf", "SIGNED"
+	repo := &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domain.Loan, error) {
+			return &domain.Loan{LoanID: loanID}, nil
+		},
+		GetDetailByLoanIDFn: func(ctx context.Context, loanID string) (*domain.Detail, error) {
+			return &domain.Detail{
+				Loan:               domain.Loan{LoanID: loanID, Principal: money.NewFromInt(3_000_000)},
+				Invested:           money.NewFromInt(1_000_000),
+				Investments:        1,
+				Investors:          1,
+				DisbursementID:     &disbID,
+				SignedAgreementURL: &url,
+				SignatureStatus:    &status,
+				DisbursedAt:        &day,
+			}, nil
+		},
+	}
+	uc := NewUsecase(repo)
+
+	// no expand: plain read, no detail query
+	dto, err := uc.GetDetail(context.Background(), "LN-1", Expand{})
+	if err != nil || dto.Funding != nil || dto.Approval != nil || dto.Disbursement != nil {
+		t.Fatalf("plain = %+v, %v", dto, err)
+	}
+
+	dto, err = uc.GetDetail(context.Background(), "LN-1", Expand{Approval: true, Funding: true, Disbursement: true})
+	if err != nil {
+		t.Fatalf("GetDetail: %v", err)
+	}
+	if dto.Approval != nil {
+		t.Fatalf("no approval row, got %+v", dto.Approval)
+	}
+	if f := dto.Funding; f == nil || f.FundingPercentage.String() != "33.33" || !f.RemainingAmount.Equal(money.NewFromInt(2_000_000)) {
+		t.Fatalf("funding: %+v", dto.Funding)
+	}
+	if d := dto.Disbursement; d == nil || d.DisbursementID != disbID || d.SignatureStatus != status || !d.DisbursedAt.Equal(day) {
+		t.Fatalf("disbursement: %+v", dto.Disbursement)
+	}
+}
@@ -0,0 +1,20 @@
+package loanhistory
+
+import (
+	"time"
+)
+
+type TransitionDTO struct {
+	From           string    `json:"from"`
+	To             string    `json:"to"`
+	Actor          string    `json:"actor"`
+	Reason         string    `json:"reason,omitempty"`
+	RequestID      string    `json:"request_id,omitempty"`
+	TransitionedAt time.Time `json:"transitioned_at"`
+}
+
+type HistoryDTO struct {
+	LoanID       string          `json:"loan_id"`
+	CurrentState string          `json:"current_state"`
+	Transitions  []TransitionDTO `json:"transitions"`
+}
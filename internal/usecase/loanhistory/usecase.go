@@ -0,0 +1,52 @@
+package loanhistory
+
+import (
+	"context"
+	"errors"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	loanRepo    domainLoan.Repository
+	historyRepo domainLoan.HistoryRepository
+}
+
+func NewUsecase(loans domainLoan.Repository, history domainLoan.HistoryRepository) *Usecase {
+	return &Usecase{loanRepo: loans, historyRepo: history}
+}
+
+// Get returns the audit trail of a loan, oldest transition first.
+func (u *Usecase) Get(ctx context.Context, loanID string) (*HistoryDTO, error) {
+	l, err := u.loanRepo.GetByLoanID(ctx, loanID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := u.historyRepo.ListByLoanID(ctx, l.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &HistoryDTO{
+		LoanID:       l.LoanID,
+		CurrentState: string(l.State),
+		Transitions:  make([]TransitionDTO, 0, len(rows)),
+	}
+	for _, r := range rows {
+		out.Transitions = append(out.Transitions, TransitionDTO{
+			From:           string(r.FromState),
+			To:             string(r.ToState),
+			Actor:          r.Actor,
+			Reason:         r.Reason,
+			RequestID:      r.RequestID,
+			TransitionedAt: r.TransitionedAt,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,89 @@
+package loanhistory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/testutil/loanmock"
+
+	"gorm.io/gorm"
+)
+
+func TestGet_Success(t *testing.T) {
+	now := time.Now().UTC()
+	uc := NewUsecase(
+		&loanmock.Repo{
+			GetByLoanIDFn: func(ctx context.Context, loanID string) (*loan.Loan, error) {
+				return &loan.Loan{ID: 7, LoanID: loanID, State: loan.StateApproved}, nil
+			},
+		},
+		&loanmock.HistoryRepo{
+			ListByLoanIDFn: func(ctx context.Context, id uint64) ([]loan.StateTransition, error) {
+				if id != 7 {
+					t.Fatalf("numeric id mismatch: %d", id)
+				}
+				return []loan.StateTransition{{
+					LoanID: 7, FromState: loan.StateProposed, ToState: loan.StateApproved,
+					Actor: "EMP-1", RequestID: "REQ-1", TransitionedAt: now,
+				}}, nil
+			},
+		},
+	)
+
+	dto, err := uc.Get(context.Background(), "LN-7")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if dto.LoanID != "LN-7" || dto.CurrentState != "approved" || len(dto.Transitions) != 1 {
+		t.Fatalf("unexpected dto: %+v", dto)
+	}
+	if tr := dto.Transitions[0]; tr.From != "proposed" || tr.To != "approved" || tr.Actor != "EMP-1" || tr.RequestID != "REQ-1" {
+		t.Fatalf("unexpected transition: %+v", tr)
+	}
+}
+
+func TestGet_EmptyHistory(t *testing.T) {
+	uc := NewUsecase(
+		&loanmock.Repo{
+			GetByLoanIDFn: func(ctx context.Context, loanID string) (*loan.Loan, error) {
+				return &loan.Loan{ID: 7, LoanID: loanID, State: loan.StateProposed}, nil
+			},
+		},
+		&loanmock.HistoryRepo{
+			ListByLoanIDFn: func(context.Context, uint64) ([]loan.StateTransition, error) { return nil, nil },
+		},
+	)
+	dto, err := uc.Get(context.Background(), "LN-7")
+	if err != nil || dto.Transitions == nil || len(dto.Transitions) != 0 {
+		t.Fatalf("want empty non-nil transitions, got %+v err=%v", dto, err)
+	}
+}
+
+func TestGet_LoanNotFound(t *testing.T) {
+	uc := NewUsecase(
+		&loanmock.Repo{
+			GetByLoanIDFn: func(context.Context, string) (*loan.Loan, error) { return nil, gorm.ErrRecordNotFound },
+		},
+		&loanmock.HistoryRepo{},
+	)
+	if _, err := uc.Get(context.Background(), "LN-404"); !errors.Is(err, loan.ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
+
+func TestGet_HistoryError(t *testing.T) {
+	uc := NewUsecase(
+		&loanmock.Repo{
+			GetByLoanIDFn: func(ctx context.Context, loanID string) (*loan.Loan, error) {
+				return &loan.Loan{ID: 7}, nil
+			},
+		},
+		&loanmock.HistoryRepo{}, // default → context.Canceled
+	)
+	if _, err := uc.Get(context.Background(), "LN-7"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
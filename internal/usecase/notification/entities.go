@@ -0,0 +1,28 @@
+package notification
+
+import "time"
+
+type NotificationDTO struct {
+	NotificationID string     `json:"notification_id"`
+	Template       string     `json:"template"`
+	RecipientID    string     `json:"recipient_id"`
+	Email          string     `json:"email,omitempty"`
+	Status         string     `json:"status"`
+	Attempts       uint16     `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"` // pending only
+	LastError      string     `json:"last_error,omitempty"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type NotificationListDTO struct {
+	LoanID string            `json:"loan_id"`
+	Items  []NotificationDTO `json:"items"`
+}
+
+// DispatchResult summarises one dispatcher run.
+type DispatchResult struct {
+	Sent    int `json:"sent"`
+	Retried int `json:"retried"` // failed, rescheduled with backoff
+	Failed  int `json:"failed"`  // failed for the last allowed time
+}
@@ -0,0 +1,263 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"amartha-backend-test/internal/domain/blob"
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainNotification "amartha-backend-test/internal/domain/notification"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/usecase/dispatch"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultMaxAttempts is how often a notification is tried before it is FAILED.
+	DefaultMaxAttempts = 8
+	// noAddress is the last_error of SKIPPED notifications.
+	noAddress = "no email address on file"
+)
+
+type Usecase struct {
+	repo        domainNotification.Repository
+	uow         uow.UnitOfWork
+	sender      domainNotification.Sender
+	links       blob.Store
+	linkTTL     time.Duration
+	maxAttempts uint16
+}
+
+// NewUsecase: notifications repo for plain reads and writes, UoW for fan-out
+// and claiming, sender to deliver; links signs the agreement URLs put in
+// messages, valid for linkTTL.
+func NewUsecase(repo domainNotification.Repository, tx uow.UnitOfWork, sender domainNotification.Sender, links blob.Store, linkTTL time.Duration) *Usecase {
+	return &Usecase{repo: repo, uow: tx, sender: sender, links: links, linkTTL: linkTTL, maxAttempts: DefaultMaxAttempts}
+}
+
+// WithMaxAttempts caps how often a notification is tried (n >= 1).
+func (u *Usecase) WithMaxAttempts(n int) *Usecase {
+	u.maxAttempts = uint16(max(n, 1))
+	return u
+}
+
+// Publish queues one TemplateLoanInvested notification per investor when m
+// is LoanInvested and ignores other events; it is an outbox publisher. A
+// replayed event notifies nobody twice. Investors without an email address
+// are recorded as SKIPPED.
+func (u *Usecase) Publish(ctx context.Context, m domainOutbox.Message) error {
+	if m.EventType != domainOutbox.LoanInvested {
+		return nil
+	}
+	if u.uow == nil {
+		return domainLoan.ErrInvalidTransition
+	}
+	var ev domainOutbox.LoanEvent
+	if err := json.Unmarshal([]byte(m.Payload), &ev); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	return u.uow.WithinTx(ctx, func(r uow.Repos) error {
+		l, err := r.Loans.GetByLoanID(ctx, ev.Loan.LoanID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// cancelled since; there is no agreement to send
+				return nil
+			}
+			return err
+		}
+		invs, err := r.Investments.ListByLoanID(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		for _, rc := range recipientsOf(invs) {
+			_, err := r.Notifications.Get(ctx, m.EventID, rc.investorID)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			data, err := json.Marshal(domainNotification.LoanInvested{
+				LoanID:       l.LoanID,
+				InvestorID:   rc.investorID,
+				Principal:    l.Principal,
+				Amount:       rc.amount,
+				ROI:          l.ROI,
+				Tenor:        l.Tenor,
+				TenorUnit:    string(l.TenorUnit),
+				AgreementKey: l.AgreementLink,
+			})
+			if err != nil {
+				return err
+			}
+			n := &domainNotification.Notification{
+				NotificationID: id.NewID32(),
+				EventID:        m.EventID,
+				Template:       domainNotification.TemplateLoanInvested,
+				LoanID:         l.LoanID,
+				RecipientID:    rc.investorID,
+				Email:          rc.email,
+				Data:           string(data),
+				Status:         domainNotification.StatusPending,
+				NextAttemptAt:  now,
+			}
+			if rc.email == nil {
+				msg := noAddress
+				n.Status, n.LastError = domainNotification.StatusSkipped, &msg
+			}
+			if err := r.Notifications.Create(ctx, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type recipient struct {
+	investorID string
+	email      *string
+	amount     money.Decimal
+}
+
+// recipientsOf merges a loan's investments per investor, in order of their
+// first investment, keeping the most recent email address given.
+func recipientsOf(invs []domainInvestment.Investment) []recipient {
+	var out []recipient
+	at := map[string]int{}
+	for _, inv := range invs {
+		if inv.Status == domainInvestment.StatusReleased {
+			continue
+		}
+		i, ok := at[inv.InvestorID]
+		if !ok {
+			i = len(out)
+			at[inv.InvestorID] = i
+			out = append(out, recipient{investorID: inv.InvestorID, amount: money.Zero})
+		}
+		out[i].amount = out[i].amount.Add(inv.Amount)
+		if inv.InvestorEmail != nil && *inv.InvestorEmail != "" {
+			out[i].email = inv.InvestorEmail
+		}
+	}
+	return out
+}
+
+// Dispatch sends every notification due at now through dispatch.Run.
+func (u *Usecase) Dispatch(ctx context.Context, now time.Time) (*DispatchResult, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	res := &DispatchResult{}
+	err := dispatch.Run(ctx, u.uow, now, dispatch.Queue[domainNotification.Notification]{
+		ListDue: func(ctx context.Context, r uow.Repos, now time.Time, limit int) ([]domainNotification.Notification, error) {
+			return r.Notifications.ListDue(ctx, now, limit)
+		},
+		Claim: func(ctx context.Context, r uow.Repos, n *domainNotification.Notification, until time.Time) error {
+			n.NextAttemptAt = until
+			return r.Notifications.Save(ctx, n)
+		},
+		Send: func(ctx context.Context, n *domainNotification.Notification) error {
+			u.send(ctx, n, now, res)
+			return nil
+		},
+		Save: u.repo.Save,
+	})
+	return res, err
+}
+
+// send renders and sends n once and records the outcome on it: SENT, or
+// rescheduled with backoff, or FAILED after the last allowed attempt.
+func (u *Usecase) send(ctx context.Context, n *domainNotification.Notification, now time.Time, res *DispatchResult) {
+	n.Attempts++
+	msg, err := u.render(ctx, n, now)
+	if err == nil {
+		if n.Email == nil {
+			err = errors.New(noAddress)
+		} else {
+			msg.To = *n.Email
+			err = u.sender.Send(ctx, *msg)
+		}
+	}
+
+	switch {
+	case err == nil:
+		at := now
+		n.Status, n.SentAt, n.LastError = domainNotification.StatusSent, &at, nil
+		res.Sent++
+	case n.Attempts >= u.maxAttempts:
+		e := err.Error()
+		n.Status, n.LastError = domainNotification.StatusFailed, &e
+		res.Failed++
+	default:
+		e := err.Error()
+		n.NextAttemptAt, n.LastError = now.Add(domainNotification.Backoff(n.Attempts)), &e
+		res.Retried++
+	}
+}
+
+// render fills n's template from its stored data; links are signed now, so
+// a retried message never carries an expired one.
+func (u *Usecase) render(ctx context.Context, n *domainNotification.Notification, now time.Time) (*domainNotification.Message, error) {
+	var data any
+	switch n.Template {
+	case domainNotification.TemplateLoanInvested:
+		var d domainNotification.LoanInvested
+		if err := json.Unmarshal([]byte(n.Data), &d); err != nil {
+			return nil, err
+		}
+		if d.AgreementKey != "" && u.links != nil {
+			link, err := u.links.SignedURL(ctx, d.AgreementKey, u.linkTTL)
+			if err != nil {
+				return nil, err
+			}
+			d.AgreementURL, d.LinkExpiresAt = link, now.Add(u.linkTTL).UTC().Truncate(time.Second)
+		}
+		data = d
+	}
+	return domainNotification.Render(n.Template, data)
+}
+
+// ListByLoan returns each recipient's notifications about the loan, oldest
+// first.
+func (u *Usecase) ListByLoan(ctx context.Context, loanID string) (*NotificationListDTO, error) {
+	rows, err := u.repo.ListByLoanID(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	out := &NotificationListDTO{LoanID: loanID, Items: make([]NotificationDTO, 0, len(rows))}
+	for i := range rows {
+		out.Items = append(out.Items, toNotificationDTO(&rows[i]))
+	}
+	return out, nil
+}
+
+func toNotificationDTO(n *domainNotification.Notification) NotificationDTO {
+	dto := NotificationDTO{
+		NotificationID: n.NotificationID,
+		Template:       n.Template,
+		RecipientID:    n.RecipientID,
+		Status:         string(n.Status),
+		Attempts:       n.Attempts,
+		SentAt:         n.SentAt,
+		CreatedAt:      n.CreatedAt,
+	}
+	if n.Email != nil {
+		dto.Email = *n.Email
+	}
+	if n.Status == domainNotification.StatusPending {
+		next := n.NextAttemptAt
+		dto.NextAttemptAt = &next
+	}
+	if n.LastError != nil {
+		dto.LastError = *n.LastError
+	}
+	return dto
+}
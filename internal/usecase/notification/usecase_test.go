@@ -0,0 +1,309 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/investment"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainNotification "amartha-backend-test/internal/domain/notification"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/blobmock"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/notificationmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+var (
+	loanID    = strings.Repeat("1", 32)
+	investorA = strings.Repeat("a", 32)
+	investorB = strings.Repeat("b", 32)
+	investorC = strings.Repeat("c", 32)
+)
+
+func email(s string) *string { return &s }
+
+// store is an in-memory notifications table behind a notificationmock.Repo,
+// next to one invested loan and its investments.
+type store struct {
+	rows []*domainNotification.Notification
+	loan *domainLoan.Loan
+	invs []investment.Investment
+}
+
+func newStore() *store {
+	return &store{
+		loan: &domainLoan.Loan{
+			ID: 9, LoanID: loanID, State: domainLoan.StateInvested, Principal: money.NewFromInt(5_000_000),
+			ROI: money.MustParse("1.2"), Tenor: 12, TenorUnit: domainLoan.TenorMonth,
+			AgreementLink: "agreements/" + loanID + "/agreement-v1.txt",
+		},
+		invs: []investment.Investment{
+			{InvestorID: investorA, Amount: money.NewFromInt(2_000_000), Status: investment.StatusHeld},
+			{InvestorID: investorB, InvestorEmail: email("b@example.com"), Amount: money.NewFromInt(1_000_000), Status: investment.StatusHeld},
+			{InvestorID: investorC, InvestorEmail: email("c@example.com"), Amount: money.NewFromInt(500_000), Status: investment.StatusReleased},
+			{InvestorID: investorA, InvestorEmail: email("a@example.com"), Amount: money.NewFromInt(2_000_000), Status: investment.StatusHeld},
+		},
+	}
+}
+
+func (s *store) repo() *notificationmock.Repo {
+	return &notificationmock.Repo{
+		GetFn: func(ctx context.Context, eventID, recipientID string) (*domainNotification.Notification, error) {
+			for _, n := range s.rows {
+				if n.EventID == eventID && n.RecipientID == recipientID {
+					return n, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		CreateFn: func(ctx context.Context, n *domainNotification.Notification) error {
+			n.ID = uint64(len(s.rows) + 1)
+			s.rows = append(s.rows, n)
+			return nil
+		},
+		ListDueFn: func(ctx context.Context, now time.Time, limit int) ([]domainNotification.Notification, error) {
+			var out []domainNotification.Notification
+			for _, n := range s.rows {
+				if n.Status == domainNotification.StatusPending && !n.NextAttemptAt.After(now) && len(out) < limit {
+					out = append(out, *n)
+				}
+			}
+			return out, nil
+		},
+		SaveFn: func(ctx context.Context, saved *domainNotification.Notification) error {
+			for _, n := range s.rows {
+				if n.ID == saved.ID {
+					*n = *saved
+				}
+			}
+			return nil
+		},
+		ListByLoanIDFn: func(ctx context.Context, id string) ([]domainNotification.Notification, error) {
+			var out []domainNotification.Notification
+			for _, n := range s.rows {
+				if n.LoanID == id {
+					out = append(out, *n)
+				}
+			}
+			return out, nil
+		},
+	}
+}
+
+// usecase wires a Usecase over s; its UoW hands the same repos to every tx.
+func (s *store) usecase(sender domainNotification.Sender) *Usecase {
+	repo := s.repo()
+	loans := &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, id string) (*domainLoan.Loan, error) {
+			if s.loan == nil || id != s.loan.LoanID {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return s.loan, nil
+		},
+	}
+	invs := &investmentmock.Repo{
+		ListByLoanIDFn: func(ctx context.Context, id uint64) ([]investment.Investment, error) { return s.invs, nil },
+	}
+	tx := uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+		return fn(uow.Repos{Loans: loans, Investments: invs, Notifications: repo})
+	})
+	links := &blobmock.Store{
+		SignedURLFn: func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+			return "http://blobs.test/" + key + "?ttl=" + ttl.String(), nil
+		},
+	}
+	return NewUsecase(repo, tx, sender, links, 72*time.Hour)
+}
+
+// sender answers every send with the next error (nil = accepted) and records what it got.
+type sender struct {
+	errs []error
+	got  []domainNotification.Message
+}
+
+func (s *sender) Send(ctx context.Context, m domainNotification.Message) error {
+	s.got = append(s.got, m)
+	if i := len(s.got) - 1; i < len(s.errs) {
+		return s.errs[i]
+	}
+	return nil
+}
+
+func invested(t *testing.T, eventID string) domainOutbox.Message {
+	t.Helper()
+	m, err := domainOutbox.ForLoan(domainOutbox.LoanInvested, &domainLoan.Loan{LoanID: loanID, State: domainLoan.StateInvested})
+	if err != nil {
+		t.Fatalf("ForLoan: %v", err)
+	}
+	m.EventID = eventID
+	return *m
+}
+
+func TestUsecase_Publish_OneNotificationPerInvestor(t *testing.T) {
+	s := newStore()
+	uc := s.usecase(&sender{})
+	ctx := context.Background()
+	m := invested(t, strings.Repeat("e", 32))
+
+	if err := uc.Publish(ctx, m); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	// the relay may publish the same message again
+	if err := uc.Publish(ctx, m); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(s.rows) != 2 {
+		t.Fatalf("want one notification per funding investor, got %+v", s.rows)
+	}
+	a, b := s.rows[0], s.rows[1]
+	if a.RecipientID != investorA || a.Email == nil || *a.Email != "a@example.com" || a.Status != domainNotification.StatusPending ||
+		a.EventID != m.EventID || a.LoanID != loanID || a.Template != domainNotification.TemplateLoanInvested || len(a.NotificationID) != 32 {
+		t.Fatalf("investor A: %+v", a)
+	}
+	var data domainNotification.LoanInvested
+	if err := json.Unmarshal([]byte(a.Data), &data); err != nil || !data.Amount.Equal(money.NewFromInt(4_000_000)) ||
+		data.AgreementKey != s.loan.AgreementLink || !data.Principal.Equal(s.loan.Principal) {
+		t.Fatalf("investor A data: %s, %v", a.Data, err)
+	}
+	if b.RecipientID != investorB || *b.Email != "b@example.com" {
+		t.Fatalf("investor B: %+v", b)
+	}
+
+	// other events and cancelled loans notify nobody
+	other := m
+	other.EventType, other.EventID = domainOutbox.LoanApproved, strings.Repeat("f", 32)
+	if err := uc.Publish(ctx, other); err != nil || len(s.rows) != 2 {
+		t.Fatalf("LoanApproved: %v, %d rows", err, len(s.rows))
+	}
+	s.loan = nil
+	if err := uc.Publish(ctx, invested(t, strings.Repeat("d", 32))); err != nil || len(s.rows) != 2 {
+		t.Fatalf("cancelled loan: %v, %d rows", err, len(s.rows))
+	}
+
+	if err := NewUsecase(s.repo(), nil, nil, nil, time.Hour).Publish(ctx, m); !errors.Is(err, domainLoan.ErrInvalidTransition) {
+		t.Fatalf("nil uow: want ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestUsecase_Publish_SkipsInvestorsWithoutAddress(t *testing.T) {
+	s := newStore()
+	s.invs = s.invs[:1]
+	uc := s.usecase(&sender{})
+	if err := uc.Publish(context.Background(), invested(t, strings.Repeat("e", 32))); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(s.rows) != 1 || s.rows[0].Status != domainNotification.StatusSkipped || s.rows[0].Email != nil || *s.rows[0].LastError != noAddress {
+		t.Fatalf("rows: %+v", s.rows)
+	}
+	if res, err := uc.Dispatch(context.Background(), time.Now().UTC().Add(time.Second)); err != nil || *res != (DispatchResult{}) {
+		t.Fatalf("skipped rows must not be sent: %+v, %v", res, err)
+	}
+}
+
+func TestUsecase_Dispatch_SendsWithFreshLink(t *testing.T) {
+	s := newStore()
+	out := &sender{}
+	uc := s.usecase(out)
+	ctx := context.Background()
+	_ = uc.Publish(ctx, invested(t, strings.Repeat("e", 32)))
+
+	now := time.Now().UTC().Add(time.Second).Truncate(time.Second)
+	res, err := uc.Dispatch(ctx, now)
+	if err != nil || *res != (DispatchResult{Sent: 2}) {
+		t.Fatalf("Dispatch = %+v, %v", res, err)
+	}
+	if len(out.got) != 2 || out.got[0].To != "a@example.com" || out.got[1].To != "b@example.com" {
+		t.Fatalf("sent: %+v", out.got)
+	}
+	body := out.got[0].Body
+	for _, want := range []string{
+		"http://blobs.test/" + s.loan.AgreementLink + "?ttl=72h0m0s",
+		now.Add(72 * time.Hour).Format("2006-01-02 15:04 MST"),
+		"Your investment : IDR 4000000.00",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body misses %q:\n%s", want, body)
+		}
+	}
+	if !strings.Contains(out.got[0].Subject, loanID) {
+		t.Fatalf("subject: %q", out.got[0].Subject)
+	}
+	for _, n := range s.rows {
+		if n.Status != domainNotification.StatusSent || n.Attempts != 1 || n.SentAt == nil || !n.SentAt.Equal(now) || n.LastError != nil {
+			t.Fatalf("after send: %+v", n)
+		}
+	}
+	if res, _ := uc.Dispatch(ctx, now.Add(time.Hour)); *res != (DispatchResult{}) || len(out.got) != 2 {
+		t.Fatalf("second run: %+v", res)
+	}
+}
+
+func TestUsecase_Dispatch_RetriesThenFails(t *testing.T) {
+	s := newStore()
+	s.invs = s.invs[1:2] // investor B only
+	refused := errors.New("550 mailbox unavailable")
+	out := &sender{errs: []error{refused, refused, refused}}
+	uc := s.usecase(out).WithMaxAttempts(3)
+	ctx := context.Background()
+	_ = uc.Publish(ctx, invested(t, strings.Repeat("e", 32)))
+	n := s.rows[0]
+
+	now := time.Now().UTC().Add(time.Second)
+	if res, err := uc.Dispatch(ctx, now); err != nil || res.Retried != 1 {
+		t.Fatalf("first run: %+v, %v", res, err)
+	}
+	if n.Status != domainNotification.StatusPending || n.Attempts != 1 || *n.LastError != refused.Error() || !n.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("after a failure: %+v", n)
+	}
+	// still backing off
+	if res, _ := uc.Dispatch(ctx, now.Add(30*time.Second)); *res != (DispatchResult{}) {
+		t.Fatalf("sent during backoff: %+v", res)
+	}
+
+	now = n.NextAttemptAt
+	if res, _ := uc.Dispatch(ctx, now); res.Retried != 1 || !n.NextAttemptAt.Equal(now.Add(2*time.Minute)) {
+		t.Fatalf("second failure: %+v, %+v", res, n)
+	}
+	now = n.NextAttemptAt
+	if res, _ := uc.Dispatch(ctx, now); res.Failed != 1 || n.Status != domainNotification.StatusFailed || n.Attempts != 3 {
+		t.Fatalf("last attempt: %+v, %+v", res, n)
+	}
+	if res, _ := uc.Dispatch(ctx, now.Add(24*time.Hour)); *res != (DispatchResult{}) || len(out.got) != 3 {
+		t.Fatalf("failed notifications must not be retried: %+v", res)
+	}
+}
+
+func TestUsecase_ListByLoan(t *testing.T) {
+	s := newStore()
+	uc := s.usecase(&sender{errs: []error{errors.New("connection refused")}})
+	ctx := context.Background()
+	_ = uc.Publish(ctx, invested(t, strings.Repeat("e", 32)))
+	if _, err := uc.Dispatch(ctx, time.Now().UTC().Add(time.Second)); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	dto, err := uc.ListByLoan(ctx, loanID)
+	if err != nil || dto.LoanID != loanID || len(dto.Items) != 2 {
+		t.Fatalf("ListByLoan = %+v, %v", dto, err)
+	}
+	a, b := dto.Items[0], dto.Items[1]
+	if a.RecipientID != investorA || a.Status != "PENDING" || a.NextAttemptAt == nil || a.LastError != "connection refused" || a.Attempts != 1 {
+		t.Fatalf("retrying item: %+v", a)
+	}
+	if b.Status != "SENT" || b.SentAt == nil || b.NextAttemptAt != nil || b.Email != "b@example.com" || b.Template != "loan_invested" {
+		t.Fatalf("sent item: %+v", b)
+	}
+	if empty, err := uc.ListByLoan(ctx, strings.Repeat("9", 32)); err != nil || empty.Items == nil || len(empty.Items) != 0 {
+		t.Fatalf("no notifications: %+v, %v", empty, err)
+	}
+}
@@ -0,0 +1,8 @@
+package outbox
+
+// RelayResult summarises one relay run.
+type RelayResult struct {
+	Sent    int `json:"sent"`
+	Retried int `json:"retried"` // failed, rescheduled with backoff
+	Failed  int `json:"failed"`  // failed for the last allowed time
+}
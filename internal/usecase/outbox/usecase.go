@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/usecase/dispatch"
+)
+
+const (
+	// batchSize bounds how many messages one relay claim takes.
+	batchSize = 100
+	// DefaultMaxAttempts is how often a message is tried before it is FAILED.
+	DefaultMaxAttempts = 10
+)
+
+type Usecase struct {
+	uow         uow.UnitOfWork
+	publisher   domainOutbox.Publisher
+	maxAttempts uint16
+}
+
+// NewUsecase: UoW to lock and update outbox rows, publisher to deliver them.
+func NewUsecase(tx uow.UnitOfWork, pub domainOutbox.Publisher) *Usecase {
+	return &Usecase{uow: tx, publisher: pub, maxAttempts: DefaultMaxAttempts}
+}
+
+// WithMaxAttempts caps how often a message is tried (n >= 1).
+func (u *Usecase) WithMaxAttempts(n int) *Usecase {
+	u.maxAttempts = uint16(max(n, 1))
+	return u
+}
+
+// Relay publishes every message due at now through dispatch.Run, so a slow
+// publisher never holds outbox row locks. Delivery is at-least-once: a
+// message published but not yet saved as SENT when the run dies is
+// published again after its lease, so consumers dedupe on event_id.
+func (u *Usecase) Relay(ctx context.Context, now time.Time) (*RelayResult, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	res := &RelayResult{}
+	err := dispatch.Run(ctx, u.uow, now, dispatch.Queue[domainOutbox.Message]{
+		Batch: batchSize,
+		ListDue: func(ctx context.Context, r uow.Repos, now time.Time, limit int) ([]domainOutbox.Message, error) {
+			return r.Outbox.ListDue(ctx, now, limit)
+		},
+		Claim: func(ctx context.Context, r uow.Repos, m *domainOutbox.Message, until time.Time) error {
+			m.NextAttemptAt = until
+			return r.Outbox.Save(ctx, m)
+		},
+		Send: func(ctx context.Context, m *domainOutbox.Message) error {
+			u.publish(ctx, m, now, res)
+			return nil
+		},
+		Save: func(ctx context.Context, m *domainOutbox.Message) error {
+			return u.uow.WithinTx(ctx, func(r uow.Repos) error { return r.Outbox.Save(ctx, m) })
+		},
+	})
+	return res, err
+}
+
+// publish sends m once and records the outcome on it: SENT, rescheduled
+// with backoff, or FAILED after the last allowed attempt.
+func (u *Usecase) publish(ctx context.Context, m *domainOutbox.Message, now time.Time, res *RelayResult) {
+	m.Attempts++
+	err := u.publisher.Publish(ctx, *m)
+	switch {
+	case err == nil:
+		sentAt := now
+		m.Status, m.SentAt, m.LastError = domainOutbox.StatusSent, &sentAt, nil
+		res.Sent++
+	case m.Attempts >= u.maxAttempts:
+		msg := err.Error()
+		m.Status, m.LastError = domainOutbox.StatusFailed, &msg
+		res.Failed++
+	default:
+		msg := err.Error()
+		m.NextAttemptAt, m.LastError = now.Add(domainOutbox.Backoff(m.Attempts)), &msg
+		res.Retried++
+	}
+}
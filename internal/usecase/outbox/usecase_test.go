@@ -0,0 +1,146 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/infrastructure/eventbus"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+)
+
+// table is an in-memory outbox: ListDue honours status, next_attempt_at and limit.
+func table(msgs ...*domainOutbox.Message) (*uowmock.UoW, *outboxmock.Repo) {
+	repo := &outboxmock.Repo{
+		ListDueFn: func(ctx context.Context, now time.Time, limit int) ([]domainOutbox.Message, error) {
+			var out []domainOutbox.Message
+			for _, m := range msgs {
+				if m.Status == domainOutbox.StatusPending && !m.NextAttemptAt.After(now) && len(out) < limit {
+					out = append(out, *m)
+				}
+			}
+			return out, nil
+		},
+		SaveFn: func(ctx context.Context, saved *domainOutbox.Message) error {
+			for _, m := range msgs {
+				if m.EventID == saved.EventID {
+					*m = *saved
+				}
+			}
+			return nil
+		},
+	}
+	tx := uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+		return fn(uow.Repos{Outbox: repo})
+	})
+	return tx, repo
+}
+
+func pending(eventID string, at time.Time) *domainOutbox.Message {
+	return &domainOutbox.Message{EventID: eventID, EventType: domainOutbox.LoanApproved, Status: domainOutbox.StatusPending, NextAttemptAt: at}
+}
+
+func TestUsecase_Relay_PublishesAndMarksSent(t *testing.T) {
+	now := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	a, b, later := pending("E-1", now.Add(-time.Minute)), pending("E-2", now), pending("E-3", now.Add(time.Minute))
+	tx, _ := table(a, b, later)
+	bus := eventbus.NewMemory()
+
+	res, err := NewUsecase(tx, bus).Relay(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	if *res != (RelayResult{Sent: 2}) {
+		t.Fatalf("result: %+v", res)
+	}
+	got := bus.Published()
+	if len(got) != 2 || got[0].EventID != "E-1" || got[1].EventID != "E-2" {
+		t.Fatalf("published: %+v", got)
+	}
+	for _, m := range []*domainOutbox.Message{a, b} {
+		if m.Status != domainOutbox.StatusSent || m.Attempts != 1 || m.SentAt == nil || !m.SentAt.Equal(now) {
+			t.Fatalf("%s not marked sent: %+v", m.EventID, m)
+		}
+	}
+	if later.Status != domainOutbox.StatusPending || later.Attempts != 0 {
+		t.Fatalf("not yet due must be left alone: %+v", later)
+	}
+}
+
+func TestUsecase_Relay_RetriesWithBackoffThenFails(t *testing.T) {
+	now := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	m := pending("E-1", now)
+	tx, _ := table(m)
+	bus := eventbus.NewMemory()
+	uc := NewUsecase(tx, bus).WithMaxAttempts(3)
+	boom := errors.New("broker down")
+
+	bus.FailNext(boom, boom, boom)
+	res, err := uc.Relay(context.Background(), now)
+	if err != nil || *res != (RelayResult{Retried: 1}) {
+		t.Fatalf("first run: %+v, %v", res, err)
+	}
+	if m.Attempts != 1 || m.LastError == nil || *m.LastError != "broker down" || !m.NextAttemptAt.Equal(now.Add(5*time.Second)) {
+		t.Fatalf("after first failure: %+v", m)
+	}
+
+	// not due again until the backoff passes
+	if res, _ := uc.Relay(context.Background(), now.Add(time.Second)); *res != (RelayResult{}) {
+		t.Fatalf("relayed during backoff: %+v", res)
+	}
+
+	now = m.NextAttemptAt
+	if res, _ := uc.Relay(context.Background(), now); res.Retried != 1 || !m.NextAttemptAt.Equal(now.Add(10*time.Second)) {
+		t.Fatalf("second failure: %+v, %+v", res, m)
+	}
+	now = m.NextAttemptAt
+	if res, _ := uc.Relay(context.Background(), now); res.Failed != 1 || m.Status != domainOutbox.StatusFailed || m.Attempts != 3 {
+		t.Fatalf("last attempt: %+v, %+v", res, m)
+	}
+	if res, _ := uc.Relay(context.Background(), now.Add(time.Hour)); *res != (RelayResult{}) || len(bus.Published()) != 0 {
+		t.Fatalf("failed messages must not be retried: %+v", res)
+	}
+}
+
+func TestUsecase_Relay_DrainsFullBatches(t *testing.T) {
+	now := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	msgs := make([]*domainOutbox.Message, batchSize+5)
+	for i := range msgs {
+		msgs[i] = pending(fmt.Sprintf("E-%d", i), now)
+	}
+	tx, _ := table(msgs...)
+	bus := eventbus.NewMemory()
+
+	res, err := NewUsecase(tx, bus).Relay(context.Background(), now)
+	if err != nil || res.Sent != len(msgs) || len(bus.Published()) != len(msgs) {
+		t.Fatalf("Relay = %+v, %v; published %d", res, err, len(bus.Published()))
+	}
+}
+
+func TestUsecase_Relay_Errors(t *testing.T) {
+	now := time.Now()
+	if _, err := NewUsecase(nil, eventbus.NewMemory()).Relay(context.Background(), now); !errors.Is(err, domainLoan.ErrInvalidTransition) {
+		t.Fatalf("nil uow: want ErrInvalidTransition, got %v", err)
+	}
+
+	tx, repo := table(pending("E-1", now))
+	saveErr := errors.New("db down")
+	repo.SaveFn = func(context.Context, *domainOutbox.Message) error { return saveErr }
+	if _, err := NewUsecase(tx, eventbus.NewMemory()).Relay(context.Background(), now); !errors.Is(err, saveErr) {
+		t.Fatalf("save error: want %v, got %v", saveErr, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tx, _ = table(pending("E-2", now))
+	bus := eventbus.NewMemory()
+	if _, err := NewUsecase(tx, bus).Relay(ctx, now); !errors.Is(err, context.Canceled) || len(bus.Published()) != 0 {
+		t.Fatalf("cancelled: want context.Canceled and nothing published, got %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+package photo
+
+import (
+	"io"
+	"time"
+)
+
+type UploadInput struct {
+	UploadedBy string    // 32-char hex employee id
+	Body       io.Reader // raw image bytes; read up to photo.MaxBytes+1
+}
+
+type PhotoDTO struct {
+	PhotoID      string    `json:"photo_id"`
+	SHA256       string    `json:"sha256"`
+	ContentType  string    `json:"content_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	UploadedBy   string    `json:"uploaded_by"`
+	URL          string    `json:"url"` // signed, read-only
+	URLExpiresAt time.Time `json:"url_expires_at"`
+}
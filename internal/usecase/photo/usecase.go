@@ -0,0 +1,126 @@
+package photo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	_ "image/jpeg" // decoders for the accepted types
+	_ "image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"amartha-backend-test/internal/domain/blob"
+	domainPhoto "amartha-backend-test/internal/domain/photo"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/phash"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	repo  domainPhoto.Repository
+	store blob.Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewUsecase: ttl is how long the signed URLs it hands out stay valid.
+func NewUsecase(r domainPhoto.Repository, store blob.Store, ttl time.Duration) *Usecase {
+	return &Usecase{repo: r, store: store, ttl: ttl, now: time.Now}
+}
+
+// Upload stores a field-visit photo and records it. The content type is
+// sniffed from the bytes, not taken from the client, and the returned
+// photo_id is the only reference ApproveLoan accepts.
+func (u *Usecase) Upload(ctx context.Context, in UploadInput) (*PhotoDTO, error) {
+	data, err := io.ReadAll(io.LimitReader(in.Body, domainPhoto.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(data) == 0:
+		return nil, domainPhoto.ErrEmpty
+	case len(data) > domainPhoto.MaxBytes:
+		return nil, domainPhoto.ErrTooLarge
+	}
+	ct := http.DetectContentType(data)
+	ext, ok := domainPhoto.Extensions[ct]
+	if !ok {
+		return nil, domainPhoto.ErrUnsupportedType
+	}
+	h, err := perceptualHash(data)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+
+	photoID := id.NewID32()
+	p := &domainPhoto.Photo{
+		PhotoID:     photoID,
+		StorageKey:  "photos/" + photoID + ext,
+		SHA256:      hex.EncodeToString(sum[:]),
+		PHash:       &h,
+		ContentType: ct,
+		SizeBytes:   int64(len(data)),
+		UploadedBy:  in.UploadedBy,
+	}
+	// bytes first: a failed insert leaves an unreferenced blob, never a
+	// row pointing at nothing
+	if err := u.store.Put(ctx, p.StorageKey, bytes.NewReader(data), p.SizeBytes, ct); err != nil {
+		return nil, err
+	}
+	if err := u.repo.Create(ctx, p); err != nil {
+		return nil, err
+	}
+	return u.toDTO(ctx, p)
+}
+
+// perceptualHash decodes the image, checking its dimensions before
+// allocating any pixels.
+func perceptualHash(data []byte) (phash.Hash, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, domainPhoto.ErrUnsupportedType
+	}
+	if cfg.Width*cfg.Height > domainPhoto.MaxPixels {
+		return 0, domainPhoto.ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, domainPhoto.ErrUnsupportedType
+	}
+	return phash.Compute(img), nil
+}
+
+// Get returns the photo with a freshly signed URL.
+func (u *Usecase) Get(ctx context.Context, photoID string) (*PhotoDTO, error) {
+	p, err := u.repo.GetByPhotoID(ctx, photoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainPhoto.ErrNotFound
+		}
+		return nil, err
+	}
+	return u.toDTO(ctx, p)
+}
+
+func (u *Usecase) toDTO(ctx context.Context, p *domainPhoto.Photo) (*PhotoDTO, error) {
+	expires := u.now().Add(u.ttl).UTC().Truncate(time.Second)
+	link, err := u.store.SignedURL(ctx, p.StorageKey, u.ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &PhotoDTO{
+		PhotoID:      p.PhotoID,
+		SHA256:       p.SHA256,
+		ContentType:  p.ContentType,
+		SizeBytes:    p.SizeBytes,
+		UploadedBy:   p.UploadedBy,
+		URL:          link,
+		URLExpiresAt: expires,
+	}, nil
+}
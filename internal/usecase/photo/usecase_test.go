@@ -0,0 +1,158 @@
+package photo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	domainPhoto "amartha-backend-test/internal/domain/photo"
+	"amartha-backend-test/internal/testutil/blobmock"
+	"amartha-backend-test/internal/testutil/photomock"
+
+	"gorm.io/gorm"
+)
+
+var (
+	employeeID = strings.Repeat("e", 32)
+	jpegBytes  = encode(jpeg.Encode, 1)
+	pngBytes   = encode(func(w io.Writer, m image.Image, _ *jpeg.Options) error { return png.Encode(w, m) }, 2)
+)
+
+// encode renders a small patchwork picture; seed varies it.
+func encode(enc func(io.Writer, image.Image, *jpeg.Options) error, seed int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.RGBA{uint8(x / 7 * 53 * seed), uint8(y / 6 * 29), uint8(seed * 40), 255})
+		}
+	}
+	var buf bytes.Buffer
+	_ = enc(&buf, img, nil)
+	return buf.Bytes()
+}
+
+// hugePNG is a valid PNG header declaring w×h pixels, with no pixel data.
+func hugePNG(w, h uint32) []byte {
+	b := append([]byte(nil), pngBytes[:33]...)
+	binary.BigEndian.PutUint32(b[16:], w)
+	binary.BigEndian.PutUint32(b[20:], h)
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+	return b
+}
+
+// gallery is an in-memory photo table plus blob bucket.
+func gallery() (*photomock.Repo, *blobmock.Store, map[string][]byte) {
+	rows := map[string]*domainPhoto.Photo{}
+	blobs := map[string][]byte{}
+	repo := &photomock.Repo{
+		CreateFn: func(ctx context.Context, p *domainPhoto.Photo) error {
+			rows[p.PhotoID] = p
+			return nil
+		},
+		GetByPhotoIDFn: func(ctx context.Context, photoID string) (*domainPhoto.Photo, error) {
+			if p, ok := rows[photoID]; ok {
+				return p, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	store := &blobmock.Store{
+		PutFn: func(ctx context.Context, key string, r io.Reader, size int64, ct string) error {
+			b, _ := io.ReadAll(r)
+			if int64(len(b)) != size {
+				return errors.New("size mismatch")
+			}
+			blobs[key] = b
+			return nil
+		},
+		SignedURLFn: func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+			return "https://blobs.test/" + key + "?sig=x", nil
+		},
+	}
+	return repo, store, blobs
+}
+
+func TestUsecase_Upload(t *testing.T) {
+	repo, store, blobs := gallery()
+	uc := NewUsecase(repo, store, 15*time.Minute)
+	now := time.Date(2025, 9, 6, 10, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	for data, want := range map[string]string{string(jpegBytes): ".jpg", string(pngBytes): ".png"} {
+		dto, err := uc.Upload(context.Background(), UploadInput{UploadedBy: employeeID, Body: strings.NewReader(data)})
+		if err != nil {
+			t.Fatalf("Upload: %v", err)
+		}
+		sum := sha256.Sum256([]byte(data))
+		key := "photos/" + dto.PhotoID + want
+		if dto.SHA256 != hex.EncodeToString(sum[:]) || dto.SizeBytes != int64(len(data)) || dto.UploadedBy != employeeID {
+			t.Fatalf("unexpected dto: %+v", dto)
+		}
+		if p, _ := repo.GetByPhotoID(context.Background(), dto.PhotoID); p.PHash == nil || *p.PHash == 0 {
+			t.Fatalf("perceptual hash not recorded: %+v", p)
+		}
+		if dto.URL != "https://blobs.test/"+key+"?sig=x" || !dto.URLExpiresAt.Equal(now.Add(15*time.Minute)) {
+			t.Fatalf("unexpected link: %s %s", dto.URL, dto.URLExpiresAt)
+		}
+		if !bytes.Equal(blobs[key], []byte(data)) {
+			t.Fatalf("blob %s not stored", key)
+		}
+
+		got, err := uc.Get(context.Background(), dto.PhotoID)
+		if err != nil || got.SHA256 != dto.SHA256 {
+			t.Fatalf("Get = %+v, %v", got, err)
+		}
+	}
+
+	if _, err := uc.Get(context.Background(), strings.Repeat("0", 32)); !errors.Is(err, domainPhoto.ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
+
+func TestUsecase_Upload_Rejects(t *testing.T) {
+	repo, store, blobs := gallery()
+	uc := NewUsecase(repo, store, time.Minute)
+
+	cases := map[string]struct {
+		body io.Reader
+		want error
+	}{
+		"empty":     {strings.NewReader(""), domainPhoto.ErrEmpty},
+		"too large": {io.MultiReader(bytes.NewReader(jpegBytes), bytes.NewReader(make([]byte, domainPhoto.MaxBytes))), domainPhoto.ErrTooLarge},
+		"gif":       {strings.NewReader("GIF89a......"), domainPhoto.ErrUnsupportedType},
+		"text":      {strings.NewReader("https://example.com/a.jpg"), domainPhoto.ErrUnsupportedType},
+		"truncated": {bytes.NewReader(jpegBytes[:len(jpegBytes)/2]), domainPhoto.ErrUnsupportedType},
+		"pixels":    {bytes.NewReader(hugePNG(10000, 10000)), domainPhoto.ErrTooLarge},
+	}
+	for name, c := range cases {
+		if _, err := uc.Upload(context.Background(), UploadInput{UploadedBy: employeeID, Body: c.body}); !errors.Is(err, c.want) {
+			t.Fatalf("%s: want %v, got %v", name, c.want, err)
+		}
+	}
+	if len(blobs) != 0 {
+		t.Fatalf("rejected uploads must not reach the store, got %d blobs", len(blobs))
+	}
+
+	// store failure: nothing recorded
+	sentinel := errors.New("bucket down")
+	store.PutFn = func(context.Context, string, io.Reader, int64, string) error { return sentinel }
+	repo.CreateFn = func(context.Context, *domainPhoto.Photo) error {
+		t.Fatalf("Create called after a failed Put")
+		return nil
+	}
+	if _, err := uc.Upload(context.Background(), UploadInput{UploadedBy: employeeID, Body: bytes.NewReader(jpegBytes)}); !errors.Is(err, sentinel) {
+		t.Fatalf("want %v, got %v", sentinel, err)
+	}
+}
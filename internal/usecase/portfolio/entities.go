@@ -0,0 +1,45 @@
+package portfolio
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+// HoldingDTO is one investment as the investor sees it.
+type HoldingDTO struct {
+	InvestmentID string        `json:"investment_id"`
+	LoanID       string        `json:"loan_id"`
+	LoanState    string        `json:"loan_state"`
+	Status       string        `json:"status"` // held | converted | released
+	Amount       money.Decimal `json:"amount"`
+	// Amount as a percentage of the loan principal, 2 dp
+	Share money.Decimal `json:"share"`
+	ROI   money.Decimal `json:"roi"`
+	// This investment's cut of the loan's scheduled investor interest (ROI / rate of all interest)
+	ExpectedInterest money.Decimal `json:"expected_interest"`
+	ExpectedReturn   money.Decimal `json:"expected_return"` // amount + expected_interest
+	// Paid out so far through repayments
+	RealisedPrincipal money.Decimal `json:"realised_principal"`
+	RealisedInterest  money.Decimal `json:"realised_interest"`
+	Realised          money.Decimal `json:"realised"`
+	CreatedAt         time.Time     `json:"created_at"`
+}
+
+// TotalsDTO sums holdings; one per loan state plus the overall figure.
+type TotalsDTO struct {
+	LoanState        string        `json:"loan_state,omitempty"`
+	Investments      int           `json:"investments"`
+	Invested         money.Decimal `json:"invested"`
+	ExpectedInterest money.Decimal `json:"expected_interest"`
+	ExpectedReturn   money.Decimal `json:"expected_return"`
+	Realised         money.Decimal `json:"realised"`
+}
+
+type PortfolioDTO struct {
+	InvestorID string       `json:"investor_id"`
+	Items      []HoldingDTO `json:"items"`
+	ByState    []TotalsDTO  `json:"by_state"` // in order of first appearance in Items
+	Total      TotalsDTO    `json:"total"`
+	AsOf       time.Time    `json:"as_of"`
+}
@@ -0,0 +1,133 @@
+package portfolio
+
+import (
+	"context"
+	"time"
+
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainPayout "amartha-backend-test/internal/domain/payout"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/money"
+)
+
+var hundred = money.NewFromInt(100)
+
+type Usecase struct {
+	investmentRepo domainInvestment.Repository
+	loanRepo       domainLoan.Repository
+	payoutRepo     domainPayout.Repository
+}
+
+// NewUsecase: read-only; three indexed queries per portfolio (investments by
+// investor, their loans by id, payout sums by investor).
+func NewUsecase(investments domainInvestment.Repository, loans domainLoan.Repository, payouts domainPayout.Repository) *Usecase {
+	return &Usecase{investmentRepo: investments, loanRepo: loans, payoutRepo: payouts}
+}
+
+// Get lists every active investment of the investor, oldest first, with
+// totals per loan state. An investor with no investments gets an empty portfolio.
+func (u *Usecase) Get(ctx context.Context, investorID string) (*PortfolioDTO, error) {
+	invs, err := u.investmentRepo.ListByInvestorID(ctx, investorID)
+	if err != nil {
+		return nil, err
+	}
+	out := &PortfolioDTO{InvestorID: investorID, Items: make([]HoldingDTO, 0, len(invs)), ByState: []TotalsDTO{}, AsOf: time.Now().UTC()}
+	if len(invs) == 0 {
+		return out, nil
+	}
+
+	ids := make([]uint64, 0, len(invs))
+	seen := map[uint64]bool{}
+	for _, inv := range invs {
+		if !seen[inv.LoanID] {
+			seen[inv.LoanID] = true
+			ids = append(ids, inv.LoanID)
+		}
+	}
+	rows, err := u.loanRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	loans := make(map[uint64]*domainLoan.Loan, len(rows))
+	// investor interest over the whole tenor, per loan
+	interest := make(map[uint64]money.Decimal, len(rows))
+	for i := range rows {
+		l := &rows[i]
+		loans[l.ID] = l
+		interest[l.ID] = scheduledInvestorInterest(l)
+	}
+
+	sums, err := u.payoutRepo.SumByInvestorID(ctx, investorID)
+	if err != nil {
+		return nil, err
+	}
+	paid := make(map[uint64]domainPayout.Sum, len(sums))
+	for _, s := range sums {
+		paid[s.InvestmentID] = s
+	}
+
+	byState := map[string]int{}
+	for _, inv := range invs {
+		l, ok := loans[inv.LoanID]
+		if !ok {
+			continue // loan no longer active
+		}
+		h := holding(&inv, l, interest[l.ID], paid[inv.ID])
+		out.Items = append(out.Items, h)
+
+		i, ok := byState[h.LoanState]
+		if !ok {
+			i = len(out.ByState)
+			byState[h.LoanState] = i
+			out.ByState = append(out.ByState, TotalsDTO{LoanState: h.LoanState})
+		}
+		add(&out.ByState[i], &h)
+		add(&out.Total, &h)
+	}
+	return out, nil
+}
+
+func holding(inv *domainInvestment.Investment, l *domainLoan.Loan, loanInterest money.Decimal, paid domainPayout.Sum) HoldingDTO {
+	h := HoldingDTO{
+		InvestmentID:      inv.InvestmentID,
+		LoanID:            l.LoanID,
+		LoanState:         string(l.State),
+		Status:            string(inv.Status),
+		Amount:            inv.Amount,
+		ROI:               l.ROI,
+		RealisedPrincipal: paid.Principal,
+		RealisedInterest:  paid.Interest,
+		Realised:          paid.Total,
+		CreatedAt:         inv.CreatedAt,
+	}
+	if l.Principal.IsPositive() {
+		h.Share = inv.Amount.Mul(hundred).Div(l.Principal, 2)
+		h.ExpectedInterest = loanInterest.Mul(inv.Amount).Div(l.Principal, 2)
+	}
+	h.ExpectedReturn = h.Amount.Add(h.ExpectedInterest)
+	return h
+}
+
+func add(t *TotalsDTO, h *HoldingDTO) {
+	t.Investments++
+	t.Invested = t.Invested.Add(h.Amount)
+	t.ExpectedInterest = t.ExpectedInterest.Add(h.ExpectedInterest)
+	t.ExpectedReturn = t.ExpectedReturn.Add(h.ExpectedReturn)
+	t.Realised = t.Realised.Add(h.Realised)
+}
+
+// scheduledInvestorInterest is the ROI part of all interest the loan's
+// schedule charges. The schedule is derived from the loan terms alone, so this
+// matches the stored schedule of a disbursed loan and previews it before.
+func scheduledInvestorInterest(l *domainLoan.Loan) money.Decimal {
+	items, err := domainRepayment.BuildSchedule(l, time.Now())
+	if err != nil {
+		return money.Zero // loans without a tenor have no schedule
+	}
+	total := money.Zero
+	for _, it := range items {
+		total = total.Add(it.InterestDue)
+	}
+	return domainPayout.InvestorInterest(total, l.Rate, l.ROI)
+}
@@ -0,0 +1,98 @@
+package portfolio
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainInvestment "amartha-backend-test/internal/domain/investment"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainPayout "amartha-backend-test/internal/domain/payout"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/payoutmock"
+	"amartha-backend-test/pkg/money"
+)
+
+const investorID = "alice"
+
+func TestUsecase_Get(t *testing.T) {
+	// flat 2%/month over 10 months on 5M: 1M interest, of which roi/rate = 1/2 to investors
+	disbursed := domainLoan.Loan{
+		ID: 1, LoanID: "LN-1", State: domainLoan.StateDisbursed, Principal: money.NewFromInt(5_000_000),
+		Rate: money.MustParse("2"), ROI: money.MustParse("1"), Tenor: 10, TenorUnit: domainLoan.TenorMonth,
+	}
+	approved := domainLoan.Loan{
+		ID: 2, LoanID: "LN-2", State: domainLoan.StateApproved, Principal: money.NewFromInt(3_000_000),
+		Rate: money.MustParse("1.5"), ROI: money.MustParse("1.5"), Tenor: 3, TenorUnit: domainLoan.TenorMonth,
+	}
+	u := NewUsecase(
+		&investmentmock.Repo{ListByInvestorIDFn: func(ctx context.Context, id string) ([]domainInvestment.Investment, error) {
+			if id != investorID {
+				t.Fatalf("investor mismatch: %s", id)
+			}
+			return []domainInvestment.Investment{
+				{ID: 10, InvestmentID: "INV-10", LoanID: 1, Amount: money.NewFromInt(1_000_000), Status: domainInvestment.StatusConverted},
+				{ID: 11, InvestmentID: "INV-11", LoanID: 2, Amount: money.NewFromInt(1_000_000), Status: domainInvestment.StatusHeld},
+				{ID: 12, InvestmentID: "INV-12", LoanID: 1, Amount: money.NewFromInt(500_000), Status: domainInvestment.StatusConverted},
+				{ID: 13, InvestmentID: "INV-13", LoanID: 99, Amount: money.NewFromInt(1), Status: domainInvestment.StatusHeld},
+			}, nil
+		}},
+		&loanmock.Repo{ListByIDsFn: func(ctx context.Context, ids []uint64) ([]domainLoan.Loan, error) {
+			if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 99 {
+				t.Fatalf("each loan fetched once, got %v", ids)
+			}
+			return []domainLoan.Loan{disbursed, approved}, nil // 99 is gone
+		}},
+		&payoutmock.Repo{SumByInvestorIDFn: func(ctx context.Context, id string) ([]domainPayout.Sum, error) {
+			return []domainPayout.Sum{{InvestmentID: 10, Principal: money.NewFromInt(100_000), Interest: money.NewFromInt(10_000), Total: money.NewFromInt(110_000)}}, nil
+		}},
+	)
+
+	p, err := u.Get(context.Background(), investorID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(p.Items) != 3 {
+		t.Fatalf("want 3 holdings (orphan skipped), got %+v", p.Items)
+	}
+	h := p.Items[0]
+	if h.LoanID != "LN-1" || h.LoanState != "disbursed" || h.Status != "converted" || h.Share.String() != "20" ||
+		h.ExpectedInterest.String() != "100000" || h.ExpectedReturn.String() != "1100000" || h.Realised.String() != "110000" {
+		t.Fatalf("holding 10: %+v", h)
+	}
+	// 3M × 1.5% × 3 = 135000, all to investors since roi == rate; a third of it
+	if h := p.Items[1]; h.Share.String() != "33.33" || h.ExpectedInterest.String() != "45000" || !h.Realised.IsZero() {
+		t.Fatalf("holding 11: %+v", h)
+	}
+
+	if len(p.ByState) != 2 || p.ByState[0].LoanState != "disbursed" || p.ByState[0].Investments != 2 ||
+		p.ByState[0].Invested.String() != "1500000" || p.ByState[0].ExpectedInterest.String() != "150000" ||
+		p.ByState[1].LoanState != "approved" || p.ByState[1].Invested.String() != "1000000" {
+		t.Fatalf("by state: %+v", p.ByState)
+	}
+	if p.Total.Investments != 3 || p.Total.Invested.String() != "2500000" || p.Total.ExpectedReturn.String() != "2695000" || p.Total.Realised.String() != "110000" {
+		t.Fatalf("total: %+v", p.Total)
+	}
+}
+
+func TestUsecase_Get_Empty(t *testing.T) {
+	u := NewUsecase(
+		&investmentmock.Repo{ListByInvestorIDFn: func(context.Context, string) ([]domainInvestment.Investment, error) { return nil, nil }},
+		&loanmock.Repo{}, &payoutmock.Repo{}, // not reached
+	)
+	p, err := u.Get(context.Background(), investorID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if p.Items == nil || len(p.Items) != 0 || len(p.ByState) != 0 || !p.Total.Invested.IsZero() {
+		t.Fatalf("empty portfolio: %+v", p)
+	}
+}
+
+func TestUsecase_Get_RepoError(t *testing.T) {
+	u := NewUsecase(&investmentmock.Repo{}, &loanmock.Repo{}, &payoutmock.Repo{})
+	if _, err := u.Get(context.Background(), investorID); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
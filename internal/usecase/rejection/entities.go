@@ -0,0 +1,22 @@
+package rejection
+
+import (
+	"time"
+)
+
+type RejectInput struct {
+	LoanID              string
+	ReasonCode          string // must be in the rejection catalog
+	ReasonText          string // free text; required for OTHER
+	ValidatorEmployeeID string // 32-char hex
+}
+
+type RejectionDTO struct {
+	RejectionID         string    `json:"rejection_id"`
+	LoanID              string    `json:"loan_id"`
+	ReasonCode          string    `json:"reason_code"`
+	ReasonLabel         string    `json:"reason_label"`
+	ReasonText          string    `json:"reason_text,omitempty"`
+	ValidatorEmployeeID string    `json:"validator_employee_id"`
+	RejectedAt          time.Time `json:"rejected_at"`
+}
@@ -0,0 +1,106 @@
+package rejection
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	domainRejection "amartha-backend-test/internal/domain/rejection"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/requestctx"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	rejectionRepo domainRejection.Repository
+	uow           uow.UnitOfWork
+}
+
+// NewUsecase: rejections repo for plain reads, UoW for the locked reject flow.
+func NewUsecase(rejections domainRejection.Repository, tx uow.UnitOfWork) *Usecase {
+	return &Usecase{rejectionRepo: rejections, uow: tx}
+}
+
+func (u *Usecase) Reject(ctx context.Context, in RejectInput) (*RejectionDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	code := domainRejection.ReasonCode(in.ReasonCode)
+	if !code.Known() {
+		return nil, domainRejection.ErrUnknownReason
+	}
+	text := strings.TrimSpace(in.ReasonText)
+	if code == domainRejection.ReasonOther && text == "" {
+		return nil, domainRejection.ErrReasonTextRequired
+	}
+	var dto *RejectionDTO
+
+	err := u.uow.WithinLoanTx(ctx, in.LoanID, func(r uow.Repos, l *domainLoan.Loan) error {
+		// State guard: only proposed → rejected (ErrAlreadyRejected when repeated);
+		// the stamped state_updated_at drives the re-application cooldown
+		reason := string(code)
+		if text != "" {
+			reason += ": " + text
+		}
+		tr, err := domainLoan.Lifecycle.Transition(l, domainLoan.StateRejected, domainLoan.TransitionMeta{
+			Actor:     in.ValidatorEmployeeID,
+			Reason:    reason,
+			RequestID: requestctx.RequestID(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Insert rejection record
+		rj := &domainRejection.Rejection{
+			RejectionID:         id.NewID32(),
+			LoanID:              l.ID, // numeric FK
+			ReasonCode:          code,
+			ReasonText:          text,
+			ValidatorEmployeeID: in.ValidatorEmployeeID,
+			RejectedAt:          l.StateUpdatedAt,
+		}
+		if err := r.Rejections.Create(ctx, rj); err != nil {
+			return err
+		}
+
+		// Persist loan → rejected, with its audit row and outbox event
+		if err := r.Loans.Save(ctx, l); err != nil {
+			return err
+		}
+		if err := r.LoanHistory.Append(ctx, tr); err != nil {
+			return err
+		}
+		ev, err := domainOutbox.ForLoan(domainOutbox.LoanRejected, l)
+		if err != nil {
+			return err
+		}
+		if err := r.Outbox.Append(ctx, ev); err != nil {
+			return err
+		}
+
+		dto = &RejectionDTO{
+			RejectionID:         rj.RejectionID,
+			LoanID:              l.LoanID, // public id
+			ReasonCode:          string(rj.ReasonCode),
+			ReasonLabel:         rj.ReasonCode.Label(),
+			ReasonText:          rj.ReasonText,
+			ValidatorEmployeeID: rj.ValidatorEmployeeID,
+			RejectedAt:          rj.RejectedAt,
+		}
+		return nil
+	})
+
+	if err != nil {
+		// WithinLoanTx surfaces the raw lookup error when the loan row is missing
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+	return dto, nil
+}
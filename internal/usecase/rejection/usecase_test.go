@@ -0,0 +1,166 @@
+package rejection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/rejection"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/rejectionmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+
+	"gorm.io/gorm"
+)
+
+func TestUsecase_Reject(t *testing.T) {
+	validIn := func() RejectInput {
+		return RejectInput{
+			LoanID:              "LN-123",
+			ReasonCode:          string(rejection.ReasonFieldVisitFailed),
+			ReasonText:          "  business address does not exist  ",
+			ValidatorEmployeeID: "EMP-9",
+		}
+	}
+	newProposedLoan := func() *loan.Loan {
+		return &loan.Loan{ID: 777, LoanID: "LN-123", State: loan.StateProposed}
+	}
+	var events []*outbox.Message
+	lockedTx := func(l *loan.Loan, loans *loanmock.Repo, rjs *rejectionmock.Repo) *uowmock.UoW {
+		events = nil
+		outboxRepo := &outboxmock.Repo{AppendFn: func(ctx context.Context, m *outbox.Message) error {
+			events = append(events, m)
+			return nil
+		}}
+		return &uowmock.UoW{
+			WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *loan.Loan) error) error {
+				return fn(uow.Repos{Loans: loans, LoanHistory: &loanmock.HistoryRepo{}, Rejections: rjs, Outbox: outboxRepo}, l)
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		in      RejectInput
+		setup   func() *Usecase
+		wantErr error
+		check   func(*RejectionDTO) error
+	}{
+		{
+			name: "happy path proposed -> rejected",
+			in:   validIn(),
+			setup: func() *Usecase {
+				loans := &loanmock.Repo{
+					SaveFn: func(ctx context.Context, l *loan.Loan) error {
+						if l.State != loan.StateRejected || l.StateUpdatedAt.IsZero() {
+							t.Fatalf("expected rejected with timestamp, got %+v", l)
+						}
+						return nil
+					},
+				}
+				rjs := &rejectionmock.Repo{
+					CreateFn: func(ctx context.Context, r *rejection.Rejection) error {
+						if r.LoanID != 777 || r.ReasonText != "business address does not exist" {
+							t.Fatalf("rejection mismatch: %+v", r)
+						}
+						return nil
+					},
+				}
+				return NewUsecase(rjs, lockedTx(newProposedLoan(), loans, rjs))
+			},
+			check: func(dto *RejectionDTO) error {
+				if dto.LoanID != "LN-123" || dto.ReasonLabel == "" {
+					return errors.New("dto mismatch")
+				}
+				if len(events) != 1 || events[0].EventType != outbox.LoanRejected {
+					return errors.New("want one LoanRejected event")
+				}
+				return nil
+			},
+		},
+		{
+			name: "unknown reason code",
+			in: func() RejectInput {
+				in := validIn()
+				in.ReasonCode = "BAD_VIBES"
+				return in
+			}(),
+			setup:   func() *Usecase { return NewUsecase(nil, &uowmock.UoW{}) },
+			wantErr: rejection.ErrUnknownReason,
+		},
+		{
+			name: "OTHER needs free text",
+			in: func() RejectInput {
+				in := validIn()
+				in.ReasonCode = string(rejection.ReasonOther)
+				in.ReasonText = "   "
+				return in
+			}(),
+			setup:   func() *Usecase { return NewUsecase(nil, &uowmock.UoW{}) },
+			wantErr: rejection.ErrReasonTextRequired,
+		},
+		{
+			name: "already rejected",
+			in:   validIn(),
+			setup: func() *Usecase {
+				l := newProposedLoan()
+				l.State = loan.StateRejected
+				return NewUsecase(nil, lockedTx(l, &loanmock.Repo{}, &rejectionmock.Repo{}))
+			},
+			wantErr: loan.ErrAlreadyRejected,
+		},
+		{
+			name: "approved loan cannot be rejected",
+			in:   validIn(),
+			setup: func() *Usecase {
+				l := newProposedLoan()
+				l.State = loan.StateApproved
+				return NewUsecase(nil, lockedTx(l, &loanmock.Repo{}, &rejectionmock.Repo{}))
+			},
+			wantErr: loan.ErrInvalidTransition,
+		},
+		{
+			name: "loan not found",
+			in:   validIn(),
+			setup: func() *Usecase {
+				tx := &uowmock.UoW{
+					WithinLoanTxFn: func(context.Context, string, func(uow.Repos, *loan.Loan) error) error {
+						return gorm.ErrRecordNotFound
+					},
+				}
+				return NewUsecase(nil, tx)
+			},
+			wantErr: loan.ErrNotFound,
+		},
+		{
+			name:    "nil UoW",
+			in:      validIn(),
+			setup:   func() *Usecase { return NewUsecase(nil, nil) },
+			wantErr: loan.ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			uc := tt.setup()
+			dto, err := uc.Reject(context.Background(), tt.in)
+
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want err=%v, got %v", tt.wantErr, err)
+			}
+			if tt.check != nil && err == nil {
+				if cerr := tt.check(dto); cerr != nil {
+					t.Fatalf("dto check failed: %v", cerr)
+				}
+			}
+		})
+	}
+}
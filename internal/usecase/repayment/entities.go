@@ -0,0 +1,47 @@
+package repayment
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+type RecordInput struct {
+	LoanID              string
+	Amount              money.Decimal // > 0, at most 2 decimals
+	CollectorEmployeeID string        // 32-char hex
+	PaidAt              *time.Time    // optional; defaults to now
+}
+
+type AllocationDTO struct {
+	InstallmentNo uint16        `json:"installment_no"`
+	Fee           money.Decimal `json:"fee"`
+	Interest      money.Decimal `json:"interest"`
+	Principal     money.Decimal `json:"principal"`
+	Settled       bool          `json:"settled"`
+}
+
+// PayoutDTO is one investor's share of a repayment.
+type PayoutDTO struct {
+	PayoutID     string        `json:"payout_id"`
+	InvestmentID string        `json:"investment_id"`
+	InvestorID   string        `json:"investor_id"`
+	Principal    money.Decimal `json:"principal"`
+	Interest     money.Decimal `json:"interest"` // ROI share; the platform keeps the spread
+	Total        money.Decimal `json:"total"`
+}
+
+type RepaymentDTO struct {
+	RepaymentID         string          `json:"repayment_id"`
+	LoanID              string          `json:"loan_id"`
+	Amount              money.Decimal   `json:"amount"`
+	FeePaid             money.Decimal   `json:"fee_paid"`
+	InterestPaid        money.Decimal   `json:"interest_paid"`
+	PrincipalPaid       money.Decimal   `json:"principal_paid"`
+	OutstandingBalance  money.Decimal   `json:"outstanding_balance"`
+	LoanState           string          `json:"loan_state"` // "repaid" once the balance hits zero
+	CollectorEmployeeID string          `json:"collector_employee_id"`
+	PaidAt              time.Time       `json:"paid_at"`
+	Allocations         []AllocationDTO `json:"allocations"`
+	Payouts             []PayoutDTO     `json:"payouts"`
+}
@@ -0,0 +1,185 @@
+package repayment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	domainPayout "amartha-backend-test/internal/domain/payout"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/pkg/id"
+	"amartha-backend-test/pkg/requestctx"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	repaymentRepo domainRepayment.PaymentRepository
+	uow           uow.UnitOfWork
+}
+
+// NewUsecase: repayments repo for plain reads, UoW for the locked record flow.
+func NewUsecase(repayments domainRepayment.PaymentRepository, tx uow.UnitOfWork) *Usecase {
+	return &Usecase{repaymentRepo: repayments, uow: tx}
+}
+
+// Record applies a collected payment to the loan's schedule, splits its
+// principal and ROI interest across the investors as payout rows, and moves
+// the loan to repaid once nothing is left outstanding.
+func (u *Usecase) Record(ctx context.Context, in RecordInput) (*RepaymentDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	paidAt := time.Now().UTC()
+	if in.PaidAt != nil {
+		paidAt = in.PaidAt.UTC()
+	}
+	var dto *RepaymentDTO
+
+	err := u.uow.WithinLoanTx(ctx, in.LoanID, func(r uow.Repos, l *domainLoan.Loan) error {
+		// Only disbursed loans collect; repaid → ErrAlreadyRepaid, earlier states → ErrInvalidTransition
+		if l.State != domainLoan.StateDisbursed {
+			return domainLoan.Lifecycle.CheckEdge(l, domainLoan.StateRepaid)
+		}
+
+		items, err := r.Schedules.ListByLoanID(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		alloc, err := domainRepayment.Allocate(items, in.Amount, paidAt)
+		if err != nil {
+			return err
+		}
+		if err := r.Schedules.UpdatePaid(ctx, touched(items, alloc)); err != nil {
+			return err
+		}
+
+		l.OutstandingBalance = domainRepayment.Outstanding(items)
+		p := &domainRepayment.Repayment{
+			RepaymentID:         id.NewID32(),
+			LoanID:              l.ID, // numeric FK
+			Amount:              in.Amount,
+			FeePaid:             alloc.Fee,
+			InterestPaid:        alloc.Interest,
+			PrincipalPaid:       alloc.Principal,
+			OutstandingAfter:    l.OutstandingBalance,
+			CollectorEmployeeID: in.CollectorEmployeeID,
+			PaidAt:              paidAt,
+		}
+		if err := r.Repayments.Create(ctx, p); err != nil {
+			return err
+		}
+
+		// Investors' cut, pro-rata to their stake; needs p.ID from the insert above
+		investments, err := r.Investments.ListByLoanID(ctx, l.ID)
+		if err != nil {
+			return err
+		}
+		payouts, err := domainPayout.Distribute(l, p, investments)
+		if err != nil {
+			return err
+		}
+		for i := range payouts {
+			payouts[i].PayoutID = id.NewID32()
+		}
+		if err := r.Payouts.CreateBatch(ctx, payouts); err != nil {
+			return err
+		}
+		if err := r.Ledger.Post(ctx, domainLedger.ForRepayment(l, p, payouts)); err != nil {
+			return err
+		}
+
+		// Last cent collected → repaid (terminal), with its audit row; the
+		// edge's guard keeps the loan disbursed while anything is outstanding
+		tr, err := domainLoan.Lifecycle.Transition(l, domainLoan.StateRepaid, domainLoan.TransitionMeta{
+			Actor:     in.CollectorEmployeeID,
+			Reason:    "fully repaid",
+			RequestID: requestctx.RequestID(ctx),
+		})
+		if err != nil && !errors.Is(err, domainLoan.ErrNotFullyRepaid) {
+			return err
+		}
+		if err := r.Loans.Save(ctx, l); err != nil {
+			return err
+		}
+		if tr != nil {
+			if err := r.LoanHistory.Append(ctx, tr); err != nil {
+				return err
+			}
+			ev, err := domainOutbox.ForLoan(domainOutbox.LoanRepaid, l)
+			if err != nil {
+				return err
+			}
+			if err := r.Outbox.Append(ctx, ev); err != nil {
+				return err
+			}
+		}
+
+		dto = &RepaymentDTO{
+			RepaymentID:         p.RepaymentID,
+			LoanID:              l.LoanID, // public id
+			Amount:              p.Amount,
+			FeePaid:             p.FeePaid,
+			InterestPaid:        p.InterestPaid,
+			PrincipalPaid:       p.PrincipalPaid,
+			OutstandingBalance:  p.OutstandingAfter,
+			LoanState:           string(l.State),
+			CollectorEmployeeID: p.CollectorEmployeeID,
+			PaidAt:              p.PaidAt,
+			Allocations:         make([]AllocationDTO, 0, len(alloc.Lines)),
+			Payouts:             make([]PayoutDTO, 0, len(payouts)),
+		}
+		for _, line := range alloc.Lines {
+			dto.Allocations = append(dto.Allocations, AllocationDTO{
+				InstallmentNo: line.InstallmentNo,
+				Fee:           line.Fee,
+				Interest:      line.Interest,
+				Principal:     line.Principal,
+				Settled:       line.Settled,
+			})
+		}
+		publicID := make(map[uint64]string, len(investments))
+		for _, it := range investments {
+			publicID[it.ID] = it.InvestmentID
+		}
+		for _, po := range payouts {
+			dto.Payouts = append(dto.Payouts, PayoutDTO{
+				PayoutID:     po.PayoutID,
+				InvestmentID: publicID[po.InvestmentID],
+				InvestorID:   po.InvestorID,
+				Principal:    po.Principal,
+				Interest:     po.Interest,
+				Total:        po.Total,
+			})
+		}
+		return nil
+	})
+
+	if err != nil {
+		// WithinLoanTx surfaces the raw lookup error when the loan row is missing
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+	return dto, nil
+}
+
+// touched returns the installments that received part of the payment.
+func touched(items []domainRepayment.Installment, alloc *domainRepayment.Allocation) []domainRepayment.Installment {
+	hit := make(map[uint16]bool, len(alloc.Lines))
+	for _, line := range alloc.Lines {
+		hit[line.InstallmentNo] = true
+	}
+	out := make([]domainRepayment.Installment, 0, len(alloc.Lines))
+	for _, it := range items {
+		if hit[it.InstallmentNo] {
+			out = append(out, it)
+		}
+	}
+	return out
+}
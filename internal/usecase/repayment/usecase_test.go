@@ -0,0 +1,280 @@
+package repayment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/investment"
+	"amartha-backend-test/internal/domain/ledger"
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/payout"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/testutil/investmentmock"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/outboxmock"
+	"amartha-backend-test/internal/testutil/payoutmock"
+	"amartha-backend-test/internal/testutil/repaymentmock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+const collector = "cccccccccccccccccccccccccccccccc"
+
+// fixture is a disbursed loan with two 510 installments (10 interest + 500 principal),
+// funded 60/40 by two investors at half the borrower rate
+type fixture struct {
+	loan        *loan.Loan
+	items       []repayment.Installment
+	investments []investment.Investment
+	updated     []repayment.Installment
+	payments    []*repayment.Repayment
+	payouts     []payout.Payout
+	postings    []*ledger.Entry
+	saved       int
+	history     []*loan.StateTransition
+	events      []*outbox.Message
+	schedules   *repaymentmock.Repo
+	tx          *uowmock.UoW
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		loan: &loan.Loan{
+			ID: 7, LoanID: "LN-7", State: loan.StateDisbursed,
+			Rate: money.NewFromInt(2), ROI: money.NewFromInt(1),
+			OutstandingBalance: money.NewFromInt(1020),
+		},
+		investments: []investment.Investment{
+			{ID: 1, InvestmentID: "INV-1", LoanID: 7, InvestorID: "alice", Amount: money.NewFromInt(600)},
+			{ID: 2, InvestmentID: "INV-2", LoanID: 7, InvestorID: "bob", Amount: money.NewFromInt(400)},
+		},
+	}
+	for no := uint16(1); no <= 2; no++ {
+		f.items = append(f.items, repayment.Installment{
+			ID: uint64(no), LoanID: 7, InstallmentNo: no,
+			PrincipalDue: money.NewFromInt(500), InterestDue: money.NewFromInt(10), TotalDue: money.NewFromInt(510),
+		})
+	}
+	f.schedules = &repaymentmock.Repo{
+		ListByLoanIDFn: func(ctx context.Context, id uint64) ([]repayment.Installment, error) {
+			out := make([]repayment.Installment, len(f.items))
+			copy(out, f.items)
+			return out, nil
+		},
+		UpdatePaidFn: func(ctx context.Context, items []repayment.Installment) error {
+			f.updated = items
+			for _, it := range items {
+				f.items[it.InstallmentNo-1] = it
+			}
+			return nil
+		},
+	}
+	repos := uow.Repos{
+		Loans: &loanmock.Repo{SaveFn: func(context.Context, *loan.Loan) error { f.saved++; return nil }},
+		LoanHistory: &loanmock.HistoryRepo{AppendFn: func(ctx context.Context, tr *loan.StateTransition) error {
+			f.history = append(f.history, tr)
+			return nil
+		}},
+		Outbox: &outboxmock.Repo{AppendFn: func(ctx context.Context, m *outbox.Message) error {
+			f.events = append(f.events, m)
+			return nil
+		}},
+		Schedules: f.schedules,
+		Repayments: &repaymentmock.PaymentRepo{CreateFn: func(ctx context.Context, p *repayment.Repayment) error {
+			p.ID = uint64(len(f.payments) + 1)
+			f.payments = append(f.payments, p)
+			return nil
+		}},
+		Investments: &investmentmock.Repo{ListByLoanIDFn: func(ctx context.Context, id uint64) ([]investment.Investment, error) {
+			return f.investments, nil
+		}},
+		Payouts: &payoutmock.Repo{CreateBatchFn: func(ctx context.Context, items []payout.Payout) error {
+			f.payouts = append(f.payouts, items...)
+			return nil
+		}},
+		Ledger: &ledgermock.Repo{PostFn: func(ctx context.Context, e *ledger.Entry) error {
+			f.postings = append(f.postings, e)
+			return nil
+		}},
+	}
+	f.tx = &uowmock.UoW{
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(r uow.Repos, l *loan.Loan) error) error {
+			return fn(repos, f.loan)
+		},
+	}
+	return f
+}
+
+func (f *fixture) record(amount string) (*RepaymentDTO, error) {
+	paidAt := time.Date(2025, 10, 10, 9, 0, 0, 0, time.UTC)
+	return NewUsecase(&repaymentmock.PaymentRepo{}, f.tx).Record(context.Background(), RecordInput{
+		LoanID: "LN-7", Amount: money.MustParse(amount), CollectorEmployeeID: collector, PaidAt: &paidAt,
+	})
+}
+
+func TestRecord_PartialPayment(t *testing.T) {
+	f := newFixture()
+
+	dto, err := f.record("520")
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	// installment 1 settled (10 + 500), then installment 2 interest (10)
+	if dto.InterestPaid.String() != "20" || dto.PrincipalPaid.String() != "500" || !dto.FeePaid.IsZero() {
+		t.Fatalf("split: %+v", dto)
+	}
+	if dto.OutstandingBalance.String() != "500" || f.loan.OutstandingBalance.String() != "500" {
+		t.Fatalf("outstanding: dto=%s loan=%s", dto.OutstandingBalance, f.loan.OutstandingBalance)
+	}
+	if dto.LoanState != "disbursed" || len(f.history) != 0 || len(f.events) != 0 {
+		t.Fatalf("partial payment must not transition: state=%s history=%d", dto.LoanState, len(f.history))
+	}
+	if len(dto.Allocations) != 2 || !dto.Allocations[0].Settled || dto.Allocations[1].Settled {
+		t.Fatalf("allocations: %+v", dto.Allocations)
+	}
+	if len(f.updated) != 2 || f.saved != 1 || len(f.payments) != 1 {
+		t.Fatalf("writes: updated=%d saved=%d payments=%d", len(f.updated), f.saved, len(f.payments))
+	}
+	if p := f.payments[0]; p.LoanID != 7 || len(p.RepaymentID) != 32 || p.CollectorEmployeeID != collector {
+		t.Fatalf("repayment row: %+v", p)
+	}
+}
+
+func TestRecord_DistributesPayouts(t *testing.T) {
+	f := newFixture()
+
+	dto, err := f.record("520")
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	// principal 500 → 300/200; interest 20 at ROI 1 / rate 2 → 10 for investors → 6/4
+	want := []struct {
+		investment, investor string
+		principal, interest  string
+	}{{"INV-1", "alice", "300", "6"}, {"INV-2", "bob", "200", "4"}}
+	if len(dto.Payouts) != 2 || len(f.payouts) != 2 {
+		t.Fatalf("payouts: dto=%d stored=%d", len(dto.Payouts), len(f.payouts))
+	}
+	for i, w := range want {
+		got := dto.Payouts[i]
+		if got.InvestmentID != w.investment || got.InvestorID != w.investor ||
+			got.Principal.String() != w.principal || got.Interest.String() != w.interest {
+			t.Fatalf("payout %d = %+v, want %+v", i, got, w)
+		}
+		row := f.payouts[i]
+		if row.RepaymentID != f.payments[0].ID || row.LoanID != 7 || len(row.PayoutID) != 32 || row.PayoutID != got.PayoutID {
+			t.Fatalf("payout row %d: %+v", i, row)
+		}
+	}
+
+	// One balanced journal entry: the 10 interest spread is platform revenue
+	if len(f.postings) != 1 || f.postings[0].Kind != ledger.KindRepayment || f.postings[0].RefID != dto.RepaymentID {
+		t.Fatalf("postings: %+v", f.postings)
+	}
+	credits := map[ledger.Account]string{}
+	for _, line := range f.postings[0].Lines {
+		if line.Direction == ledger.Credit {
+			credits[ledger.Account{Type: line.Account, OwnerID: line.OwnerID}] = line.Amount.String()
+		}
+	}
+	if credits[ledger.InvestorWallet("alice")] != "306" || credits[ledger.InvestorWallet("bob")] != "204" ||
+		credits[ledger.PlatformFee] != "10" || credits[ledger.BorrowerReceivable("LN-7")] != "500" {
+		t.Fatalf("credits: %v", credits)
+	}
+}
+
+func TestRecord_FinalPaymentMovesToRepaid(t *testing.T) {
+	f := newFixture()
+	if _, err := f.record("510"); err != nil {
+		t.Fatalf("first payment: %v", err)
+	}
+	dto, err := f.record("510")
+	if err != nil {
+		t.Fatalf("final payment: %v", err)
+	}
+	if dto.LoanState != "repaid" || f.loan.State != loan.StateRepaid || !dto.OutstandingBalance.IsZero() {
+		t.Fatalf("expected repaid with zero balance, got %+v", dto)
+	}
+	if len(f.history) != 1 || f.history[0].FromState != loan.StateDisbursed || f.history[0].Actor != collector {
+		t.Fatalf("history: %+v", f.history)
+	}
+	if len(f.events) != 1 || f.events[0].EventType != outbox.LoanRepaid || f.events[0].AggregateID != f.loan.LoanID {
+		t.Fatalf("events: %+v", f.events)
+	}
+	// only installment 2 was touched by the second payment
+	if len(f.updated) != 1 || f.updated[0].InstallmentNo != 2 || f.updated[0].PaidAt == nil {
+		t.Fatalf("updated: %+v", f.updated)
+	}
+
+	// Anything after payoff is a conflict
+	if _, err := f.record("1"); !errors.Is(err, loan.ErrAlreadyRepaid) {
+		t.Fatalf("want ErrAlreadyRepaid, got %v", err)
+	}
+}
+
+func TestRecord_Errors(t *testing.T) {
+	t.Run("overpayment", func(t *testing.T) {
+		f := newFixture()
+		if _, err := f.record("1020.01"); !errors.Is(err, repayment.ErrOverpayment) {
+			t.Fatalf("want ErrOverpayment, got %v", err)
+		}
+		if len(f.payments) != 0 || f.saved != 0 {
+			t.Fatalf("nothing must be written on overpayment")
+		}
+	})
+	t.Run("ahead of schedule", func(t *testing.T) {
+		f := newFixture()
+		f.items[1].DueDate = time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC) // after the 10 Oct payment
+		if _, err := f.record("520"); !errors.Is(err, repayment.ErrOverpayment) {
+			t.Fatalf("want ErrOverpayment, got %v", err)
+		}
+		if len(f.payments) != 0 || f.saved != 0 {
+			t.Fatalf("nothing must be written when paying ahead")
+		}
+	})
+	t.Run("not disbursed", func(t *testing.T) {
+		f := newFixture()
+		f.loan.State = loan.StateInvested
+		if _, err := f.record("10"); !errors.Is(err, loan.ErrInvalidTransition) {
+			t.Fatalf("want ErrInvalidTransition, got %v", err)
+		}
+	})
+	t.Run("loan not found", func(t *testing.T) {
+		tx := &uowmock.UoW{
+			WithinLoanTxFn: func(context.Context, string, func(uow.Repos, *loan.Loan) error) error {
+				return gorm.ErrRecordNotFound
+			},
+		}
+		_, err := NewUsecase(&repaymentmock.PaymentRepo{}, tx).Record(context.Background(), RecordInput{LoanID: "nope", Amount: money.NewFromInt(1)})
+		if !errors.Is(err, loan.ErrNotFound) {
+			t.Fatalf("want loan.ErrNotFound, got %v", err)
+		}
+	})
+	t.Run("schedule update fails", func(t *testing.T) {
+		f := newFixture()
+		boom := errors.New("update failed")
+		f.schedules.UpdatePaidFn = func(context.Context, []repayment.Installment) error { return boom }
+		if _, err := f.record("10"); !errors.Is(err, boom) {
+			t.Fatalf("want %v, got %v", boom, err)
+		}
+	})
+	t.Run("no investments", func(t *testing.T) {
+		f := newFixture()
+		f.investments = nil
+		if _, err := f.record("10"); !errors.Is(err, payout.ErrNoInvestments) {
+			t.Fatalf("want ErrNoInvestments, got %v", err)
+		}
+	})
+	t.Run("nil uow", func(t *testing.T) {
+		if _, err := NewUsecase(&repaymentmock.PaymentRepo{}, nil).Record(context.Background(), RecordInput{}); !errors.Is(err, loan.ErrInvalidTransition) {
+			t.Fatalf("want ErrInvalidTransition, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package schedule
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+type InstallmentDTO struct {
+	InstallmentNo uint16        `json:"installment_no"`
+	DueDate       string        `json:"due_date"` // YYYY-MM-DD
+	Principal     money.Decimal `json:"principal"`
+	Interest      money.Decimal `json:"interest"`
+	Fee           money.Decimal `json:"fee"`
+	Total         money.Decimal `json:"total"`
+	Paid          money.Decimal `json:"paid"`
+	Outstanding   money.Decimal `json:"outstanding"`
+	PaidAt        *time.Time    `json:"paid_at,omitempty"` // set once fully paid
+}
+
+type ScheduleDTO struct {
+	LoanID         string           `json:"loan_id"`
+	Principal      money.Decimal    `json:"principal"`
+	Rate           money.Decimal    `json:"rate"`
+	Tenor          uint16           `json:"tenor"`
+	TenorUnit      string           `json:"tenor_unit"`
+	InterestMethod string           `json:"interest_method"`
+	TotalInterest  money.Decimal    `json:"total_interest"`
+	TotalDue       money.Decimal    `json:"total_due"`
+	Outstanding    money.Decimal    `json:"outstanding"`
+	Installments   []InstallmentDTO `json:"installments"`
+}
@@ -0,0 +1,70 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainRepayment "amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	loanRepo     domainLoan.Repository
+	scheduleRepo domainRepayment.Repository
+}
+
+func NewUsecase(loans domainLoan.Repository, schedules domainRepayment.Repository) *Usecase {
+	return &Usecase{loanRepo: loans, scheduleRepo: schedules}
+}
+
+// Get returns the installments generated at disbursement, first due first.
+func (u *Usecase) Get(ctx context.Context, loanID string) (*ScheduleDTO, error) {
+	l, err := u.loanRepo.GetByLoanID(ctx, loanID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := u.scheduleRepo.ListByLoanID(ctx, l.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Schedules only exist once the loan is disbursed
+	if len(rows) == 0 {
+		return nil, domainRepayment.ErrScheduleNotFound
+	}
+
+	out := &ScheduleDTO{
+		LoanID:         l.LoanID,
+		Principal:      l.Principal,
+		Rate:           l.Rate,
+		Tenor:          l.Tenor,
+		TenorUnit:      string(l.TenorUnit),
+		InterestMethod: string(l.InterestMethod),
+		Installments:   make([]InstallmentDTO, 0, len(rows)),
+	}
+	interest, total := money.Zero, money.Zero
+	for _, r := range rows {
+		interest = interest.Add(r.InterestDue)
+		total = total.Add(r.TotalDue)
+		out.Installments = append(out.Installments, InstallmentDTO{
+			InstallmentNo: r.InstallmentNo,
+			DueDate:       r.DueDate.Format("2006-01-02"),
+			Principal:     r.PrincipalDue,
+			Interest:      r.InterestDue,
+			Fee:           r.FeeDue,
+			Total:         r.TotalDue,
+			Paid:          r.FeePaid.Add(r.InterestPaid).Add(r.PrincipalPaid),
+			Outstanding:   r.Outstanding(),
+			PaidAt:        r.PaidAt,
+		})
+	}
+	out.TotalInterest, out.TotalDue = interest, total
+	out.Outstanding = domainRepayment.Outstanding(rows)
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/repayment"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/repaymentmock"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+func disbursedLoans() *loanmock.Repo {
+	return &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*loan.Loan, error) {
+			return &loan.Loan{
+				ID: 7, LoanID: loanID, State: loan.StateDisbursed,
+				Principal: money.NewFromInt(1000), Rate: money.NewFromInt(1),
+				Tenor: 2, TenorUnit: loan.TenorMonth, InterestMethod: loan.InterestFlat,
+			}, nil
+		},
+	}
+}
+
+func TestGet_Success(t *testing.T) {
+	uc := NewUsecase(disbursedLoans(), &repaymentmock.Repo{
+		ListByLoanIDFn: func(ctx context.Context, id uint64) ([]repayment.Installment, error) {
+			if id != 7 {
+				t.Fatalf("numeric id mismatch: %d", id)
+			}
+			return []repayment.Installment{
+				{InstallmentNo: 1, DueDate: time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC),
+					PrincipalDue: money.NewFromInt(500), InterestDue: money.NewFromInt(10), TotalDue: money.NewFromInt(510),
+					InterestPaid: money.NewFromInt(10), PrincipalPaid: money.NewFromInt(200)},
+				{InstallmentNo: 2, DueDate: time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC),
+					PrincipalDue: money.NewFromInt(500), InterestDue: money.NewFromInt(10), TotalDue: money.NewFromInt(510)},
+			}, nil
+		},
+	})
+
+	dto, err := uc.Get(context.Background(), "LN-7")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if dto.LoanID != "LN-7" || dto.Tenor != 2 || dto.TenorUnit != "month" || dto.InterestMethod != "flat" {
+		t.Fatalf("unexpected header: %+v", dto)
+	}
+	if len(dto.Installments) != 2 || dto.Installments[1].DueDate != "2025-11-10" {
+		t.Fatalf("unexpected installments: %+v", dto.Installments)
+	}
+	if dto.TotalInterest.String() != "20" || dto.TotalDue.String() != "1020" {
+		t.Fatalf("totals: interest=%s due=%s", dto.TotalInterest, dto.TotalDue)
+	}
+	if dto.Installments[0].Paid.String() != "210" || dto.Installments[0].Outstanding.String() != "300" ||
+		dto.Outstanding.String() != "810" {
+		t.Fatalf("paid tracking: %+v outstanding=%s", dto.Installments[0], dto.Outstanding)
+	}
+}
+
+func TestGet_NotDisbursedYet(t *testing.T) {
+	uc := NewUsecase(disbursedLoans(), &repaymentmock.Repo{
+		ListByLoanIDFn: func(context.Context, uint64) ([]repayment.Installment, error) { return nil, nil },
+	})
+	if _, err := uc.Get(context.Background(), "LN-7"); !errors.Is(err, repayment.ErrScheduleNotFound) {
+		t.Fatalf("want ErrScheduleNotFound, got %v", err)
+	}
+}
+
+func TestGet_LoanNotFound(t *testing.T) {
+	uc := NewUsecase(&loanmock.Repo{
+		GetByLoanIDFn: func(context.Context, string) (*loan.Loan, error) { return nil, gorm.ErrRecordNotFound },
+	}, &repaymentmock.Repo{})
+	if _, err := uc.Get(context.Background(), "LN-404"); !errors.Is(err, loan.ErrNotFound) {
+		t.Fatalf("want loan.ErrNotFound, got %v", err)
+	}
+}
+
+func TestGet_RepoError(t *testing.T) {
+	uc := NewUsecase(disbursedLoans(), &repaymentmock.Repo{}) // default ListByLoanID → context.Canceled
+	if _, err := uc.Get(context.Background(), "LN-7"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package signature
+
+import "time"
+
+type RequestInput struct {
+	LoanID      string
+	Provider    string
+	DocumentURL string // optional; defaults to the loan's generated agreement
+	RequestedBy string // 32-char hex employee id
+}
+
+type EnvelopeDTO struct {
+	EnvelopeID        string     `json:"envelope_id"`
+	LoanID            string     `json:"loan_id"`
+	SignatureProvider string     `json:"signature_provider"`
+	SignatureTxID     string     `json:"signature_tx_id"`
+	SignatureStatus   string     `json:"signature_status"`
+	DocumentURL       string     `json:"document_url"`
+	SigningURL        string     `json:"signing_url"`
+	SignedAt          *time.Time `json:"signed_at,omitempty"`
+	DocumentSHA256    string     `json:"document_sha256,omitempty"` // of the signed copy
+	RequestedBy       string     `json:"requested_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type WebhookInput struct {
+	Provider  string
+	Body      []byte // raw, as signed by the provider
+	Signature string // Ax-Signature header
+}
+
+type WebhookDTO struct {
+	EventID         string `json:"event_id"`
+	EnvelopeID      string `json:"envelope_id"`
+	SignatureStatus string `json:"signature_status"` // envelope status after the event
+	// Applied is false when the event did not move the status forward
+	Applied bool `json:"applied"`
+	// Duplicate is true when the event id was already processed
+	Duplicate bool `json:"duplicate"`
+}
@@ -0,0 +1,353 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	domainAgreement "amartha-backend-test/internal/domain/agreement"
+	"amartha-backend-test/internal/domain/blob"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainSig "amartha-backend-test/internal/domain/signature"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/pkg/id"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxSignedBytes caps the signed copy pulled from a provider.
+	maxSignedBytes = 32 << 20
+	// documentURLTTL is how long a provider may fetch the generated
+	// agreement it is sent.
+	documentURLTTL = 24 * time.Hour
+)
+
+type Usecase struct {
+	providers map[string]domainSig.Provider
+	store     blob.Store
+	uow       uow.UnitOfWork
+	now       func() time.Time
+}
+
+// NewUsecase: providers are looked up by Name(); store keeps the signed
+// copies downloaded when an envelope is completed.
+func NewUsecase(tx uow.UnitOfWork, store blob.Store, providers ...domainSig.Provider) *Usecase {
+	byName := make(map[string]domainSig.Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &Usecase{providers: byName, store: store, uow: tx, now: time.Now}
+}
+
+func (u *Usecase) provider(name string) (domainSig.Provider, error) {
+	p, ok := u.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", domainSig.ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// Request sends the loan agreement to the borrower for signing: the one
+// rendered when the loan became invested, unless a DocumentURL is given. A
+// loan has at most one open or signed envelope; a cancelled one may be
+// replaced.
+func (u *Usecase) Request(ctx context.Context, in RequestInput) (*EnvelopeDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	p, err := u.provider(in.Provider)
+	if err != nil {
+		return nil, err
+	}
+	var dto *EnvelopeDTO
+
+	err = u.uow.WithinLoanTx(ctx, in.LoanID, func(r uow.Repos, l *domainLoan.Loan) error {
+		if l.State != domainLoan.StateInvested {
+			return domainSig.ErrLoanNotInvested
+		}
+		prev, err := r.Signatures.GetLatestByLoanID(ctx, l.ID)
+		switch {
+		case err == nil && prev.SignatureStatus != domainSig.StatusCancelled:
+			return domainSig.ErrEnvelopeExists
+		case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		docURL := in.DocumentURL
+		if docURL == "" {
+			if l.AgreementLink == "" {
+				return domainAgreement.ErrNotGenerated
+			}
+			if docURL, err = u.store.SignedURL(ctx, l.AgreementLink, documentURLTTL); err != nil {
+				return err
+			}
+		}
+
+		envelopeID := id.NewID32()
+		pe, err := p.CreateEnvelope(ctx, domainSig.EnvelopeRequest{
+			Reference:   envelopeID,
+			DocumentURL: docURL,
+			SignerID:    l.BorrowerID,
+		})
+		if err != nil {
+			return err
+		}
+		env := &domainSig.Envelope{
+			EnvelopeID:        envelopeID,
+			LoanID:            l.ID,
+			SignatureProvider: p.Name(),
+			SignatureTxID:     pe.TxID,
+			SignatureStatus:   domainSig.StatusPending,
+			DocumentURL:       docURL,
+			SigningURL:        pe.SigningURL,
+			RequestedBy:       in.RequestedBy,
+		}
+		if err := r.Signatures.CreateEnvelope(ctx, env); err != nil {
+			return err
+		}
+		dto = toDTO(env, l.LoanID)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainLoan.ErrNotFound
+		}
+		return nil, err
+	}
+	return dto, nil
+}
+
+// Get returns the loan's latest envelope. A PENDING one is first checked
+// against the provider, so a missed webhook does not leave it stuck. The
+// provider and blob store are called outside any transaction; only the
+// status change itself runs under the envelope's row lock.
+func (u *Usecase) Get(ctx context.Context, loanID string) (*EnvelopeDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	var (
+		l   *domainLoan.Loan
+		env *domainSig.Envelope
+	)
+	err := u.uow.WithinTx(ctx, func(r uow.Repos) error {
+		var err error
+		if l, err = r.Loans.GetByLoanID(ctx, loanID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainLoan.ErrNotFound
+			}
+			return err
+		}
+		if env, err = r.Signatures.GetLatestByLoanID(ctx, l.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainSig.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if env.SignatureStatus != domainSig.StatusPending {
+		return toDTO(env, l.LoanID), nil
+	}
+
+	p, err := u.provider(env.SignatureProvider)
+	if err != nil {
+		return nil, err
+	}
+	status, err := p.FetchStatus(ctx, env.SignatureTxID)
+	if err != nil {
+		return nil, err
+	}
+	if !env.SignatureStatus.Advances(status) {
+		return toDTO(env, l.LoanID), nil
+	}
+	var doc *signedCopy
+	if status == domainSig.StatusSigned {
+		if doc, err = u.fetchSigned(ctx, p, env); err != nil {
+			return nil, err
+		}
+	}
+	at := u.now()
+	err = u.uow.WithinTx(ctx, func(r uow.Repos) error {
+		// a webhook may have settled it meanwhile; only a PENDING row moves
+		locked, err := r.Signatures.GetByProviderTxForUpdate(ctx, env.SignatureProvider, env.SignatureTxID)
+		if err != nil {
+			return err
+		}
+		env = locked
+		if !env.SignatureStatus.Advances(status) {
+			return nil
+		}
+		return apply(ctx, r, env, status, at, doc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(env, l.LoanID), nil
+}
+
+// HandleWebhook applies a provider callback. The signature is checked
+// before anything is read from the body. A SIGNED event has its signed copy
+// pulled and stored before the transaction, which then locks the envelope
+// row, so concurrent events for one envelope apply one after the other; an
+// event id seen before is acknowledged without being applied again, and an
+// event that would move the status backwards is recorded but ignored.
+func (u *Usecase) HandleWebhook(ctx context.Context, in WebhookInput) (*WebhookDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	p, err := u.provider(in.Provider)
+	if err != nil {
+		return nil, err
+	}
+	ev, err := p.ParseWebhook(in.Body, in.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc *signedCopy
+	if ev.Status == domainSig.StatusSigned {
+		var env *domainSig.Envelope
+		fresh := false
+		err := u.uow.WithinTx(ctx, func(r uow.Repos) error {
+			var err error
+			if env, err = r.Signatures.GetByProviderTx(ctx, p.Name(), ev.TxID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domainSig.ErrNotFound
+				}
+				return err
+			}
+			_, err = r.Signatures.GetEvent(ctx, p.Name(), ev.EventID)
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				fresh = true
+			case err != nil:
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Status only moves forward, so what is not PENDING now never will be
+		if fresh && env.SignatureStatus.Advances(ev.Status) {
+			if doc, err = u.fetchSigned(ctx, p, env); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var dto *WebhookDTO
+
+	err = u.uow.WithinTx(ctx, func(r uow.Repos) error {
+		env, err := r.Signatures.GetByProviderTxForUpdate(ctx, p.Name(), ev.TxID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domainSig.ErrNotFound
+			}
+			return err
+		}
+		seen, err := r.Signatures.GetEvent(ctx, p.Name(), ev.EventID)
+		switch {
+		case err == nil:
+			dto = &WebhookDTO{EventID: seen.EventID, EnvelopeID: env.EnvelopeID, SignatureStatus: string(env.SignatureStatus), Applied: seen.Applied, Duplicate: true}
+			return nil
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		applied := env.SignatureStatus.Advances(ev.Status)
+		if applied {
+			if err := apply(ctx, r, env, ev.Status, ev.OccurredAt, doc); err != nil {
+				return err
+			}
+		}
+		if err := r.Signatures.CreateEvent(ctx, &domainSig.Event{
+			SignatureProvider: p.Name(),
+			EventID:           ev.EventID,
+			EnvelopeID:        env.ID,
+			SignatureStatus:   ev.Status,
+			Applied:           applied,
+			OccurredAt:        ev.OccurredAt.UTC(),
+		}); err != nil {
+			return err
+		}
+		dto = &WebhookDTO{EventID: ev.EventID, EnvelopeID: env.EnvelopeID, SignatureStatus: string(env.SignatureStatus), Applied: applied}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// signedCopy is a signed document already kept in the blob store.
+type signedCopy struct {
+	key  string
+	hash string
+}
+
+// fetchSigned pulls env's signed copy from the provider and keeps it. It
+// runs outside any transaction; the key is per envelope, so a repeat only
+// rewrites the same document.
+func (u *Usecase) fetchSigned(ctx context.Context, p domainSig.Provider, env *domainSig.Envelope) (*signedCopy, error) {
+	rc, err := p.DownloadSigned(ctx, env.SignatureTxID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, maxSignedBytes+1))
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSignedBytes {
+		return nil, fmt.Errorf("signed document of %s exceeds %d bytes", env.SignatureTxID, maxSignedBytes)
+	}
+	sum := sha256.Sum256(data)
+	key := "agreements/signed/" + env.EnvelopeID + ".pdf"
+	if err := u.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+		return nil, err
+	}
+	return &signedCopy{key: key, hash: hex.EncodeToString(sum[:])}, nil
+}
+
+// apply moves the locked env to status. On SIGNED the hash of the copy we
+// hold becomes document_sha256: what we record is what we hold, not what
+// the callback claims.
+func apply(ctx context.Context, r uow.Repos, env *domainSig.Envelope, status domainSig.Status, at time.Time, doc *signedCopy) error {
+	if status == domainSig.StatusSigned {
+		if doc == nil {
+			return fmt.Errorf("envelope %s: signed copy was not fetched", env.EnvelopeID)
+		}
+		signedAt := at.UTC()
+		env.SignedAt, env.DocumentSHA256, env.SignedDocumentKey = &signedAt, &doc.hash, &doc.key
+	}
+	env.SignatureStatus = status
+	return r.Signatures.SaveEnvelope(ctx, env)
+}
+
+func toDTO(env *domainSig.Envelope, loanID string) *EnvelopeDTO {
+	dto := &EnvelopeDTO{
+		EnvelopeID:        env.EnvelopeID,
+		LoanID:            loanID,
+		SignatureProvider: env.SignatureProvider,
+		SignatureTxID:     env.SignatureTxID,
+		SignatureStatus:   string(env.SignatureStatus),
+		DocumentURL:       env.DocumentURL,
+		SigningURL:        env.SigningURL,
+		SignedAt:          env.SignedAt,
+		RequestedBy:       env.RequestedBy,
+		CreatedAt:         env.CreatedAt,
+	}
+	if env.DocumentSHA256 != nil {
+		dto.DocumentSHA256 = *env.DocumentSHA256
+	}
+	return dto
+}
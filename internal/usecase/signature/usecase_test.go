@@ -0,0 +1,313 @@
+package signature
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	domainAgreement "amartha-backend-test/internal/domain/agreement"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainSig "amartha-backend-test/internal/domain/signature"
+	"amartha-backend-test/internal/domain/uow"
+	"amartha-backend-test/internal/infrastructure/esign"
+	"amartha-backend-test/internal/testutil/blobmock"
+	"amartha-backend-test/internal/testutil/loanmock"
+	"amartha-backend-test/internal/testutil/signaturemock"
+	"amartha-backend-test/internal/testutil/uowmock"
+
+	"gorm.io/gorm"
+)
+
+var (
+	employeeID = strings.Repeat("e", 32)
+	borrowerID = strings.Repeat("b", 32)
+)
+
+// desk is an in-memory envelope/event table, blob bucket and fake provider
+// around one loan.
+type desk struct {
+	loan      *domainLoan.Loan
+	envelopes []*domainSig.Envelope
+	events    []*domainSig.Event
+	blobs     map[string][]byte
+	fake      *esign.Fake
+	uc        *Usecase
+	inTx      bool // a transaction is open
+	loanLocks int  // WithinLoanTx calls
+}
+
+func newDesk(t *testing.T, state domainLoan.State) *desk {
+	t.Helper()
+	fake, err := esign.NewFake("fake", []byte("0123456789abcdef"), "http://esign.local/sign")
+	if err != nil {
+		t.Fatalf("NewFake: %v", err)
+	}
+	d := &desk{
+		loan:  &domainLoan.Loan{ID: 9, LoanID: "LN-9", BorrowerID: borrowerID, State: state},
+		blobs: map[string][]byte{},
+		fake:  fake,
+	}
+	byTx := func(ctx context.Context, provider, txID string) (*domainSig.Envelope, error) {
+		for _, e := range d.envelopes {
+			if e.SignatureProvider == provider && e.SignatureTxID == txID {
+				return e, nil
+			}
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+	sigs := &signaturemock.Repo{
+		CreateEnvelopeFn: func(ctx context.Context, e *domainSig.Envelope) error {
+			e.ID = uint64(len(d.envelopes) + 1)
+			d.envelopes = append(d.envelopes, e)
+			return nil
+		},
+		GetLatestByLoanIDFn: func(ctx context.Context, loanID uint64) (*domainSig.Envelope, error) {
+			for i := len(d.envelopes) - 1; i >= 0; i-- {
+				if d.envelopes[i].LoanID == loanID {
+					return d.envelopes[i], nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		GetByProviderTxFn:          byTx,
+		GetByProviderTxForUpdateFn: byTx,
+		GetEventFn: func(ctx context.Context, provider, eventID string) (*domainSig.Event, error) {
+			for _, ev := range d.events {
+				if ev.SignatureProvider == provider && ev.EventID == eventID {
+					return ev, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		CreateEventFn: func(ctx context.Context, ev *domainSig.Event) error {
+			d.events = append(d.events, ev)
+			return nil
+		},
+	}
+	store := &blobmock.Store{
+		SignedURLFn: func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+			return "http://blobs/" + key + "?sig", nil
+		},
+		PutFn: func(ctx context.Context, key string, r io.Reader, size int64, ct string) error {
+			if d.inTx {
+				t.Fatalf("blob upload of %s inside a transaction", key)
+			}
+			b, _ := io.ReadAll(r)
+			d.blobs[key] = b
+			return nil
+		},
+	}
+	loans := &loanmock.Repo{
+		GetByLoanIDFn: func(ctx context.Context, loanID string) (*domainLoan.Loan, error) {
+			if loanID != d.loan.LoanID {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return d.loan, nil
+		},
+	}
+	tx := &uowmock.UoW{
+		WithinTxFn: func(ctx context.Context, fn func(uow.Repos) error) error {
+			d.inTx = true
+			defer func() { d.inTx = false }()
+			return fn(uow.Repos{Loans: loans, Signatures: sigs})
+		},
+		WithinLoanTxFn: func(ctx context.Context, loanID string, fn func(uow.Repos, *domainLoan.Loan) error) error {
+			d.loanLocks++
+			if loanID != d.loan.LoanID {
+				return gorm.ErrRecordNotFound
+			}
+			d.inTx = true
+			defer func() { d.inTx = false }()
+			return fn(uow.Repos{Loans: loans, Signatures: sigs}, d.loan)
+		},
+	}
+	d.uc = NewUsecase(tx, store, fake)
+	return d
+}
+
+func (d *desk) request(t *testing.T) *EnvelopeDTO {
+	t.Helper()
+	dto, err := d.uc.Request(context.Background(), RequestInput{
+		LoanID: "LN-9", Provider: "fake", DocumentURL: "http://docs/agreement.pdf", RequestedBy: employeeID,
+	})
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	return dto
+}
+
+func TestUsecase_Request(t *testing.T) {
+	d := newDesk(t, domainLoan.StateInvested)
+	dto := d.request(t)
+	if dto.LoanID != "LN-9" || dto.SignatureStatus != "PENDING" || dto.SignatureProvider != "fake" ||
+		!strings.HasPrefix(dto.SignatureTxID, "fake_") || dto.SigningURL == "" {
+		t.Fatalf("dto: %+v", dto)
+	}
+
+	// one open envelope per loan
+	if _, err := d.uc.Request(context.Background(), RequestInput{LoanID: "LN-9", Provider: "fake", DocumentURL: "http://docs/a.pdf"}); !errors.Is(err, domainSig.ErrEnvelopeExists) {
+		t.Fatalf("second envelope: want ErrEnvelopeExists, got %v", err)
+	}
+	// ...but a cancelled one can be replaced
+	d.envelopes[0].SignatureStatus = domainSig.StatusCancelled
+	if again := d.request(t); again.EnvelopeID == dto.EnvelopeID {
+		t.Fatalf("expected a new envelope")
+	}
+}
+
+func TestUsecase_Request_DefaultsToGeneratedAgreement(t *testing.T) {
+	d := newDesk(t, domainLoan.StateInvested)
+	in := RequestInput{LoanID: "LN-9", Provider: "fake", RequestedBy: employeeID}
+	if _, err := d.uc.Request(context.Background(), in); !errors.Is(err, domainAgreement.ErrNotGenerated) {
+		t.Fatalf("no agreement yet: want ErrNotGenerated, got %v", err)
+	}
+
+	d.loan.AgreementLink = "agreements/LN-9/agreement-v1.txt"
+	dto, err := d.uc.Request(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if dto.DocumentURL != "http://blobs/agreements/LN-9/agreement-v1.txt?sig" {
+		t.Fatalf("document_url = %q", dto.DocumentURL)
+	}
+}
+
+func TestUsecase_Request_Errors(t *testing.T) {
+	in := RequestInput{LoanID: "LN-9", Provider: "fake", DocumentURL: "http://docs/a.pdf", RequestedBy: employeeID}
+
+	if _, err := newDesk(t, domainLoan.StateApproved).uc.Request(context.Background(), in); !errors.Is(err, domainSig.ErrLoanNotInvested) {
+		t.Fatalf("approved loan: want ErrLoanNotInvested, got %v", err)
+	}
+	bad := in
+	bad.Provider = "docusign"
+	if _, err := newDesk(t, domainLoan.StateInvested).uc.Request(context.Background(), bad); !errors.Is(err, domainSig.ErrUnknownProvider) {
+		t.Fatalf("unknown provider: %v", err)
+	}
+	bad = in
+	bad.LoanID = "LN-404"
+	if _, err := newDesk(t, domainLoan.StateInvested).uc.Request(context.Background(), bad); !errors.Is(err, domainLoan.ErrNotFound) {
+		t.Fatalf("missing loan: %v", err)
+	}
+	if _, err := NewUsecase(nil, nil).Request(context.Background(), in); !errors.Is(err, domainLoan.ErrInvalidTransition) {
+		t.Fatalf("nil uow: %v", err)
+	}
+}
+
+func TestUsecase_HandleWebhook(t *testing.T) {
+	d := newDesk(t, domainLoan.StateInvested)
+	env := d.request(t)
+	ctx := context.Background()
+
+	body, sig, err := d.fake.Complete(env.SignatureTxID, domainSig.StatusSigned)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	got, err := d.uc.HandleWebhook(ctx, WebhookInput{Provider: "fake", Body: body, Signature: sig})
+	if err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+	if !got.Applied || got.Duplicate || got.SignatureStatus != "SIGNED" || got.EnvelopeID != env.EnvelopeID {
+		t.Fatalf("dto: %+v", got)
+	}
+
+	// the signed copy was pulled and kept; its hash is what we record
+	row := d.envelopes[0]
+	doc := d.blobs["agreements/signed/"+env.EnvelopeID+".pdf"]
+	sum := sha256.Sum256(doc)
+	if len(doc) == 0 || row.DocumentSHA256 == nil || *row.DocumentSHA256 != hex.EncodeToString(sum[:]) {
+		t.Fatalf("signed copy not stored/hashed: %d bytes, row %+v", len(doc), row)
+	}
+	if row.SignedAt == nil || row.SignedDocumentKey == nil {
+		t.Fatalf("signed_at/key not recorded: %+v", row)
+	}
+
+	// provider retries the same event: acknowledged, not re-applied
+	again, err := d.uc.HandleWebhook(ctx, WebhookInput{Provider: "fake", Body: body, Signature: sig})
+	if err != nil || !again.Duplicate || !again.Applied || len(d.events) != 1 {
+		t.Fatalf("replay: %+v, %v (%d events)", again, err, len(d.events))
+	}
+
+	// a late CANCELLED cannot undo a signature
+	body, sig, _ = d.fake.Complete(env.SignatureTxID, domainSig.StatusCancelled)
+	late, err := d.uc.HandleWebhook(ctx, WebhookInput{Provider: "fake", Body: body, Signature: sig})
+	if err != nil || late.Applied || late.SignatureStatus != "SIGNED" || row.SignatureStatus != domainSig.StatusSigned {
+		t.Fatalf("backwards event: %+v, %v", late, err)
+	}
+	if len(d.events) != 2 || d.events[1].Applied {
+		t.Fatalf("ignored event should still be recorded: %+v", d.events)
+	}
+}
+
+func TestUsecase_HandleWebhook_Errors(t *testing.T) {
+	d := newDesk(t, domainLoan.StateInvested)
+	env := d.request(t)
+	ctx := context.Background()
+	body, sig, _ := d.fake.Complete(env.SignatureTxID, domainSig.StatusCancelled)
+
+	if _, err := d.uc.HandleWebhook(ctx, WebhookInput{Provider: "fake", Body: body, Signature: strings.Repeat("0", 64)}); !errors.Is(err, domainSig.ErrBadSignature) {
+		t.Fatalf("bad signature: %v", err)
+	}
+	if _, err := d.uc.HandleWebhook(ctx, WebhookInput{Provider: "other", Body: body, Signature: sig}); !errors.Is(err, domainSig.ErrUnknownProvider) {
+		t.Fatalf("unknown provider: %v", err)
+	}
+	d.envelopes = nil
+	if _, err := d.uc.HandleWebhook(ctx, WebhookInput{Provider: "fake", Body: body, Signature: sig}); !errors.Is(err, domainSig.ErrNotFound) {
+		t.Fatalf("unknown envelope: %v", err)
+	}
+	if len(d.events) != 0 {
+		t.Fatalf("rejected webhooks must not be recorded: %+v", d.events)
+	}
+}
+
+func TestUsecase_Get_ReconcilesPending(t *testing.T) {
+	d := newDesk(t, domainLoan.StateInvested)
+	ctx := context.Background()
+	if _, err := d.uc.Get(ctx, "LN-9"); !errors.Is(err, domainSig.ErrNotFound) {
+		t.Fatalf("no envelope: want ErrNotFound, got %v", err)
+	}
+
+	env := d.request(t)
+	// signed at the provider, but the webhook never arrived
+	if _, _, err := d.fake.Complete(env.SignatureTxID, domainSig.StatusSigned); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	locks := d.loanLocks
+	got, err := d.uc.Get(ctx, "LN-9")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.SignatureStatus != "SIGNED" || got.SignedAt == nil || got.DocumentSHA256 == "" {
+		t.Fatalf("pending envelope not reconciled: %+v", got)
+	}
+	// a read never takes the loan lock
+	if d.loanLocks != locks {
+		t.Fatalf("Get locked the loan")
+	}
+
+	// a webhook that settled it between the provider call and the write wins
+	d.envelopes[0].SignatureStatus, d.envelopes[0].SignedAt, d.envelopes[0].DocumentSHA256 = domainSig.StatusPending, nil, nil
+	env2 := d.envelopes[0]
+	sigs := d.uc.uow.(*uowmock.UoW)
+	inner := sigs.WithinTxFn
+	calls := 0
+	sigs.WithinTxFn = func(ctx context.Context, fn func(uow.Repos) error) error {
+		calls++
+		if calls == 2 { // the locking write
+			env2.SignatureStatus = domainSig.StatusCancelled
+		}
+		return inner(ctx, fn)
+	}
+	got, err = d.uc.Get(ctx, "LN-9")
+	if err != nil || got.SignatureStatus != "CANCELLED" || env2.SignedAt != nil {
+		t.Fatalf("settled envelope overwritten: %+v, %v", got, err)
+	}
+	sigs.WithinTxFn = inner
+	if _, err := d.uc.Get(ctx, "LN-404"); !errors.Is(err, domainLoan.ErrNotFound) {
+		t.Fatalf("missing loan: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package wallet
+
+import (
+	"time"
+
+	"amartha-backend-test/pkg/money"
+)
+
+type TopUpInput struct {
+	GatewayRef string        // gateway's settlement id (32-char hex); one credit per ref
+	InvestorID string        // 32-char hex
+	Amount     money.Decimal // > 0, at most 2 decimals
+	Status     string        // "PAID" | "FAILED"
+}
+
+type TopUpDTO struct {
+	GatewayRef string        `json:"gateway_ref"`
+	InvestorID string        `json:"investor_id"`
+	Amount     money.Decimal `json:"amount"`
+	Status     string        `json:"status"`
+	Credited   bool          `json:"credited"` // false for FAILED
+	Balance    money.Decimal `json:"balance"`  // wallet balance after the callback
+}
+
+type WalletDTO struct {
+	InvestorID string        `json:"investor_id"`
+	Balance    money.Decimal `json:"balance"` // available to invest
+	AsOf       time.Time     `json:"as_of"`
+}
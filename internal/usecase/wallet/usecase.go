@@ -0,0 +1,108 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/uow"
+	domainWallet "amartha-backend-test/internal/domain/wallet"
+
+	"gorm.io/gorm"
+)
+
+type Usecase struct {
+	ledgerRepo    domainLedger.Repository
+	uow           uow.UnitOfWork
+	gatewaySecret []byte
+}
+
+// NewUsecase: ledger repo for plain reads, UoW for the locked top-up flow.
+func NewUsecase(entries domainLedger.Repository, tx uow.UnitOfWork) *Usecase {
+	return &Usecase{ledgerRepo: entries, uow: tx}
+}
+
+// WithGatewaySecret sets the secret the payment gateway signs callbacks
+// with. Without one every callback is refused.
+func (u *Usecase) WithGatewaySecret(secret string) *Usecase {
+	u.gatewaySecret = []byte(secret)
+	return u
+}
+
+// VerifyCallback checks the gateway's signature over the raw callback body
+// (ErrBadSignature).
+func (u *Usecase) VerifyCallback(body []byte, sig string) error {
+	if !domainWallet.VerifyCallback(u.gatewaySecret, body, sig) {
+		return domainWallet.ErrBadSignature
+	}
+	return nil
+}
+
+// Get returns the investor's available balance. Unknown investors simply have 0.
+func (u *Usecase) Get(ctx context.Context, investorID string) (*WalletDTO, error) {
+	bal, err := u.ledgerRepo.Balance(ctx, domainLedger.InvestorWallet(investorID))
+	if err != nil {
+		return nil, err
+	}
+	return &WalletDTO{InvestorID: investorID, Balance: bal, AsOf: time.Now().UTC()}, nil
+}
+
+// TopUp handles the payment gateway's settlement callback. A PAID callback
+// credits the wallet once per gateway reference; replays return the same
+// result, and a replay with different details is ErrTopUpMismatch. FAILED
+// callbacks are acknowledged without touching the ledger.
+func (u *Usecase) TopUp(ctx context.Context, in TopUpInput) (*TopUpDTO, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	if !in.Amount.IsPositive() || in.Amount.Places() > 2 {
+		return nil, domainWallet.ErrInvalidAmount
+	}
+	wallet := domainLedger.InvestorWallet(in.InvestorID)
+	dto := &TopUpDTO{GatewayRef: in.GatewayRef, InvestorID: in.InvestorID, Amount: in.Amount, Status: in.Status}
+
+	err := u.uow.WithinTx(ctx, func(r uow.Repos) error {
+		if err := r.Ledger.Lock(ctx, wallet); err != nil {
+			return err
+		}
+		if domainWallet.TopUpStatus(in.Status) == domainWallet.TopUpPaid {
+			prev, err := r.Ledger.GetByRef(ctx, domainLedger.KindTopUp, in.GatewayRef)
+			switch {
+			case err == nil:
+				if !sameTopUp(prev, wallet, in) {
+					return domainWallet.ErrTopUpMismatch
+				}
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if err := r.Ledger.Post(ctx, domainLedger.ForTopUp(in.InvestorID, in.GatewayRef, in.Amount, time.Now())); err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+			dto.Credited = true
+		}
+
+		bal, err := r.Ledger.Balance(ctx, wallet)
+		if err != nil {
+			return err
+		}
+		dto.Balance = bal
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// sameTopUp reports whether an earlier top-up entry credited the same wallet the same amount.
+func sameTopUp(e *domainLedger.Entry, wallet domainLedger.Account, in TopUpInput) bool {
+	for _, l := range e.Lines {
+		if l.Direction == domainLedger.Credit && l.Account == wallet.Type {
+			return l.OwnerID == wallet.OwnerID && l.Amount.Equal(in.Amount)
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	domainLedger "amartha-backend-test/internal/domain/ledger"
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	"amartha-backend-test/internal/domain/uow"
+	domainWallet "amartha-backend-test/internal/domain/wallet"
+	"amartha-backend-test/internal/testutil/ledgermock"
+	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+var (
+	investorID = strings.Repeat("a", 32)
+	gatewayRef = strings.Repeat("f", 32)
+)
+
+// books is an in-memory ledger: entries keyed by ref, balance = sum of wallet credits.
+func books(t *testing.T) (*ledgermock.Repo, *[]*domainLedger.Entry) {
+	var posted []*domainLedger.Entry
+	locked := false
+	return &ledgermock.Repo{
+		LockFn: func(ctx context.Context, a domainLedger.Account) error {
+			if a != domainLedger.InvestorWallet(investorID) {
+				t.Fatalf("locked unexpected account %+v", a)
+			}
+			locked = true
+			return nil
+		},
+		GetByRefFn: func(ctx context.Context, kind domainLedger.EntryKind, ref string) (*domainLedger.Entry, error) {
+			for _, e := range posted {
+				if e.Kind == kind && e.RefID == ref {
+					return e, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		PostFn: func(ctx context.Context, e *domainLedger.Entry) error {
+			if !locked {
+				t.Fatalf("posted before the wallet was locked")
+			}
+			posted = append(posted, e)
+			return nil
+		},
+		BalanceFn: func(ctx context.Context, a domainLedger.Account) (money.Decimal, error) {
+			sum := money.Zero
+			for _, e := range posted {
+				for _, l := range e.Lines {
+					if l.Account == a.Type && l.OwnerID == a.OwnerID {
+						sum = sum.Add(l.Amount)
+					}
+				}
+			}
+			return sum, nil
+		},
+	}, &posted
+}
+
+func txOver(repo *ledgermock.Repo) *uowmock.UoW {
+	return uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+		return fn(uow.Repos{Ledger: repo})
+	})
+}
+
+func TestUsecase_TopUp(t *testing.T) {
+	repo, posted := books(t)
+	uc := NewUsecase(repo, txOver(repo))
+	in := TopUpInput{GatewayRef: gatewayRef, InvestorID: investorID, Amount: money.MustParse("500000.50"), Status: "PAID"}
+
+	dto, err := uc.TopUp(context.Background(), in)
+	if err != nil {
+		t.Fatalf("TopUp: %v", err)
+	}
+	if !dto.Credited || dto.Balance.String() != "500000.5" {
+		t.Fatalf("dto: %+v", dto)
+	}
+
+	// gateway retries the same callback: acknowledged, not credited twice
+	dto, err = uc.TopUp(context.Background(), in)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(*posted) != 1 || !dto.Credited || dto.Balance.String() != "500000.5" {
+		t.Fatalf("replay posted %d entries, dto %+v", len(*posted), dto)
+	}
+
+	// same ref, different amount
+	other := in
+	other.Amount = money.NewFromInt(1)
+	if _, err := uc.TopUp(context.Background(), other); !errors.Is(err, domainWallet.ErrTopUpMismatch) {
+		t.Fatalf("want ErrTopUpMismatch, got %v", err)
+	}
+
+	got, err := uc.Get(context.Background(), investorID)
+	if err != nil || got.Balance.String() != "500000.5" {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+}
+
+func TestUsecase_TopUp_Failed(t *testing.T) {
+	repo, posted := books(t)
+	dto, err := NewUsecase(repo, txOver(repo)).TopUp(context.Background(), TopUpInput{
+		GatewayRef: gatewayRef, InvestorID: investorID, Amount: money.NewFromInt(100), Status: "FAILED",
+	})
+	if err != nil {
+		t.Fatalf("TopUp: %v", err)
+	}
+	if dto.Credited || !dto.Balance.IsZero() || len(*posted) != 0 {
+		t.Fatalf("FAILED callback must not credit: %+v, %d entries", dto, len(*posted))
+	}
+}
+
+func TestUsecase_TopUp_Errors(t *testing.T) {
+	repo, _ := books(t)
+	in := TopUpInput{GatewayRef: gatewayRef, InvestorID: investorID, Amount: money.MustParse("0.001"), Status: "PAID"}
+	if _, err := NewUsecase(repo, txOver(repo)).TopUp(context.Background(), in); !errors.Is(err, domainWallet.ErrInvalidAmount) {
+		t.Fatalf("want ErrInvalidAmount, got %v", err)
+	}
+	in.Amount = money.NewFromInt(1)
+	if _, err := NewUsecase(repo, nil).TopUp(context.Background(), in); !errors.Is(err, domainLoan.ErrInvalidTransition) {
+		t.Fatalf("nil uow: want ErrInvalidTransition, got %v", err)
+	}
+}
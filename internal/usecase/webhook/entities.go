@@ -0,0 +1,63 @@
+package webhook
+
+import "time"
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 100
+)
+
+type SubscribeInput struct {
+	PartnerID string
+	URL       string
+	Events    []string
+}
+
+type SubscriptionDTO struct {
+	SubscriptionID string   `json:"subscription_id"`
+	PartnerID      string   `json:"partner_id"`
+	URL            string   `json:"url"`
+	Events         []string `json:"events"`
+	// Secret signs every delivery; only returned when the subscription is created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SubscriptionListDTO struct {
+	Items []SubscriptionDTO `json:"items"`
+}
+
+type ListDeliveriesInput struct {
+	PartnerID string
+	Status    string
+	EventID   string
+	Cursor    string
+	Limit     int
+}
+
+type DeliveryDTO struct {
+	DeliveryID     string     `json:"delivery_id"`
+	SubscriptionID string     `json:"subscription_id"`
+	PartnerID      string     `json:"partner_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       uint16     `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"` // pending only
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type DeliveryListDTO struct {
+	Items      []DeliveryDTO `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// DispatchResult summarises one dispatcher run.
+type DispatchResult struct {
+	Delivered int `json:"delivered"`
+	Retried   int `json:"retried"` // failed, rescheduled with backoff
+	Dead      int `json:"dead"`    // failed for the last allowed time
+}
@@ -0,0 +1,363 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	domainWebhook "amartha-backend-test/internal/domain/webhook"
+	"amartha-backend-test/internal/usecase/dispatch"
+	"amartha-backend-test/pkg/id"
+
+	"gorm.io/gorm"
+)
+
+// DefaultMaxAttempts is how often a delivery is tried before it is DEAD.
+const DefaultMaxAttempts = 12
+
+var errSubscriptionRemoved = errors.New("subscription removed")
+
+type Usecase struct {
+	repo        domainWebhook.Repository
+	uow         uow.UnitOfWork
+	poster      domainWebhook.Poster
+	maxAttempts uint16
+	resolver    domainWebhook.Resolver
+	insecure    bool
+}
+
+// NewUsecase: webhooks repo for plain reads and writes, UoW for fan-out and
+// claiming, poster to reach partners.
+func NewUsecase(repo domainWebhook.Repository, tx uow.UnitOfWork, poster domainWebhook.Poster) *Usecase {
+	return &Usecase{repo: repo, uow: tx, poster: poster, maxAttempts: DefaultMaxAttempts, resolver: net.DefaultResolver}
+}
+
+// WithResolver replaces the DNS lookup used to vet subscription hosts.
+func (u *Usecase) WithResolver(r domainWebhook.Resolver) *Usecase {
+	u.resolver = r
+	return u
+}
+
+// WithInsecureTargets accepts plain http and non-public hosts, for local
+// development against a partner stub.
+func (u *Usecase) WithInsecureTargets(allow bool) *Usecase {
+	u.insecure = allow
+	return u
+}
+
+// WithMaxAttempts caps how often a delivery is tried (n >= 1).
+func (u *Usecase) WithMaxAttempts(n int) *Usecase {
+	u.maxAttempts = uint16(max(n, 1))
+	return u
+}
+
+// Subscribe registers a partner endpoint for the given events and returns
+// it with its signing secret, which is not shown again.
+func (u *Usecase) Subscribe(ctx context.Context, in SubscribeInput) (*SubscriptionDTO, error) {
+	if err := u.checkURL(ctx, in.URL); err != nil {
+		return nil, err
+	}
+	var events []string
+	for _, e := range in.Events {
+		if !slices.Contains(domainWebhook.Events, domainOutbox.EventType(e)) {
+			return nil, domainWebhook.ErrInvalidEvents
+		}
+		if !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		return nil, domainWebhook.ErrInvalidEvents
+	}
+
+	s := &domainWebhook.Subscription{
+		SubscriptionID: id.NewID32(),
+		PartnerID:      in.PartnerID,
+		URL:            in.URL,
+		Secret:         id.NewID32() + id.NewID32(),
+		EventTypes:     strings.Join(events, ","),
+	}
+	if err := u.repo.CreateSubscription(ctx, s); err != nil {
+		return nil, err
+	}
+	dto := toSubscriptionDTO(s)
+	dto.Secret = s.Secret
+	return dto, nil
+}
+
+// checkURL accepts absolute https URLs whose host resolves to public
+// addresses only. The poster checks the address again when it dials, since
+// DNS may change after subscribing.
+func (u *Usecase) checkURL(ctx context.Context, raw string) error {
+	p, err := url.Parse(raw)
+	if err != nil || (p.Scheme != "http" && p.Scheme != "https") || p.Hostname() == "" {
+		return domainWebhook.ErrInvalidURL
+	}
+	if u.insecure {
+		return nil
+	}
+	if p.Scheme != "https" {
+		return domainWebhook.ErrInsecureURL
+	}
+	host := p.Hostname()
+	addrs := []netip.Addr{}
+	if a, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, a)
+	} else if addrs, err = u.resolver.LookupNetIP(ctx, "ip", host); err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: %s does not resolve", domainWebhook.ErrInvalidURL, host)
+	}
+	for _, a := range addrs {
+		if !domainWebhook.PublicAddr(a) {
+			return domainWebhook.ErrPrivateHost
+		}
+	}
+	return nil
+}
+
+// ListSubscriptions returns the partner's subscriptions, without secrets.
+func (u *Usecase) ListSubscriptions(ctx context.Context, partnerID string) (*SubscriptionListDTO, error) {
+	subs, err := u.repo.ListSubscriptionsByPartner(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	out := &SubscriptionListDTO{Items: make([]SubscriptionDTO, 0, len(subs))}
+	for i := range subs {
+		out.Items = append(out.Items, *toSubscriptionDTO(&subs[i]))
+	}
+	return out, nil
+}
+
+// Publish queues m for every subscription that wants its type; it is the
+// outbox relay's publisher. A replayed message queues nothing twice.
+func (u *Usecase) Publish(ctx context.Context, m domainOutbox.Message) error {
+	if u.uow == nil {
+		return domainLoan.ErrInvalidTransition
+	}
+	now := time.Now().UTC()
+	return u.uow.WithinTx(ctx, func(r uow.Repos) error {
+		subs, err := r.Webhooks.ListSubscriptions(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range subs {
+			if !s.Wants(m.EventType) {
+				continue
+			}
+			_, err := r.Webhooks.GetDelivery(ctx, s.ID, m.EventID)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err := r.Webhooks.CreateDelivery(ctx, &domainWebhook.Delivery{
+				DeliveryID:     id.NewID32(),
+				SubscriptionID: s.ID,
+				PartnerID:      s.PartnerID,
+				EventID:        m.EventID,
+				EventType:      m.EventType,
+				Payload:        m.Payload,
+				Status:         domainWebhook.StatusPending,
+				NextAttemptAt:  now,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Dispatch delivers every delivery due at now through dispatch.Run.
+func (u *Usecase) Dispatch(ctx context.Context, now time.Time) (*DispatchResult, error) {
+	if u.uow == nil {
+		return nil, domainLoan.ErrInvalidTransition
+	}
+	res := &DispatchResult{}
+	subs := map[uint64]*domainWebhook.Subscription{}
+	err := dispatch.Run(ctx, u.uow, now, dispatch.Queue[domainWebhook.Delivery]{
+		ListDue: func(ctx context.Context, r uow.Repos, now time.Time, limit int) ([]domainWebhook.Delivery, error) {
+			return r.Webhooks.ListDueDeliveries(ctx, now, limit)
+		},
+		Claim: func(ctx context.Context, r uow.Repos, d *domainWebhook.Delivery, until time.Time) error {
+			d.NextAttemptAt = until
+			return r.Webhooks.SaveDelivery(ctx, d)
+		},
+		Send: func(ctx context.Context, d *domainWebhook.Delivery) error {
+			s, err := u.subscription(ctx, subs, d.SubscriptionID)
+			if err != nil {
+				return err
+			}
+			u.deliver(ctx, s, d, now, res)
+			return nil
+		},
+		Save: u.repo.SaveDelivery,
+	})
+	return res, err
+}
+
+// subscription reads a subscription by numeric id through cache.
+func (u *Usecase) subscription(ctx context.Context, cache map[uint64]*domainWebhook.Subscription, subID uint64) (*domainWebhook.Subscription, error) {
+	if s, ok := cache[subID]; ok {
+		return s, nil
+	}
+	s, err := u.repo.GetSubscriptionByID(ctx, subID)
+	if err != nil {
+		return nil, err
+	}
+	cache[subID] = s
+	return s, nil
+}
+
+// deliver posts d once and records the outcome on it: DELIVERED on a 2xx,
+// otherwise rescheduled with backoff, or DEAD after the last allowed attempt
+// (or at once when the subscription was removed).
+func (u *Usecase) deliver(ctx context.Context, s *domainWebhook.Subscription, d *domainWebhook.Delivery, now time.Time, res *DispatchResult) {
+	d.Attempts++
+	d.LastStatusCode = nil
+
+	err := errSubscriptionRemoved
+	if !s.DeletedAt.Valid {
+		body := []byte(d.Payload)
+		var status int
+		status, err = u.poster.Post(ctx, domainWebhook.Request{
+			URL: s.URL,
+			Headers: map[string]string{
+				domainWebhook.TimestampHeader: strconv.FormatInt(now.Unix(), 10),
+				domainWebhook.SignatureHeader: domainWebhook.Sign([]byte(s.Secret), now, body),
+				domainWebhook.DeliveryHeader:  d.DeliveryID,
+			},
+			Body: body,
+		})
+		if err == nil {
+			d.LastStatusCode = &status
+			if status < 200 || status > 299 {
+				err = fmt.Errorf("partner answered %d", status)
+			}
+		}
+	}
+
+	switch {
+	case err == nil:
+		at := now
+		d.Status, d.DeliveredAt, d.LastError = domainWebhook.StatusDelivered, &at, nil
+		res.Delivered++
+	case d.Attempts >= u.maxAttempts || errors.Is(err, errSubscriptionRemoved):
+		msg := err.Error()
+		d.Status, d.LastError = domainWebhook.StatusDead, &msg
+		res.Dead++
+	default:
+		msg := err.Error()
+		d.NextAttemptAt, d.LastError = now.Add(domainWebhook.Backoff(d.Attempts)), &msg
+		res.Retried++
+	}
+}
+
+// ListDeliveries returns one page of deliveries, newest first.
+func (u *Usecase) ListDeliveries(ctx context.Context, in ListDeliveriesInput) (*DeliveryListDTO, error) {
+	f := domainWebhook.DeliveryFilter{
+		PartnerID: in.PartnerID,
+		Status:    domainWebhook.Status(in.Status),
+		EventID:   in.EventID,
+		Limit:     in.Limit,
+	}
+	if f.Status != "" && !f.Status.Valid() {
+		return nil, fmt.Errorf("unsupported status %q", in.Status)
+	}
+	if f.Limit <= 0 || f.Limit > MaxListLimit {
+		f.Limit = DefaultListLimit
+	}
+	if in.Cursor != "" {
+		before, err := decodeCursor(in.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		f.BeforeID = before
+	}
+
+	// one extra row tells whether another page follows
+	want := f.Limit
+	f.Limit++
+	rows, err := u.repo.SearchDeliveries(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	out := &DeliveryListDTO{Items: make([]DeliveryDTO, 0, len(rows))}
+	if len(rows) > want {
+		rows = rows[:want]
+		out.NextCursor = encodeCursor(rows[want-1].ID)
+	}
+	subs := map[uint64]*domainWebhook.Subscription{}
+	for i := range rows {
+		s, err := u.subscription(ctx, subs, rows[i].SubscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		out.Items = append(out.Items, toDeliveryDTO(&rows[i], s))
+	}
+	return out, nil
+}
+
+// encodeCursor makes the opaque ?cursor= value from the last row's numeric id.
+func encodeCursor(n uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(n, 10)))
+}
+
+func decodeCursor(s string) (uint64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, domainWebhook.ErrInvalidCursor
+	}
+	n, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil || n == 0 {
+		return 0, domainWebhook.ErrInvalidCursor
+	}
+	return n, nil
+}
+
+func toSubscriptionDTO(s *domainWebhook.Subscription) *SubscriptionDTO {
+	dto := &SubscriptionDTO{
+		SubscriptionID: s.SubscriptionID,
+		PartnerID:      s.PartnerID,
+		URL:            s.URL,
+		Events:         []string{},
+		CreatedAt:      s.CreatedAt,
+	}
+	for _, e := range s.Events() {
+		dto.Events = append(dto.Events, string(e))
+	}
+	return dto
+}
+
+func toDeliveryDTO(d *domainWebhook.Delivery, s *domainWebhook.Subscription) DeliveryDTO {
+	dto := DeliveryDTO{
+		DeliveryID:     d.DeliveryID,
+		SubscriptionID: s.SubscriptionID,
+		PartnerID:      d.PartnerID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == domainWebhook.StatusPending {
+		next := d.NextAttemptAt
+		dto.NextAttemptAt = &next
+	}
+	if d.LastError != nil {
+		dto.LastError = *d.LastError
+	}
+	return dto
+}
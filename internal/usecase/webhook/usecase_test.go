@@ -0,0 +1,393 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	domainLoan "amartha-backend-test/internal/domain/loan"
+	domainOutbox "amartha-backend-test/internal/domain/outbox"
+	"amartha-backend-test/internal/domain/uow"
+	domainWebhook "amartha-backend-test/internal/domain/webhook"
+	"amartha-backend-test/internal/testutil/uowmock"
+	"amartha-backend-test/internal/testutil/webhookmock"
+
+	"gorm.io/gorm"
+)
+
+var (
+	partnerA = strings.Repeat("a", 32)
+	partnerB = strings.Repeat("b", 32)
+)
+
+// store is an in-memory webhooks table pair behind a webhookmock.Repo.
+type store struct {
+	subs []*domainWebhook.Subscription
+	dels []*domainWebhook.Delivery
+}
+
+func (s *store) repo() *webhookmock.Repo {
+	return &webhookmock.Repo{
+		CreateSubscriptionFn: func(ctx context.Context, sub *domainWebhook.Subscription) error {
+			sub.ID = uint64(len(s.subs) + 1)
+			s.subs = append(s.subs, sub)
+			return nil
+		},
+		ListSubscriptionsByPartnerFn: func(ctx context.Context, partnerID string) ([]domainWebhook.Subscription, error) {
+			var out []domainWebhook.Subscription
+			for _, sub := range s.subs {
+				if sub.PartnerID == partnerID && !sub.DeletedAt.Valid {
+					out = append(out, *sub)
+				}
+			}
+			return out, nil
+		},
+		ListSubscriptionsFn: func(ctx context.Context) ([]domainWebhook.Subscription, error) {
+			var out []domainWebhook.Subscription
+			for _, sub := range s.subs {
+				if !sub.DeletedAt.Valid {
+					out = append(out, *sub)
+				}
+			}
+			return out, nil
+		},
+		GetSubscriptionByIDFn: func(ctx context.Context, id uint64) (*domainWebhook.Subscription, error) {
+			for _, sub := range s.subs {
+				if sub.ID == id {
+					return sub, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		GetDeliveryFn: func(ctx context.Context, subID uint64, eventID string) (*domainWebhook.Delivery, error) {
+			for _, d := range s.dels {
+				if d.SubscriptionID == subID && d.EventID == eventID {
+					return d, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		CreateDeliveryFn: func(ctx context.Context, d *domainWebhook.Delivery) error {
+			d.ID = uint64(len(s.dels) + 1)
+			s.dels = append(s.dels, d)
+			return nil
+		},
+		ListDueDeliveriesFn: func(ctx context.Context, now time.Time, limit int) ([]domainWebhook.Delivery, error) {
+			var out []domainWebhook.Delivery
+			for _, d := range s.dels {
+				if d.Status == domainWebhook.StatusPending && !d.NextAttemptAt.After(now) && len(out) < limit {
+					out = append(out, *d)
+				}
+			}
+			return out, nil
+		},
+		SaveDeliveryFn: func(ctx context.Context, saved *domainWebhook.Delivery) error {
+			for _, d := range s.dels {
+				if d.ID == saved.ID {
+					*d = *saved
+				}
+			}
+			return nil
+		},
+		SearchDeliveriesFn: func(ctx context.Context, f domainWebhook.DeliveryFilter) ([]domainWebhook.Delivery, error) {
+			var out []domainWebhook.Delivery
+			for i := len(s.dels) - 1; i >= 0 && len(out) < f.Limit; i-- {
+				d := s.dels[i]
+				if (f.PartnerID == "" || d.PartnerID == f.PartnerID) && (f.Status == "" || d.Status == f.Status) &&
+					(f.BeforeID == 0 || d.ID < f.BeforeID) {
+					out = append(out, *d)
+				}
+			}
+			return out, nil
+		},
+	}
+}
+
+// dns answers lookups from a fixed table; unknown hosts do not resolve.
+type dns map[string][]netip.Addr
+
+func (d dns) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	if addrs, ok := d[host]; ok {
+		return addrs, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+var partnerDNS = dns{
+	"a.example":        {netip.MustParseAddr("93.184.215.14")},
+	"b.example":        {netip.MustParseAddr("93.184.215.15")},
+	"coop.example":     {netip.MustParseAddr("93.184.215.16"), netip.MustParseAddr("2606:4700::6810:84e5")},
+	"rebind.example":   {netip.MustParseAddr("93.184.215.17"), netip.MustParseAddr("10.0.0.5")},
+	"metadata.example": {netip.MustParseAddr("169.254.169.254")},
+}
+
+// usecase wires a Usecase over s; its UoW hands the same repo to every tx.
+func (s *store) usecase(p domainWebhook.Poster) *Usecase {
+	repo := s.repo()
+	tx := uowmock.New().WithWithinTx(func(ctx context.Context, fn func(uow.Repos) error) error {
+		return fn(uow.Repos{Webhooks: repo})
+	})
+	return NewUsecase(repo, tx, p).WithResolver(partnerDNS)
+}
+
+// poster answers every post with the next status (or error) and records what it got.
+type poster struct {
+	statuses []int
+	errs     []error
+	got      []domainWebhook.Request
+}
+
+func (p *poster) Post(ctx context.Context, r domainWebhook.Request) (int, error) {
+	p.got = append(p.got, r)
+	i := len(p.got) - 1
+	if i < len(p.errs) && p.errs[i] != nil {
+		return 0, p.errs[i]
+	}
+	if i < len(p.statuses) {
+		return p.statuses[i], nil
+	}
+	return http.StatusOK, nil
+}
+
+func message(t domainOutbox.EventType, eventID string) domainOutbox.Message {
+	return domainOutbox.Message{EventID: eventID, EventType: t, AggregateID: strings.Repeat("1", 32), Payload: `{"type":"` + string(t) + `"}`}
+}
+
+func TestUsecase_Subscribe(t *testing.T) {
+	s := &store{}
+	uc := s.usecase(&poster{})
+
+	dto, err := uc.Subscribe(context.Background(), SubscribeInput{
+		PartnerID: partnerA, URL: "https://coop.example/hooks",
+		Events: []string{"LoanApproved", "LoanInvested", "LoanApproved"},
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if len(dto.SubscriptionID) != 32 || len(dto.Secret) != 64 || strings.Join(dto.Events, ",") != "LoanApproved,LoanInvested" {
+		t.Fatalf("dto: %+v", dto)
+	}
+	if len(s.subs) != 1 || s.subs[0].Secret != dto.Secret || s.subs[0].EventTypes != "LoanApproved,LoanInvested" {
+		t.Fatalf("stored: %+v", s.subs)
+	}
+
+	// listings never show the secret
+	list, err := uc.ListSubscriptions(context.Background(), partnerA)
+	if err != nil || len(list.Items) != 1 || list.Items[0].Secret != "" || list.Items[0].SubscriptionID != dto.SubscriptionID {
+		t.Fatalf("ListSubscriptions = %+v, %v", list, err)
+	}
+	if list, _ := uc.ListSubscriptions(context.Background(), partnerB); list == nil || len(list.Items) != 0 {
+		t.Fatalf("other partner = %+v", list)
+	}
+
+	for name, in := range map[string]SubscribeInput{
+		"ftp url":        {PartnerID: partnerA, URL: "ftp://coop.example", Events: []string{"LoanApproved"}},
+		"relative url":   {PartnerID: partnerA, URL: "/hooks", Events: []string{"LoanApproved"}},
+		"unknown event":  {PartnerID: partnerA, URL: "https://coop.example", Events: []string{"LoanDefaulted"}},
+		"no events":      {PartnerID: partnerA, URL: "https://coop.example"},
+		"malformed url":  {PartnerID: partnerA, URL: "https://%zz", Events: []string{"LoanApproved"}},
+		"no host in url": {PartnerID: partnerA, URL: "https://", Events: []string{"LoanApproved"}},
+	} {
+		_, err := uc.Subscribe(context.Background(), in)
+		if !errors.Is(err, domainWebhook.ErrInvalidURL) && !errors.Is(err, domainWebhook.ErrInvalidEvents) {
+			t.Fatalf("%s: want a validation error, got %v", name, err)
+		}
+	}
+}
+
+func TestUsecase_Subscribe_RefusesNonPublicTargets(t *testing.T) {
+	s := &store{}
+	uc := s.usecase(&poster{})
+	events := []string{"LoanApproved"}
+
+	for name, tc := range map[string]struct {
+		url  string
+		want error
+	}{
+		"plain http":          {"http://coop.example/hooks", domainWebhook.ErrInsecureURL},
+		"loopback literal":    {"https://127.0.0.1/hooks", domainWebhook.ErrPrivateHost},
+		"ipv6 loopback":       {"https://[::1]:8443/hooks", domainWebhook.ErrPrivateHost},
+		"metadata literal":    {"https://169.254.169.254/latest/meta-data", domainWebhook.ErrPrivateHost},
+		"private literal":     {"https://192.168.1.10/hooks", domainWebhook.ErrPrivateHost},
+		"metadata by name":    {"https://metadata.example/", domainWebhook.ErrPrivateHost},
+		"one private address": {"https://rebind.example/hooks", domainWebhook.ErrPrivateHost},
+		"unresolvable":        {"https://nowhere.example/hooks", domainWebhook.ErrInvalidURL},
+	} {
+		if _, err := uc.Subscribe(context.Background(), SubscribeInput{PartnerID: partnerA, URL: tc.url, Events: events}); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: want %v, got %v", name, tc.want, err)
+		}
+	}
+	if len(s.subs) != 0 {
+		t.Fatalf("stored %+v", s.subs)
+	}
+
+	// a public literal is fine, and dev mode accepts a local stub
+	if _, err := uc.Subscribe(context.Background(), SubscribeInput{PartnerID: partnerA, URL: "https://93.184.215.14/hooks", Events: events}); err != nil {
+		t.Fatalf("public literal: %v", err)
+	}
+	if _, err := uc.WithInsecureTargets(true).Subscribe(context.Background(), SubscribeInput{PartnerID: partnerA, URL: "http://localhost:9000/hooks", Events: events}); err != nil {
+		t.Fatalf("insecure targets: %v", err)
+	}
+}
+
+func TestUsecase_Publish_FansOutByFilter(t *testing.T) {
+	s := &store{}
+	uc := s.usecase(&poster{})
+	ctx := context.Background()
+	approvals, _ := uc.Subscribe(ctx, SubscribeInput{PartnerID: partnerA, URL: "https://a.example", Events: []string{"LoanApproved"}})
+	_, _ = uc.Subscribe(ctx, SubscribeInput{PartnerID: partnerB, URL: "https://b.example", Events: []string{"LoanInvested"}})
+	both, _ := uc.Subscribe(ctx, SubscribeInput{PartnerID: partnerB, URL: "https://b.example/all", Events: []string{"LoanApproved", "LoanInvested"}})
+
+	m := message(domainOutbox.LoanApproved, strings.Repeat("e", 32))
+	if err := uc.Publish(ctx, m); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	// the relay may publish the same message again
+	if err := uc.Publish(ctx, m); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(s.dels) != 2 {
+		t.Fatalf("want one delivery per interested subscription, got %+v", s.dels)
+	}
+	for i, want := range []string{approvals.SubscriptionID, both.SubscriptionID} {
+		d := s.dels[i]
+		if s.subs[d.SubscriptionID-1].SubscriptionID != want || d.EventID != m.EventID || d.Payload != m.Payload ||
+			d.Status != domainWebhook.StatusPending || d.NextAttemptAt.IsZero() || len(d.DeliveryID) != 32 {
+			t.Fatalf("delivery %d: %+v", i, d)
+		}
+	}
+	if s.dels[1].PartnerID != partnerB {
+		t.Fatalf("partner not copied: %+v", s.dels[1])
+	}
+
+	if err := NewUsecase(s.repo(), nil, &poster{}).Publish(ctx, m); !errors.Is(err, domainLoan.ErrInvalidTransition) {
+		t.Fatalf("nil uow: want ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestUsecase_Dispatch_SignsAndDelivers(t *testing.T) {
+	s := &store{}
+	p := &poster{}
+	uc := s.usecase(p)
+	ctx := context.Background()
+	sub, _ := uc.Subscribe(ctx, SubscribeInput{PartnerID: partnerA, URL: "https://a.example/hook", Events: []string{"LoanApproved"}})
+	_ = uc.Publish(ctx, message(domainOutbox.LoanApproved, strings.Repeat("e", 32)))
+
+	now := time.Now().UTC().Add(time.Second)
+	res, err := uc.Dispatch(ctx, now)
+	if err != nil || *res != (DispatchResult{Delivered: 1}) {
+		t.Fatalf("Dispatch = %+v, %v", res, err)
+	}
+	if len(p.got) != 1 {
+		t.Fatalf("posted %d times", len(p.got))
+	}
+	req, d := p.got[0], s.dels[0]
+	if req.URL != "https://a.example/hook" || string(req.Body) != d.Payload ||
+		req.Headers[domainWebhook.TimestampHeader] != strconv.FormatInt(now.Unix(), 10) ||
+		req.Headers[domainWebhook.SignatureHeader] != domainWebhook.Sign([]byte(sub.Secret), now, req.Body) ||
+		req.Headers[domainWebhook.DeliveryHeader] != d.DeliveryID {
+		t.Fatalf("request: %+v", req)
+	}
+	if d.Status != domainWebhook.StatusDelivered || d.Attempts != 1 || d.DeliveredAt == nil || *d.LastStatusCode != 200 {
+		t.Fatalf("delivery: %+v", d)
+	}
+
+	// nothing left to do
+	if res, _ := uc.Dispatch(ctx, now.Add(time.Hour)); *res != (DispatchResult{}) || len(p.got) != 1 {
+		t.Fatalf("second run: %+v", res)
+	}
+}
+
+func TestUsecase_Dispatch_RetriesThenDeadLetters(t *testing.T) {
+	s := &store{}
+	p := &poster{statuses: []int{500, 0, 410}, errs: []error{nil, errors.New("connection refused")}}
+	uc := s.usecase(p).WithMaxAttempts(3)
+	ctx := context.Background()
+	_, _ = uc.Subscribe(ctx, SubscribeInput{PartnerID: partnerA, URL: "https://a.example/hook", Events: []string{"LoanApproved"}})
+	_ = uc.Publish(ctx, message(domainOutbox.LoanApproved, strings.Repeat("e", 32)))
+	d := s.dels[0]
+
+	now := time.Now().UTC().Add(time.Second)
+	if res, err := uc.Dispatch(ctx, now); err != nil || res.Retried != 1 {
+		t.Fatalf("first run: %+v, %v", res, err)
+	}
+	if d.Status != domainWebhook.StatusPending || d.Attempts != 1 || *d.LastStatusCode != 500 ||
+		*d.LastError != "partner answered 500" || !d.NextAttemptAt.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("after a 500: %+v", d)
+	}
+	// still backing off
+	if res, _ := uc.Dispatch(ctx, now.Add(10*time.Second)); *res != (DispatchResult{}) {
+		t.Fatalf("dispatched during backoff: %+v", res)
+	}
+
+	now = d.NextAttemptAt
+	if res, _ := uc.Dispatch(ctx, now); res.Retried != 1 || d.LastStatusCode != nil ||
+		*d.LastError != "connection refused" || !d.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("after a transport error: %+v, %+v", res, d)
+	}
+
+	now = d.NextAttemptAt
+	if res, _ := uc.Dispatch(ctx, now); res.Dead != 1 || d.Status != domainWebhook.StatusDead || d.Attempts != 3 || *d.LastStatusCode != 410 {
+		t.Fatalf("last attempt: %+v, %+v", res, d)
+	}
+	if res, _ := uc.Dispatch(ctx, now.Add(24*time.Hour)); *res != (DispatchResult{}) || len(p.got) != 3 {
+		t.Fatalf("dead deliveries must not be retried: %+v", res)
+	}
+}
+
+func TestUsecase_Dispatch_RemovedSubscription(t *testing.T) {
+	s := &store{}
+	p := &poster{}
+	uc := s.usecase(p)
+	ctx := context.Background()
+	_, _ = uc.Subscribe(ctx, SubscribeInput{PartnerID: partnerA, URL: "https://a.example/hook", Events: []string{"LoanApproved"}})
+	_ = uc.Publish(ctx, message(domainOutbox.LoanApproved, strings.Repeat("e", 32)))
+	s.subs[0].DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	res, err := uc.Dispatch(ctx, time.Now().UTC().Add(time.Second))
+	if err != nil || res.Dead != 1 || len(p.got) != 0 || s.dels[0].Status != domainWebhook.StatusDead {
+		t.Fatalf("Dispatch = %+v, %v; delivery %+v", res, err, s.dels[0])
+	}
+}
+
+func TestUsecase_ListDeliveries(t *testing.T) {
+	s := &store{}
+	uc := s.usecase(&poster{statuses: []int{500}})
+	ctx := context.Background()
+	sub, _ := uc.Subscribe(ctx, SubscribeInput{PartnerID: partnerA, URL: "https://a.example", Events: []string{"LoanApproved", "LoanInvested"}})
+	_ = uc.Publish(ctx, message(domainOutbox.LoanApproved, strings.Repeat("e", 32)))
+	_ = uc.Publish(ctx, message(domainOutbox.LoanInvested, strings.Repeat("f", 32)))
+	_ = uc.Publish(ctx, message(domainOutbox.LoanInvested, strings.Repeat("c", 32)))
+	if _, err := uc.Dispatch(ctx, time.Now().UTC().Add(time.Second)); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	page, err := uc.ListDeliveries(ctx, ListDeliveriesInput{PartnerID: partnerA, Limit: 2})
+	if err != nil || len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("first page = %+v, %v", page, err)
+	}
+	if page.Items[0].EventID != strings.Repeat("c", 32) || page.Items[0].SubscriptionID != sub.SubscriptionID ||
+		page.Items[0].Status != "DELIVERED" || page.Items[0].NextAttemptAt != nil {
+		t.Fatalf("newest first: %+v", page.Items[0])
+	}
+	rest, err := uc.ListDeliveries(ctx, ListDeliveriesInput{PartnerID: partnerA, Limit: 2, Cursor: page.NextCursor})
+	if err != nil || len(rest.Items) != 1 || rest.NextCursor != "" {
+		t.Fatalf("second page = %+v, %v", rest, err)
+	}
+	failed := rest.Items[0]
+	if failed.Status != "PENDING" || failed.NextAttemptAt == nil || failed.LastError != "partner answered 500" || *failed.LastStatusCode != 500 {
+		t.Fatalf("retrying delivery: %+v", failed)
+	}
+
+	if page, _ := uc.ListDeliveries(ctx, ListDeliveriesInput{Status: "PENDING"}); len(page.Items) != 1 {
+		t.Fatalf("status filter: %+v", page)
+	}
+	if _, err := uc.ListDeliveries(ctx, ListDeliveriesInput{Cursor: "!!"}); !errors.Is(err, domainWebhook.ErrInvalidCursor) {
+		t.Fatalf("bad cursor: want ErrInvalidCursor, got %v", err)
+	}
+}
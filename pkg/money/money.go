@@ -0,0 +1,403 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidDecimal  = errors.New("invalid decimal")
+	ErrDivisionByZero  = errors.New("decimal division by zero")
+	ErrUnsupportedScan = errors.New("unsupported decimal scan source")
+)
+
+// Decimal is an exact base-10 number (coef × 10^-scale).
+// Values are immutable; the zero value is 0 and ready to use.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+// Zero is the additive identity.
+var Zero = Decimal{}
+
+var bigTen = big.NewInt(10)
+
+// Parse limits: amounts and rates need far less, and unbounded input would
+// let a caller make pow10 (and every later rescale) arbitrarily expensive.
+const (
+	maxParseDigits   = 40
+	maxParseExponent = 40
+)
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// New returns coef × 10^-scale, e.g. New(129, 2) == 1.29.
+func New(coef int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: new(big.Int).Mul(big.NewInt(coef), pow10(-scale))}
+	}
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// NewFromInt returns i as a Decimal.
+func NewFromInt(i int64) Decimal { return New(i, 0) }
+
+// NewFromFloat converts f using its shortest round-trip representation,
+// so 1.29 becomes exactly 1.29 rather than 1.2899999999999999.
+// It panics on NaN or ±Inf.
+func NewFromFloat(f float64) Decimal {
+	return MustParse(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Parse reads a plain or exponent decimal string ("5000000", "-1.29", "9e2").
+// At most 40 digits and an exponent of at most ±40 are accepted.
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	orig := s
+	if s == "" {
+		return Zero, fmt.Errorf("%w: empty string", ErrInvalidDecimal)
+	}
+
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil || e > maxParseExponent || e < -maxParseExponent {
+			return Zero, fmt.Errorf("%w: %q", ErrInvalidDecimal, orig)
+		}
+		exp, s = e, s[:i]
+	}
+
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	digits := intPart + fracPart
+	if digits == "" || len(digits) > maxParseDigits || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalidDecimal, orig)
+	}
+
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		coef.Neg(coef)
+	}
+	scale := int64(len(fracPart)) - exp
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParse is Parse for constants and tests; it panics on bad input.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns d's coefficient expressed at a larger scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	c := d.int()
+	if scale == d.scale {
+		return c
+	}
+	return new(big.Int).Mul(c, pow10(scale-d.scale))
+}
+
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	s := a.scale
+	if b.scale > s {
+		s = b.scale
+	}
+	return a.rescale(s), b.rescale(s), s
+}
+
+func (d Decimal) Add(o Decimal) Decimal {
+	x, y, s := align(d, o)
+	return Decimal{coef: new(big.Int).Add(x, y), scale: s}
+}
+
+func (d Decimal) Sub(o Decimal) Decimal {
+	x, y, s := align(d, o)
+	return Decimal{coef: new(big.Int).Sub(x, y), scale: s}
+}
+
+// Mul is exact; the result scale is the sum of both scales.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), o.int()), scale: d.scale + o.scale}
+}
+
+// Div returns d / o rounded half away from zero to places decimals.
+// It panics with ErrDivisionByZero when o is zero.
+func (d Decimal) Div(o Decimal, places int32) Decimal {
+	if o.IsZero() {
+		panic(ErrDivisionByZero)
+	}
+	// Compute one guard digit past places, then round it away.
+	num, den := new(big.Int).Set(d.int()), new(big.Int).Set(o.int())
+	k := places + 1 - d.scale + o.scale
+	if k >= 0 {
+		num.Mul(num, pow10(k))
+	} else {
+		den.Mul(den, pow10(-k))
+	}
+	q := new(big.Int).Quo(num, den)
+	return Decimal{coef: q, scale: places + 1}.Round(places)
+}
+
+// Pow returns d^n exactly for n >= 0.
+func (d Decimal) Pow(n int) Decimal {
+	if n < 0 {
+		panic("money: negative exponent")
+	}
+	return Decimal{coef: new(big.Int).Exp(d.int(), big.NewInt(int64(n)), nil), scale: d.scale * int32(n)}
+}
+
+func (d Decimal) Neg() Decimal { return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale} }
+
+func (d Decimal) Abs() Decimal { return Decimal{coef: new(big.Int).Abs(d.int()), scale: d.scale} }
+
+// Round rounds half away from zero to places decimals.
+func (d Decimal) Round(places int32) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	p := pow10(d.scale - places)
+	q, r := new(big.Int).QuoRem(d.int(), p, new(big.Int))
+	if r.Sign() != 0 && new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(p) >= 0 {
+		q.Add(q, big.NewInt(int64(d.int().Sign())))
+	}
+	return Decimal{coef: q, scale: places}
+}
+
+// Truncate drops digits past places (rounds toward zero).
+func (d Decimal) Truncate(places int32) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	return Decimal{coef: new(big.Int).Quo(d.int(), pow10(d.scale-places)), scale: places}
+}
+
+// Allocate splits d across weights pro-rata, each share rounded down to
+// places decimals. The leftover units go one each to the largest
+// truncated remainders, ties to the lower index, so the shares always sum
+// to exactly d.Round(places). Zero weights never receive a share; it returns nil when
+// no weight is positive or d is negative.
+func (d Decimal) Allocate(weights []Decimal, places int32) []Decimal {
+	total := Zero
+	for _, w := range weights {
+		if w.IsNegative() {
+			return nil
+		}
+		total = total.Add(w)
+	}
+	if !total.IsPositive() || d.IsNegative() {
+		return nil
+	}
+
+	// Bring every weight to one scale so the remainders share a denominator.
+	var scale int32
+	for _, w := range weights {
+		if w.scale > scale {
+			scale = w.scale
+		}
+	}
+	den := total.rescale(scale)
+
+	// Work in integer units of 10^-places: share_i = units × w_i / total.
+	units := d.Round(places).rescale(places)
+	left := new(big.Int).Set(units)
+	out := make([]Decimal, len(weights))
+	rems := make([]*big.Int, len(weights))
+	for i, w := range weights {
+		num := new(big.Int).Mul(units, w.rescale(scale))
+		q, r := num.QuoRem(num, den, new(big.Int))
+		out[i] = Decimal{coef: q, scale: places}
+		rems[i] = r
+		left.Sub(left, q)
+	}
+
+	order := make([]int, 0, len(weights))
+	for i, w := range weights {
+		if w.IsPositive() {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return rems[order[a]].Cmp(rems[order[b]]) > 0 })
+	one := big.NewInt(1)
+	for k := 0; left.Sign() > 0; k++ {
+		i := order[k%len(order)]
+		out[i] = Decimal{coef: new(big.Int).Add(out[i].int(), one), scale: places}
+		left.Sub(left, one)
+	}
+	return out
+}
+
+func (d Decimal) Cmp(o Decimal) int {
+	x, y, _ := align(d, o)
+	return x.Cmp(y)
+}
+
+func (d Decimal) Equal(o Decimal) bool              { return d.Cmp(o) == 0 }
+func (d Decimal) LessThan(o Decimal) bool           { return d.Cmp(o) < 0 }
+func (d Decimal) LessThanOrEqual(o Decimal) bool    { return d.Cmp(o) <= 0 }
+func (d Decimal) GreaterThan(o Decimal) bool        { return d.Cmp(o) > 0 }
+func (d Decimal) GreaterThanOrEqual(o Decimal) bool { return d.Cmp(o) >= 0 }
+
+func (d Decimal) Sign() int        { return d.int().Sign() }
+func (d Decimal) IsZero() bool     { return d.Sign() == 0 }
+func (d Decimal) IsNegative() bool { return d.Sign() < 0 }
+func (d Decimal) IsPositive() bool { return d.Sign() > 0 }
+
+// Places reports the number of significant decimal places (1.2900 → 2).
+func (d Decimal) Places() int32 {
+	c := d.int()
+	if c.Sign() == 0 {
+		return 0
+	}
+	s := d.scale
+	r := new(big.Int)
+	q := new(big.Int).Set(c)
+	for s > 0 {
+		q.QuoRem(q, bigTen, r)
+		if r.Sign() != 0 {
+			break
+		}
+		s--
+	}
+	return s
+}
+
+// IsInteger reports whether d has no fractional part.
+func (d Decimal) IsInteger() bool { return d.Places() == 0 }
+
+// Float64 is for reporting only; never feed the result back into money math.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String renders d without trailing fractional zeros ("1.29", "5000000").
+func (d Decimal) String() string { return d.Round(d.Places()).format() }
+
+// StringFixed renders d rounded to exactly places decimals ("5000000.00").
+func (d Decimal) StringFixed(places int32) string {
+	r := d.Round(places)
+	return Decimal{coef: r.rescale(places), scale: places}.format()
+}
+
+func (d Decimal) format() string {
+	c := d.int()
+	digits := new(big.Int).Abs(c).String()
+	sign := ""
+	if c.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale <= 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	cut := len(digits) - int(d.scale)
+	return sign + digits[:cut] + "." + digits[cut:]
+}
+
+// ---- database/sql ----
+
+// Value stores the decimal as its exact string form.
+func (d Decimal) Value() (driver.Value, error) { return d.String(), nil }
+
+// Scan accepts the representations MySQL and SQLite drivers return for DECIMAL columns.
+func (d *Decimal) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Zero
+		return nil
+	case []byte:
+		p, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*d = p
+		return nil
+	case string:
+		p, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*d = p
+		return nil
+	case int64:
+		*d = NewFromInt(v)
+		return nil
+	case float64:
+		*d = NewFromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedScan, src)
+	}
+}
+
+// GormDataType is used when a field has no explicit `type:` tag.
+func (Decimal) GormDataType() string { return "decimal" }
+
+// ---- JSON ----
+
+// MarshalJSON writes a JSON string so clients never round-trip through float.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts both "1.29" and 1.29.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		*d = Zero
+		return nil
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	p, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = p
+	return nil
+}
+
+// UnmarshalText lets query and form binders parse decimals, e.g. ?principal_min=5000000.
+func (d *Decimal) UnmarshalText(b []byte) error {
+	p, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*d = p
+	return nil
+}
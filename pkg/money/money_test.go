@@ -0,0 +1,277 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAndString(t *testing.T) {
+	cases := map[string]string{
+		"5000000":      "5000000",
+		"5000000.00":   "5000000",
+		"1.2900":       "1.29",
+		"-0.05":        "-0.05",
+		"+12.5":        "12.5",
+		".5":           "0.5",
+		"9e2":          "900",
+		"1.5E-3":       "0.0015",
+		"  42.10  ":    "42.1",
+		"0.000":        "0",
+		"123456789.99": "123456789.99",
+	}
+	for in, want := range cases {
+		d, err := Parse(in)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", in, err)
+		}
+		if got := d.String(); got != want {
+			t.Fatalf("Parse(%q).String() = %q, want %q", in, got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "abc", "1.2.3", "-", "1e", "1,000", "0x10"} {
+		if _, err := Parse(bad); !errors.Is(err, ErrInvalidDecimal) {
+			t.Fatalf("Parse(%q) err = %v, want ErrInvalidDecimal", bad, err)
+		}
+	}
+}
+
+func TestParseRejectsHugeInput(t *testing.T) {
+	for _, ok := range []string{"1e40", "1e-40", strings.Repeat("9", 40), "0." + strings.Repeat("1", 39)} {
+		if _, err := Parse(ok); err != nil {
+			t.Fatalf("Parse(%q) error: %v", ok, err)
+		}
+	}
+
+	start := time.Now()
+	for _, bad := range []string{
+		"1e41", "1e-41", "1e50000000", "1e2000000000", "-1E-2000000000",
+		strings.Repeat("9", 41), "0." + strings.Repeat("0", 1<<20) + "1",
+	} {
+		if _, err := Parse(bad); !errors.Is(err, ErrInvalidDecimal) {
+			t.Fatalf("Parse(%.20q) err = %v, want ErrInvalidDecimal", bad, err)
+		}
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("rejecting huge input took %v", d)
+	}
+}
+
+func TestArithmeticIsExact(t *testing.T) {
+	// 0.1 + 0.2 is the classic float trap
+	if got := MustParse("0.1").Add(MustParse("0.2")); !got.Equal(MustParse("0.3")) {
+		t.Fatalf("0.1+0.2 = %s", got)
+	}
+
+	total := Zero
+	for i := 0; i < 1000; i++ {
+		total = total.Add(MustParse("0.01"))
+	}
+	if !total.Equal(NewFromInt(10)) {
+		t.Fatalf("sum of 1000 cents = %s, want 10", total)
+	}
+
+	if got := MustParse("5000000").Sub(MustParse("1250000.50")); got.String() != "3749999.5" {
+		t.Fatalf("sub = %s", got)
+	}
+	if got := MustParse("5000000").Mul(MustParse("0.0129")); got.String() != "64500" {
+		t.Fatalf("mul = %s", got)
+	}
+	if got := MustParse("1.02").Pow(3); got.String() != "1.061208" {
+		t.Fatalf("pow = %s", got)
+	}
+	if got := MustParse("1.5").Pow(0); got.String() != "1" {
+		t.Fatalf("pow 0 = %s", got)
+	}
+	if got := MustParse("1.29").Neg().Abs(); got.String() != "1.29" {
+		t.Fatalf("neg/abs = %s", got)
+	}
+}
+
+func TestDivAndRounding(t *testing.T) {
+	if got := NewFromInt(10).Div(NewFromInt(3), 2); got.String() != "3.33" {
+		t.Fatalf("10/3 = %s", got)
+	}
+	if got := NewFromInt(20).Div(NewFromInt(3), 2); got.String() != "6.67" {
+		t.Fatalf("20/3 = %s", got)
+	}
+	if got := NewFromInt(-20).Div(NewFromInt(3), 2); got.String() != "-6.67" {
+		t.Fatalf("-20/3 = %s", got)
+	}
+	if got := MustParse("1").Div(MustParse("0.08"), 0); got.String() != "13" { // 12.5 → 13
+		t.Fatalf("1/0.08 = %s", got)
+	}
+
+	for in, want := range map[string]string{
+		"2.345":  "2.35",
+		"2.344":  "2.34",
+		"-2.345": "-2.35",
+		"2.3":    "2.3",
+	} {
+		if got := MustParse(in).Round(2).String(); got != want {
+			t.Fatalf("Round(%s) = %s, want %s", in, got, want)
+		}
+	}
+	if got := MustParse("2.349").Truncate(2).String(); got != "2.34" {
+		t.Fatalf("Truncate = %s", got)
+	}
+	if got := MustParse("7").StringFixed(2); got != "7.00" {
+		t.Fatalf("StringFixed = %s", got)
+	}
+	if got := MustParse("-0.005").StringFixed(2); got != "-0.01" {
+		t.Fatalf("StringFixed negative = %s", got)
+	}
+
+	defer func() {
+		if r := recover(); r != ErrDivisionByZero {
+			t.Fatalf("expected ErrDivisionByZero panic, got %v", r)
+		}
+	}()
+	_ = NewFromInt(1).Div(Zero, 2)
+}
+
+func TestCompareAndPlaces(t *testing.T) {
+	a, b := MustParse("1.2900"), MustParse("1.29")
+	if !a.Equal(b) || a.Cmp(b) != 0 {
+		t.Fatalf("1.2900 should equal 1.29")
+	}
+	if !MustParse("0.9").LessThan(MustParse("0.90001")) || !NewFromInt(2).GreaterThan(MustParse("1.99")) {
+		t.Fatalf("ordering broken")
+	}
+	if a.Places() != 2 || NewFromInt(5000000).Places() != 0 || MustParse("1.234").Places() != 3 {
+		t.Fatalf("Places mismatch")
+	}
+	if !MustParse("5000000.00").IsInteger() || MustParse("5000000.01").IsInteger() {
+		t.Fatalf("IsInteger mismatch")
+	}
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" || zero.Sign() != 0 {
+		t.Fatalf("zero value not usable: %s", zero)
+	}
+	if NewFromFloat(1.29).String() != "1.29" || NewFromFloat(5000000.01).String() != "5000000.01" {
+		t.Fatalf("NewFromFloat shortest repr broken")
+	}
+	if New(129, 2).Float64() != 1.29 {
+		t.Fatalf("Float64 mismatch")
+	}
+}
+
+func TestScanAndValue(t *testing.T) {
+	for _, src := range []any{[]byte("1.2900"), "1.29", float64(1.29)} {
+		var d Decimal
+		if err := d.Scan(src); err != nil {
+			t.Fatalf("Scan(%v) error: %v", src, err)
+		}
+		if d.String() != "1.29" {
+			t.Fatalf("Scan(%v) = %s", src, d)
+		}
+	}
+	var d Decimal
+	if err := d.Scan(int64(5000000)); err != nil || d.String() != "5000000" {
+		t.Fatalf("Scan(int64) = %s, %v", d, err)
+	}
+	if err := d.Scan(nil); err != nil || !d.IsZero() {
+		t.Fatalf("Scan(nil) = %s, %v", d, err)
+	}
+	if err := d.Scan(true); !errors.Is(err, ErrUnsupportedScan) {
+		t.Fatalf("Scan(bool) err = %v", err)
+	}
+	if err := d.Scan("nope"); !errors.Is(err, ErrInvalidDecimal) {
+		t.Fatalf("Scan(bad string) err = %v", err)
+	}
+
+	v, err := MustParse("5000000.50").Value()
+	if err != nil || v != "5000000.5" {
+		t.Fatalf("Value = %v, %v", v, err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type payload struct {
+		Principal Decimal `json:"principal"`
+		Rate      Decimal `json:"rate"`
+	}
+	b, err := json.Marshal(payload{Principal: NewFromInt(5000000), Rate: MustParse("1.29")})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(b) != `{"principal":"5000000","rate":"1.29"}` {
+		t.Fatalf("marshal = %s", b)
+	}
+
+	// accepts strings, bare numbers and null
+	var p payload
+	if err := json.Unmarshal([]byte(`{"principal":5000000,"rate":"1.29"}`), &p); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !p.Principal.Equal(NewFromInt(5000000)) || !p.Rate.Equal(MustParse("1.29")) {
+		t.Fatalf("unmarshal = %+v", p)
+	}
+	if err := json.Unmarshal([]byte(`{"principal":null}`), &p); err != nil || !p.Principal.IsZero() {
+		t.Fatalf("null => %s, %v", p.Principal, err)
+	}
+	if err := json.Unmarshal([]byte(`{"principal":"12abc"}`), &p); err == nil {
+		t.Fatalf("expected error for bad decimal string")
+	}
+
+	var d Decimal
+	if err := d.UnmarshalText([]byte("5000000.50")); err != nil || d.String() != "5000000.5" {
+		t.Fatalf("UnmarshalText = %s, %v", d, err)
+	}
+	if err := d.UnmarshalText([]byte("5e")); !errors.Is(err, ErrInvalidDecimal) {
+		t.Fatalf("UnmarshalText bad err = %v", err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	str := func(ds []Decimal) []string {
+		out := make([]string, len(ds))
+		for i, d := range ds {
+			out[i] = d.String()
+		}
+		return out
+	}
+	cases := []struct {
+		total   string
+		weights []string
+		want    []string
+	}{
+		// 100/3 = 33.333… each; the spare cent goes to the first of the tie
+		{"100", []string{"1", "1", "1"}, []string{"33.34", "33.33", "33.33"}},
+		// largest remainder wins regardless of position: 0.05 × 2/3 = 0.0333, × 1/3 = 0.0166
+		{"0.05", []string{"1000000", "2000000"}, []string{"0.02", "0.03"}},
+		// mixed scales and a zero weight
+		{"10", []string{"0.5", "0", "1.25"}, []string{"2.86", "0", "7.14"}},
+		{"64500", []string{"2500000", "1500000", "1000000"}, []string{"32250", "19350", "12900"}},
+		{"0", []string{"1", "2"}, []string{"0", "0"}},
+	}
+	for _, c := range cases {
+		ws := make([]Decimal, len(c.weights))
+		for i, w := range c.weights {
+			ws[i] = MustParse(w)
+		}
+		got := MustParse(c.total).Allocate(ws, 2)
+		sum := Zero
+		for _, g := range got {
+			sum = sum.Add(g)
+		}
+		if !sum.Equal(MustParse(c.total)) {
+			t.Fatalf("Allocate(%s, %v) sums to %s", c.total, c.weights, sum)
+		}
+		for i := range c.want {
+			if str(got)[i] != c.want[i] {
+				t.Fatalf("Allocate(%s, %v) = %v, want %v", c.total, c.weights, str(got), c.want)
+			}
+		}
+	}
+
+	if got := NewFromInt(1).Allocate([]Decimal{Zero, Zero}, 2); got != nil {
+		t.Fatalf("all-zero weights should return nil, got %v", got)
+	}
+	if got := NewFromInt(1).Allocate([]Decimal{NewFromInt(1), NewFromInt(-1)}, 2); got != nil {
+		t.Fatalf("negative weight should return nil, got %v", got)
+	}
+}
@@ -0,0 +1,92 @@
+// Package phash computes a 64-bit difference hash (dHash) of an image.
+// Re-encoding, resizing or mild recolouring leaves the hash within a few
+// bits, so the Hamming distance between two hashes measures how alike two
+// pictures look rather than whether their bytes match.
+package phash
+
+import (
+	"image"
+	"math/bits"
+)
+
+// Hash is a dHash; int64 so it round-trips through signed SQL integers.
+type Hash int64
+
+const (
+	cols = 9 // 9 columns give 8 left-to-right comparisons per row
+	rows = 8
+)
+
+// Compute shrinks img to 9×8 cells of average luma and sets one bit per
+// cell that is brighter than its right-hand neighbour.
+func Compute(img image.Image) Hash {
+	var sum [rows][cols]uint64
+	var n [rows][cols]uint64
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return 0
+	}
+
+	add := func(x, y int, luma uint64) {
+		cy, cx := y*rows/h, x*cols/w
+		sum[cy][cx] += luma
+		n[cy][cx]++
+	}
+	if ycc, ok := img.(*image.YCbCr); ok {
+		// JPEGs decode to YCbCr: read the luma plane directly
+		for y := 0; y < h; y++ {
+			row := ycc.Y[(y+b.Min.Y-ycc.Rect.Min.Y)*ycc.YStride-ycc.Rect.Min.X+b.Min.X:]
+			for x := 0; x < w; x++ {
+				add(x, y, uint64(row[x]))
+			}
+		}
+	} else {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+				// ITU-R BT.601 luma, scaled from 16-bit channels to 8-bit
+				add(x, y, (299*uint64(r)+587*uint64(g)+114*uint64(bl))/1000>>8)
+			}
+		}
+	}
+
+	var avg [rows][cols]uint64
+	for y := range avg {
+		for x := range avg[y] {
+			if n[y][x] > 0 {
+				avg[y][x] = sum[y][x] * 16 / n[y][x] // keep 4 fractional bits
+			}
+		}
+	}
+	var out uint64
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols-1; x++ {
+			out <<= 1
+			if avg[y][x] > avg[y][x+1] {
+				out |= 1
+			}
+		}
+	}
+	return Hash(out)
+}
+
+// Distance is the number of differing bits, 0 (same picture) to 64.
+func Distance(a, b Hash) int { return bits.OnesCount64(uint64(a ^ b)) }
+
+// Bands cuts h into n contiguous bit ranges of near-equal width and returns
+// the value of each. Hashes fewer than n bits apart agree on at least one
+// band, so exact lookups on the bands find every such neighbour.
+func Bands(h Hash, n int) []uint64 {
+	out := make([]uint64, n)
+	u, lo := uint64(h), 0
+	for i := range out {
+		w := 64 / n
+		if i < 64%n {
+			w++
+		}
+		out[i] = u >> lo & (1<<w - 1)
+		lo += w
+	}
+	return out
+}
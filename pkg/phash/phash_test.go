@@ -0,0 +1,117 @@
+package phash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+// scene draws a deterministic picture; seed changes its layout.
+func scene(w, h, seed int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8((x*255/w + y*seed*97/h + (x/(w/7+1)+y/(h/5+1))*seed*31) % 256)
+			img.Set(x, y, color.RGBA{v, uint8(255 - int(v)/2), uint8(int(v) * seed % 256), 255})
+		}
+	}
+	return img
+}
+
+func shrink(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dst.Set(x, y, src.At(b.Min.X+x*b.Dx()/w, b.Min.Y+y*b.Dy()/h))
+		}
+	}
+	return dst
+}
+
+func roundTrip(t *testing.T, img image.Image, enc func(*bytes.Buffer, image.Image) error) image.Image {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := enc(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	out, _, err := image.Decode(&buf)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return out
+}
+
+func TestCompute_NearDuplicates(t *testing.T) {
+	orig := scene(640, 480, 3)
+	h := Compute(orig)
+	if h == 0 {
+		t.Fatalf("hash of a textured scene should not be zero")
+	}
+
+	jpg := roundTrip(t, orig, func(b *bytes.Buffer, i image.Image) error { return jpeg.Encode(b, i, &jpeg.Options{Quality: 60}) })
+	small := roundTrip(t, shrink(orig, 320, 240), func(b *bytes.Buffer, i image.Image) error { return png.Encode(b, i) })
+	if _, ok := jpg.(*image.YCbCr); !ok {
+		t.Fatalf("expected the JPEG to decode as YCbCr, got %T", jpg)
+	}
+
+	for name, img := range map[string]image.Image{"jpeg q60": jpg, "half size png": small} {
+		if d := Distance(h, Compute(img)); d > 4 {
+			t.Fatalf("%s: distance %d, want a near-duplicate (<= 4)", name, d)
+		}
+	}
+
+	if d := Distance(h, Compute(scene(640, 480, 11))); d < 16 {
+		t.Fatalf("different scene: distance %d, want >= 16", d)
+	}
+}
+
+func TestCompute_SubImage(t *testing.T) {
+	// a cropped view must hash its own bounds, not the parent's
+	whole := roundTrip(t, scene(400, 400, 5), func(b *bytes.Buffer, i image.Image) error { return jpeg.Encode(b, i, nil) })
+	sub := whole.(*image.YCbCr).SubImage(image.Rect(100, 100, 300, 300))
+	copied := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			copied.Set(x, y, sub.At(100+x, 100+y))
+		}
+	}
+	if d := Distance(Compute(sub), Compute(copied)); d > 2 {
+		t.Fatalf("sub-image and its copy differ by %d bits", d)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	if Distance(0, -1) != 64 || Distance(0b1011, 0b0001) != 2 || Distance(42, 42) != 0 {
+		t.Fatalf("Distance mismatch")
+	}
+	if Compute(image.NewGray(image.Rect(0, 0, 0, 0))) != 0 {
+		t.Fatalf("empty image should hash to 0")
+	}
+}
+
+func TestBands(t *testing.T) {
+	if got := Bands(-1, 7); len(got) != 7 || got[0] != 1<<10-1 || got[6] != 1<<9-1 {
+		t.Fatalf("Bands(-1, 7) = %x", got)
+	}
+	// flipping any 6 bits leaves at least one of 7 bands untouched
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a := Hash(rng.Uint64())
+		b := a
+		for _, bit := range rng.Perm(64)[:6] {
+			b ^= 1 << bit
+		}
+		shared := false
+		for j, v := range Bands(b, 7) {
+			shared = shared || Bands(a, 7)[j] == v
+		}
+		if !shared {
+			t.Fatalf("%x and %x share no band", a, b)
+		}
+	}
+}
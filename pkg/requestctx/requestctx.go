@@ -0,0 +1,16 @@
+package requestctx
+
+import "context"
+
+type ctxKey struct{}
+
+// WithRequestID stores the caller's Ax-Request-Id on ctx.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, requestID)
+}
+
+// RequestID returns the Ax-Request-Id stored on ctx, or "" when absent.
+func RequestID(ctx context.Context) string {
+	v, _ := ctx.Value(ctxKey{}).(string)
+	return v
+}
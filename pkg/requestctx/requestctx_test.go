@@ -0,0 +1,19 @@
+package requestctx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestID_RoundTrip(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "0123456789abcdef0123456789abcdef")
+	if got := RequestID(ctx); got != "0123456789abcdef0123456789abcdef" {
+		t.Fatalf("RequestID = %q", got)
+	}
+}
+
+func TestRequestID_Absent(t *testing.T) {
+	if got := RequestID(context.Background()); got != "" {
+		t.Fatalf("RequestID on empty ctx = %q, want empty", got)
+	}
+}